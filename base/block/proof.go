@@ -0,0 +1,45 @@
+package block
+
+import (
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// VerifyOperationProof checks whether the operation of fact is in the
+// operations tree of block with the proof from tree.FixedTree.Proof.
+func VerifyOperationProof(m Manifest, fact valuehash.Hash, pr []tree.FixedTreeNode) error {
+	if fact == nil || fact.IsEmpty() {
+		return tree.InvalidProofError.Errorf("empty fact hash")
+	}
+
+	h := m.OperationsHash()
+	if h == nil || h.IsEmpty() {
+		return tree.InvalidProofError.Errorf("block, %d has no operations", m.Height())
+	}
+
+	return tree.ProveFixedTreeNode(pr, fact.Bytes(), h.Bytes())
+}
+
+// VerifyStateProof checks whether the state is in the states tree of block
+// with the proof from tree.FixedTree.Proof. The hash of state is regenerated,
+// so the value of state also can be trusted.
+func VerifyStateProof(m Manifest, st state.State, pr []tree.FixedTreeNode) error {
+	if err := st.IsValid(nil); err != nil {
+		return tree.InvalidProofError.Wrap(err)
+	}
+
+	switch {
+	case st.Height() != m.Height():
+		return tree.InvalidProofError.Errorf("height of state does not match, %d != %d", st.Height(), m.Height())
+	case st.Hash() == nil || !st.Hash().Equal(st.GenerateHash()):
+		return tree.InvalidProofError.Errorf("wrong state hash")
+	}
+
+	h := m.StatesHash()
+	if h == nil || h.IsEmpty() {
+		return tree.InvalidProofError.Errorf("block, %d has no states", m.Height())
+	}
+
+	return tree.ProveFixedTreeNode(pr, st.Hash().Bytes(), h.Bytes())
+}
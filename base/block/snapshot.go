@@ -0,0 +1,195 @@
+package block
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	BaseSnapshotHeaderType   = hint.Type("base-snapshot-header")
+	BaseSnapshotHeaderHint   = hint.NewHint(BaseSnapshotHeaderType, "v0.0.1")
+	BaseSnapshotHeaderHinter = BaseSnapshotHeader{BaseHinter: hint.NewBaseHinter(BaseSnapshotHeaderHint)}
+)
+
+var BlockdataSnapshot = "snapshot"
+
+// SnapshotHeader describes the state snapshot at the specific height. The
+// snapshot contains the latest states of every keys at the height. Root is
+// the root of the fixed tree of state hashes, which is built like the states
+// tree of block. The states body can be fetched by Item.
+type SnapshotHeader interface {
+	hint.Hinter
+	valuehash.HashGenerator
+	valuehash.Hasher
+	isvalid.IsValider
+	Height() base.Height
+	Block() valuehash.Hash
+	Root() valuehash.Hash
+	Count() uint64
+	Item() BlockdataMapItem
+}
+
+type BaseSnapshotHeader struct {
+	hint.BaseHinter
+	h      valuehash.Hash
+	height base.Height
+	block  valuehash.Hash
+	root   valuehash.Hash
+	count  uint64
+	item   BaseBlockdataMapItem
+}
+
+func NewBaseSnapshotHeader(
+	height base.Height,
+	blk valuehash.Hash,
+	root valuehash.Hash,
+	count uint64,
+	item BaseBlockdataMapItem,
+) (BaseSnapshotHeader, error) {
+	sh := BaseSnapshotHeader{
+		BaseHinter: hint.NewBaseHinter(BaseSnapshotHeaderHint),
+		height:     height,
+		block:      blk,
+		root:       root,
+		count:      count,
+		item:       item,
+	}
+
+	if err := sh.isReadyToHash(); err != nil {
+		return BaseSnapshotHeader{}, err
+	}
+
+	sh.h = sh.GenerateHash()
+
+	return sh, nil
+}
+
+func (sh BaseSnapshotHeader) isReadyToHash() error {
+	if err := isvalid.Check(nil, false, sh.BaseHinter, sh.height, sh.block, sh.root, sh.item); err != nil {
+		return err
+	}
+
+	if sh.count < 1 {
+		return isvalid.InvalidError.Errorf("empty states in snapshot")
+	}
+
+	if sh.item.Type() != BlockdataSnapshot {
+		return isvalid.InvalidError.Errorf("not snapshot item, %q", sh.item.Type())
+	}
+
+	return nil
+}
+
+func (sh BaseSnapshotHeader) IsValid([]byte) error {
+	if err := isvalid.Check(nil, false, sh.h); err != nil {
+		return err
+	}
+
+	if err := sh.isReadyToHash(); err != nil {
+		return isvalid.InvalidError.Wrap(err)
+	}
+
+	if !sh.h.Equal(sh.GenerateHash()) {
+		return isvalid.InvalidError.Errorf("incorrect snapshot header hash")
+	}
+
+	return nil
+}
+
+func (sh BaseSnapshotHeader) Hash() valuehash.Hash {
+	return sh.h
+}
+
+// GenerateHash does not include the item; the item can be different by nodes,
+// but the snapshot of same height should have the same hash.
+func (sh BaseSnapshotHeader) GenerateHash() valuehash.Hash {
+	return valuehash.NewSHA256(util.ConcatBytesSlice(
+		sh.height.Bytes(),
+		sh.block.Bytes(),
+		sh.root.Bytes(),
+		util.Uint64ToBytes(sh.count),
+	))
+}
+
+func (sh BaseSnapshotHeader) Height() base.Height {
+	return sh.height
+}
+
+func (sh BaseSnapshotHeader) Block() valuehash.Hash {
+	return sh.block
+}
+
+func (sh BaseSnapshotHeader) Root() valuehash.Hash {
+	return sh.root
+}
+
+func (sh BaseSnapshotHeader) Count() uint64 {
+	return sh.count
+}
+
+func (sh BaseSnapshotHeader) Item() BlockdataMapItem {
+	return sh.item
+}
+
+// SortSnapshotStates sorts the states by it's hash, the order of states in
+// snapshot.
+func SortSnapshotStates(sts []state.State) {
+	sort.Slice(sts, func(i, j int) bool {
+		return bytes.Compare(sts[i].Hash().Bytes(), sts[j].Hash().Bytes()) < 0
+	})
+}
+
+// SnapshotStatesTree builds the fixed tree from the sorted states of snapshot.
+func SnapshotStatesTree(sts []state.State) (tree.FixedTree, error) {
+	trg := tree.NewFixedTreeGenerator(uint64(len(sts)))
+	for i := range sts {
+		if err := trg.Add(state.NewFixedTreeNode(uint64(i), sts[i].Hash().Bytes())); err != nil {
+			return tree.FixedTree{}, err
+		}
+	}
+
+	return trg.Tree()
+}
+
+// VerifySnapshotStates checks the states of snapshot; the states should be
+// sorted and the root of states should match with the root of header.
+func VerifySnapshotStates(sh SnapshotHeader, sts []state.State) error {
+	if uint64(len(sts)) != sh.Count() {
+		return isvalid.InvalidError.Errorf("number of states does not match, %d != %d", len(sts), sh.Count())
+	}
+
+	for i := range sts {
+		st := sts[i]
+		if err := st.IsValid(nil); err != nil {
+			return err
+		}
+
+		switch {
+		case st.Height() > sh.Height():
+			return isvalid.InvalidError.Errorf("state, %q is higher than snapshot, %d", st.Key(), st.Height())
+		case !st.Hash().Equal(st.GenerateHash()):
+			return isvalid.InvalidError.Errorf("wrong state hash, %q", st.Key())
+		case i > 0 && bytes.Compare(sts[i-1].Hash().Bytes(), st.Hash().Bytes()) >= 0:
+			return isvalid.InvalidError.Errorf("states of snapshot are not sorted")
+		}
+	}
+
+	tr, err := SnapshotStatesTree(sts)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(tr.Root(), sh.Root().Bytes()) {
+		return isvalid.InvalidError.Errorf("root of snapshot states does not match")
+	}
+
+	return nil
+}
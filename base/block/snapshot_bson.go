@@ -0,0 +1,40 @@
+package block
+
+import (
+	"github.com/spikeekips/mitum/base"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (sh BaseSnapshotHeader) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(sh.Hint()),
+		bson.M{
+			"hash":   sh.h,
+			"height": sh.height,
+			"block":  sh.block,
+			"root":   sh.root,
+			"count":  sh.count,
+			"item":   sh.item,
+		},
+	))
+}
+
+type BaseSnapshotHeaderBSONUnpacker struct {
+	H      valuehash.Bytes `bson:"hash"`
+	Height base.Height     `bson:"height"`
+	Block  valuehash.Bytes `bson:"block"`
+	Root   valuehash.Bytes `bson:"root"`
+	Count  uint64          `bson:"count"`
+	Item   bson.Raw        `bson:"item"`
+}
+
+func (sh *BaseSnapshotHeader) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ush BaseSnapshotHeaderBSONUnpacker
+	if err := enc.Unmarshal(b, &ush); err != nil {
+		return err
+	}
+
+	return sh.unpack(enc, ush.H, ush.Height, ush.Block, ush.Root, ush.Count, ush.Item)
+}
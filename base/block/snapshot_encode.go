@@ -0,0 +1,31 @@
+package block
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (sh *BaseSnapshotHeader) unpack(
+	enc encoder.Encoder,
+	h valuehash.Hash,
+	height base.Height,
+	blk valuehash.Hash,
+	root valuehash.Hash,
+	count uint64,
+	bitem []byte,
+) error {
+	item, err := DecodeBaseBlockdataMapItem(bitem, enc)
+	if err != nil {
+		return err
+	}
+
+	sh.h = h
+	sh.height = height
+	sh.block = blk
+	sh.root = root
+	sh.count = count
+	sh.item = item
+
+	return nil
+}
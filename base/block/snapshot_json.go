@@ -0,0 +1,49 @@
+package block
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type BaseSnapshotHeaderJSONPacker struct {
+	jsonenc.HintedHead
+	H      valuehash.Hash       `json:"hash"`
+	Height base.Height          `json:"height"`
+	Block  valuehash.Hash       `json:"block"`
+	Root   valuehash.Hash       `json:"root"`
+	Count  uint64               `json:"count"`
+	Item   BaseBlockdataMapItem `json:"item"`
+}
+
+func (sh BaseSnapshotHeader) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(BaseSnapshotHeaderJSONPacker{
+		HintedHead: jsonenc.NewHintedHead(sh.Hint()),
+		H:          sh.h,
+		Height:     sh.height,
+		Block:      sh.block,
+		Root:       sh.root,
+		Count:      sh.count,
+		Item:       sh.item,
+	})
+}
+
+type BaseSnapshotHeaderJSONUnpacker struct {
+	H      valuehash.Bytes `json:"hash"`
+	Height base.Height     `json:"height"`
+	Block  valuehash.Bytes `json:"block"`
+	Root   valuehash.Bytes `json:"root"`
+	Count  uint64          `json:"count"`
+	Item   json.RawMessage `json:"item"`
+}
+
+func (sh *BaseSnapshotHeader) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ush BaseSnapshotHeaderJSONUnpacker
+	if err := enc.Unmarshal(b, &ush); err != nil {
+		return err
+	}
+
+	return sh.unpack(enc, ush.H, ush.Height, ush.Block, ush.Root, ush.Count, ush.Item)
+}
@@ -0,0 +1,147 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSnapshotHeader struct {
+	suite.Suite
+
+	enc encoder.Encoder
+}
+
+func (t *testSnapshotHeader) SetupSuite() {
+	t.enc.Add(BaseSnapshotHeaderHinter)
+}
+
+func (t *testSnapshotHeader) newStates(n int, height base.Height) []state.State {
+	sts := make([]state.State, n)
+	for i := range sts {
+		v, err := state.NewStringValue(util.UUID().String())
+		t.NoError(err)
+
+		st, err := state.NewStateV0(util.UUID().String(), v, height)
+		t.NoError(err)
+
+		ust, err := st.SetHash(st.GenerateHash())
+		t.NoError(err)
+
+		sts[i] = ust
+	}
+
+	SortSnapshotStates(sts)
+
+	return sts
+}
+
+func (t *testSnapshotHeader) newHeader(height base.Height, sts []state.State) BaseSnapshotHeader {
+	tr, err := SnapshotStatesTree(sts)
+	t.NoError(err)
+
+	item := NewBaseBlockdataMapItem(BlockdataSnapshot, valuehash.RandomSHA256().String(), "file:///snapshot/33.gz")
+
+	sh, err := NewBaseSnapshotHeader(height, valuehash.RandomSHA256(), valuehash.NewBytes(tr.Root()), uint64(len(sts)), item)
+	t.NoError(err)
+
+	return sh
+}
+
+func (t *testSnapshotHeader) TestNew() {
+	sts := t.newStates(3, 33)
+	sh := t.newHeader(33, sts)
+
+	t.NoError(sh.IsValid(nil))
+	t.NoError(VerifySnapshotStates(sh, sts))
+}
+
+func (t *testSnapshotHeader) TestWrongItem() {
+	sts := t.newStates(3, 33)
+	tr, err := SnapshotStatesTree(sts)
+	t.NoError(err)
+
+	item := NewBaseBlockdataMapItem(BlockdataStates, valuehash.RandomSHA256().String(), "file:///snapshot/33.gz")
+
+	_, err = NewBaseSnapshotHeader(33, valuehash.RandomSHA256(), valuehash.NewBytes(tr.Root()), uint64(len(sts)), item)
+	t.Error(err)
+	t.Contains(err.Error(), "not snapshot item")
+}
+
+func (t *testSnapshotHeader) TestVerifyStates() {
+	sts := t.newStates(3, 33)
+	sh := t.newHeader(33, sts)
+
+	{ // NOTE missing state
+		err := VerifySnapshotStates(sh, sts[:2])
+		t.Error(err)
+		t.Contains(err.Error(), "number of states does not match")
+	}
+
+	{ // NOTE not sorted
+		usts := []state.State{sts[1], sts[0], sts[2]}
+		err := VerifySnapshotStates(sh, usts)
+		t.Error(err)
+		t.Contains(err.Error(), "not sorted")
+	}
+
+	{ // NOTE different state
+		usts := make([]state.State, len(sts))
+		copy(usts, sts)
+		usts[1] = t.newStates(1, 33)[0]
+		SortSnapshotStates(usts)
+
+		err := VerifySnapshotStates(sh, usts)
+		t.Error(err)
+		t.Contains(err.Error(), "root of snapshot states does not match")
+	}
+
+	{ // NOTE higher state
+		usts := t.newStates(3, 34)
+		err := VerifySnapshotStates(t.newHeader(33, usts), usts)
+		t.Error(err)
+		t.Contains(err.Error(), "higher than snapshot")
+	}
+}
+
+func (t *testSnapshotHeader) TestEncode() {
+	sh := t.newHeader(33, t.newStates(3, 33))
+
+	b, err := t.enc.Marshal(sh)
+	t.NoError(err)
+
+	i, err := t.enc.Decode(b)
+	t.NoError(err)
+	t.IsType(BaseSnapshotHeader{}, i)
+
+	ush := i.(BaseSnapshotHeader)
+	t.NoError(ush.IsValid(nil))
+
+	t.True(sh.Hash().Equal(ush.Hash()))
+	t.Equal(sh.Height(), ush.Height())
+	t.True(sh.Block().Equal(ush.Block()))
+	t.True(sh.Root().Equal(ush.Root()))
+	t.Equal(sh.Count(), ush.Count())
+	t.Equal(sh.item, ush.item)
+}
+
+func TestSnapshotHeaderJSON(t *testing.T) {
+	b := new(testSnapshotHeader)
+	b.enc = jsonenc.NewEncoder()
+
+	suite.Run(t, b)
+}
+
+func TestSnapshotHeaderBSON(t *testing.T) {
+	b := new(testSnapshotHeader)
+	b.enc = bsonenc.NewEncoder()
+
+	suite.Run(t, b)
+}
@@ -27,6 +27,8 @@ func (t *testFactSignEncoding) SetupSuite() {
 	_ = t.encs.AddEncoder(t.enc)
 
 	_ = t.encs.TestAddHinter(key.BasePublickey{})
+	_ = t.encs.TestAddHinter(key.Ed25519Publickey{})
+	_ = t.encs.TestAddHinter(key.P256Publickey{})
 	_ = t.encs.TestAddHinter(BaseFactSignHinter)
 }
 
@@ -48,6 +50,29 @@ func (t *testFactSignEncoding) TestMarshal() {
 	t.True(localtime.Equal(fs.SignedAt(), ufs.SignedAt()))
 }
 
+func (t *testFactSignEncoding) TestMarshalOtherKeys() {
+	for _, pk := range []key.Privatekey{key.NewEd25519Privatekey(), key.NewP256Privatekey()} {
+		input := util.UUID().Bytes()
+		sig, err := pk.Sign(input)
+		t.NoError(err)
+
+		fs := NewBaseFactSign(pk.Publickey(), sig)
+		t.NoError(fs.IsValid(nil))
+
+		b, err := t.enc.Marshal(fs)
+		t.NoError(err)
+
+		hinter, err := t.enc.Decode(b)
+		t.NoError(err)
+
+		ufs := hinter.(FactSign)
+
+		t.True(fs.Signer().Equal(ufs.Signer()))
+		t.True(pk.Publickey().Hint().Equal(ufs.Signer().Hint()))
+		t.NoError(ufs.Signer().Verify(input, ufs.Signature()))
+	}
+}
+
 func TestFactSignEncodingJSON(t *testing.T) {
 	s := new(testFactSignEncoding)
 	s.enc = jsonenc.NewEncoder()
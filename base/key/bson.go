@@ -85,3 +85,135 @@ func (k *BasePublickey) UnpackBSON(b []byte, _ *bsonenc.Encoder) error {
 
 	return nil
 }
+
+func (k Ed25519Privatekey) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.String, bsoncore.AppendString(nil, k.String()), nil
+}
+
+func (k *Ed25519Privatekey) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	i, err := unmarshalbson(t, b, func(s string) (Key, error) {
+		return ParseEd25519Privatekey(s)
+	})
+	if err != nil {
+		return err
+	}
+
+	uk, ok := i.(Ed25519Privatekey)
+	if !ok {
+		return errors.Errorf("not privatekey: %T", uk)
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *Ed25519Privatekey) UnpackBSON(b []byte, _ *bsonenc.Encoder) error {
+	uk, err := LoadEd25519Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k Ed25519Publickey) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.String, bsoncore.AppendString(nil, k.String()), nil
+}
+
+func (k *Ed25519Publickey) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	i, err := unmarshalbson(t, b, func(s string) (Key, error) {
+		return ParseEd25519Publickey(s)
+	})
+	if err != nil {
+		return err
+	}
+
+	uk, ok := i.(Ed25519Publickey)
+	if !ok {
+		return errors.Errorf("not publickey: %T", uk)
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *Ed25519Publickey) UnpackBSON(b []byte, _ *bsonenc.Encoder) error {
+	uk, err := LoadEd25519Publickey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k P256Privatekey) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.String, bsoncore.AppendString(nil, k.String()), nil
+}
+
+func (k *P256Privatekey) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	i, err := unmarshalbson(t, b, func(s string) (Key, error) {
+		return ParseP256Privatekey(s)
+	})
+	if err != nil {
+		return err
+	}
+
+	uk, ok := i.(P256Privatekey)
+	if !ok {
+		return errors.Errorf("not privatekey: %T", uk)
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *P256Privatekey) UnpackBSON(b []byte, _ *bsonenc.Encoder) error {
+	uk, err := LoadP256Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k P256Publickey) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.String, bsoncore.AppendString(nil, k.String()), nil
+}
+
+func (k *P256Publickey) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	i, err := unmarshalbson(t, b, func(s string) (Key, error) {
+		return ParseP256Publickey(s)
+	})
+	if err != nil {
+		return err
+	}
+
+	uk, ok := i.(P256Publickey)
+	if !ok {
+		return errors.Errorf("not publickey: %T", uk)
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *P256Publickey) UnpackBSON(b []byte, _ *bsonenc.Encoder) error {
+	uk, err := LoadP256Publickey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package key
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	Ed25519PrivatekeyType = hint.Type("epr")
+	Ed25519PrivatekeyHint = hint.NewHint(Ed25519PrivatekeyType, "v0.0.1")
+	Ed25519PublickeyType  = hint.Type("epu")
+	Ed25519PublickeyHint  = hint.NewHint(Ed25519PublickeyType, "v0.0.1")
+)
+
+// Ed25519Privatekey is based on Ed25519 of RFC 8032.
+type Ed25519Privatekey struct {
+	k   ed25519.PrivateKey
+	pub Ed25519Publickey
+	s   string
+	b   []byte
+}
+
+func NewEd25519Privatekey() Ed25519Privatekey {
+	_, k, _ := ed25519.GenerateKey(rand.Reader)
+
+	return newEd25519Privatekey(k)
+}
+
+func NewEd25519PrivatekeyFromSeed(s string) (Ed25519Privatekey, error) {
+	if l := len(s); l < MinSeedSize {
+		return Ed25519Privatekey{}, isvalid.InvalidError.Errorf(
+			"wrong seed for privatekey; too short, %d < %d", l, MinSeedSize)
+	}
+
+	return newEd25519Privatekey(ed25519.NewKeyFromSeed(valuehash.NewSHA256([]byte(s)).Bytes())), nil
+}
+
+func ParseEd25519Privatekey(s string) (Ed25519Privatekey, error) {
+	t := string(Ed25519PrivatekeyType)
+	switch {
+	case !strings.HasSuffix(s, t):
+		return Ed25519Privatekey{}, InvalidKeyError.Errorf("unknown privatekey string")
+	case len(s) <= len(t):
+		return Ed25519Privatekey{}, InvalidKeyError.Errorf("invalid privatekey string; too short")
+	}
+
+	return LoadEd25519Privatekey(s[:len(s)-len(t)])
+}
+
+func LoadEd25519Privatekey(s string) (Ed25519Privatekey, error) {
+	seed := base58.Decode(s)
+	if len(seed) != ed25519.SeedSize {
+		return Ed25519Privatekey{}, InvalidKeyError.Errorf(
+			"wrong ed25519 privatekey seed size, %d != %d", len(seed), ed25519.SeedSize)
+	}
+
+	return newEd25519Privatekey(ed25519.NewKeyFromSeed(seed)), nil
+}
+
+func newEd25519Privatekey(k ed25519.PrivateKey) Ed25519Privatekey {
+	s := fmt.Sprintf("%s%s", base58.Encode(k.Seed()), Ed25519PrivatekeyType)
+	pub := NewEd25519Publickey(k.Public().(ed25519.PublicKey))
+
+	return Ed25519Privatekey{k: k, s: s, b: []byte(s), pub: pub}
+}
+
+func (Ed25519Privatekey) Hint() hint.Hint {
+	return Ed25519PrivatekeyHint
+}
+
+func (k Ed25519Privatekey) Publickey() Publickey {
+	return k.pub
+}
+
+func (k Ed25519Privatekey) Equal(b Key) bool {
+	if b == nil {
+		return false
+	}
+
+	if k.Hint().Type() != b.Hint().Type() {
+		return false
+	}
+
+	if err := b.IsValid(nil); err != nil {
+		return false
+	}
+
+	return k.s == b.String()
+}
+
+func (k Ed25519Privatekey) String() string {
+	return k.s
+}
+
+func (k Ed25519Privatekey) Bytes() []byte {
+	return k.b
+}
+
+func (k Ed25519Privatekey) IsValid([]byte) error {
+	switch {
+	case len(k.k) != ed25519.PrivateKeySize:
+		return isvalid.InvalidError.Wrap(InvalidKeyError.Errorf("empty ed25519 PrivateKey"))
+	case len(k.s) < 1:
+		return isvalid.InvalidError.Wrap(InvalidKeyError.Errorf("empty privatekey string"))
+	case len(k.b) < 1:
+		return isvalid.InvalidError.Wrap(InvalidKeyError.Errorf("empty privatekey []byte"))
+	}
+
+	return nil
+}
+
+func (k Ed25519Privatekey) Sign(b []byte) (Signature, error) {
+	return Signature(ed25519.Sign(k.k, b)), nil
+}
+
+type Ed25519Publickey struct {
+	k ed25519.PublicKey
+	s string
+	b []byte
+}
+
+func NewEd25519Publickey(k ed25519.PublicKey) Ed25519Publickey {
+	s := fmt.Sprintf("%s%s", base58.Encode(k), Ed25519PublickeyType)
+
+	return Ed25519Publickey{
+		k: k,
+		s: s,
+		b: []byte(s),
+	}
+}
+
+func ParseEd25519Publickey(s string) (Ed25519Publickey, error) {
+	t := string(Ed25519PublickeyType)
+	switch {
+	case !strings.HasSuffix(s, t):
+		return Ed25519Publickey{}, InvalidKeyError.Errorf("unknown publickey string")
+	case len(s) <= len(t):
+		return Ed25519Publickey{}, InvalidKeyError.Errorf("invalid publickey string; too short")
+	}
+
+	return LoadEd25519Publickey(s[:len(s)-len(t)])
+}
+
+func LoadEd25519Publickey(s string) (Ed25519Publickey, error) {
+	b := base58.Decode(s)
+	if len(b) != ed25519.PublicKeySize {
+		return Ed25519Publickey{}, InvalidKeyError.Errorf(
+			"wrong ed25519 publickey size, %d != %d", len(b), ed25519.PublicKeySize)
+	}
+
+	return NewEd25519Publickey(ed25519.PublicKey(b)), nil
+}
+
+func (k Ed25519Publickey) String() string {
+	return k.s
+}
+
+func (k Ed25519Publickey) Bytes() []byte {
+	return k.b
+}
+
+func (Ed25519Publickey) Hint() hint.Hint {
+	return Ed25519PublickeyHint
+}
+
+func (k Ed25519Publickey) IsValid([]byte) error {
+	switch {
+	case len(k.k) != ed25519.PublicKeySize:
+		return InvalidKeyError.Errorf("empty ed25519 PublicKey")
+	case len(k.s) < 1:
+		return InvalidKeyError.Errorf("empty publickey string")
+	case len(k.b) < 1:
+		return InvalidKeyError.Errorf("empty publickey []byte")
+	}
+
+	return nil
+}
+
+func (k Ed25519Publickey) Equal(b Key) bool {
+	if b == nil {
+		return false
+	}
+
+	if k.Hint().Type() != b.Hint().Type() {
+		return false
+	}
+
+	if err := b.IsValid(nil); err != nil {
+		return false
+	}
+
+	return k.s == b.String()
+}
+
+func (k Ed25519Publickey) Verify(input []byte, sig Signature) error {
+	if len(sig) != ed25519.SignatureSize || !ed25519.Verify(k.k, input, sig) {
+		return SignatureVerificationFailedError.Call()
+	}
+
+	return nil
+}
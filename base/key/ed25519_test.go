@@ -0,0 +1,177 @@
+package key
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spikeekips/mitum/util"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/stretchr/testify/suite"
+)
+
+type testEd25519Privatekey struct {
+	suite.Suite
+}
+
+func (t *testEd25519Privatekey) TestNew() {
+	priv := NewEd25519Privatekey()
+	t.NoError(priv.IsValid(nil))
+	t.NoError(priv.Publickey().IsValid(nil))
+
+	t.Implements((*Privatekey)(nil), priv)
+	t.Implements((*Publickey)(nil), priv.Publickey())
+}
+
+func (t *testEd25519Privatekey) TestFromSeed() {
+	seed := util.UUID().String() + util.UUID().String()
+
+	priva, err := NewEd25519PrivatekeyFromSeed(seed)
+	t.NoError(err)
+
+	privb, err := NewEd25519PrivatekeyFromSeed(seed)
+	t.NoError(err)
+
+	t.True(priva.Equal(privb))
+	t.True(priva.Publickey().Equal(privb.Publickey()))
+
+	_, err = NewEd25519PrivatekeyFromSeed(seed[:MinSeedSize-1])
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "too short")
+}
+
+func (t *testEd25519Privatekey) TestParse() {
+	priv := NewEd25519Privatekey()
+
+	upriv, err := ParseEd25519Privatekey(priv.String())
+	t.NoError(err)
+	t.True(priv.Equal(upriv))
+
+	upub, err := ParseEd25519Publickey(priv.Publickey().String())
+	t.NoError(err)
+	t.True(priv.Publickey().Equal(upub))
+
+	_, err = ParseEd25519Privatekey(NewBasePrivatekey().String())
+	t.True(errors.Is(err, InvalidKeyError))
+	t.Contains(err.Error(), "unknown privatekey string")
+
+	_, err = ParseEd25519Publickey(util.UUID().String() + string(Ed25519PublickeyType))
+	t.True(errors.Is(err, InvalidKeyError))
+	t.Contains(err.Error(), "wrong ed25519 publickey size")
+}
+
+func (t *testEd25519Privatekey) TestEqual() {
+	priv := NewEd25519Privatekey()
+	b := NewEd25519Privatekey()
+
+	t.True(priv.Equal(priv))
+	t.False(priv.Equal(b))
+	t.False(priv.Equal(nil))
+	t.False(priv.Publickey().Equal(b.Publickey()))
+	t.False(priv.Publickey().Equal(NewBasePrivatekey().Publickey()))
+}
+
+func (t *testEd25519Privatekey) TestSign() {
+	priv := NewEd25519Privatekey()
+
+	input := []byte("makeme")
+
+	sig, err := priv.Sign(input)
+	t.NoError(err)
+	t.NoError(priv.Publickey().Verify(input, sig))
+
+	err = priv.Publickey().Verify([]byte("findme"), sig)
+	t.True(errors.Is(err, SignatureVerificationFailedError))
+
+	err = NewEd25519Privatekey().Publickey().Verify(input, sig)
+	t.True(errors.Is(err, SignatureVerificationFailedError))
+
+	bsig, err := NewBasePrivatekey().Sign(input)
+	t.NoError(err)
+
+	err = priv.Publickey().Verify(input, bsig)
+	t.True(errors.Is(err, SignatureVerificationFailedError))
+}
+
+func TestEd25519Privatekey(t *testing.T) {
+	suite.Run(t, new(testEd25519Privatekey))
+}
+
+func TestEd25519PrivatekeyDecoderJSON(t *testing.T) {
+	s := new(baseTestKeyEncode)
+	s.enc = jsonenc.NewEncoder()
+	s.encode = func() (Key, []byte) {
+		k := NewEd25519Privatekey()
+		b, err := s.enc.Marshal(k)
+		s.NoError(err)
+
+		return k, b
+	}
+	s.decode = func(b []byte) Key {
+		var d PrivatekeyDecoder
+		s.NoError(s.enc.Unmarshal(b, &d))
+		uk, err := d.Encode(s.enc)
+		s.NoError(err)
+
+		return uk
+	}
+	s.compare = func(a, b Key) {
+		s.IsType(Ed25519Privatekey{}, b)
+		s.True(a.Equal(b))
+	}
+
+	suite.Run(t, s)
+}
+
+func TestEd25519PublickeyDecoderBSON(t *testing.T) {
+	s := new(baseTestKeyEncode)
+	s.enc = bsonenc.NewEncoder()
+	s.encode = func() (Key, []byte) {
+		k := NewEd25519Privatekey().Publickey()
+		b, err := s.enc.Marshal(struct {
+			K Publickey
+		}{K: k})
+		s.NoError(err)
+
+		return k, b
+	}
+	s.decode = func(b []byte) Key {
+		var d struct {
+			K PublickeyDecoder
+		}
+		s.NoError(s.enc.Unmarshal(b, &d))
+		uk, err := d.K.Encode(s.enc)
+		s.NoError(err)
+
+		return uk
+	}
+	s.compare = func(a, b Key) {
+		s.IsType(Ed25519Publickey{}, b)
+		s.True(a.Equal(b))
+	}
+
+	suite.Run(t, s)
+}
+
+func TestEd25519DecodeKeyFromString(t *testing.T) {
+	enc := jsonenc.NewEncoder()
+	_ = enc.Add(Ed25519Privatekey{})
+	_ = enc.Add(Ed25519Publickey{})
+
+	priv := NewEd25519Privatekey()
+
+	upriv, err := DecodePrivatekeyFromString(priv.String(), enc)
+	if err != nil {
+		t.Fatal(err)
+	} else if !priv.Equal(upriv) {
+		t.Fatal("privatekey not matched")
+	}
+
+	upub, err := DecodePublickeyFromString(priv.Publickey().String(), enc)
+	if err != nil {
+		t.Fatal(err)
+	} else if !priv.Publickey().Equal(upub) {
+		t.Fatal("publickey not matched")
+	}
+}
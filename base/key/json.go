@@ -55,3 +55,107 @@ func (k *BasePublickey) UnpackJSON(b []byte, _ *jsonenc.Encoder) error {
 
 	return nil
 }
+
+func (k Ed25519Privatekey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *Ed25519Privatekey) UnmarshalText(b []byte) error {
+	uk, err := ParseEd25519Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *Ed25519Privatekey) UnpackJSON(b []byte, _ *jsonenc.Encoder) error {
+	uk, err := LoadEd25519Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k Ed25519Publickey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *Ed25519Publickey) UnmarshalText(b []byte) error {
+	uk, err := ParseEd25519Publickey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *Ed25519Publickey) UnpackJSON(b []byte, _ *jsonenc.Encoder) error {
+	uk, err := LoadEd25519Publickey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k P256Privatekey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *P256Privatekey) UnmarshalText(b []byte) error {
+	uk, err := ParseP256Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *P256Privatekey) UnpackJSON(b []byte, _ *jsonenc.Encoder) error {
+	uk, err := LoadP256Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k P256Publickey) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *P256Publickey) UnmarshalText(b []byte) error {
+	uk, err := ParseP256Publickey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
+
+func (k *P256Publickey) UnpackJSON(b []byte, _ *jsonenc.Encoder) error {
+	uk, err := LoadP256Publickey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = uk
+
+	return nil
+}
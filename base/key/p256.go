@@ -0,0 +1,247 @@
+package key
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	P256PrivatekeyType = hint.Type("ppr")
+	P256PrivatekeyHint = hint.NewHint(P256PrivatekeyType, "v0.0.1")
+	P256PublickeyType  = hint.Type("ppu")
+	P256PublickeyHint  = hint.NewHint(P256PublickeyType, "v0.0.1")
+)
+
+const p256PrivatekeySize = 32
+
+// P256Privatekey is based on ECDSA of NIST P-256, also known as secp256r1.
+type P256Privatekey struct {
+	k   *ecdsa.PrivateKey
+	pub P256Publickey
+	s   string
+	b   []byte
+}
+
+func NewP256Privatekey() P256Privatekey {
+	k, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	return newP256Privatekey(k)
+}
+
+func NewP256PrivatekeyFromSeed(s string) (P256Privatekey, error) {
+	if l := len(s); l < MinSeedSize {
+		return P256Privatekey{}, isvalid.InvalidError.Errorf(
+			"wrong seed for privatekey; too short, %d < %d", l, MinSeedSize)
+	}
+
+	// NOTE d is in [1, N-1]
+	n := new(big.Int).Sub(elliptic.P256().Params().N, big.NewInt(1))
+	d := new(big.Int).SetBytes(valuehash.NewSHA256([]byte(s)).Bytes())
+	d.Mod(d, n)
+	d.Add(d, big.NewInt(1))
+
+	return newP256Privatekey(newP256ECDSAPrivatekey(d)), nil
+}
+
+func ParseP256Privatekey(s string) (P256Privatekey, error) {
+	t := string(P256PrivatekeyType)
+	switch {
+	case !strings.HasSuffix(s, t):
+		return P256Privatekey{}, InvalidKeyError.Errorf("unknown privatekey string")
+	case len(s) <= len(t):
+		return P256Privatekey{}, InvalidKeyError.Errorf("invalid privatekey string; too short")
+	}
+
+	return LoadP256Privatekey(s[:len(s)-len(t)])
+}
+
+func LoadP256Privatekey(s string) (P256Privatekey, error) {
+	b := base58.Decode(s)
+	if len(b) != p256PrivatekeySize {
+		return P256Privatekey{}, InvalidKeyError.Errorf(
+			"wrong p256 privatekey size, %d != %d", len(b), p256PrivatekeySize)
+	}
+
+	d := new(big.Int).SetBytes(b)
+	if d.Sign() < 1 || d.Cmp(elliptic.P256().Params().N) >= 0 {
+		return P256Privatekey{}, InvalidKeyError.Errorf("invalid p256 privatekey")
+	}
+
+	return newP256Privatekey(newP256ECDSAPrivatekey(d)), nil
+}
+
+func newP256ECDSAPrivatekey(d *big.Int) *ecdsa.PrivateKey {
+	c := elliptic.P256()
+
+	k := &ecdsa.PrivateKey{D: d}
+	k.PublicKey.Curve = c
+	k.PublicKey.X, k.PublicKey.Y = c.ScalarBaseMult(d.Bytes())
+
+	return k
+}
+
+func newP256Privatekey(k *ecdsa.PrivateKey) P256Privatekey {
+	b := make([]byte, p256PrivatekeySize)
+	k.D.FillBytes(b)
+
+	s := fmt.Sprintf("%s%s", base58.Encode(b), P256PrivatekeyType)
+	pub := NewP256Publickey(&k.PublicKey)
+
+	return P256Privatekey{k: k, s: s, b: []byte(s), pub: pub}
+}
+
+func (P256Privatekey) Hint() hint.Hint {
+	return P256PrivatekeyHint
+}
+
+func (k P256Privatekey) Publickey() Publickey {
+	return k.pub
+}
+
+func (k P256Privatekey) Equal(b Key) bool {
+	if b == nil {
+		return false
+	}
+
+	if k.Hint().Type() != b.Hint().Type() {
+		return false
+	}
+
+	if err := b.IsValid(nil); err != nil {
+		return false
+	}
+
+	return k.s == b.String()
+}
+
+func (k P256Privatekey) String() string {
+	return k.s
+}
+
+func (k P256Privatekey) Bytes() []byte {
+	return k.b
+}
+
+func (k P256Privatekey) IsValid([]byte) error {
+	switch {
+	case k.k == nil:
+		return isvalid.InvalidError.Wrap(InvalidKeyError.Errorf("empty ecdsa PrivateKey"))
+	case len(k.s) < 1:
+		return isvalid.InvalidError.Wrap(InvalidKeyError.Errorf("empty privatekey string"))
+	case len(k.b) < 1:
+		return isvalid.InvalidError.Wrap(InvalidKeyError.Errorf("empty privatekey []byte"))
+	}
+
+	return nil
+}
+
+func (k P256Privatekey) Sign(b []byte) (Signature, error) {
+	h := sha256.Sum256(b)
+
+	sig, err := ecdsa.SignASN1(rand.Reader, k.k, h[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return Signature(sig), nil
+}
+
+type P256Publickey struct {
+	k *ecdsa.PublicKey
+	s string
+	b []byte
+}
+
+func NewP256Publickey(k *ecdsa.PublicKey) P256Publickey {
+	s := fmt.Sprintf("%s%s", base58.Encode(elliptic.MarshalCompressed(k.Curve, k.X, k.Y)), P256PublickeyType)
+
+	return P256Publickey{
+		k: k,
+		s: s,
+		b: []byte(s),
+	}
+}
+
+func ParseP256Publickey(s string) (P256Publickey, error) {
+	t := string(P256PublickeyType)
+	switch {
+	case !strings.HasSuffix(s, t):
+		return P256Publickey{}, InvalidKeyError.Errorf("unknown publickey string")
+	case len(s) <= len(t):
+		return P256Publickey{}, InvalidKeyError.Errorf("invalid publickey string; too short")
+	}
+
+	return LoadP256Publickey(s[:len(s)-len(t)])
+}
+
+func LoadP256Publickey(s string) (P256Publickey, error) {
+	c := elliptic.P256()
+
+	x, y := elliptic.UnmarshalCompressed(c, base58.Decode(s))
+	if x == nil {
+		return P256Publickey{}, InvalidKeyError.Errorf("invalid p256 publickey")
+	}
+
+	return NewP256Publickey(&ecdsa.PublicKey{Curve: c, X: x, Y: y}), nil
+}
+
+func (k P256Publickey) String() string {
+	return k.s
+}
+
+func (k P256Publickey) Bytes() []byte {
+	return k.b
+}
+
+func (P256Publickey) Hint() hint.Hint {
+	return P256PublickeyHint
+}
+
+func (k P256Publickey) IsValid([]byte) error {
+	switch {
+	case k.k == nil:
+		return InvalidKeyError.Errorf("empty ecdsa PublicKey")
+	case len(k.s) < 1:
+		return InvalidKeyError.Errorf("empty publickey string")
+	case len(k.b) < 1:
+		return InvalidKeyError.Errorf("empty publickey []byte")
+	}
+
+	return nil
+}
+
+func (k P256Publickey) Equal(b Key) bool {
+	if b == nil {
+		return false
+	}
+
+	if k.Hint().Type() != b.Hint().Type() {
+		return false
+	}
+
+	if err := b.IsValid(nil); err != nil {
+		return false
+	}
+
+	return k.s == b.String()
+}
+
+func (k P256Publickey) Verify(input []byte, sig Signature) error {
+	h := sha256.Sum256(input)
+
+	if !ecdsa.VerifyASN1(k.k, h[:], sig) {
+		return SignatureVerificationFailedError.Call()
+	}
+
+	return nil
+}
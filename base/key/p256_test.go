@@ -0,0 +1,177 @@
+package key
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spikeekips/mitum/util"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/stretchr/testify/suite"
+)
+
+type testP256Privatekey struct {
+	suite.Suite
+}
+
+func (t *testP256Privatekey) TestNew() {
+	priv := NewP256Privatekey()
+	t.NoError(priv.IsValid(nil))
+	t.NoError(priv.Publickey().IsValid(nil))
+
+	t.Implements((*Privatekey)(nil), priv)
+	t.Implements((*Publickey)(nil), priv.Publickey())
+}
+
+func (t *testP256Privatekey) TestFromSeed() {
+	seed := util.UUID().String() + util.UUID().String()
+
+	priva, err := NewP256PrivatekeyFromSeed(seed)
+	t.NoError(err)
+
+	privb, err := NewP256PrivatekeyFromSeed(seed)
+	t.NoError(err)
+
+	t.True(priva.Equal(privb))
+	t.True(priva.Publickey().Equal(privb.Publickey()))
+
+	_, err = NewP256PrivatekeyFromSeed(seed[:MinSeedSize-1])
+	t.True(errors.Is(err, isvalid.InvalidError))
+	t.Contains(err.Error(), "too short")
+}
+
+func (t *testP256Privatekey) TestParse() {
+	priv := NewP256Privatekey()
+
+	upriv, err := ParseP256Privatekey(priv.String())
+	t.NoError(err)
+	t.True(priv.Equal(upriv))
+
+	upub, err := ParseP256Publickey(priv.Publickey().String())
+	t.NoError(err)
+	t.True(priv.Publickey().Equal(upub))
+
+	_, err = ParseP256Privatekey(NewBasePrivatekey().String())
+	t.True(errors.Is(err, InvalidKeyError))
+	t.Contains(err.Error(), "unknown privatekey string")
+
+	_, err = ParseP256Publickey(util.UUID().String() + string(P256PublickeyType))
+	t.True(errors.Is(err, InvalidKeyError))
+	t.Contains(err.Error(), "invalid p256 publickey")
+}
+
+func (t *testP256Privatekey) TestEqual() {
+	priv := NewP256Privatekey()
+	b := NewP256Privatekey()
+
+	t.True(priv.Equal(priv))
+	t.False(priv.Equal(b))
+	t.False(priv.Equal(nil))
+	t.False(priv.Publickey().Equal(b.Publickey()))
+	t.False(priv.Publickey().Equal(NewBasePrivatekey().Publickey()))
+}
+
+func (t *testP256Privatekey) TestSign() {
+	priv := NewP256Privatekey()
+
+	input := []byte("makeme")
+
+	sig, err := priv.Sign(input)
+	t.NoError(err)
+	t.NoError(priv.Publickey().Verify(input, sig))
+
+	err = priv.Publickey().Verify([]byte("findme"), sig)
+	t.True(errors.Is(err, SignatureVerificationFailedError))
+
+	err = NewP256Privatekey().Publickey().Verify(input, sig)
+	t.True(errors.Is(err, SignatureVerificationFailedError))
+
+	bsig, err := NewBasePrivatekey().Sign(input)
+	t.NoError(err)
+
+	err = priv.Publickey().Verify(input, bsig)
+	t.True(errors.Is(err, SignatureVerificationFailedError))
+}
+
+func TestP256Privatekey(t *testing.T) {
+	suite.Run(t, new(testP256Privatekey))
+}
+
+func TestP256PrivatekeyDecoderJSON(t *testing.T) {
+	s := new(baseTestKeyEncode)
+	s.enc = jsonenc.NewEncoder()
+	s.encode = func() (Key, []byte) {
+		k := NewP256Privatekey()
+		b, err := s.enc.Marshal(k)
+		s.NoError(err)
+
+		return k, b
+	}
+	s.decode = func(b []byte) Key {
+		var d PrivatekeyDecoder
+		s.NoError(s.enc.Unmarshal(b, &d))
+		uk, err := d.Encode(s.enc)
+		s.NoError(err)
+
+		return uk
+	}
+	s.compare = func(a, b Key) {
+		s.IsType(P256Privatekey{}, b)
+		s.True(a.Equal(b))
+	}
+
+	suite.Run(t, s)
+}
+
+func TestP256PublickeyDecoderBSON(t *testing.T) {
+	s := new(baseTestKeyEncode)
+	s.enc = bsonenc.NewEncoder()
+	s.encode = func() (Key, []byte) {
+		k := NewP256Privatekey().Publickey()
+		b, err := s.enc.Marshal(struct {
+			K Publickey
+		}{K: k})
+		s.NoError(err)
+
+		return k, b
+	}
+	s.decode = func(b []byte) Key {
+		var d struct {
+			K PublickeyDecoder
+		}
+		s.NoError(s.enc.Unmarshal(b, &d))
+		uk, err := d.K.Encode(s.enc)
+		s.NoError(err)
+
+		return uk
+	}
+	s.compare = func(a, b Key) {
+		s.IsType(P256Publickey{}, b)
+		s.True(a.Equal(b))
+	}
+
+	suite.Run(t, s)
+}
+
+func TestP256DecodeKeyFromString(t *testing.T) {
+	enc := jsonenc.NewEncoder()
+	_ = enc.Add(P256Privatekey{})
+	_ = enc.Add(P256Publickey{})
+
+	priv := NewP256Privatekey()
+
+	upriv, err := DecodePrivatekeyFromString(priv.String(), enc)
+	if err != nil {
+		t.Fatal(err)
+	} else if !priv.Equal(upriv) {
+		t.Fatal("privatekey not matched")
+	}
+
+	upub, err := DecodePublickeyFromString(priv.Publickey().String(), enc)
+	if err != nil {
+		t.Fatal(err)
+	} else if !priv.Publickey().Equal(upub) {
+		t.Fatal("publickey not matched")
+	}
+}
@@ -220,6 +220,10 @@ type baseTestKeyEncode struct {
 func (t *baseTestKeyEncode) SetupSuite() {
 	t.enc.Add(BasePrivatekey{})
 	t.enc.Add(BasePublickey{})
+	t.enc.Add(Ed25519Privatekey{})
+	t.enc.Add(Ed25519Publickey{})
+	t.enc.Add(P256Privatekey{})
+	t.enc.Add(P256Publickey{})
 }
 
 func (t *baseTestKeyEncode) TestDecode() {
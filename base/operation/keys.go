@@ -0,0 +1,230 @@
+package operation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	BaseKeyType    = hint.Type("base-key")
+	BaseKeyHint    = hint.NewHint(BaseKeyType, "v0.0.1")
+	BaseKeyHinter  = BaseKey{BaseHinter: hint.NewBaseHinter(BaseKeyHint)}
+	BaseKeysType   = hint.Type("base-keys")
+	BaseKeysHint   = hint.NewHint(BaseKeysType, "v0.0.1")
+	BaseKeysHinter = BaseKeys{BaseHinter: hint.NewBaseHinter(BaseKeysHint)}
+)
+
+var NotEnoughSignsError = util.NewError("not enough signs")
+
+const (
+	MaxKeysInKeys = 10
+	MaxKeyWeight  = 100
+	MaxThreshold  = 100
+)
+
+// Key is the publickey with weight.
+type Key interface {
+	hint.Hinter
+	isvalid.IsValider
+	util.Byter
+	Key() key.Publickey
+	Weight() uint
+}
+
+// Keys is the set of Key with threshold. The sum of weights of the signers
+// should be over threshold.
+type Keys interface {
+	hint.Hinter
+	isvalid.IsValider
+	util.Byter
+	valuehash.Hasher
+	Keys() []Key
+	Key(key.Publickey) (Key, bool)
+	Threshold() uint
+}
+
+// KeysSigned is the operation, which should be signed by the Keys of the
+// account over it's threshold. The Keys of account is stored in state with
+// KeysStateKey.
+type KeysSigned interface {
+	Operation
+	KeysAddress() base.Address
+}
+
+// KeysStateKey returns the state key of Keys of the given address.
+func KeysStateKey(a base.Address) string {
+	return fmt.Sprintf("%s:keys", a.String())
+}
+
+// CheckThreshold checks whether the sum of weights of the signers is over the
+// threshold of Keys. The signatures of FactSigns should be verified before,
+// usually by Operation.IsValid().
+func CheckThreshold(fs []base.FactSign, keys Keys) error {
+	var sum uint
+
+	signed := map[string]struct{}{}
+	for i := range fs {
+		k := fs[i].Signer().String()
+		if _, found := signed[k]; found {
+			continue
+		}
+		signed[k] = struct{}{}
+
+		if ky, found := keys.Key(fs[i].Signer()); found {
+			sum += ky.Weight()
+		}
+	}
+
+	if sum < keys.Threshold() {
+		return NotEnoughSignsError.Errorf("%d < threshold, %d", sum, keys.Threshold())
+	}
+
+	return nil
+}
+
+type BaseKey struct {
+	hint.BaseHinter
+	k key.Publickey
+	w uint
+}
+
+func NewBaseKey(k key.Publickey, w uint) BaseKey {
+	return BaseKey{BaseHinter: hint.NewBaseHinter(BaseKeyHint), k: k, w: w}
+}
+
+func (ky BaseKey) IsValid([]byte) error {
+	if err := ky.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if ky.w < 1 || ky.w > MaxKeyWeight {
+		return isvalid.InvalidError.Errorf("invalid key weight, 1 <= %d <= %d", ky.w, MaxKeyWeight)
+	}
+
+	return isvalid.Check(nil, false, ky.k)
+}
+
+func (ky BaseKey) Bytes() []byte {
+	if ky.k == nil {
+		return nil
+	}
+
+	return util.ConcatBytesSlice(ky.k.Bytes(), util.UintToBytes(ky.w))
+}
+
+func (ky BaseKey) Key() key.Publickey {
+	return ky.k
+}
+
+func (ky BaseKey) Weight() uint {
+	return ky.w
+}
+
+type BaseKeys struct {
+	hint.BaseHinter
+	h         valuehash.Hash
+	keys      []Key
+	threshold uint
+}
+
+func NewBaseKeys(keys []Key, threshold uint) (BaseKeys, error) {
+	ks := make([]Key, len(keys))
+	copy(ks, keys)
+
+	sort.Slice(ks, func(i, j int) bool {
+		return strings.Compare(ks[i].Key().String(), ks[j].Key().String()) < 0
+	})
+
+	bks := BaseKeys{BaseHinter: hint.NewBaseHinter(BaseKeysHint), keys: ks, threshold: threshold}
+	bks.h = bks.GenerateHash()
+
+	return bks, bks.IsValid(nil)
+}
+
+func (bks BaseKeys) IsValid([]byte) error {
+	if err := bks.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	switch n := len(bks.keys); {
+	case n < 1:
+		return isvalid.InvalidError.Errorf("empty keys")
+	case n > MaxKeysInKeys:
+		return isvalid.InvalidError.Errorf("keys over %d, %d", MaxKeysInKeys, n)
+	}
+
+	if bks.threshold < 1 || bks.threshold > MaxThreshold {
+		return isvalid.InvalidError.Errorf("invalid threshold, 1 <= %d <= %d", bks.threshold, MaxThreshold)
+	}
+
+	var total uint
+	founds := map[string]struct{}{}
+	for i := range bks.keys {
+		ky := bks.keys[i]
+		if err := isvalid.Check(nil, false, ky); err != nil {
+			return err
+		}
+
+		if _, found := founds[ky.Key().String()]; found {
+			return isvalid.InvalidError.Errorf("duplicated key found, %q", ky.Key())
+		}
+		founds[ky.Key().String()] = struct{}{}
+
+		total += ky.Weight()
+	}
+
+	if total < bks.threshold {
+		return isvalid.InvalidError.Errorf("sum of weights under threshold, %d < %d", total, bks.threshold)
+	}
+
+	if bks.h == nil || !bks.h.Equal(bks.GenerateHash()) {
+		return isvalid.InvalidError.Errorf("wrong keys hash")
+	}
+
+	return nil
+}
+
+func (bks BaseKeys) Bytes() []byte {
+	bs := make([][]byte, len(bks.keys)+1)
+	for i := range bks.keys {
+		bs[i] = bks.keys[i].Bytes()
+	}
+
+	bs[len(bks.keys)] = util.UintToBytes(bks.threshold)
+
+	return util.ConcatBytesSlice(bs...)
+}
+
+func (bks BaseKeys) Hash() valuehash.Hash {
+	return bks.h
+}
+
+func (bks BaseKeys) GenerateHash() valuehash.Hash {
+	return valuehash.NewSHA256(bks.Bytes())
+}
+
+func (bks BaseKeys) Keys() []Key {
+	return bks.keys
+}
+
+func (bks BaseKeys) Key(k key.Publickey) (Key, bool) {
+	for i := range bks.keys {
+		if bks.keys[i].Key().Equal(k) {
+			return bks.keys[i], true
+		}
+	}
+
+	return nil, false
+}
+
+func (bks BaseKeys) Threshold() uint {
+	return bks.threshold
+}
@@ -0,0 +1,61 @@
+package operation
+
+import (
+	"github.com/spikeekips/mitum/base/key"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (ky BaseKey) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(ky.Hint()),
+		bson.M{
+			"key":    ky.k,
+			"weight": ky.w,
+		},
+	))
+}
+
+type BaseKeyUnpackerBSON struct {
+	HT hint.Hint            `bson:"_hint"`
+	K  key.PublickeyDecoder `bson:"key"`
+	W  uint                 `bson:"weight"`
+}
+
+func (ky *BaseKey) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uk BaseKeyUnpackerBSON
+	if err := enc.Unmarshal(b, &uk); err != nil {
+		return err
+	}
+
+	return ky.unpack(enc, uk.HT, uk.K, uk.W)
+}
+
+func (bks BaseKeys) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(bks.Hint()),
+		bson.M{
+			"hash":      bks.h,
+			"keys":      bks.keys,
+			"threshold": bks.threshold,
+		},
+	))
+}
+
+type BaseKeysUnpackerBSON struct {
+	HT hint.Hint       `bson:"_hint"`
+	H  valuehash.Bytes `bson:"hash"`
+	KS bson.Raw        `bson:"keys"`
+	TH uint            `bson:"threshold"`
+}
+
+func (bks *BaseKeys) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var uks BaseKeysUnpackerBSON
+	if err := enc.Unmarshal(b, &uks); err != nil {
+		return err
+	}
+
+	return bks.unpack(enc, uks.H, uks.HT, uks.KS, uks.TH)
+}
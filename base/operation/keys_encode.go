@@ -0,0 +1,46 @@
+package operation
+
+import (
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (ky *BaseKey) unpack(enc encoder.Encoder, ht hint.Hint, bk key.PublickeyDecoder, w uint) error {
+	k, err := bk.Encode(enc)
+	if err != nil {
+		return err
+	}
+
+	ky.BaseHinter = hint.NewBaseHinter(ht)
+	ky.k = k
+	ky.w = w
+
+	return nil
+}
+
+func (bks *BaseKeys) unpack(enc encoder.Encoder, h valuehash.Hash, ht hint.Hint, bk []byte, threshold uint) error {
+	hinters, err := enc.DecodeSlice(bk)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]Key, len(hinters))
+	for i := range hinters {
+		j, ok := hinters[i].(Key)
+		if !ok {
+			return util.WrongTypeError.Errorf("expected Key, not %T", hinters[i])
+		}
+
+		keys[i] = j
+	}
+
+	bks.BaseHinter = hint.NewBaseHinter(ht)
+	bks.h = h
+	bks.keys = keys
+	bks.threshold = threshold
+
+	return nil
+}
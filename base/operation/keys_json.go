@@ -0,0 +1,70 @@
+package operation
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base/key"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type BaseKeyPackerJSON struct {
+	jsonenc.HintedHead
+	K key.Publickey `json:"key"`
+	W uint          `json:"weight"`
+}
+
+func (ky BaseKey) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(BaseKeyPackerJSON{
+		HintedHead: jsonenc.NewHintedHead(ky.Hint()),
+		K:          ky.k,
+		W:          ky.w,
+	})
+}
+
+type BaseKeyUnpackerJSON struct {
+	jsonenc.HintedHead
+	K key.PublickeyDecoder `json:"key"`
+	W uint                 `json:"weight"`
+}
+
+func (ky *BaseKey) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uk BaseKeyUnpackerJSON
+	if err := enc.Unmarshal(b, &uk); err != nil {
+		return err
+	}
+
+	return ky.unpack(enc, uk.H, uk.K, uk.W)
+}
+
+type BaseKeysPackerJSON struct {
+	jsonenc.HintedHead
+	H  valuehash.Hash `json:"hash"`
+	KS []Key          `json:"keys"`
+	TH uint           `json:"threshold"`
+}
+
+func (bks BaseKeys) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(BaseKeysPackerJSON{
+		HintedHead: jsonenc.NewHintedHead(bks.Hint()),
+		H:          bks.h,
+		KS:         bks.keys,
+		TH:         bks.threshold,
+	})
+}
+
+type BaseKeysUnpackerJSON struct {
+	jsonenc.HintedHead
+	H  valuehash.Bytes `json:"hash"`
+	KS json.RawMessage `json:"keys"`
+	TH uint            `json:"threshold"`
+}
+
+func (bks *BaseKeys) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var uks BaseKeysUnpackerJSON
+	if err := enc.Unmarshal(b, &uks); err != nil {
+		return err
+	}
+
+	return bks.unpack(enc, uks.H, uks.HintedHead.H, uks.KS, uks.TH)
+}
@@ -0,0 +1,187 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/stretchr/testify/suite"
+)
+
+type testKeys struct {
+	suite.Suite
+	privs []key.Privatekey
+}
+
+func (t *testKeys) SetupTest() {
+	t.privs = []key.Privatekey{key.NewBasePrivatekey(), key.NewEd25519Privatekey(), key.NewP256Privatekey()}
+}
+
+func (t *testKeys) newKeys(threshold uint, weights ...uint) BaseKeys {
+	keys := make([]Key, len(weights))
+	for i := range weights {
+		keys[i] = NewBaseKey(t.privs[i].Publickey(), weights[i])
+	}
+
+	bks, err := NewBaseKeys(keys, threshold)
+	t.NoError(err)
+
+	return bks
+}
+
+func (t *testKeys) TestNew() {
+	bks := t.newKeys(66, 33, 33, 34)
+	t.NoError(bks.IsValid(nil))
+
+	t.Implements((*Keys)(nil), bks)
+	t.Equal(uint(66), bks.Threshold())
+	t.Equal(3, len(bks.Keys()))
+
+	ky, found := bks.Key(t.privs[1].Publickey())
+	t.True(found)
+	t.Equal(uint(33), ky.Weight())
+
+	_, found = bks.Key(key.NewBasePrivatekey().Publickey())
+	t.False(found)
+}
+
+func (t *testKeys) TestInvalid() {
+	{ // empty keys
+		_, err := NewBaseKeys(nil, 1)
+		t.True(errors.Is(err, isvalid.InvalidError))
+		t.Contains(err.Error(), "empty keys")
+	}
+
+	{ // threshold over sum of weights
+		_, err := NewBaseKeys([]Key{NewBaseKey(t.privs[0].Publickey(), 30)}, 31)
+		t.True(errors.Is(err, isvalid.InvalidError))
+		t.Contains(err.Error(), "under threshold")
+	}
+
+	{ // duplicated keys
+		_, err := NewBaseKeys([]Key{
+			NewBaseKey(t.privs[0].Publickey(), 30),
+			NewBaseKey(t.privs[0].Publickey(), 40),
+		}, 50)
+		t.True(errors.Is(err, isvalid.InvalidError))
+		t.Contains(err.Error(), "duplicated key")
+	}
+
+	{ // wrong weight
+		_, err := NewBaseKeys([]Key{NewBaseKey(t.privs[0].Publickey(), MaxKeyWeight+1)}, 50)
+		t.True(errors.Is(err, isvalid.InvalidError))
+		t.Contains(err.Error(), "invalid key weight")
+	}
+
+	{ // wrong threshold
+		_, err := NewBaseKeys([]Key{NewBaseKey(t.privs[0].Publickey(), 30)}, 0)
+		t.True(errors.Is(err, isvalid.InvalidError))
+		t.Contains(err.Error(), "invalid threshold")
+	}
+}
+
+func (t *testKeys) TestCheckThreshold() {
+	bks := t.newKeys(66, 33, 33, 34)
+
+	networkID := util.UUID().Bytes()
+	op, err := NewKVOperation(t.privs[0], []byte("this-is-token"), util.UUID().String(), util.UUID().Bytes(), networkID)
+	t.NoError(err)
+	t.NoError(op.IsValid(networkID))
+
+	t.True(errors.Is(CheckThreshold(op.Signs(), bks), NotEnoughSignsError))
+
+	{ // same signer again
+		sig, err := t.privs[0].Sign(util.ConcatBytesSlice(op.Fact().Hash().Bytes(), networkID))
+		t.NoError(err)
+
+		err = CheckThreshold(append(op.Signs(), base.NewBaseFactSign(t.privs[0].Publickey(), sig)), bks)
+		t.True(errors.Is(err, NotEnoughSignsError))
+	}
+
+	{ // unknown signer
+		priv := key.NewBasePrivatekey()
+		sig, err := priv.Sign(util.ConcatBytesSlice(op.Fact().Hash().Bytes(), networkID))
+		t.NoError(err)
+
+		err = CheckThreshold(append(op.Signs(), base.NewBaseFactSign(priv.Publickey(), sig)), bks)
+		t.True(errors.Is(err, NotEnoughSignsError))
+	}
+
+	sig, err := t.privs[2].Sign(util.ConcatBytesSlice(op.Fact().Hash().Bytes(), networkID))
+	t.NoError(err)
+
+	i, err := op.BaseOperation.AddFactSigns(base.NewBaseFactSign(t.privs[2].Publickey(), sig))
+	t.NoError(err)
+	op.BaseOperation = i.(BaseOperation)
+	t.NoError(op.IsValid(networkID))
+
+	t.NoError(CheckThreshold(op.Signs(), bks))
+}
+
+func TestKeys(t *testing.T) {
+	suite.Run(t, new(testKeys))
+}
+
+type testKeysEncode struct {
+	suite.Suite
+	encs *encoder.Encoders
+	enc  encoder.Encoder
+}
+
+func (t *testKeysEncode) SetupSuite() {
+	t.encs = encoder.NewEncoders()
+	_ = t.encs.AddEncoder(t.enc)
+
+	_ = t.encs.TestAddHinter(key.BasePublickey{})
+	_ = t.encs.TestAddHinter(key.Ed25519Publickey{})
+	_ = t.encs.TestAddHinter(key.P256Publickey{})
+	_ = t.encs.TestAddHinter(BaseKeyHinter)
+	_ = t.encs.TestAddHinter(BaseKeysHinter)
+}
+
+func (t *testKeysEncode) TestMarshal() {
+	bks, err := NewBaseKeys([]Key{
+		NewBaseKey(key.NewBasePrivatekey().Publickey(), 40),
+		NewBaseKey(key.NewEd25519Privatekey().Publickey(), 60),
+	}, 100)
+	t.NoError(err)
+
+	b, err := t.enc.Marshal(bks)
+	t.NoError(err)
+
+	hinter, err := t.enc.Decode(b)
+	t.NoError(err)
+
+	ubks, ok := hinter.(BaseKeys)
+	t.True(ok)
+	t.NoError(ubks.IsValid(nil))
+
+	t.True(bks.Hash().Equal(ubks.Hash()))
+	t.Equal(bks.Threshold(), ubks.Threshold())
+
+	for i := range bks.Keys() {
+		a, b := bks.Keys()[i], ubks.Keys()[i]
+		t.True(a.Key().Equal(b.Key()))
+		t.Equal(a.Weight(), b.Weight())
+	}
+}
+
+func TestKeysEncodeJSON(t *testing.T) {
+	s := new(testKeysEncode)
+	s.enc = jsonenc.NewEncoder()
+
+	suite.Run(t, s)
+}
+
+func TestKeysEncodeBSON(t *testing.T) {
+	s := new(testKeysEncode)
+	s.enc = bsonenc.NewEncoder()
+
+	suite.Run(t, s)
+}
@@ -0,0 +1,94 @@
+package prprocessor
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+)
+
+// KeysFromState returns operation.Keys from the state of
+// operation.KeysStateKey.
+func KeysFromState(st state.State) (operation.Keys, error) {
+	if st.Value() == nil {
+		return nil, errors.Errorf("empty keys state value")
+	}
+
+	keys, ok := st.Value().Interface().(operation.Keys)
+	if !ok {
+		return nil, util.WrongTypeError.Errorf("expected operation.Keys, not %T", st.Value().Interface())
+	}
+
+	return keys, nil
+}
+
+// CheckThresholdFromState loads the Keys of address from state and checks
+// whether the signs are over it's threshold.
+func CheckThresholdFromState(
+	getState func(string) (state.State, bool, error),
+	address base.Address,
+	fs []base.FactSign,
+) error {
+	st, found, err := getState(operation.KeysStateKey(address))
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("keys of %q not found", address)
+	}
+
+	keys, err := KeysFromState(st)
+	if err != nil {
+		return err
+	}
+
+	return operation.CheckThreshold(fs, keys)
+}
+
+// KeysThresholdOperationProcessor rejects the operation.KeysSigned, whose signs
+// does not reach the threshold of the Keys of account in state. The other
+// operations are passed to the wrapped OperationProcessor.
+// ConcurrentOperationsProcessor wraps every OperationProcessor by it.
+type KeysThresholdOperationProcessor struct {
+	opr  OperationProcessor
+	pool *storage.Statepool
+}
+
+func NewKeysThresholdOperationProcessor(opr OperationProcessor) *KeysThresholdOperationProcessor {
+	if opr == nil {
+		opr = defaultOperationProcessor{}
+	}
+
+	return &KeysThresholdOperationProcessor{opr: opr}
+}
+
+func (opp *KeysThresholdOperationProcessor) New(pool *storage.Statepool) OperationProcessor {
+	return &KeysThresholdOperationProcessor{
+		opr:  opp.opr.New(pool),
+		pool: pool,
+	}
+}
+
+func (opp *KeysThresholdOperationProcessor) PreProcess(op state.Processor) (state.Processor, error) {
+	if i, ok := op.(operation.KeysSigned); ok {
+		if err := CheckThresholdFromState(opp.pool.Get, i.KeysAddress(), i.Signs()); err != nil {
+			return nil, operation.NewBaseReasonErrorFromError(err)
+		}
+	}
+
+	return opp.opr.PreProcess(op)
+}
+
+func (opp *KeysThresholdOperationProcessor) Process(op state.Processor) error {
+	return opp.opr.Process(op)
+}
+
+func (opp *KeysThresholdOperationProcessor) Close() error {
+	return opp.opr.Close()
+}
+
+func (opp *KeysThresholdOperationProcessor) Cancel() error {
+	return opp.opr.Cancel()
+}
@@ -0,0 +1,190 @@
+package prprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	leveldbstorage "github.com/spikeekips/mitum/storage/leveldb"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyOperation struct {
+	operation.KVOperation
+}
+
+func (dummyOperation) Process(
+	func(string) (state.State, bool, error),
+	func(valuehash.Hash, ...state.State) error,
+) error {
+	return nil
+}
+
+type dummyKeysOperation struct {
+	dummyOperation
+	address base.Address
+}
+
+func (op dummyKeysOperation) KeysAddress() base.Address {
+	return op.address
+}
+
+type testKeysThreshold struct {
+	suite.Suite
+	privs   []key.Privatekey
+	address base.Address
+	db      storage.Database
+}
+
+func (t *testKeysThreshold) SetupTest() {
+	t.privs = []key.Privatekey{key.NewBasePrivatekey(), key.NewBasePrivatekey(), key.NewBasePrivatekey()}
+	t.address = base.RandomStringAddress()
+
+	encs := encoder.NewEncoders()
+	enc := jsonenc.NewEncoder()
+	t.NoError(encs.AddEncoder(enc))
+
+	t.db = leveldbstorage.NewMemDatabase(encs, enc)
+}
+
+// newOperation creates operation signed by the given keys.
+func (t *testKeysThreshold) newOperation(signers ...key.Privatekey) dummyKeysOperation {
+	op, err := operation.NewKVOperation(signers[0], util.UUID().Bytes(), util.UUID().String(), util.UUID().Bytes(), nil)
+	t.NoError(err)
+
+	for _, pk := range signers[1:] {
+		sig, err := pk.Sign(op.Fact().Hash().Bytes())
+		t.NoError(err)
+
+		i, err := op.AddFactSigns(base.NewBaseFactSign(pk.Publickey(), sig))
+		t.NoError(err)
+
+		op.BaseOperation = i.(operation.BaseOperation)
+	}
+
+	t.NoError(op.IsValid(nil))
+
+	return dummyKeysOperation{dummyOperation: dummyOperation{KVOperation: op}, address: t.address}
+}
+
+// newPool creates Statepool with the keys of 3 keys, 40 weight of each and
+// threshold 80; at least 2 signs are needed.
+func (t *testKeysThreshold) newPool() *storage.Statepool {
+	keys := make([]operation.Key, len(t.privs))
+	for i := range t.privs {
+		keys[i] = operation.NewBaseKey(t.privs[i].Publickey(), 40)
+	}
+
+	bks, err := operation.NewBaseKeys(keys, 80)
+	t.NoError(err)
+
+	value, err := state.NewHintedValue(bks)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(operation.KeysStateKey(t.address), value, base.Height(33))
+	t.NoError(err)
+
+	pool, err := storage.NewStatepoolWithBase(t.db, map[string]state.State{st.Key(): st})
+	t.NoError(err)
+
+	return pool
+}
+
+func (t *testKeysThreshold) newProcessor() OperationProcessor {
+	return NewKeysThresholdOperationProcessor(nil).New(t.newPool())
+}
+
+func (t *testKeysThreshold) TestOverThreshold() {
+	opr := t.newProcessor()
+
+	for _, signers := range [][]key.Privatekey{
+		{t.privs[0], t.privs[1]},
+		{t.privs[0], t.privs[1], t.privs[2]},
+	} {
+		op := t.newOperation(signers...)
+
+		_, err := opr.PreProcess(op)
+		t.NoError(err)
+		t.NoError(opr.Process(op))
+	}
+}
+
+func (t *testKeysThreshold) TestUnderThreshold() {
+	opr := t.newProcessor()
+
+	op := t.newOperation(t.privs[2])
+
+	_, err := opr.PreProcess(op)
+	t.True(errors.Is(err, operation.NotEnoughSignsError))
+
+	var oe operation.ReasonError
+	t.True(errors.As(err, &oe))
+
+	// NOTE unknown signer does not count
+	op = t.newOperation(t.privs[2], key.NewBasePrivatekey())
+
+	_, err = opr.PreProcess(op)
+	t.True(errors.Is(err, operation.NotEnoughSignsError))
+}
+
+func (t *testKeysThreshold) TestKeysNotFound() {
+	opr := t.newProcessor()
+
+	op := t.newOperation(t.privs[0], t.privs[1])
+	op.address = base.RandomStringAddress()
+
+	_, err := opr.PreProcess(op)
+	t.True(errors.Is(err, util.NotFoundError))
+
+	var oe operation.ReasonError
+	t.True(errors.As(err, &oe))
+}
+
+func (t *testKeysThreshold) TestNotKeysSigned() {
+	opr := t.newProcessor()
+
+	op := t.newOperation(t.privs[2])
+
+	_, err := opr.PreProcess(op.dummyOperation)
+	t.NoError(err)
+}
+
+func (t *testKeysThreshold) TestConcurrentOperationsProcessor() {
+	under := t.newOperation(t.privs[2])
+	over := t.newOperation(t.privs[0], t.privs[1])
+
+	// NOTE without registered OperationProcessor
+	co, err := NewConcurrentOperationsProcessor(2, 2, t.newPool(), nil)
+	t.NoError(err)
+
+	co.Start(context.Background(), nil)
+
+	t.NoError(co.Process(0, under))
+	t.NoError(co.Process(1, over))
+	t.NoError(co.Close())
+
+	tr, err := co.OperationsTree()
+	t.NoError(err)
+
+	no, err := tr.Node(0)
+	t.NoError(err)
+	t.False(no.(operation.FixedTreeNode).InState())
+	t.Contains(no.(operation.FixedTreeNode).Reason().Msg(), "not enough signs")
+
+	no, err = tr.Node(1)
+	t.NoError(err)
+	t.True(no.(operation.FixedTreeNode).InState())
+}
+
+func TestKeysThreshold(t *testing.T) {
+	suite.Run(t, new(testKeysThreshold))
+}
@@ -355,6 +355,12 @@ func (co *ConcurrentOperationsProcessor) opr(op state.Processor) (OperationProce
 		}
 	}
 
+	// NOTE operation.KeysSigned is checked by the threshold of keys
+	// regardless of the OperationProcessor of it's hint.
+	if _, ok := opr.(*KeysThresholdOperationProcessor); !ok {
+		opr = NewKeysThresholdOperationProcessor(opr)
+	}
+
 	opr = opr.New(co.pool)
 	co.oprs[hinter.Hint()] = opr
 
@@ -18,6 +18,7 @@ require (
 	github.com/mattn/go-isatty v0.0.14
 	github.com/oklog/ulid v1.3.1
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.11.0
 	github.com/rs/zerolog v1.26.0
 	github.com/satori/go.uuid v1.2.0
 	github.com/spikeekips/mitum v0.0.0-00010101000000-000000000000
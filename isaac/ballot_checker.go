@@ -10,11 +10,14 @@ import (
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/isvalid"
 	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/logging"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
+var BallotNotInTimespanError = util.NewError("ballot not in timespan")
+
 type BallotChecker struct {
 	*logging.Logging
 	database storage.Database
@@ -61,18 +64,21 @@ func (bc *BallotChecker) IsFromLocal() (bool, error) {
 }
 
 // InTimespan checks whether ballot is signed at a given interval,
-// policy.TimespanValidBallot().
+// policy.TimespanValidBallot(). The signing time does not prove when the
+// ballot was sent, so the old ballot can be replayed by anyone; the returned
+// BallotNotInTimespanError wraps isvalid.InvalidError to blame the node, which
+// sends the ballot, not the signer.
 func (bc *BallotChecker) InTimespan() (bool, error) {
 	if bc.fact.Stage() == base.StageProposal { // NOTE proposal should be resigned except fact
 		if !localtime.WithinNow(bc.ballot.SignedAt(), bc.policy.TimespanValidBallot()) {
-			return false, errors.Errorf("too old or new proposal")
+			return false, BallotNotInTimespanError.Wrap(isvalid.InvalidError.Errorf("too old or new proposal"))
 		}
 
 		return true, nil
 	}
 
 	if !localtime.WithinNow(bc.ballot.FactSign().SignedAt(), bc.policy.TimespanValidBallot()) {
-		return false, errors.Errorf("too old or new ballot")
+		return false, BallotNotInTimespanError.Wrap(isvalid.InvalidError.Errorf("too old or new ballot"))
 	}
 
 	return true, nil
@@ -174,7 +180,7 @@ func (bc *BallotChecker) CheckVoteproof() (bool, error) {
 	}
 	voteproof := i.BaseVoteproof()
 
-	vc := NewVoteProofChecker(voteproof, bc.policy, bc.suffrage)
+	vc := NewVoteProofChecker(voteproof, bc.database, bc.policy, bc.suffrage)
 	_ = vc.SetLogging(bc.Logging)
 
 	if err := util.NewChecker("ballot-voteproof-checker", []util.CheckerFunc{
@@ -274,7 +280,7 @@ func CheckBallotSigningNode(fs base.BallotFactSign, nodepool *network.Nodepool)
 	}
 
 	if !fs.Signer().Equal(node.Publickey()) {
-		return errors.Errorf("publickey not matched")
+		return isvalid.InvalidError.Errorf("publickey not matched")
 	}
 
 	return nil
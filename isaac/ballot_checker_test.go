@@ -89,6 +89,7 @@ func (t *testBallotChecker) TestInTimespan() {
 		err := util.NewChecker("test-ballot-checker", []util.CheckerFunc{
 			bc.InTimespan,
 		}).Check()
+		t.True(errors.Is(err, BallotNotInTimespanError))
 		t.Contains(err.Error(), "too old or new ballot")
 	}
 
@@ -105,6 +106,7 @@ func (t *testBallotChecker) TestInTimespan() {
 		err := util.NewChecker("test-ballot-checker", []util.CheckerFunc{
 			bc.InTimespan,
 		}).Check()
+		t.True(errors.Is(err, BallotNotInTimespanError))
 		t.Contains(err.Error(), "too old or new ballot")
 	}
 
@@ -131,6 +133,7 @@ func (t *testBallotChecker) TestInTimespan() {
 		err := util.NewChecker("test-ballot-checker", []util.CheckerFunc{
 			bc.InTimespan,
 		}).Check()
+		t.True(errors.Is(err, BallotNotInTimespanError))
 		t.Contains(err.Error(), "too old or new proposal")
 	}
 
@@ -147,6 +150,7 @@ func (t *testBallotChecker) TestInTimespan() {
 		err := util.NewChecker("test-ballot-checker", []util.CheckerFunc{
 			bc.InTimespan,
 		}).Check()
+		t.True(errors.Is(err, BallotNotInTimespanError))
 		t.Contains(err.Error(), "too old or new proposal")
 	}
 }
@@ -28,6 +28,7 @@ type LocalPolicy struct {
 	sync.RWMutex
 	networkID                        *util.LockedItem
 	thresholdRatio                   *util.LockedItem
+	localThresholdRatio              *util.LockedItem
 	maxOperationsInSeal              *util.LockedItem
 	maxOperationsInProposal          *util.LockedItem
 	timeoutWaitingProposal           *util.LockedItem
@@ -39,12 +40,27 @@ type LocalPolicy struct {
 	// Ballot should be within timespanValidBallot on now. By default, 1 minute.
 	timespanValidBallot      *util.LockedItem
 	networkConnectionTimeout *util.LockedItem
+	// snapshotInterval is the interval of heights to make the state snapshot.
+	// 0 means the state snapshot is not made.
+	snapshotInterval *util.LockedItem
+	// syncFromSnapshot enables Syncers to sync from the latest state snapshot
+	// of the source nodes instead of syncing from genesis block.
+	syncFromSnapshot *util.LockedItem
+	// retentionHeights is the number of the recent heights, which keep the
+	// state history in database. 0 means not pruned.
+	retentionHeights *util.LockedItem
+	// pruneBlockdata removes the block data of the pruned heights.
+	pruneBlockdata *util.LockedItem
+	// policyHeight is the height of block, which the on-chain policy was
+	// stored. base.NilHeight means the on-chain policy is not yet applied.
+	policyHeight *util.LockedItem
 }
 
 func NewLocalPolicy(networkID base.NetworkID) *LocalPolicy {
 	lp := &LocalPolicy{
 		networkID:                        util.NewLockedItem(networkID),
 		thresholdRatio:                   util.NewLockedItem(DefaultPolicyThresholdRatio),
+		localThresholdRatio:              util.NewLockedItem(DefaultPolicyThresholdRatio),
 		maxOperationsInSeal:              util.NewLockedItem(DefaultPolicyMaxOperationsInSeal),
 		maxOperationsInProposal:          util.NewLockedItem(DefaultPolicyMaxOperationsInProposal),
 		timeoutWaitingProposal:           util.NewLockedItem(DefaultPolicyTimeoutWaitingProposal),
@@ -54,6 +70,11 @@ func NewLocalPolicy(networkID base.NetworkID) *LocalPolicy {
 		intervalBroadcastingACCEPTBallot: util.NewLockedItem(DefaultPolicyIntervalBroadcastingACCEPTBallot),
 		timespanValidBallot:              util.NewLockedItem(DefaultPolicyTimespanValidBallot),
 		networkConnectionTimeout:         util.NewLockedItem(DefaultPolicyNetworkConnectionTimeout),
+		snapshotInterval:                 util.NewLockedItem(uint(0)),
+		syncFromSnapshot:                 util.NewLockedItem(false),
+		retentionHeights:                 util.NewLockedItem(uint(0)),
+		pruneBlockdata:                   util.NewLockedItem(false),
+		policyHeight:                     util.NewLockedItem(base.NilHeight),
 	}
 
 	return lp
@@ -69,10 +90,18 @@ func (lp *LocalPolicy) ThresholdRatio() base.ThresholdRatio {
 
 func (lp *LocalPolicy) SetThresholdRatio(ratio base.ThresholdRatio) *LocalPolicy {
 	_ = lp.thresholdRatio.Set(ratio)
+	_ = lp.localThresholdRatio.Set(ratio)
 
 	return lp
 }
 
+// LocalThresholdRatio returns the threshold ratio of local config, which was
+// used before the on-chain policy is stored. Unlike ThresholdRatio, it is not
+// changed by SetPolicy.
+func (lp *LocalPolicy) LocalThresholdRatio() base.ThresholdRatio {
+	return lp.localThresholdRatio.Value().(base.ThresholdRatio)
+}
+
 func (lp *LocalPolicy) TimeoutWaitingProposal() time.Duration {
 	return lp.timeoutWaitingProposal.Value().(time.Duration)
 }
@@ -171,6 +200,46 @@ func (lp *LocalPolicy) SetNetworkConnectionTimeout(d time.Duration) (*LocalPolic
 	return lp, nil
 }
 
+func (lp *LocalPolicy) SnapshotInterval() uint {
+	return lp.snapshotInterval.Value().(uint)
+}
+
+func (lp *LocalPolicy) SetSnapshotInterval(i uint) (*LocalPolicy, error) {
+	_ = lp.snapshotInterval.Set(i)
+
+	return lp, nil
+}
+
+func (lp *LocalPolicy) SyncFromSnapshot() bool {
+	return lp.syncFromSnapshot.Value().(bool)
+}
+
+func (lp *LocalPolicy) SetSyncFromSnapshot(b bool) *LocalPolicy {
+	_ = lp.syncFromSnapshot.Set(b)
+
+	return lp
+}
+
+func (lp *LocalPolicy) RetentionHeights() uint {
+	return lp.retentionHeights.Value().(uint)
+}
+
+func (lp *LocalPolicy) SetRetentionHeights(i uint) (*LocalPolicy, error) {
+	_ = lp.retentionHeights.Set(i)
+
+	return lp, nil
+}
+
+func (lp *LocalPolicy) PruneBlockdata() bool {
+	return lp.pruneBlockdata.Value().(bool)
+}
+
+func (lp *LocalPolicy) SetPruneBlockdata(b bool) *LocalPolicy {
+	_ = lp.pruneBlockdata.Set(b)
+
+	return lp
+}
+
 func (lp *LocalPolicy) MaxOperationsInSeal() uint {
 	return lp.maxOperationsInSeal.Value().(uint)
 }
@@ -199,8 +268,42 @@ func (lp *LocalPolicy) SetMaxOperationsInProposal(m uint) (*LocalPolicy, error)
 	return lp, nil
 }
 
+// PolicyHeight returns the height of block, which the applied on-chain policy
+// was stored.
+func (lp *LocalPolicy) PolicyHeight() base.Height {
+	return lp.policyHeight.Value().(base.Height)
+}
+
+// SetPolicy applies the on-chain policy, which was stored at the given height.
+// The older policy than the current one is ignored.
+func (lp *LocalPolicy) SetPolicy(po PolicyV0, height base.Height) error {
+	if err := po.IsValid(nil); err != nil {
+		return err
+	}
+
+	lp.Lock()
+	defer lp.Unlock()
+
+	if height <= lp.PolicyHeight() {
+		return nil
+	}
+
+	_ = lp.thresholdRatio.Set(po.ThresholdRatio())
+	_ = lp.maxOperationsInSeal.Set(po.MaxOperationsInSeal())
+	_ = lp.maxOperationsInProposal.Set(po.MaxOperationsInProposal())
+	_ = lp.timeoutWaitingProposal.Set(po.TimeoutWaitingProposal())
+	_ = lp.intervalBroadcastingINITBallot.Set(po.IntervalBroadcastingINITBallot())
+	_ = lp.intervalBroadcastingProposal.Set(po.IntervalBroadcastingProposal())
+	_ = lp.waitBroadcastingACCEPTBallot.Set(po.WaitBroadcastingACCEPTBallot())
+	_ = lp.intervalBroadcastingACCEPTBallot.Set(po.IntervalBroadcastingACCEPTBallot())
+	_ = lp.timespanValidBallot.Set(po.TimespanValidBallot())
+	_ = lp.policyHeight.Set(height)
+
+	return nil
+}
+
 func (lp *LocalPolicy) Config() map[string]interface{} {
-	return map[string]interface{}{
+	m := map[string]interface{}{
 		"threshold":                           lp.ThresholdRatio(),
 		"max_operations_in_seal":              lp.MaxOperationsInSeal(),
 		"max_operations_in_proposal":          lp.MaxOperationsInProposal(),
@@ -211,5 +314,15 @@ func (lp *LocalPolicy) Config() map[string]interface{} {
 		"interval_broadcasting_accept_ballot": lp.IntervalBroadcastingACCEPTBallot(),
 		"timespan_valid_ballot":               lp.TimespanValidBallot(),
 		"network_connection_timeout":          lp.NetworkConnectionTimeout(),
+		"snapshot_interval":                   lp.SnapshotInterval(),
+		"sync_from_snapshot":                  lp.SyncFromSnapshot(),
+		"retention_heights":                   lp.RetentionHeights(),
+		"prune_blockdata":                     lp.PruneBlockdata(),
+	}
+
+	if h := lp.PolicyHeight(); !h.IsEmpty() {
+		m["height"] = h
 	}
+
+	return m
 }
@@ -19,6 +19,10 @@ func (lp *LocalPolicy) MarshalJSON() ([]byte, error) {
 		TS  string              `json:"timespan_valid_ballot"`
 		TC  string              `json:"timeout_process_proposal"`
 		NC  string              `json:"network_connection_timeout"`
+		SI  uint                `json:"snapshot_interval"`
+		SS  bool                `json:"sync_from_snapshot"`
+		RH  uint                `json:"retention_heights"`
+		PB  bool                `json:"prune_blockdata"`
 	}{
 		NID: string(lp.NetworkID()),
 		TH:  lp.ThresholdRatio(),
@@ -31,5 +35,9 @@ func (lp *LocalPolicy) MarshalJSON() ([]byte, error) {
 		IA:  lp.IntervalBroadcastingACCEPTBallot().String(),
 		TS:  lp.TimespanValidBallot().String(),
 		NC:  lp.NetworkConnectionTimeout().String(),
+		SI:  lp.SnapshotInterval(),
+		SS:  lp.SyncFromSnapshot(),
+		RH:  lp.RetentionHeights(),
+		PB:  lp.PruneBlockdata(),
 	})
 }
@@ -0,0 +1,212 @@
+package isaac
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// PolicyStateKey is the state key of the on-chain policy.
+var PolicyStateKey = "network_policy"
+
+var (
+	PolicyV0Type   = hint.Type("policy")
+	PolicyV0Hint   = hint.NewHint(PolicyV0Type, "v0.0.1")
+	PolicyV0Hinter = PolicyV0{BaseHinter: hint.NewBaseHinter(PolicyV0Hint)}
+)
+
+// PolicyV0 is the policy agreed by the suffrage nodes. It is stored as
+// state.State with PolicyStateKey and it is changed only by
+// SetPolicyOperation.
+type PolicyV0 struct {
+	hint.BaseHinter
+	thresholdRatio                   base.ThresholdRatio
+	maxOperationsInSeal              uint
+	maxOperationsInProposal          uint
+	timeoutWaitingProposal           time.Duration
+	intervalBroadcastingINITBallot   time.Duration
+	intervalBroadcastingProposal     time.Duration
+	waitBroadcastingACCEPTBallot     time.Duration
+	intervalBroadcastingACCEPTBallot time.Duration
+	timespanValidBallot              time.Duration
+}
+
+func NewPolicyV0( // revive:disable-line:argument-limit
+	thresholdRatio base.ThresholdRatio,
+	maxOperationsInSeal uint,
+	maxOperationsInProposal uint,
+	timeoutWaitingProposal time.Duration,
+	intervalBroadcastingINITBallot time.Duration,
+	intervalBroadcastingProposal time.Duration,
+	waitBroadcastingACCEPTBallot time.Duration,
+	intervalBroadcastingACCEPTBallot time.Duration,
+	timespanValidBallot time.Duration,
+) PolicyV0 {
+	return PolicyV0{
+		BaseHinter:                       hint.NewBaseHinter(PolicyV0Hint),
+		thresholdRatio:                   thresholdRatio,
+		maxOperationsInSeal:              maxOperationsInSeal,
+		maxOperationsInProposal:          maxOperationsInProposal,
+		timeoutWaitingProposal:           timeoutWaitingProposal,
+		intervalBroadcastingINITBallot:   intervalBroadcastingINITBallot,
+		intervalBroadcastingProposal:     intervalBroadcastingProposal,
+		waitBroadcastingACCEPTBallot:     waitBroadcastingACCEPTBallot,
+		intervalBroadcastingACCEPTBallot: intervalBroadcastingACCEPTBallot,
+		timespanValidBallot:              timespanValidBallot,
+	}
+}
+
+// NewPolicyV0FromLocalPolicy makes PolicyV0 from the current values of
+// LocalPolicy.
+func NewPolicyV0FromLocalPolicy(lp *LocalPolicy) PolicyV0 {
+	return NewPolicyV0(
+		lp.ThresholdRatio(),
+		lp.MaxOperationsInSeal(),
+		lp.MaxOperationsInProposal(),
+		lp.TimeoutWaitingProposal(),
+		lp.IntervalBroadcastingINITBallot(),
+		lp.IntervalBroadcastingProposal(),
+		lp.WaitBroadcastingACCEPTBallot(),
+		lp.IntervalBroadcastingACCEPTBallot(),
+		lp.TimespanValidBallot(),
+	)
+}
+
+func (po PolicyV0) IsValid([]byte) error {
+	if err := po.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if err := po.thresholdRatio.IsValid(nil); err != nil {
+		return err
+	}
+
+	if po.maxOperationsInSeal < 1 {
+		return isvalid.InvalidError.Errorf("zero MaxOperationsInSeal")
+	}
+
+	if po.maxOperationsInProposal < 1 {
+		return isvalid.InvalidError.Errorf("zero MaxOperationsInProposal")
+	}
+
+	for k, d := range map[string]time.Duration{
+		"TimeoutWaitingProposal":           po.timeoutWaitingProposal,
+		"IntervalBroadcastingINITBallot":   po.intervalBroadcastingINITBallot,
+		"IntervalBroadcastingProposal":     po.intervalBroadcastingProposal,
+		"WaitBroadcastingACCEPTBallot":     po.waitBroadcastingACCEPTBallot,
+		"IntervalBroadcastingACCEPTBallot": po.intervalBroadcastingACCEPTBallot,
+		"TimespanValidBallot":              po.timespanValidBallot,
+	} {
+		if d < 1 {
+			return isvalid.InvalidError.Errorf("%s too short; %v", k, d)
+		}
+	}
+
+	return nil
+}
+
+func (po PolicyV0) Bytes() []byte {
+	return util.ConcatBytesSlice(
+		util.Float64ToBytes(po.thresholdRatio.Float64()),
+		util.UintToBytes(po.maxOperationsInSeal),
+		util.UintToBytes(po.maxOperationsInProposal),
+		util.Int64ToBytes(int64(po.timeoutWaitingProposal)),
+		util.Int64ToBytes(int64(po.intervalBroadcastingINITBallot)),
+		util.Int64ToBytes(int64(po.intervalBroadcastingProposal)),
+		util.Int64ToBytes(int64(po.waitBroadcastingACCEPTBallot)),
+		util.Int64ToBytes(int64(po.intervalBroadcastingACCEPTBallot)),
+		util.Int64ToBytes(int64(po.timespanValidBallot)),
+	)
+}
+
+func (po PolicyV0) Hash() valuehash.Hash {
+	return valuehash.NewSHA256(po.Bytes())
+}
+
+func (po PolicyV0) String() string {
+	return jsonenc.ToString(po)
+}
+
+func (po PolicyV0) ThresholdRatio() base.ThresholdRatio {
+	return po.thresholdRatio
+}
+
+func (po PolicyV0) MaxOperationsInSeal() uint {
+	return po.maxOperationsInSeal
+}
+
+func (po PolicyV0) MaxOperationsInProposal() uint {
+	return po.maxOperationsInProposal
+}
+
+func (po PolicyV0) TimeoutWaitingProposal() time.Duration {
+	return po.timeoutWaitingProposal
+}
+
+func (po PolicyV0) IntervalBroadcastingINITBallot() time.Duration {
+	return po.intervalBroadcastingINITBallot
+}
+
+func (po PolicyV0) IntervalBroadcastingProposal() time.Duration {
+	return po.intervalBroadcastingProposal
+}
+
+func (po PolicyV0) WaitBroadcastingACCEPTBallot() time.Duration {
+	return po.waitBroadcastingACCEPTBallot
+}
+
+func (po PolicyV0) IntervalBroadcastingACCEPTBallot() time.Duration {
+	return po.intervalBroadcastingACCEPTBallot
+}
+
+func (po PolicyV0) TimespanValidBallot() time.Duration {
+	return po.timespanValidBallot
+}
+
+// PolicyFromState returns PolicyV0 from the policy state.
+func PolicyFromState(st state.State) (PolicyV0, error) {
+	if st.Key() != PolicyStateKey {
+		return PolicyV0{}, errors.Errorf("not policy state, %q", st.Key())
+	}
+
+	if st.Value() == nil {
+		return PolicyV0{}, errors.Errorf("empty policy state value")
+	}
+
+	po, ok := st.Value().Interface().(PolicyV0)
+	if !ok {
+		return PolicyV0{}, errors.Errorf("not PolicyV0 in policy state, %T", st.Value().Interface())
+	}
+
+	return po, nil
+}
+
+// PolicyFromBlocks returns the last policy and it's height from the blocks.
+func PolicyFromBlocks(blks []block.Block) (PolicyV0, base.Height, bool, error) {
+	for i := len(blks) - 1; i >= 0; i-- {
+		blk := blks[i]
+		sts := blk.States()
+		for j := range sts {
+			if sts[j].Key() != PolicyStateKey {
+				continue
+			}
+
+			po, err := PolicyFromState(sts[j])
+			if err != nil {
+				return PolicyV0{}, base.NilHeight, false, err
+			}
+
+			return po, blk.Height(), true, nil
+		}
+	}
+
+	return PolicyV0{}, base.NilHeight, false, nil
+}
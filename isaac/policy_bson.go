@@ -0,0 +1,49 @@
+package isaac
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (po PolicyV0) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(po.Hint()), bson.M{
+		"threshold":                           po.thresholdRatio,
+		"max_operations_in_seal":              po.maxOperationsInSeal,
+		"max_operations_in_proposal":          po.maxOperationsInProposal,
+		"timeout_waiting_proposal":            po.timeoutWaitingProposal,
+		"interval_broadcasting_init_ballot":   po.intervalBroadcastingINITBallot,
+		"interval_broadcasting_proposal":      po.intervalBroadcastingProposal,
+		"wait_broadcasting_accept_ballot":     po.waitBroadcastingACCEPTBallot,
+		"interval_broadcasting_accept_ballot": po.intervalBroadcastingACCEPTBallot,
+		"timespan_valid_ballot":               po.timespanValidBallot,
+	}))
+}
+
+type PolicyV0UnpackerBSON struct {
+	HT hint.Hint           `bson:"_hint"`
+	TH base.ThresholdRatio `bson:"threshold"`
+	MS uint                `bson:"max_operations_in_seal"`
+	MP uint                `bson:"max_operations_in_proposal"`
+	TP time.Duration       `bson:"timeout_waiting_proposal"`
+	II time.Duration       `bson:"interval_broadcasting_init_ballot"`
+	PR time.Duration       `bson:"interval_broadcasting_proposal"`
+	WB time.Duration       `bson:"wait_broadcasting_accept_ballot"`
+	IA time.Duration       `bson:"interval_broadcasting_accept_ballot"`
+	TS time.Duration       `bson:"timespan_valid_ballot"`
+}
+
+func (po *PolicyV0) UnmarshalBSON(b []byte) error {
+	var upo PolicyV0UnpackerBSON
+	if err := bsonenc.Unmarshal(b, &upo); err != nil {
+		return err
+	}
+
+	*po = NewPolicyV0(upo.TH, upo.MS, upo.MP, upo.TP, upo.II, upo.PR, upo.WB, upo.IA, upo.TS)
+	po.BaseHinter = hint.NewBaseHinter(upo.HT)
+
+	return nil
+}
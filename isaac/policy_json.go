@@ -0,0 +1,49 @@
+package isaac
+
+import (
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+type PolicyV0PackerJSON struct {
+	jsonenc.HintedHead
+	TH base.ThresholdRatio `json:"threshold"`
+	MS uint                `json:"max_operations_in_seal"`
+	MP uint                `json:"max_operations_in_proposal"`
+	TP time.Duration       `json:"timeout_waiting_proposal"`
+	II time.Duration       `json:"interval_broadcasting_init_ballot"`
+	PR time.Duration       `json:"interval_broadcasting_proposal"`
+	WB time.Duration       `json:"wait_broadcasting_accept_ballot"`
+	IA time.Duration       `json:"interval_broadcasting_accept_ballot"`
+	TS time.Duration       `json:"timespan_valid_ballot"`
+}
+
+func (po PolicyV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(PolicyV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(po.Hint()),
+		TH:         po.thresholdRatio,
+		MS:         po.maxOperationsInSeal,
+		MP:         po.maxOperationsInProposal,
+		TP:         po.timeoutWaitingProposal,
+		II:         po.intervalBroadcastingINITBallot,
+		PR:         po.intervalBroadcastingProposal,
+		WB:         po.waitBroadcastingACCEPTBallot,
+		IA:         po.intervalBroadcastingACCEPTBallot,
+		TS:         po.timespanValidBallot,
+	})
+}
+
+func (po *PolicyV0) UnmarshalJSON(b []byte) error {
+	var upo PolicyV0PackerJSON
+	if err := jsonenc.Unmarshal(b, &upo); err != nil {
+		return err
+	}
+
+	*po = NewPolicyV0(upo.TH, upo.MS, upo.MP, upo.TP, upo.II, upo.PR, upo.WB, upo.IA, upo.TS)
+	po.BaseHinter = hint.NewBaseHinter(upo.H)
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package isaac
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/prprocessor"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	SetPolicyFactType           = hint.Type("set-policy-operation-fact")
+	SetPolicyFactHint           = hint.NewHint(SetPolicyFactType, "v0.0.1")
+	SetPolicyFactHinter         = SetPolicyFact{BaseHinter: hint.NewBaseHinter(SetPolicyFactHint)}
+	SetPolicyOperationType      = hint.Type("set-policy-operation")
+	SetPolicyOperationHint      = hint.NewHint(SetPolicyOperationType, "v0.0.1")
+	SetPolicyOperationHinter    = SetPolicyOperation{BaseOperation: operation.EmptyBaseOperation(SetPolicyOperationHint)}
+	NotEnoughSuffrageSignsError = util.NewError("not enough signs of suffrage nodes")
+)
+
+type SetPolicyFact struct {
+	hint.BaseHinter
+	h      valuehash.Hash
+	token  []byte
+	policy PolicyV0
+}
+
+func NewSetPolicyFact(token []byte, po PolicyV0) SetPolicyFact {
+	fact := SetPolicyFact{
+		BaseHinter: hint.NewBaseHinter(SetPolicyFactHint),
+		token:      token,
+		policy:     po,
+	}
+	fact.h = valuehash.NewSHA256(fact.Bytes())
+
+	return fact
+}
+
+func (fact SetPolicyFact) IsValid(networkID []byte) error {
+	if err := operation.IsValidOperationFact(fact, networkID); err != nil {
+		return err
+	}
+
+	if err := fact.policy.IsValid(nil); err != nil {
+		return err
+	}
+
+	if !fact.h.Equal(valuehash.NewSHA256(fact.Bytes())) {
+		return isvalid.InvalidError.Errorf("wrong fact hash")
+	}
+
+	return nil
+}
+
+func (fact SetPolicyFact) Hash() valuehash.Hash {
+	return fact.h
+}
+
+func (fact SetPolicyFact) Bytes() []byte {
+	return util.ConcatBytesSlice(fact.token, fact.policy.Bytes())
+}
+
+func (fact SetPolicyFact) Token() []byte {
+	return fact.token
+}
+
+func (fact SetPolicyFact) Policy() PolicyV0 {
+	return fact.policy
+}
+
+// SetPolicyOperation updates the on-chain policy. The operation should be
+// signed by the suffrage nodes over the threshold; the number of signs is
+// checked by SetPolicyOperationProcessor.
+type SetPolicyOperation struct {
+	operation.BaseOperation
+}
+
+func NewSetPolicyOperation(fact SetPolicyFact, fs []base.FactSign) (SetPolicyOperation, error) {
+	bo, err := operation.NewBaseOperationFromFact(SetPolicyOperationHint, fact, fs)
+	if err != nil {
+		return SetPolicyOperation{}, err
+	}
+
+	return SetPolicyOperation{BaseOperation: bo}, nil
+}
+
+// NewSetPolicyOperationFromSigner creates new SetPolicyOperation signed by
+// one node.
+func NewSetPolicyOperationFromSigner(
+	signer key.Privatekey,
+	token []byte,
+	po PolicyV0,
+	networkID base.NetworkID,
+) (SetPolicyOperation, error) {
+	fact := NewSetPolicyFact(token, po)
+
+	sig, err := base.NewFactSignature(signer, fact, networkID)
+	if err != nil {
+		return SetPolicyOperation{}, err
+	}
+
+	return NewSetPolicyOperation(fact, []base.FactSign{base.NewBaseFactSign(signer.Publickey(), sig)})
+}
+
+func (SetPolicyOperation) Hint() hint.Hint {
+	return SetPolicyOperationHint
+}
+
+func (op SetPolicyOperation) IsValid(networkID []byte) error {
+	if _, ok := op.Fact().(SetPolicyFact); !ok {
+		return isvalid.InvalidError.Errorf("not SetPolicyFact, %T", op.Fact())
+	}
+
+	return operation.IsValidOperation(op, networkID)
+}
+
+func (op SetPolicyOperation) AddFactSigns(fs ...base.FactSign) (base.FactSignUpdater, error) {
+	i, err := op.BaseOperation.AddFactSigns(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.BaseOperation = i.(operation.BaseOperation)
+
+	return op, nil
+}
+
+func (op SetPolicyOperation) Process(
+	getState func(key string) (state.State, bool, error),
+	setState func(valuehash.Hash, ...state.State) error,
+) error {
+	fact := op.Fact().(SetPolicyFact)
+
+	value, err := state.NewHintedValue(fact.Policy())
+	if err != nil {
+		return err
+	}
+
+	st, _, err := getState(PolicyStateKey)
+	if err != nil {
+		return err
+	}
+
+	nst, err := st.SetValue(value)
+	if err != nil {
+		return err
+	}
+
+	return setState(fact.Hash(), nst)
+}
+
+// SetPolicyOperationProcessor checks whether SetPolicyOperation is signed by
+// enough suffrage nodes except in genesis block. Only one SetPolicyOperation is
+// allowed in one block.
+type SetPolicyOperationProcessor struct {
+	sync.Mutex
+	nodepool  *network.Nodepool
+	suffrage  base.Suffrage
+	policy    *LocalPolicy
+	pool      *storage.Statepool
+	processed bool
+}
+
+func NewSetPolicyOperationProcessor(
+	nodepool *network.Nodepool,
+	suffrage base.Suffrage,
+	policy *LocalPolicy,
+) *SetPolicyOperationProcessor {
+	return &SetPolicyOperationProcessor{
+		nodepool: nodepool,
+		suffrage: suffrage,
+		policy:   policy,
+	}
+}
+
+func (opp *SetPolicyOperationProcessor) New(pool *storage.Statepool) prprocessor.OperationProcessor {
+	return &SetPolicyOperationProcessor{
+		nodepool: opp.nodepool,
+		suffrage: opp.suffrage,
+		policy:   opp.policy,
+		pool:     pool,
+	}
+}
+
+func (opp *SetPolicyOperationProcessor) PreProcess(op state.Processor) (state.Processor, error) {
+	i, ok := op.(SetPolicyOperation)
+	if !ok {
+		return nil, errors.Errorf("not SetPolicyOperation, %T", op)
+	}
+
+	// NOTE genesis block is generated by one node and agreed by the other
+	// nodes out of chain, so the signs of suffrage nodes are not checked.
+	if opp.pool.Height() > base.GenesisHeight {
+		if err := checkSuffrageSigns(opp.nodepool, opp.suffrage, opp.policy.ThresholdRatio(), i.Signs()); err != nil {
+			return nil, err
+		}
+	}
+
+	opp.Lock()
+	defer opp.Unlock()
+
+	if opp.processed {
+		return nil, operation.NewBaseReasonError("policy already updated in this block")
+	}
+
+	opp.processed = true
+
+	return op, nil
+}
+
+func (opp *SetPolicyOperationProcessor) Process(op state.Processor) error {
+	return op.Process(opp.pool.Get, opp.pool.Set)
+}
+
+func (*SetPolicyOperationProcessor) Close() error {
+	return nil
+}
+
+func (*SetPolicyOperationProcessor) Cancel() error {
+	return nil
+}
+
+// checkSuffrageSigns checks whether the signs are from the suffrage nodes over
+// threshold.
+func checkSuffrageSigns(
+	nodepool *network.Nodepool,
+	suffrage base.Suffrage,
+	ratio base.ThresholdRatio,
+	fs []base.FactSign,
+) error {
+	nodes := suffrage.Nodes()
+
+	threshold, err := base.NewThreshold(uint(len(nodes)), ratio)
+	if err != nil {
+		return err
+	}
+
+	signers := map[string]struct{}{}
+	for i := range fs {
+		signers[fs[i].Signer().String()] = struct{}{}
+	}
+
+	var signed uint
+	for i := range nodes {
+		n, _, found := nodepool.Node(nodes[i])
+		if !found {
+			continue
+		}
+
+		if _, found := signers[n.Publickey().String()]; found {
+			signed++
+		}
+	}
+
+	if signed < threshold.Threshold {
+		return operation.NewBaseReasonErrorFromError(
+			NotEnoughSuffrageSignsError.Errorf("%d < %d", signed, threshold.Threshold))
+	}
+
+	return nil
+}
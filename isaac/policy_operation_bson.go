@@ -0,0 +1,56 @@
+package isaac
+
+import (
+	"github.com/spikeekips/mitum/base/operation"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (fact SetPolicyFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(fact.Hint()),
+		bson.M{
+			"hash":   fact.h,
+			"token":  fact.token,
+			"policy": fact.policy,
+		},
+	))
+}
+
+type SetPolicyFactUnpackerBSON struct {
+	HT hint.Hint       `bson:"_hint"`
+	H  valuehash.Bytes `bson:"hash"`
+	TK []byte          `bson:"token"`
+	PO PolicyV0        `bson:"policy"`
+}
+
+func (fact *SetPolicyFact) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ufact SetPolicyFactUnpackerBSON
+	if err := enc.Unmarshal(b, &ufact); err != nil {
+		return err
+	}
+
+	fact.BaseHinter = hint.NewBaseHinter(ufact.HT)
+	fact.h = ufact.H
+	fact.token = ufact.TK
+	fact.policy = ufact.PO
+
+	return nil
+}
+
+func (op SetPolicyOperation) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(op.BaseOperation)
+}
+
+func (op *SetPolicyOperation) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo operation.BaseOperation
+	if err := ubo.UnpackBSON(b, enc); err != nil {
+		return err
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
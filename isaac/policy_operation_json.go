@@ -0,0 +1,60 @@
+package isaac
+
+import (
+	"github.com/spikeekips/mitum/base/operation"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type SetPolicyFactPackerJSON struct {
+	jsonenc.HintedHead
+	HS valuehash.Hash `json:"hash"`
+	TK []byte         `json:"token"`
+	PO PolicyV0       `json:"policy"`
+}
+
+func (fact SetPolicyFact) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(SetPolicyFactPackerJSON{
+		HintedHead: jsonenc.NewHintedHead(fact.Hint()),
+		HS:         fact.h,
+		TK:         fact.token,
+		PO:         fact.policy,
+	})
+}
+
+type SetPolicyFactUnpackerJSON struct {
+	jsonenc.HintedHead
+	HS valuehash.Bytes `json:"hash"`
+	TK []byte          `json:"token"`
+	PO PolicyV0        `json:"policy"`
+}
+
+func (fact *SetPolicyFact) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ufact SetPolicyFactUnpackerJSON
+	if err := enc.Unmarshal(b, &ufact); err != nil {
+		return err
+	}
+
+	fact.BaseHinter = hint.NewBaseHinter(ufact.H)
+	fact.h = ufact.HS
+	fact.token = ufact.TK
+	fact.policy = ufact.PO
+
+	return nil
+}
+
+func (op SetPolicyOperation) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(op.BaseOperation)
+}
+
+func (op *SetPolicyOperation) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ubo operation.BaseOperation
+	if err := ubo.UnpackJSON(b, enc); err != nil {
+		return err
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
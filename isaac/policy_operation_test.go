@@ -0,0 +1,224 @@
+package isaac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSetPolicyOperation struct {
+	BaseTest
+	local  *Local
+	remote *Local
+	other  *Local
+}
+
+func (t *testSetPolicyOperation) SetupSuite() {
+	t.BaseTest.SetupSuite()
+
+	_ = t.Encs.TestAddHinter(PolicyV0Hinter)
+	_ = t.Encs.TestAddHinter(SetPolicyFactHinter)
+	_ = t.Encs.TestAddHinter(SetPolicyOperationHinter)
+}
+
+func (t *testSetPolicyOperation) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	ls := t.Locals(3)
+	t.local, t.remote, t.other = ls[0], ls[1], ls[2]
+}
+
+func (t *testSetPolicyOperation) newPolicy() PolicyV0 {
+	return NewPolicyV0(
+		base.ThresholdRatio(67),
+		33,
+		44,
+		time.Second*3,
+		time.Second*4,
+		time.Second*5,
+		time.Second*6,
+		time.Second*7,
+		time.Minute*2,
+	)
+}
+
+func (t *testSetPolicyOperation) newOperation(signers ...*Local) SetPolicyOperation {
+	op, err := NewSetPolicyOperationFromSigner(
+		signers[0].Node().Privatekey(),
+		[]byte("this-is-token"),
+		t.newPolicy(),
+		signers[0].Policy().NetworkID(),
+	)
+	t.NoError(err)
+
+	for _, l := range signers[1:] {
+		sig, err := base.NewFactSignature(l.Node().Privatekey(), op.Fact(), l.Policy().NetworkID())
+		t.NoError(err)
+
+		i, err := op.AddFactSigns(base.NewBaseFactSign(l.Node().Privatekey().Publickey(), sig))
+		t.NoError(err)
+
+		op = i.(SetPolicyOperation)
+	}
+
+	t.NoError(op.IsValid(t.local.Policy().NetworkID()))
+
+	return op
+}
+
+func (t *testSetPolicyOperation) newProcessor() *SetPolicyOperationProcessor {
+	pool, err := storage.NewStatepool(t.local.Database())
+	t.NoError(err)
+
+	opr := NewSetPolicyOperationProcessor(
+		t.local.Nodes(),
+		t.Suffrage(t.local, t.local, t.remote, t.other),
+		t.local.Policy(),
+	)
+
+	return opr.New(pool).(*SetPolicyOperationProcessor)
+}
+
+func (t *testSetPolicyOperation) TestNew() {
+	op := t.newOperation(t.local, t.remote)
+
+	t.Implements((*operation.Operation)(nil), op)
+	t.Equal(2, len(op.Signs()))
+	t.True(op.Hint().Equal(SetPolicyOperationHint))
+}
+
+func (t *testSetPolicyOperation) TestEncode() {
+	op := t.newOperation(t.local, t.remote)
+
+	for _, enc := range []encoder.Encoder{t.JSONEnc, t.BSONEnc} {
+		b, err := enc.Marshal(op)
+		t.NoError(err)
+
+		hinter, err := enc.Decode(b)
+		t.NoError(err)
+
+		uop, ok := hinter.(SetPolicyOperation)
+		t.True(ok)
+
+		t.NoError(uop.IsValid(t.local.Policy().NetworkID()))
+		t.True(op.Hash().Equal(uop.Hash()))
+		t.True(op.Fact().Hash().Equal(uop.Fact().Hash()))
+		t.Equal(len(op.Signs()), len(uop.Signs()))
+		t.True(op.Fact().(SetPolicyFact).Policy().Hash().Equal(uop.Fact().(SetPolicyFact).Policy().Hash()))
+	}
+}
+
+func (t *testSetPolicyOperation) TestNotEnoughSigns() {
+	// NOTE default threshold ratio is 100
+	op := t.newOperation(t.local, t.remote)
+
+	_, err := t.newProcessor().PreProcess(op)
+	t.Error(err)
+	t.True(errors.Is(err, NotEnoughSuffrageSignsError))
+
+	var oe operation.ReasonError
+	t.True(errors.As(err, &oe))
+}
+
+func (t *testSetPolicyOperation) TestUnknownSigner() {
+	unknown := t.EmptyLocal()
+
+	op := t.newOperation(t.local, t.remote, unknown)
+
+	_, err := t.newProcessor().PreProcess(op)
+	t.True(errors.Is(err, NotEnoughSuffrageSignsError))
+}
+
+func (t *testSetPolicyOperation) TestProcess() {
+	op := t.newOperation(t.local, t.remote, t.other)
+
+	opr := t.newProcessor()
+
+	_, err := opr.PreProcess(op)
+	t.NoError(err)
+	t.NoError(opr.Process(op))
+
+	us := opr.pool.Updates()
+	t.Equal(1, len(us))
+	t.Equal(PolicyStateKey, us[0].Key())
+
+	po, err := PolicyFromState(us[0].GetState())
+	t.NoError(err)
+	t.True(t.newPolicy().Hash().Equal(po.Hash()))
+
+	// NOTE only one SetPolicyOperation in one block
+	_, err = opr.PreProcess(t.newOperation(t.local, t.remote, t.other))
+	t.Error(err)
+	t.Contains(err.Error(), "already updated")
+}
+
+func (t *testSetPolicyOperation) TestGenesis() {
+	t.local.Database().Clean()
+
+	op := t.newOperation(t.local)
+
+	gg, err := NewGenesisBlockV0Generator(t.local.Node(), t.local.Database(), t.local.Blockdata(), t.local.Policy(), []operation.Operation{op})
+	t.NoError(err)
+
+	blk, err := gg.Generate()
+	t.NoError(err)
+
+	po, height, found, err := PolicyFromBlocks([]block.Block{blk})
+	t.NoError(err)
+	t.True(found)
+	t.Equal(blk.Height(), height)
+	t.True(t.newPolicy().Hash().Equal(po.Hash()))
+
+	st, found, err := t.local.Database().State(PolicyStateKey)
+	t.NoError(err)
+	t.True(found)
+
+	upo, err := PolicyFromState(st)
+	t.NoError(err)
+	t.True(po.Hash().Equal(upo.Hash()))
+}
+
+func (t *testSetPolicyOperation) TestGenesisMultipleNodes() {
+	t.local.Database().Clean()
+
+	// NOTE genesis operation is signed only by the local node
+	op := t.newOperation(t.local)
+
+	opr := t.newProcessor()
+	t.Equal(base.GenesisHeight, opr.pool.Height())
+	t.Equal(3, len(opr.suffrage.Nodes()))
+
+	_, err := opr.PreProcess(op)
+	t.NoError(err)
+	t.NoError(opr.Process(op))
+
+	us := opr.pool.Updates()
+	t.Equal(1, len(us))
+	t.Equal(PolicyStateKey, us[0].Key())
+
+	gg, err := NewGenesisBlockV0Generator(t.local.Node(), t.local.Database(), t.local.Blockdata(), t.local.Policy(), []operation.Operation{op})
+	t.NoError(err)
+
+	blk, err := gg.Generate()
+	t.NoError(err)
+
+	po, _, found, err := PolicyFromBlocks([]block.Block{blk})
+	t.NoError(err)
+	t.True(found)
+	t.True(t.newPolicy().Hash().Equal(po.Hash()))
+
+	// NOTE after genesis, signs of suffrage nodes are checked
+	_, err = t.newProcessor().PreProcess(t.newOperation(t.local))
+	t.True(errors.Is(err, NotEnoughSuffrageSignsError))
+}
+
+func TestSetPolicyOperation(t *testing.T) {
+	suite.Run(t, new(testSetPolicyOperation))
+}
@@ -0,0 +1,156 @@
+package isaac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPolicyV0 struct {
+	suite.Suite
+	encs    *encoder.Encoders
+	jsonenc *jsonenc.Encoder
+	bsonenc *bsonenc.Encoder
+}
+
+func (t *testPolicyV0) SetupSuite() {
+	t.jsonenc = jsonenc.NewEncoder()
+	t.bsonenc = bsonenc.NewEncoder()
+
+	t.encs = encoder.NewEncoders()
+	t.NoError(t.encs.AddEncoder(t.jsonenc))
+	t.NoError(t.encs.AddEncoder(t.bsonenc))
+
+	t.NoError(t.encs.TestAddHinter(PolicyV0Hinter))
+	t.NoError(t.encs.TestAddHinter(state.HintedValueHinter))
+}
+
+func (t *testPolicyV0) newPolicy() PolicyV0 {
+	return NewPolicyV0(
+		base.ThresholdRatio(67),
+		33,
+		44,
+		time.Second*3,
+		time.Second*4,
+		time.Second*5,
+		time.Second*6,
+		time.Second*7,
+		time.Minute*2,
+	)
+}
+
+func (t *testPolicyV0) comparePolicy(a, b PolicyV0) {
+	t.True(a.Hint().Equal(b.Hint()))
+	t.True(a.Hash().Equal(b.Hash()))
+	t.Equal(a.ThresholdRatio(), b.ThresholdRatio())
+	t.Equal(a.MaxOperationsInSeal(), b.MaxOperationsInSeal())
+	t.Equal(a.MaxOperationsInProposal(), b.MaxOperationsInProposal())
+	t.Equal(a.TimeoutWaitingProposal(), b.TimeoutWaitingProposal())
+	t.Equal(a.IntervalBroadcastingINITBallot(), b.IntervalBroadcastingINITBallot())
+	t.Equal(a.IntervalBroadcastingProposal(), b.IntervalBroadcastingProposal())
+	t.Equal(a.WaitBroadcastingACCEPTBallot(), b.WaitBroadcastingACCEPTBallot())
+	t.Equal(a.IntervalBroadcastingACCEPTBallot(), b.IntervalBroadcastingACCEPTBallot())
+	t.Equal(a.TimespanValidBallot(), b.TimespanValidBallot())
+}
+
+func (t *testPolicyV0) TestIsValid() {
+	t.NoError(t.newPolicy().IsValid(nil))
+
+	po := t.newPolicy()
+	po.maxOperationsInProposal = 0
+	err := po.IsValid(nil)
+	t.Error(err)
+	t.Contains(err.Error(), "zero MaxOperationsInProposal")
+
+	po = t.newPolicy()
+	po.timespanValidBallot = 0
+	err = po.IsValid(nil)
+	t.Error(err)
+	t.Contains(err.Error(), "TimespanValidBallot too short")
+}
+
+func (t *testPolicyV0) TestFromLocalPolicy() {
+	lp := NewLocalPolicy(nil)
+	po := NewPolicyV0FromLocalPolicy(lp)
+	t.NoError(po.IsValid(nil))
+
+	t.Equal(lp.ThresholdRatio(), po.ThresholdRatio())
+	t.Equal(lp.MaxOperationsInSeal(), po.MaxOperationsInSeal())
+	t.Equal(lp.TimespanValidBallot(), po.TimespanValidBallot())
+}
+
+func (t *testPolicyV0) TestEncode() {
+	po := t.newPolicy()
+
+	for _, enc := range []encoder.Encoder{t.jsonenc, t.bsonenc} {
+		b, err := enc.Marshal(po)
+		t.NoError(err)
+
+		hinter, err := enc.Decode(b)
+		t.NoError(err)
+
+		upo, ok := hinter.(PolicyV0)
+		t.True(ok)
+
+		t.comparePolicy(po, upo)
+	}
+}
+
+func (t *testPolicyV0) TestFromState() {
+	po := t.newPolicy()
+
+	v, err := state.NewHintedValue(po)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(PolicyStateKey, v, base.Height(33))
+	t.NoError(err)
+
+	upo, err := PolicyFromState(st)
+	t.NoError(err)
+	t.comparePolicy(po, upo)
+
+	other, err := state.NewStateV0("showme", v, base.Height(33))
+	t.NoError(err)
+
+	_, err = PolicyFromState(other)
+	t.Error(err)
+	t.Contains(err.Error(), "not policy state")
+}
+
+func (t *testPolicyV0) TestLocalPolicySetPolicy() {
+	lp := NewLocalPolicy(nil)
+	t.Equal(base.NilHeight, lp.PolicyHeight())
+
+	_, found := lp.Config()["height"]
+	t.False(found)
+
+	po := t.newPolicy()
+	t.NoError(lp.SetPolicy(po, base.Height(33)))
+
+	t.Equal(base.Height(33), lp.PolicyHeight())
+	t.comparePolicy(po, NewPolicyV0FromLocalPolicy(lp))
+	t.Equal(DefaultPolicyNetworkConnectionTimeout, lp.NetworkConnectionTimeout())
+	t.Equal(base.Height(33), lp.Config()["height"])
+
+	// NOTE older policy is ignored
+	old := NewPolicyV0FromLocalPolicy(NewLocalPolicy(nil))
+	t.NoError(lp.SetPolicy(old, base.Height(32)))
+	t.Equal(base.Height(33), lp.PolicyHeight())
+	t.comparePolicy(po, NewPolicyV0FromLocalPolicy(lp))
+
+	// NOTE invalid policy
+	invalid := t.newPolicy()
+	invalid.maxOperationsInSeal = 0
+	t.Error(lp.SetPolicy(invalid, base.Height(34)))
+	t.Equal(base.Height(33), lp.PolicyHeight())
+}
+
+func TestPolicyV0(t *testing.T) {
+	suite.Run(t, new(testPolicyV0))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/isvalid"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -150,5 +151,5 @@ func CheckNodeIsProposer(n base.Address, suffrage base.Suffrage, height base.Hei
 		return nil
 	}
 
-	return errors.Errorf("proposal has wrong proposer")
+	return isvalid.InvalidError.Errorf("proposal has wrong proposer")
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 	"github.com/spikeekips/mitum/util/tree"
 )
 
@@ -34,6 +35,7 @@ type DefaultProcessor struct {
 	sfs              base.SignedBallotFact
 	initVoteproof    base.Voteproof
 	state            prprocessor.State
+	stateChanged     time.Time
 	blk              block.BlockUpdater
 	suffrageInfo     block.SuffrageInfoV0
 	operations       []operation.Operation
@@ -110,6 +112,7 @@ func NewDefaultProcessor(
 		oprHintset:    oprHintset,
 		sfs:           sfs,
 		state:         prprocessor.BeforePrepared,
+		stateChanged:  time.Now(),
 		initVoteproof: initVoteproof,
 		preSaveHook:   nil,
 		postSaveHook:  nil,
@@ -144,7 +147,10 @@ func (pp *DefaultProcessor) setState(s prprocessor.State) {
 		return
 	}
 
+	metrics.ProposalProcessorState.WithLabelValues(pp.state.String()).Observe(time.Since(pp.stateChanged).Seconds())
+
 	pp.state = s
+	pp.stateChanged = time.Now()
 }
 
 func (pp *DefaultProcessor) Fact() base.ProposalFact {
@@ -256,3 +262,27 @@ func (pp *DefaultProcessor) getSuffrageInfo() (block.SuffrageInfoV0, error) {
 
 	return block.NewSuffrageInfoV0(pp.Fact().Proposer(), ns), nil
 }
+
+// updateSuffrageInfo replaces the suffrage nodes of SuffrageInfo with the new
+// suffrage nodes, which are updated by the operations of this block.
+func (pp *DefaultProcessor) updateSuffrageInfo() error {
+	for i := range pp.states {
+		st := pp.states[i]
+		if st.Key() != SuffrageNodesStateKey {
+			continue
+		}
+
+		sn, err := SuffrageNodesFromState(st)
+		if err != nil {
+			return err
+		}
+
+		pp.suffrageInfo = block.NewSuffrageInfoV0(pp.Fact().Proposer(), sn.Nodes())
+
+		pp.Log().Debug().Interface("suffrage_nodes", sn.Addresses()).Msg("suffrage info updated by new suffrage nodes")
+
+		return nil
+	}
+
+	return nil
+}
@@ -236,6 +236,12 @@ func (pp *DefaultProcessor) prepareBlock(context.Context) error {
 		stsHash = valuehash.NewBytes(pp.statesTree.Root())
 	}
 
+	if err := pp.updateSuffrageInfo(); err != nil {
+		return err
+	} else if err := pp.blockdataSession.SetSuffrageInfo(pp.suffrageInfo); err != nil {
+		return err
+	}
+
 	var blk block.BlockUpdater
 	if b, err := block.NewBlockV0(
 		pp.suffrageInfo, pp.Fact().Height(), pp.Fact().Round(), pp.Fact().Hash(), pp.baseManifest.Hash(),
@@ -410,10 +416,6 @@ func (pp *DefaultProcessor) prepareBlockdataSession(context.Context) error {
 		}
 	}
 
-	if err := pp.blockdataSession.SetSuffrageInfo(pp.suffrageInfo); err != nil {
-		return err
-	}
-
 	if err := pp.blockdataSession.SetProposal(pp.sfs); err != nil {
 		return err
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/prprocessor"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
 	channetwork "github.com/spikeekips/mitum/network/gochan"
 	"github.com/spikeekips/mitum/storage/blockdata/localfs"
 	"github.com/spikeekips/mitum/util"
@@ -709,6 +710,82 @@ func (t *testDefaultProposalProcessor) TestCustomOperationProcessor() {
 	}
 }
 
+func (t *testDefaultProposalProcessor) TestSuffrageInfoByJoinSuffrageOperation() {
+	_ = t.Encs.TestAddHinter(SuffrageNodesV0Hinter)
+	_ = t.Encs.TestAddHinter(JoinSuffrageFactHinter)
+	_ = t.Encs.TestAddHinter(JoinSuffrageOperationHinter)
+	_ = t.Encs.TestAddHinter(network.HTTPConnInfoHinter)
+
+	suffrage := t.Suffrage(t.local)
+
+	ci, err := network.NewHTTPConnInfoFromString("https://remote:54321", true)
+	t.NoError(err)
+
+	op, err := NewJoinSuffrageOperationFromSigner(
+		t.remote.Node().Privatekey(), []byte("this-is-token"), t.remote.Node(), ci, TestNetworkID)
+	t.NoError(err)
+
+	sig, err := base.NewFactSignature(t.local.Node().Privatekey(), op.Fact(), TestNetworkID)
+	t.NoError(err)
+
+	i, err := op.AddFactSigns(base.NewBaseFactSign(t.local.Node().Publickey(), sig))
+	t.NoError(err)
+	op = i.(JoinSuffrageOperation)
+
+	sl, err := operation.NewBaseSeal(t.local.Node().Privatekey(), []operation.Operation{op}, TestNetworkID)
+	t.NoError(err)
+	t.NoError(t.local.Database().NewOperationSeals([]operation.Seal{sl}))
+
+	pm := NewProposalMaker(t.local.Node(), t.local.Database(), t.local.Policy())
+
+	ib := t.NewINITBallot(t.local, base.Round(0), nil)
+	ivp, err := t.NewVoteproof(base.StageINIT, ib.Fact(), t.local, t.remote)
+	t.NoError(err)
+	pr, err := pm.Proposal(ivp.Height(), ivp.Round(), ivp)
+	t.NoError(err)
+
+	hm := hint.NewHintmap()
+	opr := NewSuffrageOperationProcessor(t.local.Nodes(), suffrage, t.local.Policy())
+	t.NoError(hm.Add(JoinSuffrageOperationHinter, opr))
+
+	pps := prprocessor.NewProcessors(NewDefaultProcessorNewFunc(
+		t.local.Database(),
+		t.local.Blockdata(),
+		t.local.Nodes(),
+		suffrage,
+		hm,
+	), nil)
+
+	t.NoError(pps.Initialize())
+	t.NoError(pps.Start())
+	defer pps.Stop()
+
+	pch := pps.NewProposal(context.Background(), pr.SignedFact(), ivp)
+
+	select {
+	case <-time.After(time.Second * 3):
+		t.NoError(errors.Errorf("waiting result, but expired"))
+
+		return
+	case result := <-pch:
+		t.NoError(result.Err)
+		t.Equal(prprocessor.Prepared, pps.Current().State())
+
+		nodes := result.Block.ConsensusInfo().SuffrageInfo().Nodes()
+		t.Equal(2, len(nodes))
+
+		var found bool
+		for i := range nodes {
+			if nodes[i].Address().Equal(t.remote.Node().Address()) {
+				found = true
+
+				break
+			}
+		}
+		t.True(found)
+	}
+}
+
 func (t *testDefaultProposalProcessor) TestNotProcessedOperations() {
 	var sls []operation.Seal
 	var exclude valuehash.Hash
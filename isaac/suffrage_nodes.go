@@ -0,0 +1,293 @@
+package isaac
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// SuffrageNodesStateKey is the state key of the on-chain suffrage nodes.
+var SuffrageNodesStateKey = "network_suffrage_nodes"
+
+var (
+	SuffrageNodesV0Type   = hint.Type("suffrage-nodes")
+	SuffrageNodesV0Hint   = hint.NewHint(SuffrageNodesV0Type, "v0.0.1")
+	SuffrageNodesV0Hinter = SuffrageNodesV0{BaseHinter: hint.NewBaseHinter(SuffrageNodesV0Hint)}
+)
+
+// SuffrageNodesUpdater updates the suffrage nodes. The new nodes are used from
+// the given height. SetHeight sets the current height, which is being agreed,
+// and the suffrage nodes of current height are used for IsInside and Nodes.
+type SuffrageNodesUpdater interface {
+	SetNodes(base.Height, []base.Address) error
+	SetHeight(base.Height)
+}
+
+// SuffrageNodesV0 is the suffrage members stored as state.State with
+// SuffrageNodesStateKey. It is changed only by JoinSuffrageOperation and
+// LeaveSuffrageOperation. The nodes joined by JoinSuffrageOperation keep their
+// ConnInfo; the nodes from config does not have ConnInfo.
+type SuffrageNodesV0 struct {
+	hint.BaseHinter
+	nodes     []base.Node
+	connInfos map[string]network.ConnInfo
+}
+
+func NewSuffrageNodesV0(nodes []base.Node) SuffrageNodesV0 {
+	return newSuffrageNodesV0(nodes, nil)
+}
+
+func newSuffrageNodesV0(nodes []base.Node, connInfos map[string]network.ConnInfo) SuffrageNodesV0 {
+	ns := make([]base.Node, len(nodes))
+	copy(ns, nodes)
+
+	sort.Slice(ns, func(i, j int) bool {
+		return strings.Compare(ns[i].Address().String(), ns[j].Address().String()) < 0
+	})
+
+	cs := map[string]network.ConnInfo{}
+	for i := range ns {
+		k := ns[i].Address().String()
+		if ci, found := connInfos[k]; found && ci != nil {
+			cs[k] = ci
+		}
+	}
+
+	return SuffrageNodesV0{
+		BaseHinter: hint.NewBaseHinter(SuffrageNodesV0Hint),
+		nodes:      ns,
+		connInfos:  cs,
+	}
+}
+
+func (sn SuffrageNodesV0) IsValid([]byte) error {
+	if err := sn.BaseHinter.IsValid(nil); err != nil {
+		return err
+	}
+
+	if len(sn.nodes) < 1 {
+		return isvalid.InvalidError.Errorf("empty suffrage nodes")
+	}
+
+	founds := map[string]struct{}{}
+	for i := range sn.nodes {
+		n := sn.nodes[i]
+		if err := n.IsValid(nil); err != nil {
+			return err
+		}
+
+		if _, found := founds[n.Address().String()]; found {
+			return isvalid.InvalidError.Errorf("duplicated suffrage node found, %q", n.Address())
+		}
+
+		founds[n.Address().String()] = struct{}{}
+	}
+
+	for k := range sn.connInfos {
+		if _, found := founds[k]; !found {
+			return isvalid.InvalidError.Errorf("conninfo of unknown node found, %q", k)
+		}
+
+		if err := sn.connInfos[k].IsValid(nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sn SuffrageNodesV0) Bytes() []byte {
+	bs := make([][]byte, len(sn.nodes)*3)
+	for i := range sn.nodes {
+		bs[i*3] = sn.nodes[i].Address().Bytes()
+		bs[i*3+1] = sn.nodes[i].Publickey().Bytes()
+
+		if ci, found := sn.connInfos[sn.nodes[i].Address().String()]; found {
+			bs[i*3+2] = ci.Bytes()
+		}
+	}
+
+	return util.ConcatBytesSlice(bs...)
+}
+
+func (sn SuffrageNodesV0) Hash() valuehash.Hash {
+	return valuehash.NewSHA256(sn.Bytes())
+}
+
+func (sn SuffrageNodesV0) Nodes() []base.Node {
+	return sn.nodes
+}
+
+func (sn SuffrageNodesV0) Addresses() []base.Address {
+	as := make([]base.Address, len(sn.nodes))
+	for i := range sn.nodes {
+		as[i] = sn.nodes[i].Address()
+	}
+
+	return as
+}
+
+func (sn SuffrageNodesV0) Node(address base.Address) (base.Node, bool) {
+	for i := range sn.nodes {
+		if sn.nodes[i].Address().Equal(address) {
+			return sn.nodes[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// ConnInfo returns the ConnInfo of the node, which joined by
+// JoinSuffrageOperation.
+func (sn SuffrageNodesV0) ConnInfo(address base.Address) (network.ConnInfo, bool) {
+	ci, found := sn.connInfos[address.String()]
+
+	return ci, found
+}
+
+// Join returns new SuffrageNodesV0 with the new node.
+func (sn SuffrageNodesV0) Join(n base.Node, connInfo network.ConnInfo) (SuffrageNodesV0, error) {
+	if _, found := sn.Node(n.Address()); found {
+		return SuffrageNodesV0{}, util.FoundError.Errorf("already in suffrage, %q", n.Address())
+	}
+
+	ns := make([]base.Node, len(sn.nodes)+1)
+	copy(ns, sn.nodes)
+	ns[len(sn.nodes)] = n
+
+	cs := map[string]network.ConnInfo{}
+	for k := range sn.connInfos {
+		cs[k] = sn.connInfos[k]
+	}
+
+	if connInfo != nil {
+		cs[n.Address().String()] = connInfo
+	}
+
+	return newSuffrageNodesV0(ns, cs), nil
+}
+
+// Leave returns new SuffrageNodesV0 without the node. The last node can not
+// leave.
+func (sn SuffrageNodesV0) Leave(address base.Address) (SuffrageNodesV0, error) {
+	if _, found := sn.Node(address); !found {
+		return SuffrageNodesV0{}, util.NotFoundError.Errorf("not in suffrage, %q", address)
+	}
+
+	if len(sn.nodes) < 2 {
+		return SuffrageNodesV0{}, errors.Errorf("last suffrage node can not leave")
+	}
+
+	var ns []base.Node // nolint:prealloc
+	for i := range sn.nodes {
+		if sn.nodes[i].Address().Equal(address) {
+			continue
+		}
+
+		ns = append(ns, sn.nodes[i])
+	}
+
+	return newSuffrageNodesV0(ns, sn.connInfos), nil
+}
+
+// SuffrageNodesFromState returns SuffrageNodesV0 from the suffrage nodes state.
+func SuffrageNodesFromState(st state.State) (SuffrageNodesV0, error) {
+	if st.Key() != SuffrageNodesStateKey {
+		return SuffrageNodesV0{}, errors.Errorf("not suffrage nodes state, %q", st.Key())
+	}
+
+	if st.Value() == nil {
+		return SuffrageNodesV0{}, errors.Errorf("empty suffrage nodes state value")
+	}
+
+	sn, ok := st.Value().Interface().(SuffrageNodesV0)
+	if !ok {
+		return SuffrageNodesV0{}, errors.Errorf("not SuffrageNodesV0 in suffrage nodes state, %T", st.Value().Interface())
+	}
+
+	return sn, nil
+}
+
+// SuffrageNodesFromBlocks returns the last suffrage nodes and it's height from
+// the blocks.
+func SuffrageNodesFromBlocks(blks []block.Block) (SuffrageNodesV0, base.Height, bool, error) {
+	for i := len(blks) - 1; i >= 0; i-- {
+		blk := blks[i]
+		sts := blk.States()
+		for j := range sts {
+			if sts[j].Key() != SuffrageNodesStateKey {
+				continue
+			}
+
+			sn, err := SuffrageNodesFromState(sts[j])
+			if err != nil {
+				return SuffrageNodesV0{}, base.NilHeight, false, err
+			}
+
+			return sn, blk.Height(), true, nil
+		}
+	}
+
+	return SuffrageNodesV0{}, base.NilHeight, false, nil
+}
+
+// ApplySuffrageNodes updates the suffrage and nodepool by the suffrage nodes,
+// which was stored at the given height. The new suffrage nodes are used from
+// the next height. The unknown new nodes are added to nodepool with the
+// channel from their ConnInfo. The left nodes are kept in nodepool, because
+// their publickeys are still needed to verify the old ballots and voteproofs.
+func ApplySuffrageNodes(
+	sn SuffrageNodesV0,
+	height base.Height,
+	suffrage base.Suffrage,
+	nodepool *network.Nodepool,
+	newChannel func(network.ConnInfo) (network.Channel, error),
+) error {
+	updater, ok := suffrage.(SuffrageNodesUpdater)
+	if !ok {
+		return errors.Errorf("suffrage, %T does not support to update nodes", suffrage)
+	}
+
+	for i := range sn.nodes {
+		n := sn.nodes[i]
+		if n.Address().Equal(nodepool.LocalNode().Address()) {
+			continue
+		}
+
+		_, ch, found := nodepool.Node(n.Address())
+		if found && ch != nil {
+			continue
+		}
+
+		if ci, ok := sn.ConnInfo(n.Address()); ok && newChannel != nil {
+			j, err := newChannel(ci)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load channel of suffrage node, %q", n.Address())
+			}
+
+			ch = j
+		}
+
+		switch {
+		case !found:
+			if err := nodepool.Add(n, ch); err != nil {
+				return err
+			}
+		case ch != nil:
+			if err := nodepool.SetChannel(n.Address(), ch); err != nil {
+				return err
+			}
+		}
+	}
+
+	return updater.SetNodes(height+1, sn.Addresses())
+}
@@ -0,0 +1,50 @@
+package isaac
+
+import (
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (sn SuffrageNodesV0) MarshalBSON() ([]byte, error) {
+	m := bson.M{
+		"nodes": sn.nodes,
+	}
+
+	var cs []bson.M // nolint:prealloc
+	for i := range sn.nodes {
+		k := sn.nodes[i].Address().String()
+		if ci, found := sn.connInfos[k]; found {
+			cs = append(cs, bson.M{"address": k, "conninfo": ci})
+		}
+	}
+
+	if len(cs) > 0 {
+		m["conninfos"] = cs
+	}
+
+	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(sn.Hint()), m))
+}
+
+type SuffrageNodesV0UnpackerBSON struct {
+	HT hint.Hint `bson:"_hint"`
+	NS bson.Raw  `bson:"nodes"`
+	CS []struct {
+		AD string   `bson:"address"`
+		CI bson.Raw `bson:"conninfo"`
+	} `bson:"conninfos,omitempty"`
+}
+
+func (sn *SuffrageNodesV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var usn SuffrageNodesV0UnpackerBSON
+	if err := enc.Unmarshal(b, &usn); err != nil {
+		return err
+	}
+
+	cs := map[string][]byte{}
+	for i := range usn.CS {
+		cs[usn.CS[i].AD] = usn.CS[i].CI
+	}
+
+	return sn.unpack(enc, usn.HT, usn.NS, cs)
+}
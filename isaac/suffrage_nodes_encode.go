@@ -0,0 +1,41 @@
+package isaac
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+func (sn *SuffrageNodesV0) unpack(enc encoder.Encoder, ht hint.Hint, bns []byte, bcs map[string][]byte) error {
+	hinters, err := enc.DecodeSlice(bns)
+	if err != nil {
+		return err
+	}
+
+	nodes := make([]base.Node, len(hinters))
+	for i := range hinters {
+		j, ok := hinters[i].(base.Node)
+		if !ok {
+			return util.WrongTypeError.Errorf("expected base.Node, not %T", hinters[i])
+		}
+		nodes[i] = j
+	}
+
+	cs := map[string]network.ConnInfo{}
+	for k := range bcs {
+		var ci network.ConnInfo
+		if err := encoder.Decode(bcs[k], enc, &ci); err != nil {
+			return err
+		}
+
+		cs[k] = ci
+	}
+
+	sn.BaseHinter = hint.NewBaseHinter(ht)
+	sn.nodes = nodes
+	sn.connInfos = cs
+
+	return nil
+}
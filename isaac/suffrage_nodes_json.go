@@ -0,0 +1,43 @@
+package isaac
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/network"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+type SuffrageNodesV0PackerJSON struct {
+	jsonenc.HintedHead
+	NS []base.Node                 `json:"nodes"`
+	CS map[string]network.ConnInfo `json:"conninfos,omitempty"`
+}
+
+func (sn SuffrageNodesV0) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(SuffrageNodesV0PackerJSON{
+		HintedHead: jsonenc.NewHintedHead(sn.Hint()),
+		NS:         sn.nodes,
+		CS:         sn.connInfos,
+	})
+}
+
+type SuffrageNodesV0UnpackerJSON struct {
+	jsonenc.HintedHead
+	NS json.RawMessage            `json:"nodes"`
+	CS map[string]json.RawMessage `json:"conninfos"`
+}
+
+func (sn *SuffrageNodesV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var usn SuffrageNodesV0UnpackerJSON
+	if err := enc.Unmarshal(b, &usn); err != nil {
+		return err
+	}
+
+	cs := map[string][]byte{}
+	for k := range usn.CS {
+		cs[k] = usn.CS[k]
+	}
+
+	return sn.unpack(enc, usn.H, usn.NS, cs)
+}
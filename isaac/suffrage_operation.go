@@ -0,0 +1,504 @@
+package isaac
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/prprocessor"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	JoinSuffrageFactType        = hint.Type("join-suffrage-operation-fact")
+	JoinSuffrageFactHint        = hint.NewHint(JoinSuffrageFactType, "v0.0.1")
+	JoinSuffrageFactHinter      = JoinSuffrageFact{BaseHinter: hint.NewBaseHinter(JoinSuffrageFactHint)}
+	JoinSuffrageOperationType   = hint.Type("join-suffrage-operation")
+	JoinSuffrageOperationHint   = hint.NewHint(JoinSuffrageOperationType, "v0.0.1")
+	JoinSuffrageOperationHinter = JoinSuffrageOperation{
+		BaseOperation: operation.EmptyBaseOperation(JoinSuffrageOperationHint),
+	}
+	LeaveSuffrageFactType        = hint.Type("leave-suffrage-operation-fact")
+	LeaveSuffrageFactHint        = hint.NewHint(LeaveSuffrageFactType, "v0.0.1")
+	LeaveSuffrageFactHinter      = LeaveSuffrageFact{BaseHinter: hint.NewBaseHinter(LeaveSuffrageFactHint)}
+	LeaveSuffrageOperationType   = hint.Type("leave-suffrage-operation")
+	LeaveSuffrageOperationHint   = hint.NewHint(LeaveSuffrageOperationType, "v0.0.1")
+	LeaveSuffrageOperationHinter = LeaveSuffrageOperation{
+		BaseOperation: operation.EmptyBaseOperation(LeaveSuffrageOperationHint),
+	}
+)
+
+type JoinSuffrageFact struct {
+	hint.BaseHinter
+	h        valuehash.Hash
+	token    []byte
+	node     base.Node
+	connInfo network.ConnInfo
+}
+
+func NewJoinSuffrageFact(token []byte, n base.Node, connInfo network.ConnInfo) JoinSuffrageFact {
+	fact := JoinSuffrageFact{
+		BaseHinter: hint.NewBaseHinter(JoinSuffrageFactHint),
+		token:      token,
+		node:       n,
+		connInfo:   connInfo,
+	}
+	fact.h = valuehash.NewSHA256(fact.Bytes())
+
+	return fact
+}
+
+func (fact JoinSuffrageFact) IsValid(networkID []byte) error {
+	if err := operation.IsValidOperationFact(fact, networkID); err != nil {
+		return err
+	}
+
+	if fact.node == nil {
+		return isvalid.InvalidError.Errorf("empty node")
+	}
+
+	if err := fact.node.IsValid(nil); err != nil {
+		return err
+	}
+
+	if fact.connInfo == nil {
+		return isvalid.InvalidError.Errorf("empty conninfo")
+	}
+
+	if err := fact.connInfo.IsValid(nil); err != nil {
+		return err
+	}
+
+	if !fact.h.Equal(valuehash.NewSHA256(fact.Bytes())) {
+		return isvalid.InvalidError.Errorf("wrong fact hash")
+	}
+
+	return nil
+}
+
+func (fact JoinSuffrageFact) Hash() valuehash.Hash {
+	return fact.h
+}
+
+func (fact JoinSuffrageFact) Bytes() []byte {
+	var ab, pb, cb []byte
+	if fact.node != nil {
+		ab = fact.node.Address().Bytes()
+		pb = fact.node.Publickey().Bytes()
+	}
+
+	if fact.connInfo != nil {
+		cb = fact.connInfo.Bytes()
+	}
+
+	return util.ConcatBytesSlice(fact.token, ab, pb, cb)
+}
+
+func (fact JoinSuffrageFact) Token() []byte {
+	return fact.token
+}
+
+func (fact JoinSuffrageFact) Node() base.Node {
+	return fact.node
+}
+
+// ConnInfo is the connection info of joining node; the other nodes connect to
+// the joining node by it.
+func (fact JoinSuffrageFact) ConnInfo() network.ConnInfo {
+	return fact.connInfo
+}
+
+// JoinSuffrageOperation adds new node to the suffrage nodes. The operation
+// should be signed by the joining node and by the suffrage nodes over the
+// threshold.
+type JoinSuffrageOperation struct {
+	operation.BaseOperation
+}
+
+func NewJoinSuffrageOperation(fact JoinSuffrageFact, fs []base.FactSign) (JoinSuffrageOperation, error) {
+	bo, err := operation.NewBaseOperationFromFact(JoinSuffrageOperationHint, fact, fs)
+	if err != nil {
+		return JoinSuffrageOperation{}, err
+	}
+
+	return JoinSuffrageOperation{BaseOperation: bo}, nil
+}
+
+// NewJoinSuffrageOperationFromSigner creates new JoinSuffrageOperation signed
+// by the joining node.
+func NewJoinSuffrageOperationFromSigner(
+	signer key.Privatekey,
+	token []byte,
+	n base.Node,
+	connInfo network.ConnInfo,
+	networkID base.NetworkID,
+) (JoinSuffrageOperation, error) {
+	fact := NewJoinSuffrageFact(token, n, connInfo)
+
+	sig, err := base.NewFactSignature(signer, fact, networkID)
+	if err != nil {
+		return JoinSuffrageOperation{}, err
+	}
+
+	return NewJoinSuffrageOperation(fact, []base.FactSign{base.NewBaseFactSign(signer.Publickey(), sig)})
+}
+
+func (JoinSuffrageOperation) Hint() hint.Hint {
+	return JoinSuffrageOperationHint
+}
+
+func (op JoinSuffrageOperation) IsValid(networkID []byte) error {
+	fact, ok := op.Fact().(JoinSuffrageFact)
+	if !ok {
+		return isvalid.InvalidError.Errorf("not JoinSuffrageFact, %T", op.Fact())
+	}
+
+	if err := operation.IsValidOperation(op, networkID); err != nil {
+		return err
+	}
+
+	for i := range op.Signs() {
+		if op.Signs()[i].Signer().Equal(fact.Node().Publickey()) {
+			return nil
+		}
+	}
+
+	return isvalid.InvalidError.Errorf("not signed by joining node")
+}
+
+func (op JoinSuffrageOperation) AddFactSigns(fs ...base.FactSign) (base.FactSignUpdater, error) {
+	i, err := op.BaseOperation.AddFactSigns(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.BaseOperation = i.(operation.BaseOperation)
+
+	return op, nil
+}
+
+func (op JoinSuffrageOperation) Process(
+	getState func(key string) (state.State, bool, error),
+	setState func(valuehash.Hash, ...state.State) error,
+) error {
+	fact := op.Fact().(JoinSuffrageFact)
+
+	return processSuffrageNodes(getState, setState, fact.Hash(), func(sn SuffrageNodesV0) (SuffrageNodesV0, error) {
+		return sn.Join(fact.Node(), fact.ConnInfo())
+	})
+}
+
+type LeaveSuffrageFact struct {
+	hint.BaseHinter
+	h       valuehash.Hash
+	token   []byte
+	address base.Address
+}
+
+func NewLeaveSuffrageFact(token []byte, address base.Address) LeaveSuffrageFact {
+	fact := LeaveSuffrageFact{
+		BaseHinter: hint.NewBaseHinter(LeaveSuffrageFactHint),
+		token:      token,
+		address:    address,
+	}
+	fact.h = valuehash.NewSHA256(fact.Bytes())
+
+	return fact
+}
+
+func (fact LeaveSuffrageFact) IsValid(networkID []byte) error {
+	if err := operation.IsValidOperationFact(fact, networkID); err != nil {
+		return err
+	}
+
+	if fact.address == nil {
+		return isvalid.InvalidError.Errorf("empty address")
+	}
+
+	if err := fact.address.IsValid(nil); err != nil {
+		return err
+	}
+
+	if !fact.h.Equal(valuehash.NewSHA256(fact.Bytes())) {
+		return isvalid.InvalidError.Errorf("wrong fact hash")
+	}
+
+	return nil
+}
+
+func (fact LeaveSuffrageFact) Hash() valuehash.Hash {
+	return fact.h
+}
+
+func (fact LeaveSuffrageFact) Bytes() []byte {
+	var ab []byte
+	if fact.address != nil {
+		ab = fact.address.Bytes()
+	}
+
+	return util.ConcatBytesSlice(fact.token, ab)
+}
+
+func (fact LeaveSuffrageFact) Token() []byte {
+	return fact.token
+}
+
+func (fact LeaveSuffrageFact) Address() base.Address {
+	return fact.address
+}
+
+// LeaveSuffrageOperation removes the node from the suffrage nodes. The
+// operation should be signed by the leaving node or by the suffrage nodes over
+// the threshold.
+type LeaveSuffrageOperation struct {
+	operation.BaseOperation
+}
+
+func NewLeaveSuffrageOperation(fact LeaveSuffrageFact, fs []base.FactSign) (LeaveSuffrageOperation, error) {
+	bo, err := operation.NewBaseOperationFromFact(LeaveSuffrageOperationHint, fact, fs)
+	if err != nil {
+		return LeaveSuffrageOperation{}, err
+	}
+
+	return LeaveSuffrageOperation{BaseOperation: bo}, nil
+}
+
+// NewLeaveSuffrageOperationFromSigner creates new LeaveSuffrageOperation signed
+// by one node.
+func NewLeaveSuffrageOperationFromSigner(
+	signer key.Privatekey,
+	token []byte,
+	address base.Address,
+	networkID base.NetworkID,
+) (LeaveSuffrageOperation, error) {
+	fact := NewLeaveSuffrageFact(token, address)
+
+	sig, err := base.NewFactSignature(signer, fact, networkID)
+	if err != nil {
+		return LeaveSuffrageOperation{}, err
+	}
+
+	return NewLeaveSuffrageOperation(fact, []base.FactSign{base.NewBaseFactSign(signer.Publickey(), sig)})
+}
+
+func (LeaveSuffrageOperation) Hint() hint.Hint {
+	return LeaveSuffrageOperationHint
+}
+
+func (op LeaveSuffrageOperation) IsValid(networkID []byte) error {
+	if _, ok := op.Fact().(LeaveSuffrageFact); !ok {
+		return isvalid.InvalidError.Errorf("not LeaveSuffrageFact, %T", op.Fact())
+	}
+
+	return operation.IsValidOperation(op, networkID)
+}
+
+func (op LeaveSuffrageOperation) AddFactSigns(fs ...base.FactSign) (base.FactSignUpdater, error) {
+	i, err := op.BaseOperation.AddFactSigns(fs...)
+	if err != nil {
+		return nil, err
+	}
+
+	op.BaseOperation = i.(operation.BaseOperation)
+
+	return op, nil
+}
+
+func (op LeaveSuffrageOperation) Process(
+	getState func(key string) (state.State, bool, error),
+	setState func(valuehash.Hash, ...state.State) error,
+) error {
+	fact := op.Fact().(LeaveSuffrageFact)
+
+	return processSuffrageNodes(getState, setState, fact.Hash(), func(sn SuffrageNodesV0) (SuffrageNodesV0, error) {
+		return sn.Leave(fact.Address())
+	})
+}
+
+func processSuffrageNodes(
+	getState func(key string) (state.State, bool, error),
+	setState func(valuehash.Hash, ...state.State) error,
+	fact valuehash.Hash,
+	f func(SuffrageNodesV0) (SuffrageNodesV0, error),
+) error {
+	st, found, err := getState(SuffrageNodesStateKey)
+	switch {
+	case err != nil:
+		return err
+	case !found:
+		return operation.NewBaseReasonError("suffrage nodes state not found")
+	}
+
+	sn, err := SuffrageNodesFromState(st)
+	if err != nil {
+		return err
+	}
+
+	nsn, err := f(sn)
+	if err != nil {
+		return operation.NewBaseReasonErrorFromError(err)
+	}
+
+	value, err := state.NewHintedValue(nsn)
+	if err != nil {
+		return err
+	}
+
+	nst, err := st.SetValue(value)
+	if err != nil {
+		return err
+	}
+
+	return setState(fact, nst)
+}
+
+// SuffrageOperationProcessor processes JoinSuffrageOperation and
+// LeaveSuffrageOperation. Only one of them is allowed in one block. If the
+// suffrage nodes state is not yet stored, the current suffrage nodes are used.
+// The same SuffrageOperationProcessor should be registered for both
+// operations.
+type SuffrageOperationProcessor struct {
+	sync.Mutex
+	nodepool  *network.Nodepool
+	suffrage  base.Suffrage
+	policy    *LocalPolicy
+	pool      *storage.Statepool
+	processed bool
+	last      *SuffrageOperationProcessor
+}
+
+func NewSuffrageOperationProcessor(
+	nodepool *network.Nodepool,
+	suffrage base.Suffrage,
+	policy *LocalPolicy,
+) *SuffrageOperationProcessor {
+	return &SuffrageOperationProcessor{
+		nodepool: nodepool,
+		suffrage: suffrage,
+		policy:   policy,
+	}
+}
+
+func (opp *SuffrageOperationProcessor) New(pool *storage.Statepool) prprocessor.OperationProcessor {
+	opp.Lock()
+	defer opp.Unlock()
+
+	// NOTE join and leave share same processor in same block
+	if opp.last != nil && opp.last.pool == pool {
+		return opp.last
+	}
+
+	opp.last = &SuffrageOperationProcessor{
+		nodepool: opp.nodepool,
+		suffrage: opp.suffrage,
+		policy:   opp.policy,
+		pool:     pool,
+	}
+
+	return opp.last
+}
+
+func (opp *SuffrageOperationProcessor) PreProcess(op state.Processor) (state.Processor, error) {
+	switch t := op.(type) {
+	case JoinSuffrageOperation:
+		if err := opp.preProcessJoin(t); err != nil {
+			return nil, err
+		}
+	case LeaveSuffrageOperation:
+		if err := opp.preProcessLeave(t); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("not suffrage operation, %T", op)
+	}
+
+	opp.Lock()
+	defer opp.Unlock()
+
+	if opp.processed {
+		return nil, operation.NewBaseReasonError("suffrage already updated in this block")
+	}
+
+	opp.processed = true
+
+	return op, nil
+}
+
+func (opp *SuffrageOperationProcessor) Process(op state.Processor) error {
+	return op.Process(opp.getState, opp.pool.Set)
+}
+
+func (*SuffrageOperationProcessor) Close() error {
+	return nil
+}
+
+func (*SuffrageOperationProcessor) Cancel() error {
+	return nil
+}
+
+func (opp *SuffrageOperationProcessor) preProcessJoin(op JoinSuffrageOperation) error {
+	fact := op.Fact().(JoinSuffrageFact)
+	if opp.suffrage.IsInside(fact.Node().Address()) {
+		return operation.NewBaseReasonError("already in suffrage, %q", fact.Node().Address())
+	}
+
+	return checkSuffrageSigns(opp.nodepool, opp.suffrage, opp.policy.ThresholdRatio(), op.Signs())
+}
+
+func (opp *SuffrageOperationProcessor) preProcessLeave(op LeaveSuffrageOperation) error {
+	fact := op.Fact().(LeaveSuffrageFact)
+	if !opp.suffrage.IsInside(fact.Address()) {
+		return operation.NewBaseReasonError("not in suffrage, %q", fact.Address())
+	}
+
+	// NOTE signed by the leaving node
+	if n, _, found := opp.nodepool.Node(fact.Address()); found {
+		for i := range op.Signs() {
+			if op.Signs()[i].Signer().Equal(n.Publickey()) {
+				return nil
+			}
+		}
+	}
+
+	return checkSuffrageSigns(opp.nodepool, opp.suffrage, opp.policy.ThresholdRatio(), op.Signs())
+}
+
+func (opp *SuffrageOperationProcessor) getState(key string) (state.State, bool, error) {
+	st, found, err := opp.pool.Get(key)
+	if err != nil || found || key != SuffrageNodesStateKey {
+		return st, found, err
+	}
+
+	// NOTE suffrage nodes state is not yet stored; the current suffrage nodes
+	// are used.
+	addrs := opp.suffrage.Nodes()
+	nodes := make([]base.Node, len(addrs))
+	for i := range addrs {
+		n, _, found := opp.nodepool.Node(addrs[i])
+		if !found {
+			return nil, false, errors.Errorf("suffrage node, %q not found in nodepool", addrs[i])
+		}
+
+		nodes[i] = n
+	}
+
+	value, err := state.NewHintedValue(NewSuffrageNodesV0(nodes))
+	if err != nil {
+		return nil, false, err
+	}
+
+	nst, err := st.SetValue(value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return nst, true, nil
+}
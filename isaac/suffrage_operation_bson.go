@@ -0,0 +1,96 @@
+package isaac
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (fact JoinSuffrageFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(fact.Hint()),
+		bson.M{
+			"hash":     fact.h,
+			"token":    fact.token,
+			"node":     fact.node,
+			"conninfo": fact.connInfo,
+		},
+	))
+}
+
+type JoinSuffrageFactUnpackerBSON struct {
+	HT hint.Hint       `bson:"_hint"`
+	HS valuehash.Bytes `bson:"hash"`
+	TK []byte          `bson:"token"`
+	NO bson.Raw        `bson:"node"`
+	CI bson.Raw        `bson:"conninfo"`
+}
+
+func (fact *JoinSuffrageFact) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ufact JoinSuffrageFactUnpackerBSON
+	if err := enc.Unmarshal(b, &ufact); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, ufact.HT, ufact.HS, ufact.TK, ufact.NO, ufact.CI)
+}
+
+func (op JoinSuffrageOperation) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(op.BaseOperation)
+}
+
+func (op *JoinSuffrageOperation) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo operation.BaseOperation
+	if err := ubo.UnpackBSON(b, enc); err != nil {
+		return err
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
+
+func (fact LeaveSuffrageFact) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(bsonenc.MergeBSONM(
+		bsonenc.NewHintedDoc(fact.Hint()),
+		bson.M{
+			"hash":    fact.h,
+			"token":   fact.token,
+			"address": fact.address,
+		},
+	))
+}
+
+type LeaveSuffrageFactUnpackerBSON struct {
+	HT hint.Hint           `bson:"_hint"`
+	HS valuehash.Bytes     `bson:"hash"`
+	TK []byte              `bson:"token"`
+	AD base.AddressDecoder `bson:"address"`
+}
+
+func (fact *LeaveSuffrageFact) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ufact LeaveSuffrageFactUnpackerBSON
+	if err := enc.Unmarshal(b, &ufact); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, ufact.HT, ufact.HS, ufact.TK, ufact.AD)
+}
+
+func (op LeaveSuffrageOperation) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(op.BaseOperation)
+}
+
+func (op *LeaveSuffrageOperation) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
+	var ubo operation.BaseOperation
+	if err := ubo.UnpackBSON(b, enc); err != nil {
+		return err
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package isaac
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+func (fact *JoinSuffrageFact) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	h valuehash.Hash,
+	token []byte,
+	bn []byte,
+	bci []byte,
+) error {
+	var n base.Node
+	if err := encoder.Decode(bn, enc, &n); err != nil {
+		return err
+	}
+
+	var ci network.ConnInfo
+	if err := encoder.Decode(bci, enc, &ci); err != nil {
+		return err
+	}
+
+	fact.BaseHinter = hint.NewBaseHinter(ht)
+	fact.h = h
+	fact.token = token
+	fact.node = n
+	fact.connInfo = ci
+
+	return nil
+}
+
+func (fact *LeaveSuffrageFact) unpack(
+	enc encoder.Encoder,
+	ht hint.Hint,
+	h valuehash.Hash,
+	token []byte,
+	ad base.AddressDecoder,
+) error {
+	address, err := ad.Encode(enc)
+	if err != nil {
+		return err
+	}
+
+	fact.BaseHinter = hint.NewBaseHinter(ht)
+	fact.h = h
+	fact.token = token
+	fact.address = address
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package isaac
+
+import (
+	"encoding/json"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/network"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type JoinSuffrageFactPackerJSON struct {
+	jsonenc.HintedHead
+	HS valuehash.Hash   `json:"hash"`
+	TK []byte           `json:"token"`
+	NO base.Node        `json:"node"`
+	CI network.ConnInfo `json:"conninfo"`
+}
+
+func (fact JoinSuffrageFact) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(JoinSuffrageFactPackerJSON{
+		HintedHead: jsonenc.NewHintedHead(fact.Hint()),
+		HS:         fact.h,
+		TK:         fact.token,
+		NO:         fact.node,
+		CI:         fact.connInfo,
+	})
+}
+
+type JoinSuffrageFactUnpackerJSON struct {
+	jsonenc.HintedHead
+	HS valuehash.Bytes `json:"hash"`
+	TK []byte          `json:"token"`
+	NO json.RawMessage `json:"node"`
+	CI json.RawMessage `json:"conninfo"`
+}
+
+func (fact *JoinSuffrageFact) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ufact JoinSuffrageFactUnpackerJSON
+	if err := enc.Unmarshal(b, &ufact); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, ufact.H, ufact.HS, ufact.TK, ufact.NO, ufact.CI)
+}
+
+func (op JoinSuffrageOperation) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(op.BaseOperation)
+}
+
+func (op *JoinSuffrageOperation) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ubo operation.BaseOperation
+	if err := ubo.UnpackJSON(b, enc); err != nil {
+		return err
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
+
+type LeaveSuffrageFactPackerJSON struct {
+	jsonenc.HintedHead
+	HS valuehash.Hash `json:"hash"`
+	TK []byte         `json:"token"`
+	AD base.Address   `json:"address"`
+}
+
+func (fact LeaveSuffrageFact) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(LeaveSuffrageFactPackerJSON{
+		HintedHead: jsonenc.NewHintedHead(fact.Hint()),
+		HS:         fact.h,
+		TK:         fact.token,
+		AD:         fact.address,
+	})
+}
+
+type LeaveSuffrageFactUnpackerJSON struct {
+	jsonenc.HintedHead
+	HS valuehash.Bytes     `json:"hash"`
+	TK []byte              `json:"token"`
+	AD base.AddressDecoder `json:"address"`
+}
+
+func (fact *LeaveSuffrageFact) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ufact LeaveSuffrageFactUnpackerJSON
+	if err := enc.Unmarshal(b, &ufact); err != nil {
+		return err
+	}
+
+	return fact.unpack(enc, ufact.H, ufact.HS, ufact.TK, ufact.AD)
+}
+
+func (op LeaveSuffrageOperation) MarshalJSON() ([]byte, error) {
+	return jsonenc.Marshal(op.BaseOperation)
+}
+
+func (op *LeaveSuffrageOperation) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ubo operation.BaseOperation
+	if err := ubo.UnpackJSON(b, enc); err != nil {
+		return err
+	}
+
+	op.BaseOperation = ubo
+
+	return nil
+}
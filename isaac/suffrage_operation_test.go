@@ -0,0 +1,402 @@
+package isaac
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyUpdaterSuffrage struct {
+	base.Suffrage
+	height  base.Height
+	current base.Height
+	nodes   []base.Address
+}
+
+func (sf *dummyUpdaterSuffrage) Nodes() []base.Address {
+	if sf.nodes == nil || sf.current < sf.height {
+		return sf.Suffrage.Nodes()
+	}
+
+	return sf.nodes
+}
+
+func (sf *dummyUpdaterSuffrage) IsInside(a base.Address) bool {
+	for i := range sf.Nodes() {
+		if sf.Nodes()[i].Equal(a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (sf *dummyUpdaterSuffrage) SetNodes(height base.Height, nodes []base.Address) error {
+	sf.height = height
+	sf.nodes = nodes
+
+	return nil
+}
+
+func (sf *dummyUpdaterSuffrage) SetHeight(height base.Height) {
+	sf.current = height
+}
+
+type testSuffrageOperation struct {
+	BaseTest
+	local  *Local
+	remote *Local
+	other  *Local
+	joiner *Local
+}
+
+func (t *testSuffrageOperation) SetupSuite() {
+	t.BaseTest.SetupSuite()
+
+	_ = t.Encs.TestAddHinter(SuffrageNodesV0Hinter)
+	_ = t.Encs.TestAddHinter(JoinSuffrageFactHinter)
+	_ = t.Encs.TestAddHinter(JoinSuffrageOperationHinter)
+	_ = t.Encs.TestAddHinter(LeaveSuffrageFactHinter)
+	_ = t.Encs.TestAddHinter(LeaveSuffrageOperationHinter)
+	_ = t.Encs.TestAddHinter(network.HTTPConnInfoHinter)
+}
+
+func (t *testSuffrageOperation) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	ls := t.Locals(3)
+	t.local, t.remote, t.other = ls[0], ls[1], ls[2]
+	t.joiner = t.EmptyLocal()
+}
+
+func (t *testSuffrageOperation) signFact(op operation.Operation, signers ...*Local) operation.Operation {
+	for _, l := range signers {
+		sig, err := base.NewFactSignature(l.Node().Privatekey(), op.Fact(), l.Policy().NetworkID())
+		t.NoError(err)
+
+		i, err := op.(base.FactSignUpdater).AddFactSigns(base.NewBaseFactSign(l.Node().Privatekey().Publickey(), sig))
+		t.NoError(err)
+
+		op = i.(operation.Operation)
+	}
+
+	t.NoError(op.IsValid(t.local.Policy().NetworkID()))
+
+	return op
+}
+
+func (t *testSuffrageOperation) newConnInfo(l *Local) network.ConnInfo {
+	ci, err := network.NewHTTPConnInfoFromString(fmt.Sprintf("https://%s:54321", l.Node().Address()), true)
+	t.NoError(err)
+
+	return ci
+}
+
+func (t *testSuffrageOperation) newJoin(signers ...*Local) JoinSuffrageOperation {
+	op, err := NewJoinSuffrageOperationFromSigner(
+		t.joiner.Node().Privatekey(),
+		[]byte("this-is-token"),
+		t.joiner.Node(),
+		t.newConnInfo(t.joiner),
+		t.joiner.Policy().NetworkID(),
+	)
+	t.NoError(err)
+
+	return t.signFact(op, signers...).(JoinSuffrageOperation)
+}
+
+func (t *testSuffrageOperation) newLeave(address base.Address, signers ...*Local) LeaveSuffrageOperation {
+	op, err := NewLeaveSuffrageOperationFromSigner(
+		signers[0].Node().Privatekey(),
+		[]byte("this-is-token"),
+		address,
+		signers[0].Policy().NetworkID(),
+	)
+	t.NoError(err)
+
+	return t.signFact(op, signers[1:]...).(LeaveSuffrageOperation)
+}
+
+func (t *testSuffrageOperation) newProcessor() *SuffrageOperationProcessor {
+	pool, err := storage.NewStatepool(t.local.Database())
+	t.NoError(err)
+
+	opr := NewSuffrageOperationProcessor(
+		t.local.Nodes(),
+		t.Suffrage(t.local, t.local, t.remote, t.other),
+		t.local.Policy(),
+	)
+
+	return opr.New(pool).(*SuffrageOperationProcessor)
+}
+
+func (t *testSuffrageOperation) TestSuffrageNodes() {
+	sn := NewSuffrageNodesV0([]base.Node{t.local.Node(), t.remote.Node()})
+	t.NoError(sn.IsValid(nil))
+
+	_, err := sn.Join(t.local.Node(), nil)
+	t.Error(err)
+	t.Contains(err.Error(), "already in suffrage")
+
+	jsn, err := sn.Join(t.other.Node(), t.newConnInfo(t.other))
+	t.NoError(err)
+	t.NoError(jsn.IsValid(nil))
+	t.Equal(3, len(jsn.Nodes()))
+	t.Equal(2, len(sn.Nodes()))
+
+	ci, found := jsn.ConnInfo(t.other.Node().Address())
+	t.True(found)
+	t.True(ci.Equal(t.newConnInfo(t.other)))
+
+	_, found = jsn.ConnInfo(t.local.Node().Address())
+	t.False(found)
+
+	lsn, err := jsn.Leave(t.other.Node().Address())
+	t.NoError(err)
+	t.Equal(2, len(lsn.Nodes()))
+
+	_, found = lsn.Node(t.other.Node().Address())
+	t.False(found)
+
+	_, found = lsn.ConnInfo(t.other.Node().Address())
+	t.False(found)
+
+	lsn, err = jsn.Leave(t.remote.Node().Address())
+	t.NoError(err)
+	t.Equal(2, len(lsn.Nodes()))
+
+	_, found = lsn.Node(t.remote.Node().Address())
+	t.False(found)
+
+	_, err = lsn.Leave(t.remote.Node().Address())
+	t.Error(err)
+
+	single := NewSuffrageNodesV0([]base.Node{t.local.Node()})
+	_, err = single.Leave(t.local.Node().Address())
+	t.Error(err)
+	t.Contains(err.Error(), "last suffrage node")
+
+	t.Error(NewSuffrageNodesV0(nil).IsValid(nil))
+	t.Error(NewSuffrageNodesV0([]base.Node{t.local.Node(), t.local.Node()}).IsValid(nil))
+}
+
+func (t *testSuffrageOperation) TestSuffrageNodesEncode() {
+	sn, err := NewSuffrageNodesV0([]base.Node{t.local.Node(), t.remote.Node()}).
+		Join(t.other.Node(), t.newConnInfo(t.other))
+	t.NoError(err)
+
+	for _, enc := range []encoder.Encoder{t.JSONEnc, t.BSONEnc} {
+		b, err := enc.Marshal(sn)
+		t.NoError(err)
+
+		hinter, err := enc.Decode(b)
+		t.NoError(err)
+
+		usn, ok := hinter.(SuffrageNodesV0)
+		t.True(ok)
+
+		t.NoError(usn.IsValid(nil))
+		t.True(sn.Hash().Equal(usn.Hash()))
+
+		ci, found := usn.ConnInfo(t.other.Node().Address())
+		t.True(found)
+		t.True(ci.Equal(t.newConnInfo(t.other)))
+	}
+}
+
+func (t *testSuffrageOperation) TestJoinNotSignedByJoiner() {
+	fact := NewJoinSuffrageFact([]byte("this-is-token"), t.joiner.Node(), t.newConnInfo(t.joiner))
+
+	sig, err := base.NewFactSignature(t.local.Node().Privatekey(), fact, t.local.Policy().NetworkID())
+	t.NoError(err)
+
+	op, err := NewJoinSuffrageOperation(fact, []base.FactSign{base.NewBaseFactSign(t.local.Node().Publickey(), sig)})
+	t.NoError(err)
+
+	err = op.IsValid(t.local.Policy().NetworkID())
+	t.Error(err)
+	t.Contains(err.Error(), "not signed by joining node")
+}
+
+func (t *testSuffrageOperation) TestEncode() {
+	join := t.newJoin(t.local)
+	leave := t.newLeave(t.remote.Node().Address(), t.remote)
+
+	for _, enc := range []encoder.Encoder{t.JSONEnc, t.BSONEnc} {
+		for _, op := range []operation.Operation{join, leave} {
+			b, err := enc.Marshal(op)
+			t.NoError(err)
+
+			hinter, err := enc.Decode(b)
+			t.NoError(err)
+
+			uop, ok := hinter.(operation.Operation)
+			t.True(ok)
+			t.True(op.Hint().Equal(uop.Hint()))
+
+			t.NoError(uop.IsValid(t.local.Policy().NetworkID()))
+			t.True(op.Hash().Equal(uop.Hash()))
+			t.True(op.Fact().Hash().Equal(uop.Fact().Hash()))
+			t.Equal(len(op.Signs()), len(uop.Signs()))
+		}
+	}
+}
+
+func (t *testSuffrageOperation) TestJoinNotEnoughSigns() {
+	// NOTE default threshold ratio is 100
+	op := t.newJoin(t.local, t.remote)
+
+	_, err := t.newProcessor().PreProcess(op)
+	t.Error(err)
+	t.True(errors.Is(err, NotEnoughSuffrageSignsError))
+
+	var oe operation.ReasonError
+	t.True(errors.As(err, &oe))
+}
+
+func (t *testSuffrageOperation) TestJoinAlreadyInSuffrage() {
+	op, err := NewJoinSuffrageOperationFromSigner(
+		t.remote.Node().Privatekey(),
+		[]byte("this-is-token"),
+		t.remote.Node(),
+		t.newConnInfo(t.remote),
+		t.remote.Policy().NetworkID(),
+	)
+	t.NoError(err)
+
+	_, err = t.newProcessor().PreProcess(op)
+	t.Error(err)
+	t.Contains(err.Error(), "already in suffrage")
+}
+
+func (t *testSuffrageOperation) TestJoin() {
+	op := t.newJoin(t.local, t.remote, t.other)
+
+	opr := t.newProcessor()
+
+	_, err := opr.PreProcess(op)
+	t.NoError(err)
+	t.NoError(opr.Process(op))
+
+	us := opr.pool.Updates()
+	t.Equal(1, len(us))
+	t.Equal(SuffrageNodesStateKey, us[0].Key())
+
+	sn, err := SuffrageNodesFromState(us[0].GetState())
+	t.NoError(err)
+	t.Equal(4, len(sn.Nodes()))
+
+	n, found := sn.Node(t.joiner.Node().Address())
+	t.True(found)
+	t.True(n.Publickey().Equal(t.joiner.Node().Publickey()))
+
+	ci, found := sn.ConnInfo(t.joiner.Node().Address())
+	t.True(found)
+	t.True(ci.Equal(t.newConnInfo(t.joiner)))
+
+	// NOTE only one suffrage operation in one block
+	_, err = opr.PreProcess(t.newLeave(t.remote.Node().Address(), t.remote))
+	t.Error(err)
+	t.Contains(err.Error(), "already updated")
+}
+
+func (t *testSuffrageOperation) TestLeaveBySelf() {
+	op := t.newLeave(t.remote.Node().Address(), t.remote)
+
+	opr := t.newProcessor()
+
+	_, err := opr.PreProcess(op)
+	t.NoError(err)
+	t.NoError(opr.Process(op))
+
+	us := opr.pool.Updates()
+	t.Equal(1, len(us))
+
+	sn, err := SuffrageNodesFromState(us[0].GetState())
+	t.NoError(err)
+	t.Equal(2, len(sn.Nodes()))
+
+	_, found := sn.Node(t.remote.Node().Address())
+	t.False(found)
+}
+
+func (t *testSuffrageOperation) TestLeaveByOthers() {
+	op := t.newLeave(t.remote.Node().Address(), t.local)
+
+	_, err := t.newProcessor().PreProcess(op)
+	t.True(errors.Is(err, NotEnoughSuffrageSignsError))
+
+	op = t.newLeave(t.remote.Node().Address(), t.local, t.other)
+
+	// NOTE the threshold is calculated with the all suffrage nodes
+	_, err = t.newProcessor().PreProcess(op)
+	t.True(errors.Is(err, NotEnoughSuffrageSignsError))
+}
+
+func (t *testSuffrageOperation) TestLeaveNotInSuffrage() {
+	op := t.newLeave(t.joiner.Node().Address(), t.joiner)
+
+	_, err := t.newProcessor().PreProcess(op)
+	t.Error(err)
+	t.Contains(err.Error(), "not in suffrage")
+}
+
+func (t *testSuffrageOperation) TestApplySuffrageNodes() {
+	suffrage := &dummyUpdaterSuffrage{Suffrage: t.Suffrage(t.local, t.local, t.remote, t.other)}
+	nodepool := t.local.Nodes()
+
+	t.False(nodepool.Exists(t.joiner.Node().Address()))
+	t.True(nodepool.Exists(t.remote.Node().Address()))
+
+	sn, err := NewSuffrageNodesV0([]base.Node{t.local.Node(), t.other.Node()}).
+		Join(t.joiner.Node(), t.newConnInfo(t.joiner))
+	t.NoError(err)
+
+	var loaded []network.ConnInfo
+	newChannel := func(ci network.ConnInfo) (network.Channel, error) {
+		loaded = append(loaded, ci)
+
+		return t.joiner.Channel(), nil
+	}
+
+	t.NoError(ApplySuffrageNodes(sn, base.Height(33), suffrage, nodepool, newChannel))
+
+	t.Equal(base.Height(34), suffrage.height)
+
+	// NOTE before the new height, the old suffrage nodes are used
+	t.Equal(3, len(suffrage.Nodes()))
+	t.False(suffrage.IsInside(t.joiner.Node().Address()))
+	t.True(suffrage.IsInside(t.remote.Node().Address()))
+
+	suffrage.SetHeight(base.Height(34))
+	t.Equal(3, len(suffrage.Nodes()))
+	t.True(suffrage.IsInside(t.joiner.Node().Address()))
+	t.False(suffrage.IsInside(t.remote.Node().Address()))
+
+	// NOTE joined node has the channel from it's conninfo
+	t.Equal(1, len(loaded))
+	t.True(loaded[0].Equal(t.newConnInfo(t.joiner)))
+
+	_, ch, found := nodepool.Node(t.joiner.Node().Address())
+	t.True(found)
+	t.NotNil(ch)
+
+	// NOTE left node is still in nodepool to verify it's old ballots
+	t.True(nodepool.Exists(t.remote.Node().Address()))
+
+	// NOTE not updater
+	err = ApplySuffrageNodes(sn, base.Height(33), t.Suffrage(t.local, t.local), nodepool, newChannel)
+	t.Error(err)
+	t.Contains(err.Error(), "does not support")
+}
+
+func TestSuffrageOperation(t *testing.T) {
+	suite.Run(t, new(testSuffrageOperation))
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
 	"sync"
 	"time"
@@ -22,6 +24,10 @@ import (
 
 var blockIntegrityError = util.NewError("block integrity failed")
 
+// BlockdataChunksDirectory is the directory to keep the chunks of block data
+// items while syncing; the interrupted fetching is resumed with them.
+var BlockdataChunksDirectory = filepath.Join(os.TempDir(), "mitum-blockdata-chunks")
+
 type BlockIntegrityError struct {
 	*util.NError
 	From block.Manifest
@@ -71,11 +77,13 @@ type GeneralSyncer struct { // nolint; maligned
 	pchs                    *util.LockedItem
 	state                   SyncerState
 	baseManifest            block.Manifest
+	snapshot                block.SnapshotHeader
 	stateChan               chan<- SyncerStateChangedContext
 	tailManifest            block.Manifest
 	blksLock                sync.RWMutex
 	blks                    []block.Block
 	blockdataSessions       []blockdata.Session
+	blockdataFetcher        *network.BlockdataFetcher
 	lifeCtx                 context.Context
 	lifeCancel              func()
 	donechLock              sync.RWMutex
@@ -97,6 +105,17 @@ func NewGeneralSyncer(
 		from = baseManifest.Height() + 1
 	}
 
+	return newGeneralSyncer(odb, bd, policy, sourceChannelsFunc, baseManifest, from, to)
+}
+
+func newGeneralSyncer(
+	odb storage.Database,
+	bd blockdata.Blockdata,
+	policy *LocalPolicy,
+	sourceChannelsFunc func() map[string]network.Channel,
+	baseManifest block.Manifest,
+	from, to base.Height,
+) (*GeneralSyncer, error) {
 	if from > to {
 		return nil, errors.Errorf("from height, %d is greater than to height, %d", from, to)
 	}
@@ -129,12 +148,15 @@ func NewGeneralSyncer(
 		state:                   SyncerCreated,
 		blks:                    make([]block.Block, to-from+1),
 		blockdataSessions:       make([]blockdata.Session, to-from+1),
+		blockdataFetcher:        network.NewBlockdataFetcher(BlockdataChunksDirectory, 0),
 		lifeCtx:                 lifeCtx,
 		lifeCancel:              lifeCancel,
 	}, nil
 }
 
 func (cs *GeneralSyncer) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = cs.blockdataFetcher.SetLogging(l)
+
 	return cs.Logging.SetLogging(l)
 }
 
@@ -315,6 +337,12 @@ func (cs *GeneralSyncer) save() error {
 		return err
 	}
 
+	if cs.snapshot != nil {
+		if err := cs.applySnapshot(); err != nil {
+			return err
+		}
+	}
+
 	if err := cs.commit(); err != nil {
 		return err
 	}
@@ -1469,17 +1497,9 @@ func (cs *GeneralSyncer) fetchBlockdata(
 	item block.BlockdataMapItem,
 	ss blockdata.Session,
 ) (io.ReadSeeker, error) {
-	var r io.ReadCloser
-	if block.IsLocalBlockdataItem(item.URL()) {
-		i, err := ch.Blockdata(cs.lifeCtx, item)
-		if err != nil {
-			return nil, err
-		}
-		r = i
-	} else if i, err := network.FetchBlockdataFromRemote(cs.lifeCtx, item); err != nil {
+	r, err := cs.fetchBlockdataFromChannels(ch, ss.Height(), item)
+	if err != nil {
 		return nil, err
-	} else {
-		r = i
 	}
 
 	defer func() {
@@ -1501,6 +1521,52 @@ func (cs *GeneralSyncer) fetchBlockdata(
 	return s, nil
 }
 
+// fetchBlockdataFromChannels fetches the local block data item from the proved
+// channels at once by it's checksum. If failed, it is fetched from the given
+// channel.
+func (cs *GeneralSyncer) fetchBlockdataFromChannels(
+	ch network.Channel,
+	height base.Height,
+	item block.BlockdataMapItem,
+) (io.ReadCloser, error) {
+	if !block.IsLocalBlockdataItem(item.URL()) {
+		return network.FetchBlockdataFromRemote(cs.lifeCtx, item)
+	}
+
+	chs := []network.Channel{ch}
+
+	pchs := cs.provedChannels()
+	keys := make([]string, 0, len(pchs))
+	for k := range pchs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i := range keys {
+		if j := pchs[keys[i]]; j.ConnInfo().String() != ch.ConnInfo().String() {
+			chs = append(chs, j)
+		}
+	}
+
+	r, err := cs.blockdataFetcher.Fetch(cs.lifeCtx, height, item, chs)
+	if err == nil {
+		return r, nil
+	}
+
+	cs.Log().Debug().Err(err).Int64("height", height.Int64()).Str("data_type", item.Type()).
+		Msg("failed to fetch block data from channels; fetch from one channel")
+
+	return cs.openBlockdata(ch, item)
+}
+
+func (cs *GeneralSyncer) openBlockdata(ch network.Channel, item block.BlockdataMapItem) (io.ReadCloser, error) {
+	if block.IsLocalBlockdataItem(item.URL()) {
+		return ch.Blockdata(cs.lifeCtx, item)
+	}
+
+	return network.FetchBlockdataFromRemote(cs.lifeCtx, item)
+}
+
 func (cs *GeneralSyncer) setState(state SyncerState, force bool) {
 	cs.Lock()
 	defer cs.Unlock()
@@ -0,0 +1,400 @@
+package isaac
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/tree"
+)
+
+/*
+NewSnapshotSyncer creates GeneralSyncer, which syncs only the block of snapshot
+height. Instead of replaying the previous blocks, the states of snapshot are
+stored.
+
+1. the block of snapshot height is fetched like the usual GeneralSyncer.
+1. the states of snapshot are fetched from the proved channels and checked by
+the root of snapshot header.
+1. each state of snapshot is checked by the states tree of it's block.
+1. the states are stored and the block is committed.
+
+> The blocks before snapshot height are not stored in local, so the local node
+can not serve the previous blocks to the other nodes.
+*/
+func NewSnapshotSyncer(
+	odb storage.Database,
+	bd blockdata.Blockdata,
+	policy *LocalPolicy,
+	sourceChannelsFunc func() map[string]network.Channel,
+	sh block.SnapshotHeader,
+) (*GeneralSyncer, error) {
+	if err := sh.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	switch _, found, err := odb.LastManifest(); {
+	case err != nil:
+		return nil, err
+	case found:
+		return nil, errors.Errorf("snapshot can be applied only to empty database")
+	}
+
+	if _, ok := odb.(storage.StateUpdater); !ok {
+		return nil, errors.Errorf("database does not support storage.StateUpdater, %T", odb)
+	}
+
+	cs, err := newGeneralSyncer(odb, bd, policy, sourceChannelsFunc, nil, sh.Height(), sh.Height())
+	if err != nil {
+		return nil, err
+	}
+
+	cs.snapshot = sh
+
+	return cs, nil
+}
+
+func (cs *GeneralSyncer) applySnapshot() error {
+	sh := cs.snapshot
+
+	l := cs.Log().With().Int64("snapshot", sh.Height().Int64()).Stringer("snapshot_hash", sh.Hash()).Logger()
+	l.Debug().Msg("trying to apply snapshot")
+
+	switch m, found, err := cs.syncerSession().Manifest(sh.Height()); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("manifest of snapshot, %d not found", sh.Height())
+	case !m.Hash().Equal(sh.Block()):
+		return errors.Errorf("block of snapshot does not match; %q != %q", m.Hash(), sh.Block())
+	}
+
+	sts, err := cs.fetchSnapshotStates()
+	if err != nil {
+		return err
+	}
+
+	if err := cs.checkSnapshotStates(sts); err != nil {
+		return err
+	}
+
+	su := cs.odatabase.(storage.StateUpdater)
+
+	for i := range sts {
+		st := sts[i]
+		if st.Height() >= sh.Height() { // NOTE the states of snapshot height are stored with block
+			continue
+		}
+
+		// NOTE the same state can be already stored by the previous trial
+		if err := su.NewState(st); err != nil && !errors.Is(err, util.DuplicatedError) {
+			return err
+		}
+	}
+
+	l.Debug().Int("states", len(sts)).Msg("snapshot applied")
+
+	return nil
+}
+
+func (cs *GeneralSyncer) fetchSnapshotStates() ([]state.State, error) {
+	sh := cs.snapshot
+
+	var sts []state.State
+	var err error
+	for source, ch := range cs.provedChannels() {
+		if sts, err = cs.fetchSnapshotStatesFromChannel(ch); err == nil {
+			return sts, nil
+		}
+
+		cs.Log().Error().Err(err).Str("source", source).Msg("failed to fetch snapshot states from channel")
+	}
+
+	return nil, errors.Wrapf(err, "failed to fetch snapshot states, %d", sh.Height())
+}
+
+func (cs *GeneralSyncer) fetchSnapshotStatesFromChannel(ch network.Channel) ([]state.State, error) {
+	// NOTE the item of snapshot can be different by nodes
+	sh, err := ch.Snapshot(cs.lifeCtx, cs.snapshot.Height())
+	switch {
+	case err != nil:
+		return nil, err
+	case !sh.Hash().Equal(cs.snapshot.Hash()):
+		return nil, errors.Errorf("different snapshot header")
+	}
+
+	r, err := cs.openBlockdata(ch, sh.Item())
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	sts, err := cs.blockdata.Writer().ReadStates(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := block.VerifySnapshotStates(sh, sts); err != nil {
+		return nil, err
+	}
+
+	return sts, nil
+}
+
+// checkSnapshotStates checks whether the states of snapshot are included in
+// the states tree of their blocks.
+func (cs *GeneralSyncer) checkSnapshotStates(sts []state.State) error {
+	byHeight := map[base.Height][]state.State{}
+	for i := range sts {
+		st := sts[i]
+		byHeight[st.Height()] = append(byHeight[st.Height()], st)
+	}
+
+	heights := make([]base.Height, len(byHeight))
+	var i int
+	for h := range byHeight {
+		heights[i] = h
+		i++
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	for i := 0; i < len(heights); i += cs.limitBlocksPerOnce {
+		end := i + cs.limitBlocksPerOnce
+		if end > len(heights) {
+			end = len(heights)
+		}
+
+		if err := cs.checkSnapshotStatesByHeights(heights[i:end], byHeight); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs *GeneralSyncer) checkSnapshotStatesByHeights(
+	heights []base.Height,
+	byHeight map[base.Height][]state.State,
+) error {
+	var manifests []block.Manifest
+	switch ms, _, err := cs.fetchManifestsByChannels(heights); {
+	case err != nil:
+		return err
+	case len(ms) != len(heights):
+		return errors.Errorf("failed to fetch manifests for snapshot states")
+	default:
+		manifests = ms
+	}
+
+	var err error
+	for source, ch := range cs.provedChannels() {
+		if err = cs.checkSnapshotStatesFromChannel(ch, manifests, byHeight); err == nil {
+			return nil
+		}
+
+		cs.Log().Error().Err(err).Str("source", source).Msg("failed to check snapshot states from channel")
+	}
+
+	return err
+}
+
+func (cs *GeneralSyncer) checkSnapshotStatesFromChannel(
+	ch network.Channel,
+	manifests []block.Manifest,
+	byHeight map[base.Height][]state.State,
+) error {
+	heights := make([]base.Height, len(manifests))
+	for i := range manifests {
+		heights[i] = manifests[i].Height()
+	}
+
+	maps, err := cs.fetchBlockdataMaps(ch, heights)
+	if err != nil {
+		return err
+	}
+
+	for i := range maps {
+		m := manifests[i]
+		if err := block.CompareManifestWithMap(m, maps[i]); err != nil {
+			return err
+		}
+
+		tr, err := cs.fetchStatesTree(ch, maps[i])
+		if err != nil {
+			return err
+		}
+
+		if err := checkStatesInTree(m, tr, byHeight[m.Height()]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs *GeneralSyncer) fetchStatesTree(ch network.Channel, bd block.BlockdataMap) (tree.FixedTree, error) {
+	r, err := cs.fetchBlockdataFromChannels(ch, bd.Height(), bd.StatesTree())
+	if err != nil {
+		return tree.FixedTree{}, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return cs.blockdata.Writer().ReadStatesTree(r)
+}
+
+func checkStatesInTree(m block.Manifest, tr tree.FixedTree, sts []state.State) error {
+	if m.StatesHash() == nil || tr.Len() < 1 {
+		return errors.Errorf("block, %d has no states", m.Height())
+	}
+
+	if err := tr.IsValid(nil); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(tr.Root(), m.StatesHash().Bytes()) {
+		return errors.Errorf("states tree of block, %d does not match", m.Height())
+	}
+
+	keys := map[string]struct{}{}
+	if err := tr.Traverse(func(n tree.FixedTreeNode) (bool, error) {
+		keys[string(n.Key())] = struct{}{}
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	for i := range sts {
+		if _, found := keys[string(sts[i].Hash().Bytes())]; !found {
+			return errors.Errorf("state, %q not found in states tree of block, %d", sts[i].Key(), m.Height())
+		}
+	}
+
+	return nil
+}
+
+// FetchSnapshotHeader finds the last snapshot header, which is agreed by the
+// source channels over threshold. The height of snapshot is not higher than
+// top.
+func FetchSnapshotHeader(
+	ctx context.Context,
+	chs map[string]network.Channel,
+	thresholdRatio base.ThresholdRatio,
+	top base.Height,
+) (block.SnapshotHeader, error) {
+	if len(chs) < 1 {
+		return nil, errors.Errorf("empty source channels")
+	}
+
+	threshold, err := base.NewThreshold(uint(len(chs)), thresholdRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	lasts := fetchSnapshotHeaders(ctx, chs, base.NilHeight)
+
+	var heights []base.Height
+	founds := map[base.Height]struct{}{}
+	for source := range lasts {
+		h := lasts[source].Height()
+		if _, found := founds[h]; found || h > top {
+			continue
+		}
+
+		founds[h] = struct{}{}
+		heights = append(heights, h)
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	for i := range heights {
+		height := heights[i]
+
+		// NOTE the channels, which have the different last snapshot are asked
+		// again with height.
+		headers := map[string]block.SnapshotHeader{}
+		others := map[string]network.Channel{}
+		for source := range chs {
+			if sh, found := lasts[source]; found && sh.Height() == height {
+				headers[source] = sh
+
+				continue
+			}
+
+			others[source] = chs[source]
+		}
+
+		for source, sh := range fetchSnapshotHeaders(ctx, others, height) {
+			headers[source] = sh
+		}
+
+		var set []string
+		byHash := map[string]block.SnapshotHeader{}
+		for source := range headers {
+			k := headers[source].Hash().String()
+			set = append(set, k)
+			byHash[k] = headers[source]
+		}
+
+		if result, key := base.FindMajorityFromSlice(threshold.Total, threshold.Threshold, set); result == base.VoteResultMajority {
+			return byHash[key], nil
+		}
+	}
+
+	return nil, util.NotFoundError.Errorf("snapshot agreed by source channels not found")
+}
+
+// fetchSnapshotHeaders requests the snapshot header of height to channels. The
+// failed channels are ignored.
+func fetchSnapshotHeaders(
+	ctx context.Context,
+	chs map[string]network.Channel,
+	height base.Height,
+) map[string]block.SnapshotHeader {
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	headers := map[string]block.SnapshotHeader{}
+
+	for source := range chs {
+		wg.Add(1)
+
+		go func(source string, ch network.Channel) {
+			defer wg.Done()
+
+			switch sh, err := ch.Snapshot(ctx, height); {
+			case err != nil, sh == nil:
+				return
+			case height != base.NilHeight && sh.Height() != height:
+				return
+			default:
+				lock.Lock()
+				headers[source] = sh
+				lock.Unlock()
+			}
+		}(source, chs[source])
+	}
+
+	wg.Wait()
+
+	return headers
+}
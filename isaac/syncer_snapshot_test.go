@@ -0,0 +1,328 @@
+package isaac
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	channetwork "github.com/spikeekips/mitum/network/gochan"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSnapshotSyncer struct {
+	BaseTest
+}
+
+// prepareRemote generates the blocks with states in genesis and the snapshot
+// of height.
+func (t *testSnapshotSyncer) prepareRemote(height base.Height) (*Local, []string, block.SnapshotHeader) {
+	remote := t.Locals(1)[0]
+	t.NoError(blockdata.Clean(remote.Database(), remote.Blockdata(), false))
+
+	ops := make([]operation.Operation, 3)
+	keys := make([]string, len(ops))
+	for i := range ops {
+		op, err := NewKVOperation(
+			remote.Node().Privatekey(),
+			util.UUID().Bytes(),
+			util.UUID().String(),
+			util.UUID().Bytes(),
+			nil,
+		)
+		t.NoError(err)
+
+		ops[i] = op
+		keys[i] = op.Key()
+	}
+
+	gg, err := NewGenesisBlockV0Generator(remote.Node(), remote.Database(), remote.Blockdata(), remote.Policy(), ops)
+	t.NoError(err)
+	_, err = gg.Generate()
+	t.NoError(err)
+
+	t.SetupNodes(remote, nil)
+	t.GenerateBlocks([]*Local{remote}, height)
+
+	m, found, err := remote.Database().ManifestByHeight(height)
+	t.NoError(err)
+	t.True(found)
+
+	var sts []state.State
+	t.NoError(remote.Database().States(height, func(st state.State) (bool, error) {
+		sts = append(sts, st)
+
+		return true, nil
+	}))
+	t.Equal(len(ops), len(sts))
+
+	root, err := os.MkdirTemp(t.Root, "snapshot-")
+	t.NoError(err)
+
+	ss := blockdata.NewSnapshotStore(root, remote.Blockdata().Writer(), t.JSONEnc)
+	t.NoError(ss.Initialize())
+
+	sh, err := ss.Save(m, sts)
+	t.NoError(err)
+
+	ch := remote.Channel().(*channetwork.Channel)
+	ch.SetSnapshotHandler(func(height base.Height) (block.SnapshotHeader, error) {
+		switch sh, found, err := ss.Header(height); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.NotFoundError.Errorf("snapshot not found")
+		default:
+			return sh, nil
+		}
+	})
+
+	orig := ch.GetBlockdataHandler()
+	ch.SetBlockdataHandler(func(p string) (io.Reader, func() error, error) {
+		if !strings.HasPrefix(p, blockdata.SnapshotURLPrefix) {
+			return orig(p)
+		}
+
+		i, err := ss.Open(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return i, i.Close, nil
+	})
+
+	return remote, keys, sh
+}
+
+func (t *testSnapshotSyncer) TestSync() {
+	height := base.Height(4)
+	remote, keys, sh := t.prepareRemote(height)
+
+	syncNode := t.EmptyLocal()
+	defer t.CloseStates(syncNode)
+
+	cs, err := NewSnapshotSyncer(syncNode.Database(), syncNode.Blockdata(), syncNode.Policy(),
+		func() map[string]network.Channel {
+			return map[string]network.Channel{
+				remote.Node().Address().String(): remote.Channel(),
+			}
+		},
+		sh,
+	)
+	t.NoError(err)
+	defer cs.Close()
+
+	t.Equal(height, cs.HeightFrom())
+	t.Equal(height, cs.HeightTo())
+
+	stateChan := make(chan SyncerStateChangedContext)
+	finishedChan := make(chan SyncerStateChangedContext)
+
+	go func() {
+		for ctx := range stateChan {
+			if ctx.State() != SyncerSaved {
+				continue
+			}
+
+			finishedChan <- ctx
+			break
+		}
+	}()
+
+	cs.SetStateChan(stateChan)
+
+	t.NoError(cs.Prepare())
+
+	select {
+	case <-time.After(time.Second * 5):
+		t.NoError(errors.Errorf("timeout to wait to be finished"))
+	case ctx := <-finishedChan:
+		t.Equal(SyncerSaved, ctx.State())
+		t.Equal(1, len(ctx.Blocks()))
+		t.Equal(height, ctx.Blocks()[0].Height())
+	}
+
+	m := t.LastManifest(syncNode.Database())
+	t.Equal(height, m.Height())
+	t.True(sh.Block().Equal(m.Hash()))
+
+	// NOTE previous blocks are not stored
+	_, found, err := syncNode.Database().ManifestByHeight(height - 1)
+	t.NoError(err)
+	t.False(found)
+
+	for i := range keys {
+		key := keys[i]
+
+		st, found, err := syncNode.Database().State(key)
+		t.NoError(err)
+		t.True(found)
+
+		rst, found, err := remote.Database().State(key)
+		t.NoError(err)
+		t.True(found)
+
+		t.True(rst.Hash().Equal(st.Hash()))
+	}
+}
+
+func (t *testSnapshotSyncer) TestNotEmptyDatabase() {
+	height := base.Height(3)
+	remote, _, sh := t.prepareRemote(height)
+
+	local := t.Locals(1)[0]
+
+	_, err := NewSnapshotSyncer(local.Database(), local.Blockdata(), local.Policy(),
+		func() map[string]network.Channel {
+			return map[string]network.Channel{
+				remote.Node().Address().String(): remote.Channel(),
+			}
+		},
+		sh,
+	)
+	t.Error(err)
+	t.Contains(err.Error(), "empty database")
+}
+
+func (t *testSnapshotSyncer) TestSyncers() {
+	height := base.Height(4)
+	remote, keys, sh := t.prepareRemote(height)
+
+	target := height + 3
+	t.GenerateBlocks([]*Local{remote}, target)
+
+	syncNode := t.EmptyLocal()
+	defer t.CloseStates(syncNode)
+
+	_ = syncNode.Policy().SetSyncFromSnapshot(true)
+
+	finishedChan := make(chan base.Height, 10)
+	blocksChan := make(chan []block.Block, 10)
+
+	ss := NewSyncers(syncNode.Database(), syncNode.Blockdata(), syncNode.Policy(), nil, func() map[string]network.Channel {
+		return map[string]network.Channel{
+			remote.Node().String(): remote.Channel(),
+		}
+	})
+
+	ss.WhenFinished(func(height base.Height) {
+		finishedChan <- height
+	})
+	ss.WhenBlockSaved(func(blocks []block.Block) {
+		blocksChan <- blocks
+	})
+	t.NoError(ss.Start())
+
+	defer ss.Stop()
+
+	isFinished, err := ss.Add(target, []base.Node{remote.Node()})
+	t.NoError(err)
+	t.False(isFinished)
+
+	var blocks []base.Height
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+end:
+	for {
+		select {
+		case <-ctx.Done():
+			t.NoError(errors.Errorf("timeout to wait to be finished"))
+
+			break end
+		case bs := <-blocksChan:
+			for _, blk := range bs {
+				blocks = append(blocks, blk.Height())
+			}
+		case height := <-finishedChan:
+			t.Equal(target, height)
+
+			break end
+		}
+	}
+
+	var expected []base.Height
+	for i := sh.Height(); i <= target; i++ {
+		expected = append(expected, i)
+	}
+
+	t.Equal(expected, blocks)
+
+	t.Equal(target, t.LastManifest(syncNode.Database()).Height())
+
+	_, found, err := syncNode.Database().ManifestByHeight(sh.Height() - 1)
+	t.NoError(err)
+	t.False(found)
+
+	st, found, err := syncNode.Database().State(keys[0])
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.GenesisHeight, st.Height())
+}
+
+func (t *testSnapshotSyncer) TestFetchSnapshotHeader() {
+	ls := t.Locals(3)
+
+	newHeader := func(height base.Height) block.SnapshotHeader {
+		item := block.NewBaseBlockdataMapItem(block.BlockdataSnapshot, util.UUID().String(), "file:///snapshot/a.gz")
+		sh, err := block.NewBaseSnapshotHeader(height, valuehash.RandomSHA256(), valuehash.RandomSHA256(), 1, item)
+		t.NoError(err)
+
+		return sh
+	}
+
+	a, b, c := newHeader(33), newHeader(44), newHeader(44)
+
+	headers := map[int][]block.SnapshotHeader{
+		0: {a, b},
+		1: {a, c},
+		2: {a},
+	}
+
+	chs := map[string]network.Channel{}
+	for i := range ls {
+		i := i
+		ch := ls[i].Channel().(*channetwork.Channel)
+		ch.SetSnapshotHandler(func(height base.Height) (block.SnapshotHeader, error) {
+			hs := headers[i]
+			if height == base.NilHeight {
+				return hs[len(hs)-1], nil
+			}
+
+			for j := range hs {
+				if hs[j].Height() == height {
+					return hs[j], nil
+				}
+			}
+
+			return nil, util.NotFoundError.Errorf("snapshot not found")
+		})
+
+		chs[ls[i].Node().Address().String()] = ch
+	}
+
+	// NOTE 44 is not agreed, so 33 is selected
+	sh, err := FetchSnapshotHeader(context.Background(), chs, base.ThresholdRatio(67), base.Height(100))
+	t.NoError(err)
+	t.True(a.Hash().Equal(sh.Hash()))
+
+	// NOTE lower than top
+	_, err = FetchSnapshotHeader(context.Background(), chs, base.ThresholdRatio(67), base.Height(32))
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+func TestSnapshotSyncer(t *testing.T) {
+	suite.Run(t, new(testSnapshotSyncer))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 )
 
 type Syncers struct {
@@ -119,6 +120,8 @@ func (sy *Syncers) Add(to base.Height, sourceNodes []base.Node) (bool, error) {
 	sy.targetHeight = to
 	sy.mergeSourceNodes(sourceNodes)
 
+	metrics.SyncerTargetHeight.Set(float64(to.Int64()))
+
 	if !isFinished {
 		l.Debug().Msg("target height updated")
 
@@ -132,7 +135,16 @@ func (sy *Syncers) Add(to base.Height, sourceNodes []base.Node) (bool, error) {
 		baseManifest = sy.lastSyncer.TailManifest()
 	}
 
-	i, err := sy.newSyncer(baseManifest)
+	var i Syncer
+	if baseManifest == nil && sy.policy.SyncFromSnapshot() {
+		i = sy.newSnapshotSyncer()
+	}
+
+	var err error
+	if i == nil {
+		i, err = sy.newSyncer(baseManifest)
+	}
+
 	if err != nil {
 		l.Debug().Msg("target height updated, but failed to add new syncer")
 
@@ -224,6 +236,39 @@ func (sy *Syncers) newSyncer(baseManifest block.Manifest) (Syncer, error) {
 	return syncer, nil
 }
 
+// newSnapshotSyncer creates the syncer from the last snapshot of the source
+// channels. If failed, nil is returned and the blocks will be synced from
+// genesis.
+func (sy *Syncers) newSnapshotSyncer() Syncer {
+	ctx, cancel := context.WithTimeout(context.Background(), sy.policy.NetworkConnectionTimeout())
+	defer cancel()
+
+	sh, err := FetchSnapshotHeader(ctx, sy.sourceChannelsFunc(), sy.policy.ThresholdRatio(), sy.targetHeight)
+	if err != nil {
+		sy.Log().Debug().Err(err).Msg("snapshot not found; sync from genesis")
+
+		return nil
+	}
+
+	l := sy.Log().With().Int64("snapshot", sh.Height().Int64()).Stringer("snapshot_hash", sh.Hash()).Logger()
+
+	syncer, err := NewSnapshotSyncer(sy.database, sy.blockdata, sy.policy, sy.sourceChannelsFunc, sh)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to make snapshot syncer; sync from genesis")
+
+		return nil
+	}
+	syncer = syncer.SetStateChan(sy.stateChan)
+
+	_ = syncer.SetLogging(sy.Logging)
+
+	l.Debug().Msg("new snapshot syncer added")
+
+	sy.syncers.Store(syncer.ID(), syncer)
+
+	return syncer
+}
+
 func (sy *Syncers) prepareSyncer(baseManifest block.Manifest) error {
 	var l zerolog.Logger
 	{
@@ -351,6 +396,8 @@ func (sy *Syncers) stateChangedSaved(ctx SyncerStateChangedContext) error {
 		}
 	}
 
+	metrics.SyncerHeight.Set(float64(syncer.HeightTo().Int64()))
+
 	sy.whenBlockSaved(ctx.Blocks())
 
 	if sy.isFinished() {
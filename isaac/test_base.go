@@ -18,6 +18,7 @@ import (
 	"github.com/spikeekips/mitum/base/prprocessor"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
 	channetwork "github.com/spikeekips/mitum/network/gochan"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata/localfs"
@@ -57,6 +58,7 @@ func (t *BaseTest) SetupSuite() {
 	_ = t.Encs.TestAddHinter(base.StringAddressHinter)
 	_ = t.Encs.TestAddHinter(base.VoteproofV0Hinter)
 	_ = t.Encs.TestAddHinter(block.BaseBlockdataMapHinter)
+	_ = t.Encs.TestAddHinter(block.BaseSnapshotHeaderHinter)
 	_ = t.Encs.TestAddHinter(block.BlockV0Hinter)
 	_ = t.Encs.TestAddHinter(block.BlockConsensusInfoV0Hinter)
 	_ = t.Encs.TestAddHinter(block.ManifestV0Hinter)
@@ -132,6 +134,31 @@ func (t *BaseTest) SetupNodes(local *Local, others []*Local) {
 				return i, i.Close, nil
 			}
 		})
+		nch.SetBlockdataByChecksumHandler(func(height base.Height, dataType, checksum string) (io.ReadSeekCloser, error) {
+			bd, found, err := st.Database().BlockdataMap(height)
+			if err != nil {
+				return nil, err
+			} else if !found {
+				return nil, util.NotFoundError.Errorf("blockdata map not found")
+			}
+
+			item, found := bd.(block.BaseBlockdataMap).Item(dataType)
+			if !found || item.Checksum() != checksum {
+				return nil, util.NotFoundError.Errorf("block data not found")
+			}
+
+			u, err := network.ParseURL(item.URL(), false)
+			if err != nil {
+				return nil, err
+			}
+
+			f, err := st.Blockdata().FS().Open(u.Path)
+			if err != nil {
+				return nil, err
+			}
+
+			return f.(io.ReadSeekCloser), nil
+		})
 	}
 }
 
@@ -3,12 +3,14 @@ package isaac
 import (
 	"github.com/rs/zerolog"
 	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
 type VoteProofChecker struct {
 	*logging.Logging
 	voteproof base.Voteproof
+	database  storage.Database
 	policy    *LocalPolicy
 	suffrage  base.Suffrage
 }
@@ -17,12 +19,18 @@ type VoteProofChecker struct {
 // Ballot.Signer(), but it takes a little bit time to gather the Ballots from
 // the other node, so this will be ignored at this time for performance reason.
 
-func NewVoteProofChecker(voteproof base.Voteproof, policy *LocalPolicy, suffrage base.Suffrage) *VoteProofChecker {
+func NewVoteProofChecker(
+	voteproof base.Voteproof,
+	db storage.Database,
+	policy *LocalPolicy,
+	suffrage base.Suffrage,
+) *VoteProofChecker {
 	return &VoteProofChecker{
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
 			return c.Str("module", "voteproof-checker")
 		}),
 		voteproof: voteproof,
+		database:  db,
 		policy:    policy,
 		suffrage:  suffrage,
 	}
@@ -50,9 +58,14 @@ func (vc *VoteProofChecker) NodeIsInSuffrage() (bool, error) {
 	return true, nil
 }
 
-// CheckThreshold checks Threshold only for new incoming Voteproof.
+// CheckThreshold checks the threshold ratio of Voteproof is the threshold
+// ratio, which was in effect at the height of Voteproof.
 func (vc *VoteProofChecker) CheckThreshold() (bool, error) {
-	tr := vc.policy.ThresholdRatio()
+	tr, err := vc.thresholdRatio()
+	if err != nil {
+		return false, err
+	}
+
 	if tr != vc.voteproof.ThresholdRatio() {
 		vc.Log().Debug().
 			Interface("threshold_ratio", vc.voteproof.ThresholdRatio()).
@@ -63,3 +76,29 @@ func (vc *VoteProofChecker) CheckThreshold() (bool, error) {
 
 	return true, nil
 }
+
+// thresholdRatio returns the threshold ratio of the policy, by which the
+// voteproof was made. The voteproof until the height of on-chain policy was
+// made by the previous policy, that is, the policy state under the height of
+// voteproof. Without the previous policy state, the threshold ratio of local
+// config was used.
+func (vc *VoteProofChecker) thresholdRatio() (base.ThresholdRatio, error) {
+	height := vc.voteproof.Height()
+	if height > vc.policy.PolicyHeight() {
+		return vc.policy.ThresholdRatio(), nil
+	}
+
+	switch st, found, err := vc.database.StateAtHeight(PolicyStateKey, height-1); {
+	case err != nil:
+		return 0, err
+	case !found:
+		return vc.policy.LocalThresholdRatio(), nil
+	default:
+		po, err := PolicyFromState(st)
+		if err != nil {
+			return 0, err
+		}
+
+		return po.ThresholdRatio(), nil
+	}
+}
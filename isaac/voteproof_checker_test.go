@@ -0,0 +1,99 @@
+package isaac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/ballot"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyPolicyStatesDatabase struct {
+	storage.Database
+	states []state.State // NOTE by ascending height
+}
+
+func (db dummyPolicyStatesDatabase) StateAtHeight(key string, height base.Height) (state.State, bool, error) {
+	for i := len(db.states) - 1; i >= 0; i-- {
+		if st := db.states[i]; st.Key() == key && st.Height() <= height {
+			return st, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+type testVoteProofChecker struct {
+	suite.Suite
+}
+
+func (t *testVoteProofChecker) policyState(threshold base.ThresholdRatio, height base.Height) state.State {
+	po := NewPolicyV0(threshold, 33, 44, time.Second*3, time.Second*4, time.Second*5, time.Second*6, time.Second*7, time.Minute)
+
+	v, err := state.NewHintedValue(po)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(PolicyStateKey, v, height)
+	t.NoError(err)
+
+	return st
+}
+
+func (t *testVoteProofChecker) voteproof(height base.Height, threshold base.ThresholdRatio) base.Voteproof {
+	fact := ballot.NewACCEPTFact(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+
+	return base.NewTestVoteproofV0(
+		height, base.Round(0), nil, threshold, base.VoteResultMajority, true,
+		base.StageACCEPT, fact, nil, nil, localtime.UTCNow(),
+	)
+}
+
+func (t *testVoteProofChecker) TestCheckThreshold() {
+	// NOTE local config is 67, the on-chain policy of 70 was stored at 20 and
+	// 80 was stored at 33.
+	policy := NewLocalPolicy(TestNetworkID)
+	_ = policy.SetThresholdRatio(base.ThresholdRatio(67))
+
+	old := t.policyState(base.ThresholdRatio(70), base.Height(20))
+	last := t.policyState(base.ThresholdRatio(80), base.Height(33))
+
+	po, err := PolicyFromState(last)
+	t.NoError(err)
+	t.NoError(policy.SetPolicy(po, last.Height()))
+
+	db := dummyPolicyStatesDatabase{states: []state.State{old, last}}
+
+	cases := []struct {
+		name      string
+		height    base.Height
+		threshold base.ThresholdRatio
+		expected  bool
+	}{
+		{name: "after last policy", height: 34, threshold: 80, expected: true},
+		{name: "after last policy; previous threshold", height: 34, threshold: 70, expected: false},
+		{name: "at last policy", height: 33, threshold: 70, expected: true},
+		{name: "at last policy; low threshold", height: 33, threshold: 10, expected: false},
+		{name: "at last policy; last threshold", height: 33, threshold: 80, expected: false},
+		{name: "after old policy", height: 21, threshold: 70, expected: true},
+		{name: "at old policy; local threshold", height: 20, threshold: 67, expected: true},
+		{name: "at old policy; old threshold", height: 20, threshold: 70, expected: false},
+		{name: "before old policy; low threshold", height: 10, threshold: 10, expected: false},
+	}
+
+	for _, c := range cases {
+		vc := NewVoteProofChecker(t.voteproof(c.height, c.threshold), db, policy, nil)
+
+		ok, err := vc.CheckThreshold()
+		t.NoError(err, c.name)
+		t.Equal(c.expected, ok, c.name)
+	}
+}
+
+func TestVoteProofChecker(t *testing.T) {
+	suite.Run(t, new(testVoteProofChecker))
+}
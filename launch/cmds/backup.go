@@ -0,0 +1,160 @@
+package cmds
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lucas-clemente/quic-go"
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/launch/deploy"
+	"github.com/spikeekips/mitum/network"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+type BackupCommand struct {
+	*BaseCommand
+	DeployKey string `arg:"" name:"deploy key"`
+	Directory string `arg:"" name:"directory" help:"backup directory; should be empty or not exist"`
+	*NodeConnectFlags
+	client *quicnetwork.QuicClient
+}
+
+func NewBackupCommand() BackupCommand {
+	return BackupCommand{
+		BaseCommand:      NewBaseCommand("backup"),
+		NodeConnectFlags: &NodeConnectFlags{},
+	}
+}
+
+func (cmd *BackupCommand) Run(version util.Version) error {
+	cmd.BaseCommand.LogOutput = os.Stderr
+
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	} else if _, err := cmd.LoadEncoders(nil, nil); err != nil {
+		return err
+	}
+
+	// NOTE backup of whole blocks takes long time.
+	if cmd.Timeout < 1 {
+		cmd.Timeout = time.Hour
+	}
+
+	cmd.Log().Debug().Interface("node_url", cmd.URL).Str("directory", cmd.Directory).Msg("backup")
+
+	quicConfig := &quic.Config{HandshakeIdleTimeout: time.Second * 5}
+	i, err := quicnetwork.NewQuicClient(cmd.TLSInscure, quicConfig)
+	if err != nil {
+		return err
+	}
+	cmd.client = i
+
+	if err := blockdata.PrepareBackupDirectory(cmd.Directory); err != nil {
+		return err
+	}
+
+	bm, err := cmd.request()
+	if err != nil {
+		var pr network.Problem
+		if errors.As(err, &pr) {
+			cmd.Log().Error().Interface("problem", pr).Msg("failed")
+		}
+
+		return err
+	}
+
+	if err := blockdata.SaveBackupManifest(cmd.Directory, bm); err != nil {
+		return errors.Wrap(err, "failed to save backup manifest")
+	}
+
+	if _, f, _, err := blockdata.LoadBackup(cmd.Directory, blockdata.NewDefaultWriter(cmd.JSONEncoder())); err != nil {
+		return errors.Wrap(err, "failed to verify backup")
+	} else {
+		_ = f.Close()
+	}
+
+	cmd.Log().Info().Interface("backup", bm).Msg("backup done")
+
+	return nil
+}
+
+func (cmd *BackupCommand) request() (blockdata.BackupManifest, error) {
+	var bm blockdata.BackupManifest
+
+	u := *cmd.URL
+	u.Path = filepath.Join(u.Path, deploy.QuicHandlerPathBackup)
+
+	headers := http.Header{}
+	headers.Set("Authorization", cmd.DeployKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	res, c, err := cmd.client.Request(ctx, cmd.Timeout, u.String(), "GET", nil, headers)
+	if err != nil {
+		return bm, errors.Wrap(err, "failed to request")
+	}
+	defer func() {
+		_ = c()
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		if i, err := network.LoadProblemFromResponse(res); err == nil {
+			cmd.Log().Debug().Interface("response", res).Interface("problem", i).Msg("failed to request")
+
+			return bm, i
+		}
+
+		cmd.Log().Debug().Interface("response", res).Msg("failed to backup")
+
+		return bm, errors.Errorf("failed to backup")
+	}
+
+	if err := jsonenc.Unmarshal([]byte(res.Header.Get(deploy.BackupManifestHeader)), &bm); err != nil {
+		return bm, errors.Wrap(err, "failed to decode backup manifest")
+	}
+
+	bm.Archive = blockdata.BackupArchiveFile
+
+	if err := cmd.saveArchive(res.Body); err != nil {
+		return bm, err
+	}
+
+	return bm, nil
+}
+
+func (cmd *BackupCommand) saveArchive(r io.Reader) error {
+	p := filepath.Join(cmd.Directory, blockdata.BackupArchiveFile)
+	tmp := p + ".tmp"
+
+	f, err := os.OpenFile(filepath.Clean(tmp), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create archive file, %q", tmp)
+	}
+
+	if err := func() error {
+		defer func() {
+			_ = f.Close()
+		}()
+
+		if _, err := io.Copy(f, r); err != nil {
+			return errors.Wrap(err, "failed to receive archive")
+		}
+
+		return f.Sync()
+	}(); err != nil {
+		_ = os.Remove(tmp)
+
+		return err
+	}
+
+	return os.Rename(tmp, p)
+}
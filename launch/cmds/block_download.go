@@ -132,7 +132,7 @@ func (cmd *BlockDownloadCommand) prepareChannel() error {
 	}
 
 	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.URL), cmd.TLSInscure)
-	ch, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout)
+	ch, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout, quicnetwork.ChannelConfig{})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,93 @@
+// +build test
+
+package cmds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockdataArchive struct {
+	isaac.BaseTest
+	root string
+}
+
+func (t *testBlockdataArchive) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	p, err := os.MkdirTemp("", "archive-")
+	t.NoError(err)
+	t.root = p
+}
+
+func (t *testBlockdataArchive) TearDownTest() {
+	t.BaseTest.TearDownTest()
+
+	_ = os.RemoveAll(t.root)
+}
+
+func (t *testBlockdataArchive) run(args []string) error {
+	flags := struct {
+		Export BlockdataExportCommand `cmd:"" name:"export"`
+		Import BlockdataImportCommand `cmd:"" name:"import"`
+	}{
+		Export: NewBlockdataExportCommand(launch.EncoderTypes, launch.EncoderHinters),
+		Import: NewBlockdataImportCommand(launch.EncoderTypes, launch.EncoderHinters),
+	}
+
+	kctx, err := Context(args, &flags)
+	if err != nil {
+		return err
+	}
+
+	return kctx.Run(util.Version("v0.0.1"))
+}
+
+func (t *testBlockdataArchive) TestExportImport() {
+	local := t.Locals(1)[0]
+	src := local.Blockdata().(*localfs.Blockdata)
+
+	archive := filepath.Join(t.root, "blocks.archive")
+	t.NoError(t.run([]string{"export", src.Root(), archive}))
+
+	// NOTE without --force, existing archive is not overwritten
+	err := t.run([]string{"export", src.Root(), archive})
+	t.Error(err)
+	t.Contains(err.Error(), "already exists")
+
+	dst := filepath.Join(t.root, "blockdata")
+	t.NoError(t.run([]string{"import", archive, dst, "--network-id", string(isaac.TestNetworkID)}))
+
+	bd := localfs.NewBlockdata(dst, t.JSONEnc)
+	t.NoError(bd.Initialize())
+
+	for height := base.PreGenesisHeight; height <= t.LastManifest(local.Database()).Height(); height++ {
+		_, a, err := localfs.LoadBlock(src, height)
+		t.NoError(err)
+
+		_, b, err := localfs.LoadBlock(bd, height)
+		t.NoError(err)
+
+		t.True(a.Hash().Equal(b.Hash()))
+		t.NoError(b.IsValid(isaac.TestNetworkID))
+	}
+
+	// NOTE import again without --force
+	err = t.run([]string{"import", archive, dst})
+	t.Error(err)
+	t.Contains(err.Error(), "already exists")
+
+	t.NoError(t.run([]string{"import", archive, dst, "--force"}))
+}
+
+func TestBlockdataArchive(t *testing.T) {
+	suite.Run(t, new(testBlockdataArchive))
+}
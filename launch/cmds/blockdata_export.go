@@ -0,0 +1,169 @@
+package cmds
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+type BlockdataExportCommand struct {
+	*BaseCommand
+	Path    string `arg:"" name:"blockdata path"`
+	Archive string `arg:"" name:"archive file"`
+	From    int64  `name:"from" help:"from height; default: -1" default:"-1"`
+	To      int64  `name:"to" help:"to height; default: last height" default:"-2"`
+	Force   bool   `name:"force" help:"overwrite the existing archive file"`
+	bd      *localfs.Blockdata
+	from    base.Height
+	to      base.Height
+}
+
+func NewBlockdataExportCommand(types []hint.Type, hinters []hint.Hinter) BlockdataExportCommand {
+	cmd := BlockdataExportCommand{
+		BaseCommand: NewBaseCommand("blockdata-export"),
+	}
+
+	if _, err := cmd.LoadEncoders(types, hinters); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (cmd *BlockdataExportCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	cmd.Log().Debug().Str("path", cmd.Path).Str("archive", cmd.Archive).
+		Interface("from_to", []base.Height{cmd.from, cmd.to}).Msg("trying to export blockdata")
+
+	s := time.Now()
+	if err := cmd.export(); err != nil {
+		return err
+	}
+
+	cmd.Log().Info().Dur("elapsed", time.Since(s)).Str("archive", cmd.Archive).
+		Interface("from_to", []base.Height{cmd.from, cmd.to}).Msg("blockdata exported")
+
+	return nil
+}
+
+func (cmd *BlockdataExportCommand) prepare() error {
+	if i, err := os.Stat(cmd.Path); err != nil {
+		return errors.Wrapf(err, "invalid path, %q", cmd.Path)
+	} else if !i.IsDir() {
+		return errors.Errorf("path, %q is not directory", cmd.Path)
+	}
+
+	if !cmd.Force {
+		if _, err := os.Stat(cmd.Archive); err == nil {
+			return errors.Errorf("archive file, %q already exists; use --force", cmd.Archive)
+		}
+	}
+
+	cmd.bd = localfs.NewBlockdata(cmd.Path, cmd.jsonenc)
+	if err := cmd.bd.Initialize(); err != nil {
+		return err
+	}
+
+	cmd.from = base.Height(cmd.From)
+	if err := cmd.from.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid from height")
+	}
+
+	switch found, err := cmd.bd.Exists(cmd.from); {
+	case err != nil:
+		return err
+	case !found:
+		return errors.Errorf("from height, %d not found", cmd.from)
+	}
+
+	if cmd.To == base.NilHeight.Int64() {
+		to, err := lastBlockdataHeight(cmd.bd, cmd.from)
+		if err != nil {
+			return err
+		}
+		cmd.to = to
+	} else {
+		cmd.to = base.Height(cmd.To)
+	}
+
+	if cmd.to < cmd.from {
+		return errors.Errorf("to height, %d is lower than from height, %d", cmd.to, cmd.from)
+	}
+
+	return nil
+}
+
+func (cmd *BlockdataExportCommand) export() error {
+	// NOTE archive is written to temporary file and renamed after closed.
+	tmp := cmd.Archive + ".tmp"
+	f, err := os.OpenFile(filepath.Clean(tmp), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, localfs.DefaultFilePermission)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create archive file, %q", tmp)
+	}
+
+	if err := func() error {
+		defer func() {
+			_ = f.Close()
+		}()
+
+		aw, err := blockdata.NewArchiveWriter(f, cmd.bd.Writer())
+		if err != nil {
+			return err
+		}
+
+		for height := cmd.from; height <= cmd.to; height++ {
+			_, blk, err := localfs.LoadBlock(cmd.bd, height)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load block, %d", height)
+			}
+
+			if err := aw.Add(blk); err != nil {
+				return err
+			}
+
+			cmd.Log().Debug().Int64("height", height.Int64()).Msg("block exported")
+		}
+
+		if err := aw.Close(); err != nil {
+			return err
+		}
+
+		return f.Sync()
+	}(); err != nil {
+		_ = os.Remove(tmp)
+
+		return err
+	}
+
+	return os.Rename(tmp, cmd.Archive)
+}
+
+// lastBlockdataHeight finds the last height of the contiguous blocks from the
+// given height.
+func lastBlockdataHeight(bd blockdata.Blockdata, from base.Height) (base.Height, error) {
+	height := from
+	for {
+		switch found, err := bd.Exists(height); {
+		case err != nil:
+			return base.NilHeight, errors.Wrapf(err, "failed to check blockdata of height, %d", height)
+		case !found:
+			return height - 1, nil
+		}
+
+		height++
+	}
+}
@@ -0,0 +1,173 @@
+package cmds
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+type BlockdataImportCommand struct {
+	*BaseCommand
+	Archive   string        `arg:"" name:"archive file"`
+	Path      string        `arg:"" name:"blockdata path"`
+	NetworkID NetworkIDFlag `name:"network-id" help:"if given, blocks are validated with network id"`
+	Force     bool          `name:"force" help:"overwrite the existing blocks"`
+	bd        *localfs.Blockdata
+	networkID base.NetworkID
+}
+
+func NewBlockdataImportCommand(types []hint.Type, hinters []hint.Hinter) BlockdataImportCommand {
+	cmd := BlockdataImportCommand{
+		BaseCommand: NewBaseCommand("blockdata-import"),
+	}
+
+	if _, err := cmd.LoadEncoders(types, hinters); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (cmd *BlockdataImportCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+
+	if len(cmd.NetworkID) > 0 {
+		cmd.networkID = cmd.NetworkID.NetworkID()
+	}
+
+	if err := os.MkdirAll(cmd.Path, localfs.DefaultDirectoryPermission); err != nil {
+		return errors.Wrapf(err, "failed to create blockdata path, %q", cmd.Path)
+	}
+
+	cmd.bd = localfs.NewBlockdata(cmd.Path, cmd.jsonenc)
+	if err := cmd.bd.Initialize(); err != nil {
+		return err
+	}
+
+	f, ar, err := OpenBlockdataArchive(cmd.Archive, cmd.bd.Writer())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	cmd.Log().Debug().Str("archive", cmd.Archive).Str("path", cmd.Path).
+		Interface("from_to", []base.Height{ar.From(), ar.To()}).Msg("trying to import blockdata")
+
+	s := time.Now()
+	if err := cmd.importArchive(ar); err != nil {
+		return err
+	}
+
+	cmd.Log().Info().Dur("elapsed", time.Since(s)).Str("path", cmd.Path).
+		Interface("from_to", []base.Height{ar.From(), ar.To()}).Msg("blockdata imported")
+
+	return nil
+}
+
+func (cmd *BlockdataImportCommand) importArchive(ar *blockdata.ArchiveReader) error {
+	if !cmd.Force {
+		for height := ar.From(); height <= ar.To(); height++ {
+			switch found, err := cmd.bd.Exists(height); {
+			case err != nil:
+				return err
+			case found:
+				return errors.Errorf("block, %d already exists; use --force", height)
+			}
+		}
+	}
+
+	var prev block.Manifest
+	for height := ar.From(); height <= ar.To(); height++ {
+		blk, err := ar.Block(height)
+		if err != nil {
+			return err
+		}
+
+		if len(cmd.networkID) > 0 {
+			if err := cmd.checkBlock(blk, prev); err != nil {
+				return err
+			}
+		}
+
+		if _, err := SaveBlockdata(cmd.bd, blk); err != nil {
+			return err
+		}
+
+		prev = blk.Manifest()
+
+		cmd.Log().Debug().Int64("height", height.Int64()).Msg("block imported")
+	}
+
+	return nil
+}
+
+func (cmd *BlockdataImportCommand) checkBlock(blk block.Block, prev block.Manifest) error {
+	if err := blk.IsValid(cmd.networkID); err != nil {
+		return err
+	}
+
+	if prev == nil {
+		return nil
+	}
+
+	checker := isaac.NewManifestsValidationChecker(cmd.networkID, []block.Manifest{prev, blk.Manifest()})
+	_ = checker.SetLogging(cmd.Logging)
+
+	return util.NewChecker("manifests-validation-checker", []util.CheckerFunc{
+		checker.CheckSerialized,
+	}).Check()
+}
+
+// OpenBlockdataArchive opens the block data archive file. The returned file
+// should be closed after the archive is used.
+func OpenBlockdataArchive(p string, writer blockdata.Writer) (*os.File, *blockdata.ArchiveReader, error) {
+	f, err := os.Open(filepath.Clean(p))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to open archive, %q", p)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return nil, nil, errors.Wrapf(err, "failed to stat archive, %q", p)
+	}
+
+	ar, err := blockdata.NewArchiveReader(f, fi.Size(), writer)
+	if err != nil {
+		_ = f.Close()
+
+		return nil, nil, errors.Wrapf(err, "failed to read archive, %q", p)
+	}
+
+	return f, ar, nil
+}
+
+// SaveBlockdata stores block into blockdata thru session.
+func SaveBlockdata(bd blockdata.Blockdata, blk block.Block) (block.BlockdataMap, error) {
+	ss, err := bd.NewSession(blk.Height())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ss.SetBlock(blk); err != nil {
+		_ = ss.Cancel()
+
+		return nil, err
+	}
+
+	return bd.SaveSession(ss)
+}
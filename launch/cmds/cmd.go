@@ -38,6 +38,16 @@ var defaultHooks = []pm.Hook{
 		process.HookNameAddHinters, process.HookAddHinters(launch.EncoderTypes, launch.EncoderHinters)),
 	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
 		process.HookNameSetNetworkHandlers, process.HookSetNetworkHandlers),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
+		process.HookNameLoadPolicy, process.HookLoadPolicy),
+	pm.NewHook(pm.HookPrefixPre, process.ProcessNameProposalProcessor,
+		process.HookNameSetPolicyOperationProcessor, process.HookSetPolicyOperationProcessor),
+	pm.NewHook(pm.HookPrefixPre, process.ProcessNameProposalProcessor,
+		process.HookNameSetSuffrageOperationProcessor, process.HookSetSuffrageOperationProcessor),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameSuffrage,
+		process.HookNameLoadSuffrageNodes, process.HookLoadSuffrageNodes),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
+		process.HookNameApplySuffrageNodes, process.HookApplySuffrageNodes),
 	pm.NewHook(pm.HookPrefixPost, process.ProcessNameNetwork,
 		process.HookNameNetworkRateLimit, process.HookNetworkRateLimit),
 	pm.NewHook(pm.HookPrefixPost, process.ProcessNameLocalNode, process.HookNameSetPolicy, process.HookSetPolicy),
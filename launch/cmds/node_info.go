@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/network"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/util"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 )
@@ -48,7 +49,7 @@ func (cmd *NodeInfoCommand) Run(version util.Version) error {
 	}
 
 	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.URL), cmd.TLSInscure)
-	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout)
+	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout, quicnetwork.ChannelConfig{})
 	if err != nil {
 		return err
 	}
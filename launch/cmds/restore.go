@@ -18,6 +18,7 @@ import (
 	"github.com/spikeekips/mitum/launch/pm"
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/storage/blockdata/localfs"
 	"github.com/spikeekips/mitum/util"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
@@ -35,6 +36,8 @@ type RestoreCommand struct {
 	Concurrency           uint64 `help:"how many blocks are handled at same time default: 10" default:"10"`
 	Dryrun                bool   `help:"just check blockdata and database default: false" default:"false"`
 	One                   string `help:"restore one blockdata"`
+	Archive               string `help:"restore from blockdata archive"`
+	Backup                string `help:"restore from backup directory"`
 	enc                   *jsonenc.Encoder
 	database              storage.Database
 	blockdata             *localfs.Blockdata
@@ -46,6 +49,7 @@ type RestoreCommand struct {
 	cleanDatabase         func() error
 	cleanDatabaseByHeight func(context.Context, base.Height) error
 	oneHeight             base.Height
+	archive               *blockdata.ArchiveReader
 }
 
 func NewRestoreCommand() RestoreCommand {
@@ -89,6 +93,8 @@ func (cmd *RestoreCommand) Run(version util.Version) error {
 		Bool("dryrun", cmd.Dryrun).
 		Uint64("concurrency", cmd.Concurrency).
 		Str("one", cmd.One).
+		Str("archive", cmd.Archive).
+		Str("backup", cmd.Backup).
 		Msg("started")
 
 	if err := cmd.prepare(); err != nil {
@@ -107,6 +113,32 @@ func (cmd *RestoreCommand) Run(version util.Version) error {
 		return err
 	}
 
+	if len(cmd.Archive) > 0 {
+		f, ar, err := OpenBlockdataArchive(cmd.Archive, cmd.blockdata.Writer())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		cmd.archive = ar
+	}
+
+	if len(cmd.Backup) > 0 {
+		bm, f, ar, err := blockdata.LoadBackup(cmd.Backup, cmd.blockdata.Writer())
+		if err != nil {
+			return errors.Wrap(err, "failed to load backup")
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		cmd.Log().Debug().Interface("backup", bm).Msg("backup loaded")
+
+		cmd.archive = ar
+	}
+
 	if err := cmd.checkBlockdata(); err != nil {
 		return err
 	}
@@ -129,6 +161,43 @@ func (cmd *RestoreCommand) prepare() error {
 		return err
 	}
 
+	var sources int
+	for _, i := range []string{cmd.One, cmd.Archive, cmd.Backup} {
+		if len(i) > 0 {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		return errors.Errorf("--one, --archive and --backup can not be used together")
+	}
+
+	if len(cmd.Backup) > 0 {
+		switch fi, err := os.Stat(cmd.Backup); {
+		case err != nil:
+			if os.IsNotExist(err) {
+				return fmt.Errorf("backup, %q does not exist: %w", cmd.Backup, err)
+			}
+
+			return fmt.Errorf("failed to access backup, %q: %w", cmd.Backup, err)
+		case !fi.IsDir():
+			return fmt.Errorf("backup, %q is not directory", cmd.Backup)
+		}
+	}
+
+	if len(cmd.Archive) > 0 {
+		switch fi, err := os.Stat(cmd.Archive); {
+		case err != nil:
+			if os.IsNotExist(err) {
+				return fmt.Errorf("blockdata archive, %q does not exist: %w", cmd.Archive, err)
+			}
+
+			return fmt.Errorf("failed to access blockdata archive, %q: %w", cmd.Archive, err)
+		case fi.IsDir():
+			return fmt.Errorf("blockdata archive, %q is directory", cmd.Archive)
+		}
+	}
+
 	if len(cmd.One) > 0 {
 		switch fi, err := os.Stat(cmd.One); {
 		case err != nil:
@@ -281,9 +350,19 @@ func (cmd *RestoreCommand) checkBlockdataPath() error {
 		from = i + 1
 	}
 
-	to, err := cmd.checkBlockdataExists(from)
-	if err != nil {
-		return err
+	var to base.Height
+	if cmd.archive != nil {
+		i, err := cmd.checkArchiveHeights(from)
+		if err != nil {
+			return err
+		}
+		to = i
+	} else {
+		i, err := cmd.checkBlockdataExists(from)
+		if err != nil {
+			return err
+		}
+		to = i
 	}
 
 	switch {
@@ -299,15 +378,20 @@ func (cmd *RestoreCommand) checkBlockdataPath() error {
 			last = fmt.Sprintf("%s(%s)", lastHeight.String(), lastHash.String())
 		}
 
+		source := "blockdata"
+		if cmd.archive != nil {
+			source = "archive"
+		}
+
 		l := strings.Repeat("-", 80)
 		_, _ = fmt.Fprintf(os.Stdout, `%s
 * in database:
   last: %s
-* in blockdata:
+* in %s:
   from: %d
     to: %d
 %s
-`, l, last, from, to, l)
+`, l, last, source, from, to, l)
 	}
 
 	c := int64(cmd.Concurrency)
@@ -380,6 +464,17 @@ end:
 	return to, nil
 }
 
+// checkArchiveHeights checks the archive covers the heights from the given
+// height and returns the last height of archive.
+func (cmd *RestoreCommand) checkArchiveHeights(from base.Height) (base.Height, error) {
+	if from < cmd.archive.From() {
+		return base.NilHeight, errors.Errorf(
+			"archive starts from %d, but blocks from %d are needed", cmd.archive.From(), from)
+	}
+
+	return cmd.archive.To(), nil
+}
+
 func (cmd *RestoreCommand) checkBlockdatas(from, to base.Height) error {
 	wk := util.NewErrgroupWorker(context.Background(), int64(cmd.Concurrency))
 	defer wk.Close()
@@ -430,9 +525,19 @@ func (cmd *RestoreCommand) checkBlockdatas(from, to base.Height) error {
 }
 
 func (cmd *RestoreCommand) checkBlockdataByHeight(height base.Height) (block.Block, error) {
-	_, blk, err := localfs.LoadBlock(cmd.blockdata, height)
-	if err != nil {
-		return nil, err
+	var blk block.Block
+	if cmd.archive != nil {
+		i, err := cmd.archive.Block(height)
+		if err != nil {
+			return nil, err
+		}
+		blk = i
+	} else {
+		_, i, err := localfs.LoadBlock(cmd.blockdata, height)
+		if err != nil {
+			return nil, err
+		}
+		blk = i
 	}
 
 	if err := blk.IsValid(cmd.networkID); err != nil {
@@ -517,6 +622,13 @@ func (cmd *RestoreCommand) restoreBlockdataPath() error {
 				return err
 			}
 		}
+
+		// NOTE blocks of archive will be written into the cleaned blockdata
+		if cmd.archive != nil {
+			if err := cmd.blockdata.Clean(false); err != nil {
+				return err
+			}
+		}
 	}
 
 	wk := util.NewErrgroupWorker(context.Background(), int64(cmd.Concurrency))
@@ -551,6 +663,10 @@ func (cmd *RestoreCommand) restoreBlockdataPath() error {
 }
 
 func (cmd *RestoreCommand) restoreBlockdataByHeight(height base.Height) error {
+	if cmd.archive != nil {
+		return cmd.restoreArchiveByHeight(height)
+	}
+
 	bdm, blk, err := localfs.LoadBlock(cmd.blockdata, height)
 	if err != nil {
 		cmd.Log().Error().Int64("height", height.Int64()).Err(err).Msg("failed to load block")
@@ -561,7 +677,25 @@ func (cmd *RestoreCommand) restoreBlockdataByHeight(height base.Height) error {
 	return cmd.saveBlockdata(bdm, blk)
 }
 
-func (cmd *RestoreCommand) saveBlockdata(bdm block.BaseBlockdataMap, blk block.Block) error {
+func (cmd *RestoreCommand) restoreArchiveByHeight(height base.Height) error {
+	blk, err := cmd.archive.Block(height)
+	if err != nil {
+		cmd.Log().Error().Int64("height", height.Int64()).Err(err).Msg("failed to load block from archive")
+
+		return err
+	}
+
+	bdm, err := SaveBlockdata(cmd.blockdata, blk)
+	if err != nil {
+		cmd.Log().Error().Int64("height", height.Int64()).Err(err).Msg("failed to save block into blockdata")
+
+		return err
+	}
+
+	return cmd.saveBlockdata(bdm, blk)
+}
+
+func (cmd *RestoreCommand) saveBlockdata(bdm block.BlockdataMap, blk block.Block) error {
 	s := time.Now()
 
 	sst, err := cmd.database.NewSyncerSession()
@@ -21,14 +21,18 @@ import (
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery/memberlist"
+	querynetwork "github.com/spikeekips/mitum/network/query"
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 )
 
 var defaultRunProcesses = []pm.Process{
 	process.ProcessorDiscovery,
 	process.ProcessorConsensusStates,
+	process.ProcessorMetrics,
+	process.ProcessorQuery,
 }
 
 var defaultRunHooks = []pm.Hook{
@@ -43,8 +47,18 @@ var defaultRunHooks = []pm.Hook{
 		deploy.HookNameBlockdataCleaner, deploy.HookBlockdataCleaner),
 	pm.NewHook(pm.HookPrefixPost, process.ProcessNameNetwork,
 		deploy.HookNameInitializeDeployKeyStorage, deploy.HookInitializeDeployKeyStorage),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
+		deploy.HookNameBlockdataScrubber, deploy.HookBlockdataScrubber),
 	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
 		deploy.HookNameDeployHandlers, deploy.HookDeployHandlers),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
+		process.HookNameSnapshot, process.HookSnapshot),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameConsensusStates,
+		process.HookNamePrune, process.HookPrune),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameQuery,
+		process.HookNameSetQueryHandlers, process.HookSetQueryHandlers),
+	pm.NewHook(pm.HookPrefixPost, process.ProcessNameQuery,
+		process.HookNameSetQueryEvents, process.HookSetQueryEvents),
 }
 
 type RunCommand struct {
@@ -56,6 +70,8 @@ type RunCommand struct {
 	cs                states.States
 	nt                network.Server
 	dis               *memberlist.Discovery
+	metrics           *metrics.Server
+	query             *querynetwork.Server
 }
 
 func NewRunCommand(dryrun bool) RunCommand {
@@ -149,6 +165,14 @@ func (cmd *RunCommand) run() error {
 		return errors.Wrap(err, "failed to run network")
 	}
 
+	if err := cmd.runMetrics(ps.Context()); err != nil {
+		return errors.Wrap(err, "failed to run metrics server")
+	}
+
+	if err := cmd.runQuery(ps.Context()); err != nil {
+		return errors.Wrap(err, "failed to run query server")
+	}
+
 	if err := cmd.runDiscovery(ps.Context()); err != nil {
 		return errors.Wrap(err, "failed to run discovery")
 	}
@@ -169,6 +193,34 @@ func (*RunCommand) runNetwork(ctx context.Context) error {
 	return nt.Start()
 }
 
+func (cmd *RunCommand) runMetrics(ctx context.Context) error {
+	var sv *metrics.Server
+	switch err := process.LoadMetricsServerContextValue(ctx, &sv); {
+	case errors.Is(err, util.ContextValueNotFoundError):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	cmd.metrics = sv
+
+	return sv.Start()
+}
+
+func (cmd *RunCommand) runQuery(ctx context.Context) error {
+	var sv *querynetwork.Server
+	switch err := process.LoadQueryServerContextValue(ctx, &sv); {
+	case errors.Is(err, util.ContextValueNotFoundError):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	cmd.query = sv
+
+	return sv.Start()
+}
+
 func (*RunCommand) runPPS(ctx context.Context) error {
 	var local node.Local
 	if err := process.LoadLocalNodeContextValue(ctx, &local); err != nil {
@@ -299,5 +351,17 @@ func (cmd *RunCommand) whenExited() error {
 		}
 	}
 
+	if cmd.metrics != nil {
+		if err := cmd.metrics.Stop(); err != nil {
+			return errors.Wrap(err, "failed to stop metrics server")
+		}
+	}
+
+	if cmd.query != nil {
+		if err := cmd.query.Stop(); err != nil {
+			return errors.Wrap(err, "failed to stop query server")
+		}
+	}
+
 	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/spikeekips/mitum/launch"
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/network"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/util"
 )
 
@@ -73,7 +74,7 @@ func (cmd *StartHandoverCommand) Run(version util.Version) error {
 	}
 
 	connInfo := network.NewHTTPConnInfo(network.NormalizeURL(cmd.URL), cmd.TLSInscure)
-	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout)
+	channel, err := process.LoadNodeChannel(connInfo, encs, cmd.Timeout, quicnetwork.ChannelConfig{})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,114 @@
+package cmds
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+)
+
+type StorageMigrateCommand struct {
+	*BaseRunCommand
+	To     int64 `help:"target schema version; -1 is the latest default: -1" default:"-1"`
+	Dryrun bool  `help:"just show the migrations default: false" default:"false"`
+}
+
+func NewStorageMigrateCommand() StorageMigrateCommand {
+	cmd := StorageMigrateCommand{
+		BaseRunCommand: NewBaseRunCommand(false, "storage-migrate"),
+	}
+
+	ps := cmd.Processes()
+	if ps == nil {
+		panic(errors.Errorf("processes not prepared"))
+	}
+
+	for _, i := range []pm.Process{
+		process.ProcessorConsensusStates,
+		process.ProcessorNetwork,
+		process.ProcessorProposalProcessor,
+	} {
+		if err := ps.AddProcess(pm.NewDisabledProcess(i), true); err != nil {
+			panic(err)
+		}
+	}
+
+	_ = cmd.SetProcesses(ps)
+
+	return cmd
+}
+
+func (cmd *StorageMigrateCommand) Run(version util.Version) error {
+	if err := cmd.Initialize(cmd, version); err != nil {
+		return errors.Wrap(err, "failed to initialize command")
+	}
+	defer cmd.Done()
+	defer func() {
+		<-time.After(time.Second * 1)
+	}()
+
+	cmd.Log().Info().Bool("dryrun", cmd.Dryrun).Int64("to", cmd.To).Msg("started")
+
+	if cmd.To < -1 {
+		return errors.Errorf("invalid target schema version, %d", cmd.To)
+	}
+
+	if err := cmd.prepare(); err != nil {
+		return err
+	}
+
+	// NOTE the migrations are not run by ProcessorDatabase.
+	ps := cmd.Processes()
+	_ = ps.SetContext(context.WithValue(ps.ContextSource(), process.ContextValueSkipDatabaseMigration, true))
+	_ = ps.SetContext(context.WithValue(ps.ContextSource(), process.ContextValueGenesisBlockForceCreate, false))
+	_ = cmd.SetProcesses(ps)
+
+	if err := ps.Run(); err != nil {
+		return err
+	}
+
+	return cmd.migrate(ps.Context())
+}
+
+func (cmd *StorageMigrateCommand) migrate(ctx context.Context) error {
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ctx, &db); err != nil {
+		return err
+	}
+
+	ms, err := process.DatabaseMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	target := ms.Latest()
+	if cmd.To >= 0 {
+		target = uint64(cmd.To)
+	}
+
+	mr := storage.NewMigrator(db, ms, cmd.Dryrun)
+	_ = mr.SetLogging(cmd.Logging)
+
+	current, err := mr.Current()
+	if err != nil {
+		return err
+	}
+
+	applied, err := mr.Migrate(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	l := cmd.Log().Info().Uint64("current", current).Uint64("target", target).Int("migrations", len(applied))
+	if cmd.Dryrun {
+		l.Msg("migrations checked")
+	} else {
+		l.Msg("database migrated")
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"net"
 	"time"
 
 	"github.com/pkg/errors"
@@ -76,12 +77,30 @@ func (cc *checker) CheckLocalNetwork() (bool, error) {
 		}
 	}
 
+	if conf.GossipFanout() > 0 && conf.GossipTTL() <= 0 {
+		if err := conf.SetGossipTTL(network.DefaultGossipTTL.String()); err != nil {
+			return false, err
+		}
+	}
+
 	if conf.RateLimit() != nil {
 		if err := cc.checkRateLimit(); err != nil {
 			return false, err
 		}
 	}
 
+	if u := conf.MetricsBind(); u != nil {
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			return false, errors.Wrapf(err, "invalid metrics-bind, %q", u.String())
+		}
+	}
+
+	if u := conf.QueryBind(); u != nil {
+		if _, _, err := net.SplitHostPort(u.Host); err != nil {
+			return false, errors.Wrapf(err, "invalid query-bind, %q", u.String())
+		}
+	}
+
 	return true, nil
 }
 
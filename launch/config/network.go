@@ -3,9 +3,13 @@ package config
 import (
 	"crypto/tls"
 	"net/url"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util/cache"
+	"github.com/spikeekips/mitum/util/hint"
 )
 
 var (
@@ -50,15 +54,36 @@ type LocalNetwork interface {
 	SetSealCache(string) error
 	RateLimit() RateLimit
 	SetRateLimit(RateLimit) error
+	MetricsBind() *url.URL
+	SetMetricsBind(string) error
+	QueryBind() *url.URL
+	SetQueryBind(string) error
+	MutualTLS() bool
+	SetMutualTLS(bool) error
+	GossipFanout() uint
+	SetGossipFanout(uint) error
+	GossipTTL() time.Duration
+	SetGossipTTL(string) error
+	WireEncoder() hint.Type
+	SetWireEncoder(string) error
+	CompactWire() bool
+	SetCompactWire(bool) error
 }
 
 type BaseLocalNetwork struct {
 	*BaseNodeNetwork
-	bind      *url.URL
-	certs     []tls.Certificate
-	cache     *url.URL
-	sealCache *url.URL
-	rateLimit RateLimit
+	bind         *url.URL
+	certs        []tls.Certificate
+	cache        *url.URL
+	sealCache    *url.URL
+	rateLimit    RateLimit
+	metricsBind  *url.URL
+	queryBind    *url.URL
+	mutualTLS    bool
+	gossipFanout uint
+	gossipTTL    time.Duration
+	wireEncoder  hint.Type
+	compactWire  bool
 }
 
 func EmptyBaseLocalNetwork() *BaseLocalNetwork {
@@ -130,3 +155,103 @@ func (no *BaseLocalNetwork) SetRateLimit(s RateLimit) error {
 
 	return nil
 }
+
+func (no BaseLocalNetwork) MetricsBind() *url.URL {
+	return no.metricsBind
+}
+
+func (no *BaseLocalNetwork) SetMetricsBind(s string) error {
+	u, err := network.ParseURL(s, true)
+	if err != nil {
+		return err
+	}
+	no.metricsBind = u
+
+	return nil
+}
+
+func (no BaseLocalNetwork) QueryBind() *url.URL {
+	return no.queryBind
+}
+
+func (no *BaseLocalNetwork) SetQueryBind(s string) error {
+	u, err := network.ParseURL(s, true)
+	if err != nil {
+		return err
+	}
+	no.queryBind = u
+
+	return nil
+}
+
+// MutualTLS returns whether the nodes authenticate each other by the node
+// certificates. If true, the network server uses the node certificate of the
+// local node instead of Certs.
+func (no BaseLocalNetwork) MutualTLS() bool {
+	return no.mutualTLS
+}
+
+func (no *BaseLocalNetwork) SetMutualTLS(b bool) error {
+	no.mutualTLS = b
+
+	return nil
+}
+
+// GossipFanout returns the number of nodes, which the seal is sent to at once
+// in gossip mode. 0 means gossip mode is disabled.
+func (no BaseLocalNetwork) GossipFanout() uint {
+	return no.gossipFanout
+}
+
+func (no *BaseLocalNetwork) SetGossipFanout(i uint) error {
+	no.gossipFanout = i
+
+	return nil
+}
+
+func (no BaseLocalNetwork) GossipTTL() time.Duration {
+	return no.gossipTTL
+}
+
+func (no *BaseLocalNetwork) SetGossipTTL(s string) error {
+	t, err := parseTimeDuration(s, true)
+	if err != nil {
+		return err
+	} else if t < 0 {
+		return errors.Errorf("negative gossip ttl, %q", s)
+	}
+	no.gossipTTL = t
+
+	return nil
+}
+
+// WireEncoder returns the encoder type, which is used to send seal and is
+// preferred for the responses of the other nodes. Empty means the default
+// encoder. bson is not recommended; it is slower than json and the size is
+// almost same. To save bandwidth, use CompactWire instead.
+func (no BaseLocalNetwork) WireEncoder() hint.Type {
+	return no.wireEncoder
+}
+
+func (no *BaseLocalNetwork) SetWireEncoder(s string) error {
+	t := hint.Type(strings.TrimSpace(s))
+	if err := t.IsValid(nil); err != nil {
+		return errors.Wrapf(err, "invalid wire encoder, %q", s)
+	}
+	no.wireEncoder = t
+
+	return nil
+}
+
+// CompactWire returns whether the seals and the responses of the other nodes
+// are in the compact wire format. It saves more than half of bandwidth, but
+// costs more CPU per consensus round than the plain format.
+func (no BaseLocalNetwork) CompactWire() bool {
+	return no.compactWire
+}
+
+func (no *BaseLocalNetwork) SetCompactWire(b bool) error {
+	no.compactWire = b
+
+	return nil
+}
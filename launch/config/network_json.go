@@ -5,11 +5,26 @@ import (
 )
 
 type BaseLocalNetworkPackerJSON struct {
-	URL       string    `json:"url"`
-	Bind      string    `json:"bind"`
-	Cache     string    `json:"cache,omitempty"`
-	SealCache string    `json:"seal_cache,omitempty"`
-	RateLimit RateLimit `json:"rate-limit,omitempty"`
+	URL         string                `json:"url"`
+	Bind        string                `json:"bind"`
+	Cache       string                `json:"cache,omitempty"`
+	SealCache   string                `json:"seal_cache,omitempty"`
+	RateLimit   RateLimit             `json:"rate-limit,omitempty"`
+	MetricsBind string                `json:"metrics_bind,omitempty"`
+	QueryBind   string                `json:"query_bind,omitempty"`
+	MutualTLS   bool                  `json:"mutual_tls,omitempty"`
+	Gossip      *BaseGossipPackerJSON `json:"gossip,omitempty"`
+	Wire        *BaseWirePackerJSON   `json:"wire,omitempty"`
+}
+
+type BaseGossipPackerJSON struct {
+	Fanout uint   `json:"fanout"`
+	TTL    string `json:"ttl"`
+}
+
+type BaseWirePackerJSON struct {
+	Encoder string `json:"encoder,omitempty"`
+	Compact bool   `json:"compact,omitempty"`
 }
 
 func (no BaseLocalNetwork) MarshalJSON() ([]byte, error) {
@@ -17,6 +32,7 @@ func (no BaseLocalNetwork) MarshalJSON() ([]byte, error) {
 		URL:       no.ConnInfo().String(),
 		Bind:      no.Bind().String(),
 		RateLimit: no.RateLimit(),
+		MutualTLS: no.MutualTLS(),
 	}
 	if no.Cache() != nil {
 		nno.Cache = no.Cache().String()
@@ -26,5 +42,21 @@ func (no BaseLocalNetwork) MarshalJSON() ([]byte, error) {
 		nno.SealCache = no.SealCache().String()
 	}
 
+	if no.MetricsBind() != nil {
+		nno.MetricsBind = no.MetricsBind().String()
+	}
+
+	if no.QueryBind() != nil {
+		nno.QueryBind = no.QueryBind().String()
+	}
+
+	if no.GossipFanout() > 0 {
+		nno.Gossip = &BaseGossipPackerJSON{Fanout: no.GossipFanout(), TTL: no.GossipTTL().String()}
+	}
+
+	if len(no.WireEncoder()) > 0 || no.CompactWire() {
+		nno.Wire = &BaseWirePackerJSON{Encoder: no.WireEncoder().String(), Compact: no.CompactWire()}
+	}
+
 	return jsonenc.Marshal(nno)
 }
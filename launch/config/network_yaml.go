@@ -1,11 +1,26 @@
 package config
 
 type BaseLocalNetworkPackerYAML struct {
-	URL       string
-	Bind      string
-	Cache     string    `yaml:"cache,omitempty"`
-	SealCache string    `yaml:"seal-cache,omitempty"`
-	RateLimit RateLimit `yaml:"rate-limit,omitempty"`
+	URL         string
+	Bind        string
+	Cache       string                `yaml:"cache,omitempty"`
+	SealCache   string                `yaml:"seal-cache,omitempty"`
+	RateLimit   RateLimit             `yaml:"rate-limit,omitempty"`
+	MetricsBind string                `yaml:"metrics-bind,omitempty"`
+	QueryBind   string                `yaml:"query-bind,omitempty"`
+	MutualTLS   bool                  `yaml:"mutual-tls,omitempty"`
+	Gossip      *BaseGossipPackerYAML `yaml:"gossip,omitempty"`
+	Wire        *BaseWirePackerYAML   `yaml:"wire,omitempty"`
+}
+
+type BaseGossipPackerYAML struct {
+	Fanout uint
+	TTL    string `yaml:"ttl"`
+}
+
+type BaseWirePackerYAML struct {
+	Encoder string `yaml:"encoder,omitempty"`
+	Compact bool   `yaml:"compact,omitempty"`
 }
 
 func (no BaseLocalNetwork) MarshalYAML() (interface{}, error) {
@@ -13,6 +28,7 @@ func (no BaseLocalNetwork) MarshalYAML() (interface{}, error) {
 		URL:       no.ConnInfo().String(),
 		Bind:      no.Bind().String(),
 		RateLimit: no.RateLimit(),
+		MutualTLS: no.MutualTLS(),
 	}
 
 	if no.Cache() != nil {
@@ -23,5 +39,21 @@ func (no BaseLocalNetwork) MarshalYAML() (interface{}, error) {
 		nno.SealCache = no.SealCache().String()
 	}
 
+	if no.MetricsBind() != nil {
+		nno.MetricsBind = no.MetricsBind().String()
+	}
+
+	if no.QueryBind() != nil {
+		nno.QueryBind = no.QueryBind().String()
+	}
+
+	if no.GossipFanout() > 0 {
+		nno.Gossip = &BaseGossipPackerYAML{Fanout: no.GossipFanout(), TTL: no.GossipTTL().String()}
+	}
+
+	if len(no.WireEncoder()) > 0 || no.CompactWire() {
+		nno.Wire = &BaseWirePackerYAML{Encoder: no.WireEncoder().String(), Compact: no.CompactWire()}
+	}
+
 	return nno, nil
 }
@@ -27,6 +27,14 @@ type Policy interface {
 	SetTimespanValidBallot(string) error
 	NetworkConnectionTimeout() time.Duration
 	SetNetworkConnectionTimeout(string) error
+	SnapshotInterval() uint
+	SetSnapshotInterval(uint) error
+	SyncFromSnapshot() bool
+	SetSyncFromSnapshot(bool) error
+	RetentionHeights() uint
+	SetRetentionHeights(uint) error
+	PruneBlockdata() bool
+	SetPruneBlockdata(bool) error
 }
 
 type BasePolicy struct {
@@ -40,6 +48,10 @@ type BasePolicy struct {
 	intervalBroadcastingACCEPTBallot time.Duration
 	timespanValidBallot              time.Duration
 	networkConnectionTimeout         time.Duration
+	snapshotInterval                 uint
+	syncFromSnapshot                 bool
+	retentionHeights                 uint
+	pruneBlockdata                   bool
 }
 
 func (no BasePolicy) ThresholdRatio() base.ThresholdRatio {
@@ -173,3 +185,43 @@ func (no *BasePolicy) SetNetworkConnectionTimeout(s string) error {
 
 	return nil
 }
+
+func (no BasePolicy) SnapshotInterval() uint {
+	return no.snapshotInterval
+}
+
+func (no *BasePolicy) SetSnapshotInterval(i uint) error {
+	no.snapshotInterval = i
+
+	return nil
+}
+
+func (no BasePolicy) SyncFromSnapshot() bool {
+	return no.syncFromSnapshot
+}
+
+func (no *BasePolicy) SetSyncFromSnapshot(b bool) error {
+	no.syncFromSnapshot = b
+
+	return nil
+}
+
+func (no BasePolicy) RetentionHeights() uint {
+	return no.retentionHeights
+}
+
+func (no *BasePolicy) SetRetentionHeights(i uint) error {
+	no.retentionHeights = i
+
+	return nil
+}
+
+func (no BasePolicy) PruneBlockdata() bool {
+	return no.pruneBlockdata
+}
+
+func (no *BasePolicy) SetPruneBlockdata(b bool) error {
+	no.pruneBlockdata = b
+
+	return nil
+}
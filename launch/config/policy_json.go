@@ -16,6 +16,10 @@ type BasePolicyPackerJSON struct {
 	IntervalBroadcastingACCEPTBallot string              `json:"interval_broadcasting_accept_ballot,omitempty"`
 	TimespanValidBallot              string              `json:"timespan_valid_ballot,omitempty"`
 	NetworkConnectionTimeout         string              `json:"network_connection_timeout,omitempty"`
+	SnapshotInterval                 uint                `json:"snapshot_interval"`
+	SyncFromSnapshot                 bool                `json:"sync_from_snapshot"`
+	RetentionHeights                 uint                `json:"retention_heights"`
+	PruneBlockdata                   bool                `json:"prune_blockdata"`
 }
 
 func (no BasePolicy) MarshalJSON() ([]byte, error) {
@@ -30,5 +34,9 @@ func (no BasePolicy) MarshalJSON() ([]byte, error) {
 		IntervalBroadcastingACCEPTBallot: no.intervalBroadcastingACCEPTBallot.String(),
 		TimespanValidBallot:              no.timespanValidBallot.String(),
 		NetworkConnectionTimeout:         no.networkConnectionTimeout.String(),
+		SnapshotInterval:                 no.snapshotInterval,
+		SyncFromSnapshot:                 no.syncFromSnapshot,
+		RetentionHeights:                 no.retentionHeights,
+		PruneBlockdata:                   no.pruneBlockdata,
 	})
 }
@@ -17,6 +17,10 @@ type BasePolicyPackerYAML struct {
 	IntervalBroadcastingACCEPTBallot time.Duration       `yaml:"interval-broadcasting-accept-ballot,omitempty"`
 	TimespanValidBallot              time.Duration       `yaml:"timespan-valid-ballot,omitempty"`
 	NetworkConnectionTimeout         time.Duration       `yaml:"network-connection-timeout,omitempty"`
+	SnapshotInterval                 uint                `yaml:"snapshot-interval"`
+	SyncFromSnapshot                 bool                `yaml:"sync-from-snapshot"`
+	RetentionHeights                 uint                `yaml:"retention-heights"`
+	PruneBlockdata                   bool                `yaml:"prune-blockdata"`
 }
 
 func (no BasePolicy) MarshalYAML() (interface{}, error) {
@@ -31,5 +35,9 @@ func (no BasePolicy) MarshalYAML() (interface{}, error) {
 		IntervalBroadcastingACCEPTBallot: no.intervalBroadcastingACCEPTBallot,
 		TimespanValidBallot:              no.timespanValidBallot,
 		NetworkConnectionTimeout:         no.networkConnectionTimeout,
+		SnapshotInterval:                 no.snapshotInterval,
+		SyncFromSnapshot:                 no.syncFromSnapshot,
+		RetentionHeights:                 no.retentionHeights,
+		PruneBlockdata:                   no.pruneBlockdata,
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/blockdata/s3"
 	"github.com/spikeekips/mitum/util/cache"
 )
 
@@ -29,13 +30,19 @@ func init() {
 	}
 }
 
+// Blockdata is the config of block data storage. Without URI, the blocks are
+// stored under Path. With URI like "s3://", the blocks are stored in the object
+// storage and Path is used for the temporary files.
 type Blockdata interface {
 	Path() string
 	SetPath(string) error
+	URI() *url.URL
+	SetURI(string) error
 }
 
 type BaseBlockdata struct {
 	path string
+	uri  *url.URL
 }
 
 func (no BaseBlockdata) Path() string {
@@ -48,6 +55,27 @@ func (no *BaseBlockdata) SetPath(s string) error {
 	return nil
 }
 
+func (no BaseBlockdata) URI() *url.URL {
+	return no.uri
+}
+
+func (no *BaseBlockdata) SetURI(s string) error {
+	u, err := network.ParseURL(s, true)
+	if err != nil {
+		return err
+	}
+
+	if u != nil {
+		if _, err := s3.NewClientFromURI(u); err != nil {
+			return err
+		}
+	}
+
+	no.uri = u
+
+	return nil
+}
+
 type Database interface {
 	URI() *url.URL
 	SetURI(string) error
@@ -11,6 +11,7 @@ type DatabasePackerJSON struct {
 
 type BlockdataPackerJSON struct {
 	Path string `json:"path"`
+	URI  string `json:"uri,omitempty"`
 }
 
 type BaseStoragePackerJSON struct {
@@ -19,6 +20,11 @@ type BaseStoragePackerJSON struct {
 }
 
 func (no BaseStorage) MarshalJSON() ([]byte, error) {
+	var uri string
+	if u := no.blockdata.URI(); u != nil {
+		uri = u.String()
+	}
+
 	return jsonenc.Marshal(BaseStoragePackerJSON{
 		Database: DatabasePackerJSON{
 			URI:   no.database.URI().String(),
@@ -26,6 +32,7 @@ func (no BaseStorage) MarshalJSON() ([]byte, error) {
 		},
 		Blockdata: BlockdataPackerJSON{
 			Path: no.blockdata.Path(),
+			URI:  uri,
 		},
 	})
 }
@@ -7,6 +7,7 @@ type DatabasePackerYAML struct {
 
 type BlockdataPackerYAML struct {
 	Path string
+	URI  string `yaml:",omitempty"`
 }
 
 type BaseStoragePackerYAML struct {
@@ -15,6 +16,11 @@ type BaseStoragePackerYAML struct {
 }
 
 func (no BaseStorage) MarshalYAML() (interface{}, error) {
+	var uri string
+	if u := no.blockdata.URI(); u != nil {
+		uri = u.String()
+	}
+
 	return BaseStoragePackerYAML{
 		Database: DatabasePackerYAML{
 			URI:   no.database.URI().String(),
@@ -22,6 +28,7 @@ func (no BaseStorage) MarshalYAML() (interface{}, error) {
 		},
 		Blockdata: BlockdataPackerYAML{
 			Path: no.blockdata.Path(),
+			URI:  uri,
 		},
 	}, nil
 }
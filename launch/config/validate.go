@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -82,16 +83,16 @@ func (va *validator) CheckLocalNetwork() (bool, error) {
 		return false, errors.Errorf("network url is missing")
 	}
 
-	if s := conf.ConnInfo().URL().Scheme; s != "https" {
-		return false, errors.Errorf("at this time, publish url only HTTPS allowed, not %q", s)
+	if s := conf.ConnInfo().URL().Scheme; !network.IsNodeURLScheme(s) {
+		return false, errors.Errorf("at this time, publish url only HTTPS or %s allowed, not %q", network.TCPURLScheme, s)
 	}
 
 	if conf.Bind() == nil {
 		return false, errors.Errorf("network bind is missing")
 	}
 
-	if s := conf.Bind().Scheme; s != "https" {
-		return false, errors.Errorf("at this time, bind url only HTTPS allowed, not %q", s)
+	if s := conf.Bind().Scheme; !network.IsNodeURLScheme(s) {
+		return false, errors.Errorf("at this time, bind url only HTTPS or %s allowed, not %q", network.TCPURLScheme, s)
 	}
 
 	return true, nil
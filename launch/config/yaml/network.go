@@ -22,6 +22,11 @@ type LocalNetwork struct {
 	Cache       *string                `yaml:",omitempty"`
 	SealCache   *string                `yaml:"seal-cache,omitempty"`
 	RateLimit   *RateLimit             `yaml:"rate-limit,omitempty"`
+	MetricsBind *string                `yaml:"metrics-bind,omitempty"`
+	QueryBind   *string                `yaml:"query-bind,omitempty"`
+	MutualTLS   *bool                  `yaml:"mutual-tls,omitempty"`
+	Gossip      *Gossip                `yaml:"gossip,omitempty"`
+	Wire        *Wire                  `yaml:"wire,omitempty"`
 	Extras      map[string]interface{} `yaml:",inline"`
 }
 
@@ -58,6 +63,36 @@ func (no LocalNetwork) Set(ctx context.Context) (context.Context, error) {
 		}
 	}
 
+	if no.MetricsBind != nil {
+		if err := conf.SetMetricsBind(*no.MetricsBind); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.QueryBind != nil {
+		if err := conf.SetQueryBind(*no.QueryBind); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.MutualTLS != nil {
+		if err := conf.SetMutualTLS(*no.MutualTLS); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.Gossip != nil {
+		if err := no.Gossip.set(conf); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.Wire != nil {
+		if err := no.Wire.set(conf); err != nil {
+			return ctx, err
+		}
+	}
+
 	if no.RateLimit != nil {
 		i, err := no.RateLimit.Set(ctx)
 		if err != nil {
@@ -103,3 +138,45 @@ func (no LocalNetwork) setCerts(conf config.LocalNetwork) error {
 
 	return conf.SetCerts([]tls.Certificate{c})
 }
+
+type Gossip struct {
+	Fanout *uint   `yaml:"fanout,omitempty"`
+	TTL    *string `yaml:"ttl,omitempty"`
+}
+
+func (no Gossip) set(conf config.LocalNetwork) error {
+	if no.Fanout != nil {
+		if err := conf.SetGossipFanout(*no.Fanout); err != nil {
+			return err
+		}
+	}
+
+	if no.TTL != nil {
+		if err := conf.SetGossipTTL(*no.TTL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type Wire struct {
+	Encoder *string `yaml:"encoder,omitempty"`
+	Compact *bool   `yaml:"compact,omitempty"`
+}
+
+func (no Wire) set(conf config.LocalNetwork) error {
+	if no.Encoder != nil {
+		if err := conf.SetWireEncoder(*no.Encoder); err != nil {
+			return err
+		}
+	}
+
+	if no.Compact != nil {
+		if err := conf.SetCompactWire(*no.Compact); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -16,6 +16,8 @@ func (t *testNetwork) TestLocalNetwork() {
 url: https://local:54321
 bind: https://0.0.0.0:54321
 cache: dummy://
+metrics-bind: http://0.0.0.0:9090
+query-bind: http://0.0.0.0:9091
 `
 
 	var n LocalNetwork
@@ -25,6 +27,42 @@ cache: dummy://
 	t.Equal("https://local:54321", *n.URL)
 	t.Equal("https://0.0.0.0:54321", *n.Bind)
 	t.Equal("dummy://", *n.Cache)
+	t.Equal("http://0.0.0.0:9090", *n.MetricsBind)
+	t.Equal("http://0.0.0.0:9091", *n.QueryBind)
+}
+
+func (t *testNetwork) TestLocalNetworkGossip() {
+	y := `
+url: https://local:54321
+gossip:
+  fanout: 3
+  ttl: 30s
+`
+
+	var n LocalNetwork
+	err := yaml.Unmarshal([]byte(y), &n)
+	t.NoError(err)
+
+	t.NotNil(n.Gossip)
+	t.Equal(uint(3), *n.Gossip.Fanout)
+	t.Equal("30s", *n.Gossip.TTL)
+}
+
+func (t *testNetwork) TestLocalNetworkWire() {
+	y := `
+url: https://local:54321
+wire:
+  encoder: bson-encoder
+  compact: true
+`
+
+	var n LocalNetwork
+	err := yaml.Unmarshal([]byte(y), &n)
+	t.NoError(err)
+
+	t.NotNil(n.Wire)
+	t.Equal("bson-encoder", *n.Wire.Encoder)
+	t.True(*n.Wire.Compact)
 }
 
 func (t *testNetwork) TestLocalNetworkEmpty() {
@@ -36,6 +74,10 @@ func (t *testNetwork) TestLocalNetworkEmpty() {
 
 	t.True(n.URL == nil)
 	t.True(n.Bind == nil)
+	t.True(n.MetricsBind == nil)
+	t.True(n.QueryBind == nil)
+	t.True(n.Gossip == nil)
+	t.True(n.Wire == nil)
 }
 
 func TestNetwork(t *testing.T) {
@@ -18,6 +18,10 @@ type Policy struct {
 	IntervalBroadcastingACCEPTBallot *string                `yaml:"interval-broadcasting-accept-ballot,omitempty"`
 	TimespanValidBallot              *string                `yaml:"timespan-valid-ballot,omitempty"`
 	NetworkConnectionTimeout         *string                `yaml:"network-connection-timeout,omitempty"`
+	SnapshotInterval                 *uint                  `yaml:"snapshot-interval,omitempty"`
+	SyncFromSnapshot                 *bool                  `yaml:"sync-from-snapshot,omitempty"`
+	RetentionHeights                 *uint                  `yaml:"retention-heights,omitempty"`
+	PruneBlockdata                   *bool                  `yaml:"prune-blockdata,omitempty"`
 	Extras                           map[string]interface{} `yaml:",inline"`
 }
 
@@ -34,6 +38,18 @@ func (no Policy) Set(ctx context.Context) (context.Context, error) {
 		}
 	}
 
+	if no.SyncFromSnapshot != nil {
+		if err := conf.SetSyncFromSnapshot(*no.SyncFromSnapshot); err != nil {
+			return ctx, err
+		}
+	}
+
+	if no.PruneBlockdata != nil {
+		if err := conf.SetPruneBlockdata(*no.PruneBlockdata); err != nil {
+			return ctx, err
+		}
+	}
+
 	if err := no.setUints(conf); err != nil {
 		return ctx, err
 	}
@@ -49,6 +65,8 @@ func (no Policy) setUints(conf config.Policy) error {
 	uintCol := [][2]interface{}{
 		{no.MaxOperationsInSeal, conf.SetMaxOperationsInSeal},
 		{no.MaxOperationsInProposal, conf.SetMaxOperationsInProposal},
+		{no.SnapshotInterval, conf.SetSnapshotInterval},
+		{no.RetentionHeights, conf.SetRetentionHeights},
 	}
 
 	for i := range uintCol {
@@ -8,6 +8,7 @@ import (
 
 type Blockdata struct {
 	Path *string
+	URI  *string `yaml:",omitempty"`
 }
 
 type Database struct {
@@ -62,6 +63,11 @@ func (no Storage) Set(ctx context.Context) (context.Context, error) {
 				return ctx, err
 			}
 		}
+		if no.Blockdata.URI != nil {
+			if err := conf.Blockdata().SetURI(*no.Blockdata.URI); err != nil {
+				return ctx, err
+			}
+		}
 	}
 
 	return ctx, nil
@@ -0,0 +1,475 @@
+package deploy
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+var (
+	DefaultBlockdataScrubberInterval = time.Second * 10
+	DefaultBlockdataScrubberLimit    = 10
+)
+
+const (
+	ScrubProblemMissingMap   = "missing-map"
+	ScrubProblemMissingFile  = "missing-file"
+	ScrubProblemChecksum     = "checksum"
+	ScrubProblemInvalidBlock = "invalid-block"
+	ScrubProblemUnknown      = "unknown"
+)
+
+// ScrubFinding is the problem of block data found by BlockdataScrubber.
+type ScrubFinding struct {
+	Height      base.Height `json:"height"`
+	DataType    string      `json:"data_type,omitempty"`
+	Problem     string      `json:"problem"`
+	Message     string      `json:"message,omitempty"`
+	Repaired    bool        `json:"repaired"`
+	RepairError string      `json:"repair_error,omitempty"`
+	FoundAt     time.Time   `json:"found_at"`
+}
+
+// BlockdataScrubberStatus is the current status of BlockdataScrubber.
+type BlockdataScrubberStatus struct {
+	Next      base.Height    `json:"next"`
+	Rounds    uint64         `json:"rounds"`
+	LastRound time.Time      `json:"last_round"`
+	Findings  []ScrubFinding `json:"findings"`
+}
+
+// BlockdataScrubber walks the heights of localfs.Blockdata in background and
+// checks the block data files with the BlockdataMap of database; the checksum
+// of files, voteproofs and the tree roots of block. The problematic block data
+// is repaired by fetching it from the other suffrage nodes. The findings of
+// the last round are kept until the height is scrubbed again.
+type BlockdataScrubber struct {
+	sync.RWMutex
+	*logging.Logging
+	*util.ContextDaemon
+	db        storage.Database
+	bd        *localfs.Blockdata
+	networkID base.NetworkID
+	channels  func() []network.Channel
+	interval  time.Duration
+	limit     int
+	next      base.Height
+	rounds    uint64
+	lastRound time.Time
+	findings  map[base.Height][]ScrubFinding
+}
+
+func NewBlockdataScrubber(
+	db storage.Database,
+	bd *localfs.Blockdata,
+	networkID base.NetworkID,
+	channels func() []network.Channel,
+	interval time.Duration,
+	limit int,
+) *BlockdataScrubber {
+	if channels == nil {
+		channels = func() []network.Channel { return nil }
+	}
+
+	sc := &BlockdataScrubber{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "blockdata-scrubber")
+		}),
+		db:        db,
+		bd:        bd,
+		networkID: networkID,
+		channels:  channels,
+		interval:  interval,
+		limit:     limit,
+		next:      base.PreGenesisHeight,
+		findings:  map[base.Height][]ScrubFinding{},
+	}
+	sc.ContextDaemon = util.NewContextDaemon("blockdata-scrubber", sc.start)
+
+	return sc
+}
+
+func (sc *BlockdataScrubber) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = sc.ContextDaemon.SetLogging(l)
+
+	return sc.Logging.SetLogging(l)
+}
+
+func (sc *BlockdataScrubber) start(ctx context.Context) error {
+	ticker := time.NewTicker(sc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := sc.scrub(ctx); err != nil {
+				sc.Log().Error().Err(err).Msg("failed to scrub")
+			}
+		}
+	}
+}
+
+// Status returns the current status and the findings sorted by height.
+func (sc *BlockdataScrubber) Status() BlockdataScrubberStatus {
+	sc.RLock()
+	defer sc.RUnlock()
+
+	var findings []ScrubFinding
+	for height := range sc.findings {
+		findings = append(findings, sc.findings[height]...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Height < findings[j].Height
+	})
+
+	return BlockdataScrubberStatus{
+		Next:      sc.next,
+		Rounds:    sc.rounds,
+		LastRound: sc.lastRound,
+		Findings:  findings,
+	}
+}
+
+// scrub checks the next heights up to the limit. After the last block is
+// checked, it starts again from base.PreGenesisHeight.
+func (sc *BlockdataScrubber) scrub(ctx context.Context) error {
+	var last base.Height
+	switch m, found, err := sc.db.LastManifest(); {
+	case err != nil:
+		return err
+	case !found:
+		return nil
+	default:
+		last = m.Height()
+	}
+
+	sc.RLock()
+	from := sc.next
+	sc.RUnlock()
+
+	if from > last {
+		from = base.PreGenesisHeight
+	}
+
+	to := from + base.Height(int64(sc.limit)) - 1
+	if to > last {
+		to = last
+	}
+
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if _, err := sc.ScrubHeight(ctx, height); err != nil {
+			return err
+		}
+
+		sc.Lock()
+		sc.next = height + 1
+		sc.Unlock()
+	}
+
+	if to == last {
+		sc.Lock()
+		sc.next = base.PreGenesisHeight
+		sc.rounds++
+		sc.lastRound = localtime.UTCNow()
+		sc.Unlock()
+
+		sc.Log().Debug().Uint64("rounds", sc.rounds).Int64("last", last.Int64()).Msg("scrubbed all heights")
+	}
+
+	return nil
+}
+
+// ScrubHeight checks the block data of the height and repairs it if
+// problematic. The returned findings are also kept for Status.
+func (sc *BlockdataScrubber) ScrubHeight(ctx context.Context, height base.Height) ([]ScrubFinding, error) {
+	l := sc.Log().With().Int64("height", height.Int64()).Logger()
+
+	findings, err := sc.check(height)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(findings) > 0 {
+		l.Error().Interface("findings", findings).Msg("problematic block data found")
+
+		repairError := sc.repair(ctx, height)
+		for i := range findings {
+			if repairError == nil {
+				findings[i].Repaired = true
+			} else {
+				findings[i].RepairError = repairError.Error()
+			}
+		}
+
+		if repairError != nil {
+			l.Error().Err(repairError).Msg("failed to repair block data")
+		} else {
+			l.Info().Msg("block data repaired")
+		}
+	}
+
+	sc.Lock()
+	if len(findings) < 1 {
+		delete(sc.findings, height)
+	} else {
+		sc.findings[height] = findings
+	}
+	sc.Unlock()
+
+	return findings, nil
+}
+
+func (sc *BlockdataScrubber) check(height base.Height) ([]ScrubFinding, error) {
+	newFinding := func(dataType, problem string, err error) ScrubFinding {
+		f := ScrubFinding{Height: height, DataType: dataType, Problem: problem, FoundAt: localtime.UTCNow()}
+		if err != nil {
+			f.Message = err.Error()
+		}
+
+		return f
+	}
+
+	var bdm block.BaseBlockdataMap
+	switch i, found, err := sc.db.BlockdataMap(height); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return []ScrubFinding{newFinding("", ScrubProblemMissingMap, nil)}, nil
+	case !i.IsLocal(): // NOTE remote block data is not scrubbed
+		return nil, nil
+	default:
+		j, ok := i.(block.BaseBlockdataMap)
+		if !ok {
+			return nil, errors.Errorf("unknown blockdata map, %T", i)
+		}
+
+		bdm = j
+	}
+
+	switch found, removed, err := sc.bd.ExistsReal(height); {
+	case err != nil:
+		return nil, err
+	case removed: // NOTE removed by BlockdataCleaner
+		return nil, nil
+	case !found:
+		switch pruned, err := storage.PrunedHeight(sc.db); {
+		case err != nil:
+			return nil, err
+		case height < pruned:
+			return nil, nil
+		}
+	}
+
+	var findings []ScrubFinding
+	for i := range block.Blockdata {
+		dataType := block.Blockdata[i]
+
+		problem, err := sc.checkItem(bdm, dataType)
+		if err != nil {
+			findings = append(findings, newFinding(dataType, problem, err))
+		}
+	}
+
+	if len(findings) > 0 {
+		return findings, nil
+	}
+
+	if err := sc.checkBlock(bdm); err != nil {
+		return []ScrubFinding{newFinding("", ScrubProblemInvalidBlock, err)}, nil
+	}
+
+	return nil, nil
+}
+
+func (sc *BlockdataScrubber) checkItem(bdm block.BaseBlockdataMap, dataType string) (string, error) {
+	item, found := bdm.Item(dataType)
+	if !found {
+		return ScrubProblemMissingFile, errors.Errorf("not found in blockdata map")
+	}
+
+	u, err := network.ParseURL(item.URL(), false)
+	if err != nil {
+		return ScrubProblemUnknown, err
+	}
+
+	f, err := sc.bd.FS().Open(u.Path)
+	if err != nil {
+		return ScrubProblemMissingFile, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	switch checksum, err := util.GenerateChecksum(f); {
+	case err != nil:
+		return ScrubProblemUnknown, err
+	case checksum != item.Checksum():
+		return ScrubProblemChecksum, errors.Errorf("checksum does not match; %q != %q", checksum, item.Checksum())
+	default:
+		return "", nil
+	}
+}
+
+func (sc *BlockdataScrubber) checkBlock(bdm block.BlockdataMap) error {
+	_, blk, err := localfs.LoadBlock(sc.bd, bdm.Height())
+	if err != nil {
+		return err
+	}
+
+	if !blk.Hash().Equal(bdm.Block()) {
+		return errors.Errorf("block hash does not match with blockdata map")
+	}
+
+	return blk.IsValid(sc.networkID)
+}
+
+// repair fetches the block data of the height from the suffrage nodes one by
+// one; the block data of the first node, which has the same block, is saved
+// and the BlockdataMap of database is updated.
+func (sc *BlockdataScrubber) repair(ctx context.Context, height base.Height) error {
+	var m block.Manifest
+	switch i, found, err := sc.db.ManifestByHeight(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("manifest, %d not found", height)
+	default:
+		m = i
+	}
+
+	chs := sc.channels()
+	if len(chs) < 1 {
+		return errors.Errorf("no suffrage nodes to repair")
+	}
+
+	var errs []error
+	for i := range chs {
+		ch := chs[i]
+
+		if err := sc.repairFromChannel(ctx, ch, m); err != nil {
+			sc.Log().Debug().Err(err).Stringer("channel", ch.ConnInfo()).Int64("height", height.Int64()).
+				Msg("failed to repair from channel")
+
+			errs = append(errs, err)
+
+			continue
+		}
+
+		return nil
+	}
+
+	return errors.Errorf("failed to repair from suffrage nodes: %v", errs)
+}
+
+func (sc *BlockdataScrubber) repairFromChannel(ctx context.Context, ch network.Channel, m block.Manifest) error {
+	var bdm block.BlockdataMap
+	switch i, err := ch.BlockdataMaps(ctx, []base.Height{m.Height()}); {
+	case err != nil:
+		return err
+	case len(i) != 1 || i[0] == nil:
+		return util.NotFoundError.Errorf("blockdata map not found")
+	case !i[0].Block().Equal(m.Hash()):
+		return errors.Errorf("different block found")
+	default:
+		bdm = i[0]
+	}
+
+	ss, err := sc.bd.NewSession(m.Height())
+	if err != nil {
+		return err
+	}
+
+	if err := sc.importItems(ctx, ch, bdm, ss, m); err != nil {
+		_ = ss.Cancel()
+
+		return err
+	}
+
+	nbdm, err := sc.bd.SaveSession(ss)
+	if err != nil {
+		_ = ss.Cancel()
+
+		return err
+	}
+
+	if err := sc.checkBlock(nbdm); err != nil {
+		return errors.Wrap(err, "repaired block is invalid")
+	}
+
+	return sc.db.SetBlockdataMaps([]block.BlockdataMap{nbdm})
+}
+
+func (*BlockdataScrubber) importItems(
+	ctx context.Context,
+	ch network.Channel,
+	bdm block.BlockdataMap,
+	ss blockdata.Session,
+	m block.Manifest,
+) error {
+	items := []block.BlockdataMapItem{
+		bdm.Operations(),
+		bdm.OperationsTree(),
+		bdm.States(),
+		bdm.StatesTree(),
+		bdm.INITVoteproof(),
+		bdm.ACCEPTVoteproof(),
+		bdm.SuffrageInfo(),
+		bdm.Proposal(),
+	}
+
+	for i := range items {
+		item := items[i]
+
+		if err := func() error {
+			var r io.ReadCloser
+			if block.IsLocalBlockdataItem(item.URL()) {
+				j, err := ch.Blockdata(ctx, item)
+				if err != nil {
+					return err
+				}
+				r = j
+			} else {
+				j, err := network.FetchBlockdataFromRemote(ctx, item)
+				if err != nil {
+					return err
+				}
+				r = j
+			}
+
+			defer func() {
+				_ = r.Close()
+			}()
+
+			_, err := ss.Import(item.Type(), r)
+
+			return err
+		}(); err != nil {
+			return errors.Wrapf(err, "failed to import %q", item.Type())
+		}
+	}
+
+	// NOTE manifest is written from database
+	return ss.SetManifest(m)
+}
@@ -0,0 +1,166 @@
+package deploy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockdataScrubber struct {
+	isaac.BaseTest
+	local  *isaac.Local
+	remote *isaac.Local
+}
+
+func (t *testBlockdataScrubber) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	ls := t.Locals(2)
+	t.SetupNodes(ls[0], ls[1:])
+
+	t.local = ls[0]
+	t.remote = ls[1]
+}
+
+func (t *testBlockdataScrubber) newScrubber(channels func() []network.Channel) *BlockdataScrubber {
+	return NewBlockdataScrubber(
+		t.local.Database(),
+		t.local.Blockdata().(*localfs.Blockdata),
+		t.local.Policy().NetworkID(),
+		channels,
+		time.Second,
+		10,
+	)
+}
+
+func (t *testBlockdataScrubber) remoteChannels() []network.Channel {
+	return []network.Channel{t.remote.Channel()}
+}
+
+func (t *testBlockdataScrubber) itemPath(height base.Height, dataType string) string {
+	bdm, found, err := t.local.Database().BlockdataMap(height)
+	t.NoError(err)
+	t.True(found)
+
+	item, found := bdm.(block.BaseBlockdataMap).Item(dataType)
+	t.True(found)
+
+	u, err := network.ParseURL(item.URL(), false)
+	t.NoError(err)
+
+	return filepath.Join(t.local.Blockdata().(*localfs.Blockdata).Root(), u.Path)
+}
+
+func (t *testBlockdataScrubber) TestHealthy() {
+	sc := t.newScrubber(t.remoteChannels)
+
+	last := t.LastManifest(t.local.Database()).Height()
+	for height := base.PreGenesisHeight; height <= last; height++ {
+		findings, err := sc.ScrubHeight(context.Background(), height)
+		t.NoError(err)
+		t.Empty(findings)
+	}
+
+	t.Empty(sc.Status().Findings)
+}
+
+func (t *testBlockdataScrubber) TestRepairChecksum() {
+	sc := t.newScrubber(t.remoteChannels)
+
+	height := t.LastManifest(t.local.Database()).Height()
+	t.NoError(os.WriteFile(t.itemPath(height, block.BlockdataOperations), []byte("showme"), 0o600))
+
+	findings, err := sc.ScrubHeight(context.Background(), height)
+	t.NoError(err)
+	t.Equal(1, len(findings))
+	t.Equal(ScrubProblemChecksum, findings[0].Problem)
+	t.Equal(block.BlockdataOperations, findings[0].DataType)
+	t.True(findings[0].Repaired)
+	t.Empty(findings[0].RepairError)
+
+	_, blk, err := localfs.LoadBlock(t.local.Blockdata().(*localfs.Blockdata), height)
+	t.NoError(err)
+	t.True(t.LastManifest(t.local.Database()).Hash().Equal(blk.Hash()))
+
+	// NOTE scrub again
+	findings, err = sc.ScrubHeight(context.Background(), height)
+	t.NoError(err)
+	t.Empty(findings)
+}
+
+func (t *testBlockdataScrubber) TestRepairMissingFile() {
+	sc := t.newScrubber(t.remoteChannels)
+
+	height := t.LastManifest(t.local.Database()).Height()
+	t.NoError(os.Remove(t.itemPath(height, block.BlockdataACCEPTVoteproof)))
+
+	findings, err := sc.ScrubHeight(context.Background(), height)
+	t.NoError(err)
+	t.Equal(1, len(findings))
+	t.Equal(ScrubProblemMissingFile, findings[0].Problem)
+	t.True(findings[0].Repaired)
+
+	findings, err = sc.ScrubHeight(context.Background(), height)
+	t.NoError(err)
+	t.Empty(findings)
+}
+
+func (t *testBlockdataScrubber) TestWithoutChannels() {
+	sc := t.newScrubber(nil)
+
+	height := t.LastManifest(t.local.Database()).Height()
+	t.NoError(os.WriteFile(t.itemPath(height, block.BlockdataStates), []byte("showme"), 0o600))
+
+	findings, err := sc.ScrubHeight(context.Background(), height)
+	t.NoError(err)
+	t.Equal(1, len(findings))
+	t.False(findings[0].Repaired)
+	t.Contains(findings[0].RepairError, "no suffrage nodes")
+
+	// NOTE findings are reported thru handler
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	NewBlockdataScrubberHandler(sc)(w, r)
+
+	res := w.Result()
+	t.Equal(http.StatusOK, res.StatusCode)
+
+	b, err := io.ReadAll(res.Body)
+	t.NoError(err)
+
+	var status BlockdataScrubberStatus
+	t.NoError(jsonenc.Unmarshal(b, &status))
+
+	t.Equal(1, len(status.Findings))
+	t.Equal(height, status.Findings[0].Height)
+	t.Equal(ScrubProblemChecksum, status.Findings[0].Problem)
+}
+
+func (t *testBlockdataScrubber) TestScrubRounds() {
+	sc := t.newScrubber(t.remoteChannels)
+
+	t.NoError(sc.scrub(context.Background()))
+
+	status := sc.Status()
+	t.Equal(uint64(1), status.Rounds)
+	t.Equal(base.PreGenesisHeight, status.Next)
+	t.Empty(status.Findings)
+}
+
+func TestBlockdataScrubber(t *testing.T) {
+	suite.Run(t, new(testBlockdataScrubber))
+}
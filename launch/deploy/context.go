@@ -7,9 +7,10 @@ import (
 )
 
 var (
-	ContextValueDeployKeyStorage util.ContextKey = "deploy_key_storage"
-	ContextValueBlockdataCleaner util.ContextKey = "blockdata_cleaner"
-	ContextValueDeployHandler    util.ContextKey = "deploy_handler"
+	ContextValueDeployKeyStorage  util.ContextKey = "deploy_key_storage"
+	ContextValueBlockdataCleaner  util.ContextKey = "blockdata_cleaner"
+	ContextValueBlockdataScrubber util.ContextKey = "blockdata_scrubber"
+	ContextValueDeployHandler     util.ContextKey = "deploy_handler"
 )
 
 func LoadDeployKeyStorageContextValue(ctx context.Context, l **DeployKeyStorage) error {
@@ -20,6 +21,10 @@ func LoadBlockdataCleanerContextValue(ctx context.Context, l **BlockdataCleaner)
 	return util.LoadFromContextValue(ctx, ContextValueBlockdataCleaner, l)
 }
 
+func LoadBlockdataScrubberContextValue(ctx context.Context, l **BlockdataScrubber) error {
+	return util.LoadFromContextValue(ctx, ContextValueBlockdataScrubber, l)
+}
+
 func LoadDeployHandler(ctx context.Context, l **DeployHandlers) error {
 	return util.LoadFromContextValue(ctx, ContextValueDeployHandler, l)
 }
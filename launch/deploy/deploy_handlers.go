@@ -16,9 +16,20 @@ import (
 	"github.com/ulule/limiter/v3"
 )
 
-var QuicHandlerPathSetBlockdataMaps = "/_deploy/blockdatamaps"
+var (
+	QuicHandlerPathDeployPrefix     = "/_deploy"
+	QuicHandlerPathSetBlockdataMaps = "/_deploy/blockdatamaps"
+	QuicHandlerPathBackup           = "/_deploy/backup"
+	QuicHandlerPathBlockdataScrub   = "/_deploy/blockdata/scrub"
+	QuicHandlerPathPeerBans         = "/_deploy/peers/bans"
+)
 
-var RateLimitHandlerNameSetBlockdataMaps = "set-blockdatamaps"
+var (
+	RateLimitHandlerNameSetBlockdataMaps = "set-blockdatamaps"
+	RateLimitHandlerNameBackup           = "backup"
+	RateLimitHandlerNameBlockdataScrub   = "blockdata-scrub"
+	RateLimitHandlerNamePeerBans         = "peer-bans"
+)
 
 type BaseDeployHandler struct {
 	*logging.Logging
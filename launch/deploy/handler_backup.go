@@ -0,0 +1,89 @@
+package deploy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+// BackupManifestHeader is the response header of backup handler; it contains
+// the blockdata.BackupManifest in json.
+var BackupManifestHeader = "X-MITUM-BACKUP-MANIFEST"
+
+// NewBackupHandler returns the handler, which creates the backup of the
+// database and block data up to the last block and responds with the archive.
+// The consensus keeps running while backup. Only one backup can be run at the
+// same time.
+func NewBackupHandler(db storage.Database, bd blockdata.Blockdata) network.HTTPHandlerFunc {
+	var running int32
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+			network.WriteProblemWithError(w, http.StatusConflict, errors.Errorf("backup is already running"))
+
+			return
+		}
+		defer atomic.StoreInt32(&running, 0)
+
+		dir, err := os.MkdirTemp("", "mitum-backup-")
+		if err != nil {
+			network.WriteProblemWithError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		defer func() {
+			_ = os.RemoveAll(dir)
+		}()
+
+		if err := writeBackup(r.Context(), w, db, bd, dir); err != nil {
+			network.WriteProblemWithError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+	}
+}
+
+func writeBackup(
+	ctx context.Context,
+	w http.ResponseWriter,
+	db storage.Database,
+	bd blockdata.Blockdata,
+	dir string,
+) error {
+	bm, err := blockdata.BackupToDirectory(ctx, db, bd, dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to backup")
+	}
+
+	b, err := jsonenc.Marshal(bm)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Clean(filepath.Join(dir, bm.Archive)))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w.Header().Set(BackupManifestHeader, string(b))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = io.Copy(w, f)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package deploy
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBackup struct {
+	isaac.BaseTest
+	local *isaac.Local
+}
+
+func (t *testBackup) SetupTest() {
+	t.BaseTest.SetupTest()
+	t.local = t.Locals(1)[0]
+}
+
+func (t *testBackup) backup() (blockdata.BackupManifest, []byte) {
+	handler := NewBackupHandler(t.local.Database(), t.local.Blockdata())
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+
+	handler(w, r)
+
+	res := w.Result()
+	t.Equal(http.StatusOK, res.StatusCode)
+
+	var bm blockdata.BackupManifest
+	t.NoError(jsonenc.Unmarshal([]byte(res.Header.Get(BackupManifestHeader)), &bm))
+
+	b, err := io.ReadAll(res.Body)
+	t.NoError(err)
+
+	return bm, b
+}
+
+func (t *testBackup) save(dir string, bm blockdata.BackupManifest, b []byte) {
+	t.NoError(os.WriteFile(filepath.Join(dir, bm.Archive), b, 0o600))
+	t.NoError(blockdata.SaveBackupManifest(dir, bm))
+}
+
+func (t *testBackup) TestNew() {
+	m, found, err := t.local.Database().LastManifest()
+	t.NoError(err)
+	t.True(found)
+
+	bm, b := t.backup()
+
+	t.Equal(base.PreGenesisHeight, bm.From)
+	t.Equal(m.Height(), bm.To)
+	t.True(m.Hash().Equal(bm.Block))
+	t.Equal(blockdata.BackupArchiveFile, bm.Archive)
+	t.Equal(int64(len(b)), bm.Size)
+
+	dir := t.T().TempDir()
+	t.save(dir, bm, b)
+
+	ubm, f, ar, err := blockdata.LoadBackup(dir, t.local.Blockdata().Writer())
+	t.NoError(err)
+	defer f.Close()
+
+	t.Equal(bm.Checksum, ubm.Checksum)
+
+	for height := bm.From; height <= bm.To; height++ {
+		blk, err := ar.Block(height)
+		t.NoError(err)
+
+		um, found, err := t.local.Database().ManifestByHeight(height)
+		t.NoError(err)
+		t.True(found)
+
+		t.True(um.Hash().Equal(blk.Hash()))
+	}
+}
+
+func (t *testBackup) TestTampered() {
+	bm, b := t.backup()
+
+	dir := t.T().TempDir()
+
+	tampered := bytes.Repeat([]byte{0}, len(b))
+	t.save(dir, bm, tampered)
+
+	_, _, _, err := blockdata.LoadBackup(dir, t.local.Blockdata().Writer())
+	t.Error(err)
+	t.Contains(err.Error(), "checksum of backup archive does not match")
+}
+
+func (t *testBackup) TestWrongSize() {
+	bm, b := t.backup()
+
+	dir := t.T().TempDir()
+	t.save(dir, bm, b[:len(b)-1])
+
+	_, _, _, err := blockdata.LoadBackup(dir, t.local.Blockdata().Writer())
+	t.Error(err)
+	t.Contains(err.Error(), "size of backup archive does not match")
+}
+
+func TestBackup(t *testing.T) {
+	suite.Run(t, new(testBackup))
+}
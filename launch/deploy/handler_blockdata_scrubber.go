@@ -0,0 +1,25 @@
+package deploy
+
+import (
+	"net/http"
+
+	"github.com/spikeekips/mitum/network"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+// NewBlockdataScrubberHandler returns the handler, which responds with the
+// status and findings of BlockdataScrubber.
+func NewBlockdataScrubberHandler(sc *BlockdataScrubber) network.HTTPHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b, err := jsonenc.Marshal(sc.Status())
+		if err != nil {
+			network.WriteProblemWithError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write(b)
+	}
+}
@@ -0,0 +1,47 @@
+package deploy
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+)
+
+// NewPeerBansHandler returns the handler for the banned peers. GET responds
+// with the banned peers. DELETE clears the ban of the peer given by "peer"
+// query, or all the bans and penalties without "peer".
+func NewPeerBansHandler(ps *network.PeerScores) network.HTTPHandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			b, err := jsonenc.Marshal(ps.Bans())
+			if err != nil {
+				network.WriteProblemWithError(w, http.StatusInternalServerError, err)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+
+			_, _ = w.Write(b)
+		case "DELETE":
+			peer := r.URL.Query().Get("peer")
+			if len(peer) < 1 {
+				ps.Clear()
+
+				return
+			}
+
+			if !ps.Unban(peer) {
+				network.WriteProblemWithError(w, http.StatusNotFound,
+					util.NotFoundError.Wrap(errors.Errorf("peer, %q not banned", peer)))
+
+				return
+			}
+		default:
+			network.HTTPError(w, http.StatusMethodNotAllowed)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package deploy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/network"
+	"github.com/stretchr/testify/suite"
+)
+
+type testPeerBansHandler struct {
+	suite.Suite
+}
+
+func (t *testPeerBansHandler) request(handler http.HandlerFunc, method, path string) *http.Response {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+	return w.Result()
+}
+
+func (t *testPeerBansHandler) bans(handler http.HandlerFunc) []network.PeerBan {
+	res := t.request(handler, "GET", QuicHandlerPathPeerBans)
+	defer res.Body.Close()
+
+	t.Equal(http.StatusOK, res.StatusCode)
+
+	b, err := ioutil.ReadAll(res.Body)
+	t.NoError(err)
+
+	var bans []network.PeerBan
+	t.NoError(json.Unmarshal(b, &bans))
+
+	return bans
+}
+
+func (t *testPeerBansHandler) TestList() {
+	ps := network.NewDefaultPeerScores()
+	handler := http.HandlerFunc(NewPeerBansHandler(ps))
+
+	t.Empty(t.bans(handler))
+
+	ps.Ban("a", "findme", time.Minute)
+	ps.Ban("b", "showme", time.Minute)
+
+	bans := t.bans(handler)
+	t.Equal(2, len(bans))
+	t.Equal("a", bans[0].Peer)
+	t.Equal("findme", bans[0].Reason)
+	t.Equal("b", bans[1].Peer)
+	t.Equal("showme", bans[1].Reason)
+}
+
+func (t *testPeerBansHandler) TestClear() {
+	ps := network.NewDefaultPeerScores()
+	handler := http.HandlerFunc(NewPeerBansHandler(ps))
+
+	ps.Ban("a", "findme", time.Minute)
+	ps.Ban("b", "showme", time.Minute)
+
+	res := t.request(handler, "DELETE", QuicHandlerPathPeerBans+"?peer=a")
+	t.Equal(http.StatusOK, res.StatusCode)
+	t.False(ps.IsBanned("a"))
+	t.True(ps.IsBanned("b"))
+
+	// NOTE unknown peer
+	res = t.request(handler, "DELETE", QuicHandlerPathPeerBans+"?peer=a")
+	t.Equal(http.StatusNotFound, res.StatusCode)
+
+	res = t.request(handler, "DELETE", QuicHandlerPathPeerBans)
+	t.Equal(http.StatusOK, res.StatusCode)
+	t.Empty(ps.Bans())
+
+	res = t.request(handler, "POST", QuicHandlerPathPeerBans)
+	t.Equal(http.StatusMethodNotAllowed, res.StatusCode)
+}
+
+func TestPeerBansHandler(t *testing.T) {
+	suite.Run(t, new(testPeerBansHandler))
+}
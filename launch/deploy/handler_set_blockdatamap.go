@@ -56,10 +56,12 @@ func NewSetBlockdataMapsHandler(
 			i++
 		}
 
-		b, err := jsonenc.Marshal(map[string]interface{}{
-			"heights":       heights,
-			"expected_time": localtime.UTCNow().Add(bc.RemoveAfter()),
-		})
+		body := map[string]interface{}{"heights": heights}
+		if bc != nil {
+			body["expected_time"] = localtime.UTCNow().Add(bc.RemoveAfter())
+		}
+
+		b, err := jsonenc.Marshal(body)
 		if err != nil {
 			network.WriteProblemWithError(w, http.StatusInternalServerError, err)
 			return
@@ -140,11 +142,17 @@ func checkBlockdataMap(db storage.Database, bdm block.BlockdataMap) error {
 	return nil
 }
 
+// commitBlockdataMaps stores the block data maps. If BlockdataCleaner is given,
+// the local block data of the remote maps will be removed.
 func commitBlockdataMaps(db storage.Database, bc *BlockdataCleaner, bdms []block.BlockdataMap) error {
 	if err := db.SetBlockdataMaps(bdms); err != nil {
 		return err
 	}
 
+	if bc == nil {
+		return nil
+	}
+
 	wk := util.NewErrgroupWorker(context.Background(), 100)
 	defer wk.Close()
 
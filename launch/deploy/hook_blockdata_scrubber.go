@@ -0,0 +1,91 @@
+package deploy
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/launch/process"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+var HookNameBlockdataScrubber = "blockdata_scrubber"
+
+func HookBlockdataScrubber(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var lbd *localfs.Blockdata
+	var bd blockdata.Blockdata
+	if err := process.LoadBlockdataContextValue(ctx, &bd); err != nil {
+		return ctx, err
+	} else if i, ok := bd.(*localfs.Blockdata); !ok {
+		log.Log().Debug().Str("blockdata", bd.Hint().String()).Msg("BlockdataScrubber skipped for non-localfs blockdata")
+
+		return ctx, nil
+	} else {
+		lbd = i
+	}
+
+	var db storage.Database
+	if err := process.LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := process.LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var suffrage base.Suffrage
+	if err := process.LoadSuffrageContextValue(ctx, &suffrage); err != nil {
+		return ctx, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := process.LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return ctx, err
+	}
+
+	channels := func() []network.Channel {
+		local := nodepool.LocalNode().Address()
+
+		var chs []network.Channel
+		nodes := suffrage.Nodes()
+		for i := range nodes {
+			if nodes[i].Equal(local) {
+				continue
+			}
+
+			if ch, found := nodepool.Channel(nodes[i]); found && ch != nil {
+				chs = append(chs, ch)
+			}
+		}
+
+		return chs
+	}
+
+	sc := NewBlockdataScrubber(
+		db, lbd, policy.NetworkID(), channels,
+		DefaultBlockdataScrubberInterval, DefaultBlockdataScrubberLimit,
+	)
+	_ = sc.SetLogging(log)
+
+	if err := sc.Start(); err != nil {
+		return ctx, err
+	}
+
+	log.Log().Debug().
+		Dur("interval", DefaultBlockdataScrubberInterval).
+		Int("limit", DefaultBlockdataScrubberLimit).
+		Msg("BlockdataScrubber created")
+
+	return context.WithValue(ctx, ContextValueBlockdataScrubber, sc), nil
+}
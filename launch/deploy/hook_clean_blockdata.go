@@ -23,6 +23,10 @@ func HookBlockdataCleaner(ctx context.Context) (context.Context, error) {
 	var bd blockdata.Blockdata
 	if err := process.LoadBlockdataContextValue(ctx, &bd); err != nil {
 		return ctx, err
+	} else if !bd.IsLocal() { // NOTE remote blockdata does not need to be cleaned
+		log.Log().Debug().Str("blockdata", bd.Hint().String()).Msg("BlockdataCleaner skipped for remote blockdata")
+
+		return ctx, nil
 	} else if i, ok := bd.(*localfs.Blockdata); !ok {
 		return ctx, errors.Errorf("to clean blockdata, needs localfs.Blockdata, not %T", bd)
 	} else {
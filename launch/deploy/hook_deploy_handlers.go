@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/process"
 	"github.com/spikeekips/mitum/network"
 	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -34,6 +37,10 @@ func HookDeployHandlers(ctx context.Context) (context.Context, error) {
 		qnt = i
 	}
 
+	// NOTE deploy handlers are authenticated by deploy key, not by node
+	// certificate.
+	qnt.AddPublicHandlers(QuicHandlerPathDeployPrefix)
+
 	if i, err := newDeployKeyHandlers(ctx, qnt.Handler); err != nil {
 		return ctx, err
 	} else if err := i.setHandlers(); err != nil {
@@ -56,7 +63,9 @@ func hookDefaultDeployHandlers(ctx context.Context, qnt *quicnetwork.Server) (co
 
 	var bc *BlockdataCleaner
 	if err := LoadBlockdataCleanerContextValue(ctx, &bc); err != nil {
-		return ctx, err
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, err
+		}
 	}
 
 	setBlockdataMapsHandler := http.HandlerFunc(NewSetBlockdataMapsHandler(qnt.Encoder(), db, bc))
@@ -65,5 +74,39 @@ func hookDefaultDeployHandlers(ctx context.Context, qnt *quicnetwork.Server) (co
 		dh.RateLimit(RateLimitHandlerNameSetBlockdataMaps, setBlockdataMapsHandler),
 	)
 
+	var bd blockdata.Blockdata
+	if err := process.LoadBlockdataContextValue(ctx, &bd); err != nil {
+		return ctx, err
+	}
+
+	backupHandler := http.HandlerFunc(NewBackupHandler(db, bd))
+	_ = dh.SetHandler(
+		QuicHandlerPathBackup,
+		dh.RateLimit(RateLimitHandlerNameBackup, backupHandler),
+	)
+
+	var sc *BlockdataScrubber
+	switch err := LoadBlockdataScrubberContextValue(ctx, &sc); {
+	case err == nil:
+		scrubberHandler := http.HandlerFunc(NewBlockdataScrubberHandler(sc))
+		_ = dh.SetHandler(
+			QuicHandlerPathBlockdataScrub,
+			dh.RateLimit(RateLimitHandlerNameBlockdataScrub, scrubberHandler),
+		)
+	case !errors.Is(err, util.ContextValueNotFoundError):
+		return ctx, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := process.LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return ctx, err
+	}
+
+	peerBansHandler := http.HandlerFunc(NewPeerBansHandler(nodepool.PeerScores()))
+	_ = dh.SetHandler(
+		QuicHandlerPathPeerBans,
+		dh.RateLimit(RateLimitHandlerNamePeerBans, peerBansHandler),
+	)
+
 	return context.WithValue(ctx, ContextValueDeployHandler, dh), nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/spikeekips/mitum/base/node"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/tree"
@@ -26,12 +27,25 @@ var EncoderTypes = []hint.Type{
 	base.StringAddressType,
 	base.VoteproofV0Type,
 	block.BaseBlockdataMapType,
+	block.BaseSnapshotHeaderType,
 	block.BlockConsensusInfoV0Type,
 	block.BlockV0Type,
 	block.ManifestV0Type,
 	block.SuffrageInfoV0Type,
+	isaac.JoinSuffrageFactType,
+	isaac.JoinSuffrageOperationType,
+	isaac.LeaveSuffrageFactType,
+	isaac.LeaveSuffrageOperationType,
+	isaac.PolicyV0Type,
+	isaac.SetPolicyFactType,
+	isaac.SetPolicyOperationType,
+	isaac.SuffrageNodesV0Type,
 	key.BasePrivatekeyType,
 	key.BasePublickeyType,
+	key.Ed25519PrivatekeyType,
+	key.Ed25519PublickeyType,
+	key.P256PrivatekeyType,
+	key.P256PublickeyType,
 	network.EndHandoverSealV0Type,
 	network.HTTPConnInfoType,
 	network.NilConnInfoType,
@@ -40,6 +54,8 @@ var EncoderTypes = []hint.Type{
 	network.ProblemType,
 	network.StartHandoverSealV0Type,
 	node.BaseV0Type,
+	operation.BaseKeyType,
+	operation.BaseKeysType,
 	operation.BaseReasonErrorType,
 	operation.FixedTreeNodeType,
 	operation.SealType,
@@ -67,12 +83,25 @@ var EncoderHinters = []hint.Hinter{
 	base.StringAddressHinter,
 	base.VoteproofV0Hinter,
 	block.BaseBlockdataMapHinter,
+	block.BaseSnapshotHeaderHinter,
 	block.BlockV0Hinter,
 	block.BlockConsensusInfoV0Hinter,
 	block.ManifestV0Hinter,
 	block.SuffrageInfoV0Hinter,
+	isaac.JoinSuffrageFactHinter,
+	isaac.JoinSuffrageOperationHinter,
+	isaac.LeaveSuffrageFactHinter,
+	isaac.LeaveSuffrageOperationHinter,
+	isaac.PolicyV0Hinter,
+	isaac.SetPolicyFactHinter,
+	isaac.SetPolicyOperationHinter,
+	isaac.SuffrageNodesV0Hinter,
 	key.BasePrivatekey{},
 	key.BasePublickey{},
+	key.Ed25519Privatekey{},
+	key.Ed25519Publickey{},
+	key.P256Privatekey{},
+	key.P256Publickey{},
 	network.EndHandoverSealV0Hinter,
 	network.HTTPConnInfoHinter,
 	network.NilConnInfoHinter,
@@ -81,6 +110,8 @@ var EncoderHinters = []hint.Hinter{
 	network.ProblemHinter,
 	network.StartHandoverSealV0Hinter,
 	node.BaseV0Hinter,
+	operation.BaseKeyHinter,
+	operation.BaseKeysHinter,
 	operation.BaseReasonError{},
 	operation.FixedTreeNodeHinter,
 	operation.SealHinter,
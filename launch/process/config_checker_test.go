@@ -102,6 +102,83 @@ network:
 	t.Equal(config.DefaultLocalNetworkBind, conf.Network().Bind())
 	t.NotNil(conf.Network().Certs())
 	t.True(conf.Network().ConnInfo().Insecure())
+	t.Nil(conf.Network().MetricsBind())
+	t.Nil(conf.Network().QueryBind())
+}
+
+func (t *testConfigChecker) TestLocalNetworkMetricsBind() {
+	{
+		y := `
+network:
+  metrics-bind: http://0.0.0.0:9090
+`
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, ContextValueConfigSource, []byte(y))
+		ctx = context.WithValue(ctx, ContextValueConfigSourceType, "yaml")
+
+		ps := t.ps(ctx)
+		t.NoError(ps.Run())
+
+		cc, err := config.NewChecker(ps.Context())
+		t.NoError(err)
+		_, err = cc.CheckLocalNetwork()
+		t.NoError(err)
+
+		var conf config.LocalNode
+		t.NoError(config.LoadConfigContextValue(ps.Context(), &conf))
+
+		t.Equal("http://0.0.0.0:9090", conf.Network().MetricsBind().String())
+	}
+
+	{ // NOTE without port
+		y := `
+network:
+  metrics-bind: http://0.0.0.0
+`
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, ContextValueConfigSource, []byte(y))
+		ctx = context.WithValue(ctx, ContextValueConfigSourceType, "yaml")
+
+		ps := t.ps(ctx)
+		err := ps.Run()
+		t.Error(err)
+		t.Contains(err.Error(), "invalid metrics-bind")
+	}
+}
+
+func (t *testConfigChecker) TestLocalNetworkQueryBind() {
+	{
+		y := `
+network:
+  query-bind: http://0.0.0.0:9091
+`
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, ContextValueConfigSource, []byte(y))
+		ctx = context.WithValue(ctx, ContextValueConfigSourceType, "yaml")
+
+		ps := t.ps(ctx)
+		t.NoError(ps.Run())
+
+		var conf config.LocalNode
+		t.NoError(config.LoadConfigContextValue(ps.Context(), &conf))
+
+		t.Equal("http://0.0.0.0:9091", conf.Network().QueryBind().String())
+	}
+
+	{ // NOTE without port
+		y := `
+network:
+  query-bind: http://0.0.0.0
+`
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, ContextValueConfigSource, []byte(y))
+		ctx = context.WithValue(ctx, ContextValueConfigSourceType, "yaml")
+
+		ps := t.ps(ctx)
+		err := ps.Run()
+		t.Error(err)
+		t.Contains(err.Error(), "invalid query-bind")
+	}
 }
 
 func (t *testConfigChecker) TestLocalNetwork() {
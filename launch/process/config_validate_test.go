@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
@@ -683,6 +685,36 @@ genesis-operations:
 	t.Equal([]byte("empty"), kop.Value())
 }
 
+func (t *testConfigValidator) TestSetPolicyGenesisOperation() {
+	y := `
+privatekey: L14Ay7yp6eDs4SgYepNKdBos7aCBEmJybxvf6FjJN1CbHUEdJiUqmpr
+network-id: show me
+policy:
+  threshold: 67
+  max-operations-in-seal: 33
+genesis-operations:
+  - type: set-policy
+`
+	ctx := t.loadConfig(y)
+
+	ctx, err := HookGenesisOperationFunc(DefaultHookHandlersGenesisOperations)(ctx)
+	t.NoError(err)
+
+	var conf config.LocalNode
+	t.NoError(config.LoadConfigContextValue(ctx, &conf))
+
+	t.Equal(1, len(conf.GenesisOperations()))
+
+	op := conf.GenesisOperations()[0]
+	t.NoError(op.IsValid(conf.NetworkID()))
+	t.IsType(isaac.SetPolicyOperation{}, op)
+
+	po := op.Fact().(isaac.SetPolicyFact).Policy()
+	t.Equal(base.ThresholdRatio(67), po.ThresholdRatio())
+	t.Equal(uint(33), po.MaxOperationsInSeal())
+	t.Equal(conf.Policy().TimespanValidBallot(), po.TimespanValidBallot())
+}
+
 func (t *testConfigValidator) TestUnknownGenesisOperations() {
 	y := `
 genesis-operations:
@@ -10,11 +10,15 @@ import (
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery"
+	querynetwork "github.com/spikeekips/mitum/network/query"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/metrics"
 	"github.com/ulule/limiter/v3"
 )
 
@@ -24,6 +28,7 @@ var (
 	ContextValueConfigSourceType        util.ContextKey = "config_source_type"
 	ContextValueNetwork                 util.ContextKey = "network"
 	ContextValueBlockdata               util.ContextKey = "blockdata"
+	ContextValueSnapshotStore           util.ContextKey = "snapshot_store"
 	ContextValueDatabase                util.ContextKey = "database"
 	ContextValueLocalNode               util.ContextKey = "local_node"
 	ContextValueNodepool                util.ContextKey = "nodepool"
@@ -38,6 +43,11 @@ var (
 	ContextValueRateLimitHandlerMap     util.ContextKey = "ratelimit-handler-map"
 	ContextValueDiscovery               util.ContextKey = "discovery"
 	ContextValueDiscoveryConnInfos      util.ContextKey = "discovery-conninfos"
+	ContextValueMetricsServer           util.ContextKey = "metrics-server"
+	ContextValueQueryServer             util.ContextKey = "query-server"
+	ContextValueSkipDatabaseMigration   util.ContextKey = "skip_database_migration"
+	ContextValueChannelConfig           util.ContextKey = "channel-config"
+	ContextValueSealCache               util.ContextKey = "seal-cache"
 )
 
 func LoadConfigSourceContextValue(ctx context.Context, l *[]byte) error {
@@ -60,6 +70,10 @@ func LoadBlockdataContextValue(ctx context.Context, l *blockdata.Blockdata) erro
 	return util.LoadFromContextValue(ctx, ContextValueBlockdata, l)
 }
 
+func LoadSnapshotStoreContextValue(ctx context.Context, l **blockdata.SnapshotStore) error {
+	return util.LoadFromContextValue(ctx, ContextValueSnapshotStore, l)
+}
+
 func LoadDatabaseContextValue(ctx context.Context, l *storage.Database) error {
 	return util.LoadFromContextValue(ctx, ContextValueDatabase, l)
 }
@@ -115,3 +129,23 @@ func LoadDiscoveryContextValue(ctx context.Context, l *discovery.Discovery) erro
 func LoadDiscoveryConnInfosContextValue(ctx context.Context, l *[]network.ConnInfo) error {
 	return util.LoadFromContextValue(ctx, ContextValueDiscoveryConnInfos, l)
 }
+
+func LoadMetricsServerContextValue(ctx context.Context, l **metrics.Server) error {
+	return util.LoadFromContextValue(ctx, ContextValueMetricsServer, l)
+}
+
+func LoadQueryServerContextValue(ctx context.Context, l **querynetwork.Server) error {
+	return util.LoadFromContextValue(ctx, ContextValueQueryServer, l)
+}
+
+func LoadSkipDatabaseMigrationContextValue(ctx context.Context, l *bool) error {
+	return util.LoadFromContextValue(ctx, ContextValueSkipDatabaseMigration, l)
+}
+
+func LoadChannelConfigContextValue(ctx context.Context, l *quicnetwork.ChannelConfig) error {
+	return util.LoadFromContextValue(ctx, ContextValueChannelConfig, l)
+}
+
+func LoadSealCacheContextValue(ctx context.Context, l *cache.Cache) error {
+	return util.LoadFromContextValue(ctx, ContextValueSealCache, l)
+}
@@ -13,7 +13,9 @@ type HookHandlerGenesisOperations func(context.Context, map[string]interface{})
 
 var (
 	DefaultGenesisOperationToken         = []byte("genesis-operation-token")
-	DefaultHookHandlersGenesisOperations = map[string]HookHandlerGenesisOperations{}
+	DefaultHookHandlersGenesisOperations = map[string]HookHandlerGenesisOperations{
+		"set-policy": GenesisOperationsHandlerSetPolicy,
+	}
 )
 
 func HookGenesisOperationFunc(handlers map[string]HookHandlerGenesisOperations) pm.ProcessFunc {
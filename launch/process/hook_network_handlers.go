@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -11,10 +12,12 @@ import (
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/storage/blockdata"
@@ -42,6 +45,7 @@ type SettingNetworkHandlers struct {
 	conf      config.LocalNode
 	database  storage.Database
 	blockdata blockdata.Blockdata
+	snapshots *blockdata.SnapshotStore
 	policy    *isaac.LocalPolicy
 	nodepool  *network.Nodepool
 	suffrage  base.Suffrage
@@ -50,6 +54,7 @@ type SettingNetworkHandlers struct {
 	sealCache cache.Cache
 	logger    *zerolog.Logger
 	encs      *encoder.Encoders
+	chConf    quicnetwork.ChannelConfig
 }
 
 func SettingNetworkHandlersFromContext(ctx context.Context) (*SettingNetworkHandlers, error) {
@@ -81,6 +86,9 @@ func (sn *SettingNetworkHandlers) loadFromContext(ctx context.Context) error {
 	if err := LoadBlockdataContextValue(ctx, &sn.blockdata); err != nil {
 		return err
 	}
+	if err := LoadSnapshotStoreContextValue(ctx, &sn.snapshots); err != nil {
+		return err
+	}
 	if err := LoadSuffrageContextValue(ctx, &sn.suffrage); err != nil {
 		return err
 	}
@@ -90,12 +98,13 @@ func (sn *SettingNetworkHandlers) loadFromContext(ctx context.Context) error {
 	if err := config.LoadEncodersContextValue(ctx, &sn.encs); err != nil {
 		return err
 	}
+	if err := LoadChannelConfigContextValue(ctx, &sn.chConf); err != nil {
+		return err
+	}
 
-	i, err := cache.NewCacheFromURI(sn.conf.Network().SealCache().String())
-	if err != nil {
+	if err := LoadSealCacheContextValue(ctx, &sn.sealCache); err != nil {
 		return err
 	}
-	sn.sealCache = i
 
 	l := zerolog.Nop()
 	sn.logger = &l
@@ -116,6 +125,9 @@ func (sn *SettingNetworkHandlers) Set() error {
 	sn.network.SetNodeInfoHandler(sn.handlerNodeInfo())
 	sn.network.SetBlockdataMapsHandler(sn.handlerBlockdataMaps())
 	sn.network.SetBlockdataHandler(sn.handlerBlockdata())
+	sn.network.SetBlockdataByChecksumHandler(sn.handlerBlockdataByChecksum())
+	sn.network.SetSnapshotHandler(sn.handlerSnapshot())
+	sn.network.SetGetStateHandler(sn.handlerGetState())
 	sn.network.SetStartHandoverHandler(sn.handlerStartHandover())
 	sn.network.SetPingHandoverHandler(sn.handlerPingHandover())
 	sn.network.SetEndHandoverHandler(sn.handlerEndHandover())
@@ -127,6 +139,9 @@ func (sn *SettingNetworkHandlers) Set() error {
 	lc.SetNodeInfoHandler(sn.handlerNodeInfo())
 	lc.SetBlockdataMapsHandler(sn.handlerBlockdataMaps())
 	lc.SetBlockdataHandler(sn.handlerBlockdata())
+	lc.SetBlockdataByChecksumHandler(sn.handlerBlockdataByChecksum())
+	lc.SetSnapshotHandler(sn.handlerSnapshot())
+	lc.SetGetStateHandler(sn.handlerGetState())
 
 	sn.logger.Debug().Msg("local channel handlers binded")
 
@@ -188,10 +203,26 @@ func (sn *SettingNetworkHandlers) handlerNewSeal() network.NewSealHandler {
 			_ = sn.states.NewSeal(sl)
 		}()
 
+		if sn.nodepool.IsGossip() {
+			go sn.gossip(sl)
+		}
+
 		return nil
 	}
 }
 
+// gossip relays the received seal to the alive remote nodes. The suffrage of
+// local node may be different with the node, which broadcasts the seal, so the
+// seal is not filtered by suffrage.
+func (sn *SettingNetworkHandlers) gossip(sl seal.Seal) {
+	switch failed, err := sn.nodepool.Gossip(context.Background(), sl, nil); {
+	case err != nil:
+		sn.logger.Error().Err(err).Stringer("seal_hash", sl.Hash()).Msg("failed to gossip seal")
+	case len(failed) > 0:
+		sn.logger.Debug().Errs("failed", failed).Stringer("seal_hash", sl.Hash()).Msg("something wrong to gossip seal")
+	}
+}
+
 func (sn *SettingNetworkHandlers) handlerNodeInfo() network.NodeInfoHandler {
 	return func() (network.NodeInfo, error) {
 		var manifest block.Manifest
@@ -217,6 +248,11 @@ func (sn *SettingNetworkHandlers) handlerNodeInfo() network.NodeInfoHandler {
 			nodes[i] = network.NewRemoteNode(n, connInfo)
 		}
 
+		pruned, err := storage.PrunedHeight(sn.database)
+		if err != nil {
+			return nil, err
+		}
+
 		return network.NewNodeInfoV0(
 			sn.nodepool.LocalNode(),
 			sn.policy.NetworkID(),
@@ -227,7 +263,7 @@ func (sn *SettingNetworkHandlers) handlerNodeInfo() network.NodeInfoHandler {
 			nodes,
 			sn.suffrage,
 			sn.conf.Network().ConnInfo(),
-		), nil
+		).SetPrunedHeight(pruned), nil
 	}
 }
 
@@ -249,6 +285,14 @@ func (sn *SettingNetworkHandlers) handlerBlockdataMaps() network.BlockdataMapsHa
 			filtered = append(filtered, h)
 		}
 
+		// NOTE the pruned blocks can not be synced
+		switch pruned, err := storage.PrunedHeight(sn.database); {
+		case err != nil:
+			return nil, err
+		case len(filtered) > 0 && filtered[0] < pruned:
+			return nil, network.PrunedHeightError.Errorf("heights lower than %d were pruned", pruned)
+		}
+
 		maps := make([]block.BlockdataMap, len(filtered))
 		for i := range filtered {
 			switch m, found, err := sn.database.BlockdataMap(filtered[i]); {
@@ -267,6 +311,14 @@ func (sn *SettingNetworkHandlers) handlerBlockdataMaps() network.BlockdataMapsHa
 
 func (sn *SettingNetworkHandlers) handlerBlockdata() network.BlockdataHandler {
 	return func(p string) (io.Reader, func() error, error) {
+		if strings.HasPrefix(p, blockdata.SnapshotURLPrefix) {
+			i, err := sn.snapshots.Open(p)
+			if err != nil {
+				return nil, func() error { return nil }, err
+			}
+			return i, i.Close, nil
+		}
+
 		i, err := sn.blockdata.FS().Open(p)
 		if err != nil {
 			return nil, func() error { return nil }, err
@@ -275,6 +327,88 @@ func (sn *SettingNetworkHandlers) handlerBlockdata() network.BlockdataHandler {
 	}
 }
 
+func (sn *SettingNetworkHandlers) handlerBlockdataByChecksum() network.BlockdataByChecksumHandler {
+	return func(height base.Height, dataType, checksum string) (io.ReadSeekCloser, error) {
+		var items interface {
+			Item(string) (block.BaseBlockdataMapItem, bool)
+		}
+
+		switch m, found, err := sn.database.BlockdataMap(height); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.NotFoundError.Errorf("blockdata map, %d not found", height)
+		default:
+			i, ok := m.(interface {
+				Item(string) (block.BaseBlockdataMapItem, bool)
+			})
+			if !ok {
+				return nil, errors.Errorf("unknown blockdata map, %T", m)
+			}
+
+			items = i
+		}
+
+		item, found := items.Item(dataType)
+		switch {
+		case !found, item.Checksum() != checksum:
+			return nil, util.NotFoundError.Errorf("block data, %q of %d not found by checksum", dataType, height)
+		case !block.IsLocalBlockdataItem(item.URL()):
+			return nil, util.NotFoundError.Errorf("block data, %q of %d is not local", dataType, height)
+		}
+
+		u, err := network.ParseURL(item.URL(), false)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := sn.blockdata.FS().Open(u.Path)
+		if err != nil {
+			return nil, storage.MergeFSError(err)
+		}
+
+		i, ok := f.(io.ReadSeekCloser)
+		if !ok {
+			_ = f.Close()
+
+			return nil, errors.Errorf("block data file is not seekable, %T", f)
+		}
+
+		return i, nil
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerSnapshot() network.SnapshotHandler {
+	return func(height base.Height) (block.SnapshotHeader, error) {
+		switch sh, found, err := sn.snapshots.Header(height); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.NotFoundError.Errorf("snapshot not found")
+		default:
+			return sh, nil
+		}
+	}
+}
+
+func (sn *SettingNetworkHandlers) handlerGetState() network.GetStateHandler {
+	return func(key string, height base.Height) (state.State, bool, error) {
+		if height <= base.NilHeight {
+			return sn.database.State(key)
+		}
+
+		// NOTE the states lower than pruned height can not be queried
+		switch pruned, err := storage.PrunedHeight(sn.database); {
+		case err != nil:
+			return nil, false, err
+		case height < pruned:
+			return nil, false, network.PrunedHeightError.Errorf("states lower than %d were pruned", pruned)
+		}
+
+		return sn.database.StateAtHeight(key, height)
+	}
+}
+
 func (sn *SettingNetworkHandlers) checkHandoverSeal(sl network.HandoverSeal) error {
 	if err := network.IsValidHandoverSeal(
 		sn.nodepool.LocalNode(),
@@ -316,7 +450,7 @@ func (sn *SettingNetworkHandlers) handlerPingHandover() network.PingHandoverHand
 			return false, network.HandoverRejectedError.Wrap(err)
 		}
 
-		ch, err := discovery.LoadNodeChannel(sl.ConnInfo(), sn.encs, sn.policy.NetworkConnectionTimeout())
+		ch, err := discovery.LoadNodeChannel(sl.ConnInfo(), sn.encs, sn.policy.NetworkConnectionTimeout(), sn.chConf)
 		if err != nil {
 			return false, network.HandoverRejectedError.Errorf("failed to load channel from PingHandoverSeal: %w", err)
 		}
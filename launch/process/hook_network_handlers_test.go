@@ -0,0 +1,75 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/states"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyStates struct {
+	states.States
+	lvp base.Voteproof
+}
+
+func (st dummyStates) LastVoteproof() base.Voteproof {
+	return st.lvp
+}
+
+type testNetworkHandlers struct {
+	isaac.BaseTest
+	local  *isaac.Local
+	remote *isaac.Local
+}
+
+func (t *testNetworkHandlers) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	ls := t.Locals(2)
+	t.local, t.remote = ls[0], ls[1]
+}
+
+func (t *testNetworkHandlers) TestReplayStaleBallot() {
+	l := zerolog.Nop()
+
+	sn := &SettingNetworkHandlers{
+		database: t.local.Database(),
+		policy:   t.local.Policy(),
+		nodepool: t.local.Nodes(),
+		suffrage: t.Suffrage(t.remote, t.local, t.remote),
+		states:   dummyStates{lvp: t.local.Database().LastVoteproof(base.StageACCEPT)},
+		logger:   &l,
+	}
+
+	// NOTE the ballot, which is validly signed by remote, becomes old
+	_, err := t.local.Policy().SetTimespanValidBallot(time.Millisecond * 10)
+	t.NoError(err)
+
+	ib := t.NewINITBallot(t.remote, base.Round(0), nil)
+	t.NoError(ib.IsValid(t.local.Policy().NetworkID()))
+
+	<-time.After(time.Millisecond * 50)
+
+	handler := sn.handlerNewSeal()
+
+	// NOTE anyone can replay it over the ban threshold
+	n := int(network.DefaultPeerScoreThreshold)/int(network.PeerPenaltyInvalidSeal) + 1
+	for i := 0; i < n; i++ {
+		err := handler(ib)
+		t.True(errors.Is(err, isaac.BallotNotInTimespanError))
+		t.True(errors.Is(err, isvalid.InvalidError))
+	}
+
+	t.False(sn.nodepool.IsBanned(t.remote.Node().Address()))
+}
+
+func TestNetworkHandlers(t *testing.T) {
+	suite.Run(t, new(testNetworkHandlers))
+}
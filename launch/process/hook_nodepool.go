@@ -7,6 +7,8 @@ import (
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/network"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
+	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
 	"github.com/spikeekips/mitum/util/logging"
 )
@@ -43,13 +45,18 @@ func HookNodepool(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
+	var chConf quicnetwork.ChannelConfig
+	if err := LoadChannelConfigContextValue(ctx, &chConf); err != nil {
+		return ctx, err
+	}
+
 	for i := range nodeConfigs {
 		conf := nodeConfigs[i]
 
 		no := node.NewRemote(conf.Address(), conf.Publickey())
 		var ch network.Channel
 		if ci := conf.ConnInfo(); ci != nil {
-			i, err := LoadNodeChannel(ci, encs, policy.NetworkConnectionTimeout())
+			i, err := LoadNodeChannel(ci, encs, policy.NetworkConnectionTimeout(), chConf)
 			if err != nil {
 				return ctx, err
 			}
@@ -62,5 +69,24 @@ func HookNodepool(ctx context.Context) (context.Context, error) {
 		log.Log().Debug().Stringer("added_node", no.Address()).Msg("node added to nodepool")
 	}
 
-	return ctx, nil
+	sealCache, err := cache.NewCacheFromURI(l.Network().SealCache().String())
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := setNodepoolGossip(l.Network(), nodepool, sealCache); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueSealCache, sealCache), nil
+}
+
+// setNodepoolGossip enables the gossip mode of nodepool. The seal cache is
+// used as the seen cache.
+func setNodepoolGossip(conf config.LocalNetwork, nodepool *network.Nodepool, sealCache cache.Cache) error {
+	if conf.GossipFanout() < 1 {
+		return nil
+	}
+
+	return nodepool.SetGossip(int(conf.GossipFanout()), conf.GossipTTL(), sealCache)
 }
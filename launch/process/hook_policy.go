@@ -50,6 +50,14 @@ func HookSetPolicy(ctx context.Context) (context.Context, error) {
 	if _, err := policy.SetNetworkConnectionTimeout(conf.NetworkConnectionTimeout()); err != nil {
 		return ctx, err
 	}
+	if _, err := policy.SetSnapshotInterval(conf.SnapshotInterval()); err != nil {
+		return ctx, err
+	}
+	_ = policy.SetSyncFromSnapshot(conf.SyncFromSnapshot())
+	if _, err := policy.SetRetentionHeights(conf.RetentionHeights()); err != nil {
+		return ctx, err
+	}
+	_ = policy.SetPruneBlockdata(conf.PruneBlockdata())
 
 	return context.WithValue(ctx, ContextValuePolicy, policy), nil
 }
@@ -0,0 +1,157 @@
+package process
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const (
+	HookNameSetPolicyOperationProcessor = "set_policy_operation_processor"
+	HookNameLoadPolicy                  = "load_policy"
+	HookNameApplyPolicy                 = "apply_policy"
+)
+
+// HookSetPolicyOperationProcessor adds isaac.SetPolicyOperationProcessor to
+// the operation processors. If operation processors are not yet set, new
+// one is created.
+func HookSetPolicyOperationProcessor(ctx context.Context) (context.Context, error) {
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return ctx, err
+	}
+
+	var suffrage base.Suffrage
+	if err := LoadSuffrageContextValue(ctx, &suffrage); err != nil {
+		return ctx, err
+	}
+
+	var oprs *hint.Hintmap
+	if err := LoadOperationProcessorsContextValue(ctx, &oprs); err != nil {
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, err
+		}
+
+		oprs = hint.NewHintmap()
+	}
+
+	opr := isaac.NewSetPolicyOperationProcessor(nodepool, suffrage, policy)
+	if err := oprs.Add(isaac.SetPolicyOperationHinter, opr); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueOperationProcessors, oprs), nil
+}
+
+// HookLoadPolicy applies the on-chain policy from database and the policy
+// will be updated whenever the new policy is stored in the new blocks.
+func HookLoadPolicy(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	switch st, found, err := db.State(isaac.PolicyStateKey); {
+	case err != nil:
+		return ctx, err
+	case !found:
+		log.Log().Debug().Msg("on-chain policy not found; local policy will be used")
+	default:
+		po, err := isaac.PolicyFromState(st)
+		if err != nil {
+			return ctx, err
+		}
+
+		if err := policy.SetPolicy(po, st.Height()); err != nil {
+			return ctx, err
+		}
+
+		log.Log().Debug().Stringer("policy", po).Int64("height", st.Height().Int64()).Msg("on-chain policy applied")
+	}
+
+	if err := cs.BlockSavedHook().Add(HookNameApplyPolicy, hookApplyPolicy(policy, log), true); err != nil {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
+
+func hookApplyPolicy(policy *isaac.LocalPolicy, log *logging.Logging) func(context.Context) (context.Context, error) {
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		switch po, height, found, err := isaac.PolicyFromBlocks(blks); {
+		case err != nil:
+			return ctx, err
+		case !found:
+			return ctx, nil
+		default:
+			if err := policy.SetPolicy(po, height); err != nil {
+				return ctx, err
+			}
+
+			log.Log().Debug().Stringer("policy", po).Int64("height", height.Int64()).Msg("new on-chain policy applied")
+
+			return ctx, nil
+		}
+	}
+}
+
+// GenesisOperationsHandlerSetPolicy creates isaac.SetPolicyOperation from the
+// policy of config.
+func GenesisOperationsHandlerSetPolicy(ctx context.Context, _ map[string]interface{}) (operation.Operation, error) {
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return nil, err
+	}
+
+	c := conf.Policy()
+	po := isaac.NewPolicyV0(
+		c.ThresholdRatio(),
+		c.MaxOperationsInSeal(),
+		c.MaxOperationsInProposal(),
+		c.TimeoutWaitingProposal(),
+		c.IntervalBroadcastingINITBallot(),
+		c.IntervalBroadcastingProposal(),
+		c.WaitBroadcastingACCEPTBallot(),
+		c.IntervalBroadcastingACCEPTBallot(),
+		c.TimespanValidBallot(),
+	)
+
+	return isaac.NewSetPolicyOperationFromSigner(conf.Privatekey(), DefaultGenesisOperationToken, po, conf.NetworkID())
+}
@@ -0,0 +1,104 @@
+package process
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const HookNamePrune = "prune"
+
+// HookPrune prunes the blocks and the state history of database whenever new
+// block is saved, and also the block data if PruneBlockdata of policy is set;
+// only the recent heights of the retention heights of policy are kept. The
+// operation facts are kept for HasOperationFact. If the retention heights is 0,
+// database will not be pruned.
+func HookPrune(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var bd blockdata.Blockdata
+	if err := LoadBlockdataContextValue(ctx, &bd); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	if policy.RetentionHeights() > 0 {
+		if _, ok := db.(storage.Pruner); !ok {
+			return ctx, errors.Errorf("database does not support pruning, %T", db)
+		}
+	}
+
+	var pruning int32
+
+	if err := cs.BlockSavedHook().Add(HookNamePrune, func(ctx context.Context) (context.Context, error) {
+		retention := policy.RetentionHeights()
+		if retention < 1 {
+			return ctx, nil
+		}
+
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		if len(blks) < 1 {
+			return ctx, nil
+		}
+
+		height := blks[len(blks)-1].Height() - base.Height(retention) + 1
+		if height <= base.GenesisHeight {
+			return ctx, nil
+		}
+
+		// NOTE skip if the previous pruning is not yet finished
+		if !atomic.CompareAndSwapInt32(&pruning, 0, 1) {
+			return ctx, nil
+		}
+
+		go func() {
+			defer atomic.StoreInt32(&pruning, 0)
+
+			if err := blockdata.Prune(db, bd, height, policy.PruneBlockdata()); err != nil {
+				log.Log().Error().Err(err).Int64("height", height.Int64()).Msg("failed to prune")
+
+				return
+			}
+
+			log.Log().Debug().Int64("height", height.Int64()).Msg("pruned")
+		}()
+
+		return ctx, nil
+	}, true); err != nil {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,162 @@
+package process
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/isaac"
+	querynetwork "github.com/spikeekips/mitum/network/query"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+const (
+	HookNameSetQueryHandlers = "set_query_handlers"
+	HookNameSetQueryEvents   = "set_query_events"
+)
+
+// HookSetQueryHandlers sets the handler of submitted operations to the query
+// server. The submitted operations are signed in new seal by local node and
+// go to consensus states like the seals from network. If query server is not
+// created, it will be ignored.
+func HookSetQueryHandlers(ctx context.Context) (context.Context, error) {
+	var sv *querynetwork.Server
+	if err := LoadQueryServerContextValue(ctx, &sv); err != nil {
+		if errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, nil
+		}
+
+		return ctx, err
+	}
+
+	var local node.Local
+	if err := LoadLocalNodeContextValue(ctx, &local); err != nil {
+		return ctx, err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	_ = sv.SetSubmitOperationsHandler(submitOperationsHandler(local, policy, db, cs))
+
+	return ctx, nil
+}
+
+// HookSetQueryEvents publishes the saved blocks and the consensus state changes
+// to the event subscribers of query server. If query server is not created, it
+// will be ignored.
+func HookSetQueryEvents(ctx context.Context) (context.Context, error) {
+	var sv *querynetwork.Server
+	if err := LoadQueryServerContextValue(ctx, &sv); err != nil {
+		if errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, nil
+		}
+
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	if err := cs.BlockSavedHook().Add(HookNameSetQueryEvents, func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		return ctx, sv.PublishBlocks(blks)
+	}, true); err != nil {
+		return ctx, err
+	}
+
+	if err := cs.StateSwitchedHook().Add(HookNameSetQueryEvents, func(ctx context.Context) (context.Context, error) {
+		var sctx basicstate.StateSwitchContext
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueStateSwitchContext, &sctx); err != nil {
+			return ctx, err
+		}
+
+		sv.PublishConsensusState(sctx.FromState(), sctx.ToState())
+
+		return ctx, nil
+	}, true); err != nil {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
+
+func submitOperationsHandler(
+	local node.Local,
+	policy *isaac.LocalPolicy,
+	db storage.Database,
+	cs states.States,
+) querynetwork.SubmitOperationsHandler {
+	return func(ops []operation.Operation) error {
+		if n := uint(len(ops)); n > policy.MaxOperationsInSeal() {
+			return querynetwork.BadRequestError.Errorf(
+				"too many operations, %d > %d", n, policy.MaxOperationsInSeal())
+		}
+
+		founds := map[string]struct{}{}
+		var nops []operation.Operation
+		for i := range ops {
+			op := ops[i]
+			if err := op.IsValid(policy.NetworkID()); err != nil {
+				return querynetwork.BadRequestError.Wrap(err)
+			}
+
+			fact := op.Fact().Hash()
+			if _, found := founds[fact.String()]; found {
+				return querynetwork.BadRequestError.Errorf("duplicated operation fact, %q", fact)
+			}
+			founds[fact.String()] = struct{}{}
+
+			if known, err := isKnownOperation(db, fact); err != nil {
+				return err
+			} else if known {
+				continue
+			}
+
+			nops = append(nops, op)
+		}
+
+		if len(nops) < 1 {
+			return nil
+		}
+
+		sl, err := operation.NewBaseSeal(local.Privatekey(), nops, policy.NetworkID())
+		if err != nil {
+			return err
+		}
+
+		return cs.NewSeal(sl)
+	}
+}
+
+func isKnownOperation(db storage.Database, fact valuehash.Hash) (bool, error) {
+	if found, err := db.HasOperationFact(fact); err != nil || found {
+		return found, err
+	}
+
+	return db.HasStagedOperation(fact)
+}
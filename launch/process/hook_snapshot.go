@@ -0,0 +1,102 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const HookNameSnapshot = "snapshot"
+
+// HookSnapshot creates the state snapshot whenever the block of the height,
+// which is multiple of the snapshot interval of policy is saved. If the
+// interval is 0, snapshot will not be created.
+func HookSnapshot(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var ss *blockdata.SnapshotStore
+	if err := LoadSnapshotStoreContextValue(ctx, &ss); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	if err := cs.BlockSavedHook().Add(HookNameSnapshot, func(ctx context.Context) (context.Context, error) {
+		interval := policy.SnapshotInterval()
+		if interval < 1 {
+			return ctx, nil
+		}
+
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		for i := range blks {
+			m := blks[i].Manifest()
+			if m.Height().Int64()%int64(interval) != 0 {
+				continue
+			}
+
+			go func() {
+				if err := saveSnapshot(db, ss, m); err != nil {
+					log.Log().Error().Err(err).Int64("height", m.Height().Int64()).Msg("failed to save snapshot")
+
+					return
+				}
+
+				log.Log().Debug().Int64("height", m.Height().Int64()).Msg("snapshot saved")
+			}()
+		}
+
+		return ctx, nil
+	}, true); err != nil {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
+
+func saveSnapshot(db storage.Database, ss *blockdata.SnapshotStore, m block.Manifest) error {
+	var sts []state.State
+	if err := db.States(m.Height(), func(st state.State) (bool, error) {
+		sts = append(sts, st)
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	if len(sts) < 1 {
+		return nil
+	}
+
+	_, err := ss.Save(m, sts)
+
+	return err
+}
@@ -0,0 +1,252 @@
+package process
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/network"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
+	"github.com/spikeekips/mitum/states"
+	basicstate "github.com/spikeekips/mitum/states/basic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const (
+	HookNameSetSuffrageOperationProcessor = "set_suffrage_operation_processor"
+	HookNameLoadSuffrageNodes             = "load_suffrage_nodes"
+	HookNameApplySuffrageNodes            = "apply_suffrage_nodes"
+)
+
+// HookSetSuffrageOperationProcessor adds isaac.SuffrageOperationProcessor
+// for isaac.JoinSuffrageOperation and isaac.LeaveSuffrageOperation to the
+// operation processors.
+func HookSetSuffrageOperationProcessor(ctx context.Context) (context.Context, error) {
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return ctx, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return ctx, err
+	}
+
+	var suffrage base.Suffrage
+	if err := LoadSuffrageContextValue(ctx, &suffrage); err != nil {
+		return ctx, err
+	}
+
+	var oprs *hint.Hintmap
+	if err := LoadOperationProcessorsContextValue(ctx, &oprs); err != nil {
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return ctx, err
+		}
+
+		oprs = hint.NewHintmap()
+	}
+
+	opr := isaac.NewSuffrageOperationProcessor(nodepool, suffrage, policy)
+	if err := oprs.Add(isaac.JoinSuffrageOperationHinter, opr); err != nil {
+		return ctx, err
+	}
+
+	if err := oprs.Add(isaac.LeaveSuffrageOperationHinter, opr); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueOperationProcessors, oprs), nil
+}
+
+// HookLoadSuffrageNodes applies the history of on-chain suffrage nodes from
+// database to suffrage and nodepool.
+func HookLoadSuffrageNodes(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return ctx, err
+	}
+
+	var suffrage base.Suffrage
+	if err := LoadSuffrageContextValue(ctx, &suffrage); err != nil {
+		return ctx, err
+	}
+
+	updater, ok := suffrage.(isaac.SuffrageNodesUpdater)
+	if !ok {
+		log.Log().Debug().Str("suffrage", suffrage.Name()).Msg("suffrage does not support to update nodes")
+
+		return ctx, nil
+	}
+
+	newChannel, err := suffrageNodeChannelFunc(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	switch m, found, err := db.LastManifest(); {
+	case err != nil:
+		return ctx, err
+	case found:
+		updater.SetHeight(m.Height() + 1)
+	}
+
+	// NOTE the old suffrage nodes are also applied by ascending height, so the
+	// suffrage nodes of the old heights are kept.
+	var sts []state.State
+	if err := db.StateHistory(isaac.SuffrageNodesStateKey, base.NilHeight, 0, func(st state.State) (bool, error) {
+		sts = append(sts, st)
+
+		return true, nil
+	}); err != nil {
+		return ctx, err
+	}
+
+	if len(sts) < 1 {
+		log.Log().Debug().Msg("on-chain suffrage nodes not found; suffrage nodes from config will be used")
+
+		return ctx, nil
+	}
+
+	for i := len(sts) - 1; i >= 0; i-- {
+		st := sts[i]
+
+		sn, err := isaac.SuffrageNodesFromState(st)
+		if err != nil {
+			return ctx, err
+		}
+
+		if err := isaac.ApplySuffrageNodes(sn, st.Height(), suffrage, nodepool, newChannel); err != nil {
+			return ctx, err
+		}
+
+		log.Log().Debug().
+			Interface("suffrage_nodes", sn.Addresses()).Int64("height", st.Height().Int64()).
+			Msg("on-chain suffrage nodes applied")
+	}
+
+	return ctx, nil
+}
+
+// HookApplySuffrageNodes updates suffrage and nodepool whenever the new
+// suffrage nodes are stored in the new blocks.
+func HookApplySuffrageNodes(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var nodepool *network.Nodepool
+	if err := LoadNodepoolContextValue(ctx, &nodepool); err != nil {
+		return ctx, err
+	}
+
+	var suffrage base.Suffrage
+	if err := LoadSuffrageContextValue(ctx, &suffrage); err != nil {
+		return ctx, err
+	}
+
+	var cs states.States
+	if err := LoadConsensusStatesContextValue(ctx, &cs); err != nil {
+		return ctx, err
+	}
+
+	if _, ok := suffrage.(isaac.SuffrageNodesUpdater); !ok {
+		return ctx, nil
+	}
+
+	newChannel, err := suffrageNodeChannelFunc(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := cs.BlockSavedHook().Add(
+		HookNameApplySuffrageNodes,
+		hookApplySuffrageNodes(suffrage, nodepool, newChannel, log),
+		true,
+	); err != nil {
+		return ctx, err
+	}
+
+	return ctx, nil
+}
+
+func hookApplySuffrageNodes(
+	suffrage base.Suffrage,
+	nodepool *network.Nodepool,
+	newChannel func(network.ConnInfo) (network.Channel, error),
+	log *logging.Logging,
+) func(context.Context) (context.Context, error) {
+	updater := suffrage.(isaac.SuffrageNodesUpdater)
+
+	return func(ctx context.Context) (context.Context, error) {
+		var blks []block.Block
+		if err := util.LoadFromContextValue(ctx, basicstate.ContextValueBlockSaved, &blks); err != nil {
+			return ctx, err
+		}
+
+		if len(blks) < 1 {
+			return ctx, nil
+		}
+
+		// NOTE the next height of the last saved block is being agreed
+		defer updater.SetHeight(blks[len(blks)-1].Height() + 1)
+
+		switch sn, height, found, err := isaac.SuffrageNodesFromBlocks(blks); {
+		case err != nil:
+			return ctx, err
+		case !found:
+			return ctx, nil
+		default:
+			if err := isaac.ApplySuffrageNodes(sn, height, suffrage, nodepool, newChannel); err != nil {
+				return ctx, err
+			}
+
+			log.Log().Debug().
+				Interface("suffrage_nodes", sn.Addresses()).Int64("height", height.Int64()).
+				Msg("new on-chain suffrage nodes applied")
+
+			return ctx, nil
+		}
+	}
+}
+
+// suffrageNodeChannelFunc returns the function to load the channel of the
+// joined suffrage node from it's ConnInfo.
+func suffrageNodeChannelFunc(ctx context.Context) (func(network.ConnInfo) (network.Channel, error), error) {
+	var encs *encoder.Encoders
+	if err := config.LoadEncodersContextValue(ctx, &encs); err != nil {
+		return nil, err
+	}
+
+	var policy *isaac.LocalPolicy
+	if err := LoadPolicyContextValue(ctx, &policy); err != nil {
+		return nil, err
+	}
+
+	var chConf quicnetwork.ChannelConfig
+	if err := LoadChannelConfigContextValue(ctx, &chConf); err != nil {
+		return nil, err
+	}
+
+	return func(ci network.ConnInfo) (network.Channel, error) {
+		return LoadNodeChannel(ci, encs, policy.NetworkConnectionTimeout(), chConf)
+	}, nil
+}
@@ -0,0 +1,108 @@
+package process
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/launch/config"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/logging"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummySuffrageNodesDatabase struct {
+	storage.Database
+	sts []state.State
+}
+
+func (db dummySuffrageNodesDatabase) StateHistory(
+	key string,
+	height base.Height,
+	_ int64,
+	callback func(state.State) (bool, error),
+) error {
+	for i := len(db.sts) - 1; i >= 0; i-- {
+		st := db.sts[i]
+		if st.Key() != key || (height > base.NilHeight && st.Height() >= height) {
+			continue
+		}
+
+		if keep, err := callback(st); err != nil || !keep {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type testHookSuffrageNodes struct {
+	isaac.BaseTest
+}
+
+func (t *testHookSuffrageNodes) newState(height base.Height, ls ...*isaac.Local) state.State {
+	nodes := make([]base.Node, len(ls))
+	for i := range ls {
+		nodes[i] = ls[i].Node()
+	}
+
+	v, err := state.NewHintedValue(isaac.NewSuffrageNodesV0(nodes))
+	t.NoError(err)
+
+	st, err := state.NewStateV0(isaac.SuffrageNodesStateKey, v, height)
+	t.NoError(err)
+
+	return st
+}
+
+func (t *testHookSuffrageNodes) addresses(ls ...*isaac.Local) []base.Address {
+	as := make([]base.Address, len(ls))
+	for i := range ls {
+		as[i] = ls[i].Node().Address()
+	}
+
+	base.SortAddresses(as)
+
+	return as
+}
+
+func (t *testHookSuffrageNodes) TestLoadHistoryAfterRestart() {
+	ls := t.Locals(4)
+	local := ls[0]
+
+	suffrage, err := NewRoundrobinSuffrage(t.addresses(ls[0], ls[1], ls[2]), 3, 10, nil)
+	t.NoError(err)
+
+	db := dummySuffrageNodesDatabase{
+		Database: local.Database(),
+		sts: []state.State{
+			t.newState(base.Height(3), ls[0], ls[1], ls[2], ls[3]),
+			t.newState(base.Height(6), ls[0], ls[1], ls[3]),
+		},
+	}
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, config.ContextValueLog, logging.TestNilLogging)
+	ctx = context.WithValue(ctx, config.ContextValueEncoders, t.Encs)
+	ctx = context.WithValue(ctx, ContextValueDatabase, storage.Database(db))
+	ctx = context.WithValue(ctx, ContextValueNodepool, local.Nodes())
+	ctx = context.WithValue(ctx, ContextValueSuffrage, base.Suffrage(suffrage))
+	ctx = context.WithValue(ctx, ContextValuePolicy, local.Policy())
+	ctx = context.WithValue(ctx, ContextValueChannelConfig, quicnetwork.ChannelConfig{})
+
+	_, err = HookLoadSuffrageNodes(ctx)
+	t.NoError(err)
+
+	// NOTE the suffrage nodes state at height 3 is used from height 4
+	t.Equal(t.addresses(ls[0], ls[1], ls[2]), suffrage.NodesByHeight(base.Height(3)))
+	t.Equal(t.addresses(ls[0], ls[1], ls[2], ls[3]), suffrage.NodesByHeight(base.Height(4)))
+	t.Equal(t.addresses(ls[0], ls[1], ls[2], ls[3]), suffrage.NodesByHeight(base.Height(6)))
+	t.Equal(t.addresses(ls[0], ls[1], ls[3]), suffrage.NodesByHeight(base.Height(7)))
+}
+
+func TestHookSuffrageNodes(t *testing.T) {
+	suite.Run(t, new(testHookSuffrageNodes))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/spikeekips/mitum/launch/pm"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery/memberlist"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/states"
 	basicstate "github.com/spikeekips/mitum/states/basic"
 	"github.com/spikeekips/mitum/storage"
@@ -233,7 +234,12 @@ func createHandover(
 		}
 	}
 
-	handover, err := basicstate.NewHandoverWithDiscoveryURL(connInfo, encs, policy, nodepool, suffrage, cis)
+	var chConf quicnetwork.ChannelConfig
+	if err := LoadChannelConfigContextValue(ctx, &chConf); err != nil {
+		return nil, err
+	}
+
+	handover, err := basicstate.NewHandoverWithDiscoveryURL(connInfo, encs, policy, nodepool, suffrage, cis, chConf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make Handover: %w", err)
 	}
@@ -206,7 +206,12 @@ func processDiscoveryDelegate(ctx context.Context, dis discovery.Discovery) erro
 		return err
 	}
 
-	dg := discovery.NewNodepoolDelegate(nodepool, nt.Encoders(), policy.NetworkConnectionTimeout())
+	var chConf quicnetwork.ChannelConfig
+	if err := LoadChannelConfigContextValue(ctx, &chConf); err != nil {
+		return err
+	}
+
+	dg := discovery.NewNodepoolDelegate(nodepool, nt.Encoders(), policy.NetworkConnectionTimeout(), chConf)
 	_ = dg.SetLogging(log)
 
 	_ = dis.SetNotifyJoin(dg.NotifyJoin).
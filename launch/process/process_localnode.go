@@ -7,6 +7,7 @@ import (
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util/encoder"
 	"github.com/spikeekips/mitum/util/logging"
 )
 
@@ -45,7 +46,18 @@ func ProcessLocalNode(ctx context.Context) (context.Context, error) {
 
 	log.Log().Debug().Stringer("added_node", no.Address()).Msg("local node added to nodepool")
 
+	var encs *encoder.Encoders
+	if err := config.LoadEncodersContextValue(ctx, &encs); err != nil {
+		return ctx, err
+	}
+
+	chConf, err := newChannelConfig(conf, no, nodepool, encs)
+	if err != nil {
+		return ctx, err
+	}
+
 	ctx = context.WithValue(ctx, ContextValueNodepool, nodepool)
+	ctx = context.WithValue(ctx, ContextValueChannelConfig, chConf)
 
 	return context.WithValue(ctx, ContextValueLocalNode, no), nil
 }
@@ -0,0 +1,54 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
+)
+
+const ProcessNameMetrics = "metrics"
+
+var ProcessorMetrics pm.Process
+
+func init() {
+	if i, err := pm.NewProcess(
+		ProcessNameMetrics,
+		[]string{
+			ProcessNameConfig,
+		},
+		ProcessMetrics,
+	); err != nil {
+		panic(err)
+	} else {
+		ProcessorMetrics = i
+	}
+}
+
+// ProcessMetrics prepares metrics server. If metrics-bind is not set in
+// config, metrics server is not created.
+func ProcessMetrics(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return ctx, err
+	}
+
+	bind := conf.Network().MetricsBind()
+	if bind == nil {
+		log.Log().Debug().Msg("metrics-bind is empty; metrics server disabled")
+
+		return ctx, nil
+	}
+
+	sv := metrics.NewServer(bind.Host)
+	_ = sv.SetLogging(log)
+
+	return context.WithValue(ctx, ContextValueMetricsServer, sv), nil
+}
@@ -3,9 +3,11 @@ package process
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base/node"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
 	"github.com/spikeekips/mitum/network"
@@ -67,7 +69,19 @@ func ProcessQuicNetwork(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
-	nt, err := NewNetworkServer(conf.Bind().Host, conf.Certs(), encs, ca, conf.ConnInfo(), nodepool, httpLog)
+	var chConf quicnetwork.ChannelConfig
+	if err := LoadChannelConfigContextValue(ctx, &chConf); err != nil {
+		return ctx, err
+	}
+
+	// NOTE under mutual TLS, the network server also uses the node
+	// certificate, so the other nodes can authenticate it.
+	certs := conf.Certs()
+	if conf.MutualTLS() {
+		certs = chConf.Certificates
+	}
+
+	nt, err := NewNetworkServer(conf.Bind().Host, certs, encs, ca, conf.ConnInfo(), nodepool, httpLog)
 	if err != nil {
 		return ctx, err
 	}
@@ -75,6 +89,12 @@ func ProcessQuicNetwork(ctx context.Context) (context.Context, error) {
 		_ = i.SetLogging(l)
 	}
 
+	if conf.MutualTLS() {
+		if err := enableMutualTLS(ln, nt, nodepool); err != nil {
+			return ctx, err
+		}
+	}
+
 	ctx = context.WithValue(ctx, ContextValueNetwork, nt)
 
 	return ctx, nil
@@ -94,9 +114,20 @@ func NewNetworkServer(
 		return nil, errors.Wrap(err, "json encoder needs for quic-network")
 	}
 
-	if qs, err := quicnetwork.NewPrimitiveQuicServer(bind, certs, httpLog); err != nil {
+	// NOTE the transport follows the scheme of publish url
+	newPrimitiveServer := quicnetwork.NewPrimitiveQuicServer
+	if network.IsTCPURL(connInfo.URL()) {
+		newPrimitiveServer = quicnetwork.NewPrimitiveTCPServer
+	}
+
+	qs, err := newPrimitiveServer(bind, certs, httpLog)
+	if err != nil {
 		return nil, err
-	} else if nqs, err := quicnetwork.NewServer(qs, encs, je, ca, connInfo, nodepool.Passthroughs); err != nil {
+	}
+
+	qs.SetPeerScores(nodepool.PeerScores())
+
+	if nqs, err := quicnetwork.NewServer(qs, encs, je, ca, connInfo, nodepool.Passthroughs); err != nil {
 		return nil, err
 	} else if err := nqs.Initialize(); err != nil {
 		return nil, err
@@ -105,10 +136,69 @@ func NewNetworkServer(
 	}
 }
 
+// newChannelConfig makes the ChannelConfig of the channels to the other nodes.
+// Under mutual TLS, the channels send the node certificate of local node and
+// accept only the node certificates of the nodes in nodepool.
+func newChannelConfig(
+	ln config.LocalNode, local node.Local, nodepool *network.Nodepool, encs *encoder.Encoders,
+) (quicnetwork.ChannelConfig, error) {
+	chConf := quicnetwork.ChannelConfig{CompactWire: ln.Network().CompactWire()}
+
+	if t := ln.Network().WireEncoder(); len(t) > 0 {
+		enc, err := encs.Encoder(t, "")
+		if err != nil {
+			return chConf, errors.Wrapf(err, "unknown wire encoder, %q", t)
+		}
+		chConf.WireEncoder = enc
+	}
+
+	if !ln.Network().MutualTLS() {
+		return chConf, nil
+	}
+
+	networkID := ln.NetworkID()
+
+	certs, err := network.GenerateNodeTLSCerts(ln.Network().ConnInfo().URL().Hostname(), local, networkID)
+	if err != nil {
+		return chConf, errors.Wrap(err, "failed to generate node certificate")
+	}
+
+	chConf.Certificates = certs
+	chConf.VerifyServer = func(cert *x509.Certificate) error {
+		_, err := network.NodeFromCertificate(cert, nodepool, networkID)
+
+		return err
+	}
+
+	return chConf, nil
+}
+
+// enableMutualTLS makes the network server to accept only the requests from
+// the nodes in nodepool except the public handlers.
+func enableMutualTLS(ln config.LocalNode, nt network.Server, nodepool *network.Nodepool) error {
+	qs, ok := nt.(*quicnetwork.Server)
+	if !ok {
+		return errors.Errorf("mutual tls is supported only by quic network server, not %T", nt)
+	}
+
+	networkID := ln.NetworkID()
+	qs.EnableMutualTLS(func(cert *x509.Certificate) (string, error) {
+		no, err := network.NodeFromCertificate(cert, nodepool, networkID)
+		if err != nil {
+			return "", err
+		}
+
+		return no.Address().String(), nil
+	}, quicnetwork.DefaultPublicHandlers...)
+
+	return nil
+}
+
 func LoadNodeChannel( // TODO remove
 	connInfo network.ConnInfo,
 	encs *encoder.Encoders,
 	connectionTimeout time.Duration,
+	chConf quicnetwork.ChannelConfig,
 ) (network.Channel, error) {
-	return discovery.LoadNodeChannel(connInfo, encs, connectionTimeout)
+	return discovery.LoadNodeChannel(connInfo, encs, connectionTimeout, chConf)
 }
@@ -0,0 +1,75 @@
+package process
+
+import (
+	"context"
+
+	"github.com/spikeekips/mitum/launch/config"
+	"github.com/spikeekips/mitum/launch/pm"
+	querynetwork "github.com/spikeekips/mitum/network/query"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+const ProcessNameQuery = "query"
+
+var ProcessorQuery pm.Process
+
+func init() {
+	if i, err := pm.NewProcess(
+		ProcessNameQuery,
+		[]string{
+			ProcessNameConfig,
+			ProcessNameDatabase,
+			ProcessNameBlockdata,
+			ProcessNameConsensusStates,
+		},
+		ProcessQuery,
+	); err != nil {
+		panic(err)
+	} else {
+		ProcessorQuery = i
+	}
+}
+
+// ProcessQuery prepares the query server. If query-bind is not set
+// in config, query server is not created.
+func ProcessQuery(ctx context.Context) (context.Context, error) {
+	var log *logging.Logging
+	if err := config.LoadLogContextValue(ctx, &log); err != nil {
+		return ctx, err
+	}
+
+	var conf config.LocalNode
+	if err := config.LoadConfigContextValue(ctx, &conf); err != nil {
+		return ctx, err
+	}
+
+	bind := conf.Network().QueryBind()
+	if bind == nil {
+		log.Log().Debug().Msg("query-bind is empty; query server disabled")
+
+		return ctx, nil
+	}
+
+	var enc *jsonenc.Encoder
+	if err := config.LoadJSONEncoderContextValue(ctx, &enc); err != nil {
+		return ctx, err
+	}
+
+	var db storage.Database
+	if err := LoadDatabaseContextValue(ctx, &db); err != nil {
+		return ctx, err
+	}
+
+	var bd blockdata.Blockdata
+	if err := LoadBlockdataContextValue(ctx, &bd); err != nil {
+		return ctx, err
+	}
+
+	sv := querynetwork.NewServer(bind.Host, enc, db, bd)
+	_ = sv.SetLogging(log)
+
+	return context.WithValue(ctx, ContextValueQueryServer, sv), nil
+}
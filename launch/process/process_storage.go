@@ -2,16 +2,22 @@ package process
 
 import (
 	"context"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/launch/config"
 	"github.com/spikeekips/mitum/launch/pm"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
 	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/storage/blockdata/s3"
+	leveldbstorage "github.com/spikeekips/mitum/storage/leveldb"
 	mongodbstorage "github.com/spikeekips/mitum/storage/mongodb"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/logging"
 )
 
 const (
@@ -62,8 +68,22 @@ func ProcessBlockdata(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
-	blockdata := localfs.NewBlockdata(conf.Path(), enc)
-	if err := blockdata.Initialize(); err != nil {
+	var bd blockdata.Blockdata
+	switch u := conf.URI(); {
+	case u == nil:
+		bd = localfs.NewBlockdata(conf.Path(), enc)
+	case u.Scheme == "s3":
+		client, err := s3.NewClientFromURI(u)
+		if err != nil {
+			return ctx, err
+		}
+
+		bd = s3.NewBlockdata(conf.Path(), client, enc)
+	default:
+		return ctx, errors.Errorf("unsupported blockdata uri, %q", u.Scheme)
+	}
+
+	if err := bd.Initialize(); err != nil {
 		return nil, err
 	}
 
@@ -74,7 +94,15 @@ func ProcessBlockdata(ctx context.Context) (context.Context, error) {
 		}
 	}
 
-	return context.WithValue(ctx, ContextValueBlockdata, blockdata), nil
+	// NOTE snapshots are kept under the local blockdata path
+	ss := blockdata.NewSnapshotStore(filepath.Join(conf.Path(), "snapshot"), bd.Writer(), enc)
+	if err := ss.Initialize(); err != nil {
+		return ctx, err
+	}
+
+	ctx = context.WithValue(ctx, ContextValueSnapshotStore, ss)
+
+	return context.WithValue(ctx, ContextValueBlockdata, bd), nil
 }
 
 func ProcessDatabase(ctx context.Context) (context.Context, error) {
@@ -87,6 +115,8 @@ func ProcessDatabase(ctx context.Context) (context.Context, error) {
 	switch {
 	case conf.URI().Scheme == "mongodb", conf.URI().Scheme == "mongodb+srv":
 		return processMongodbDatabase(ctx, l)
+	case conf.URI().Scheme == leveldbstorage.SchemeLeveldb, conf.URI().Scheme == leveldbstorage.SchemeMemory:
+		return processLeveldbDatabase(ctx, l)
 	default:
 		return ctx, errors.Errorf("unsupported database type, %q", conf.URI().Scheme)
 	}
@@ -114,5 +144,80 @@ func processMongodbDatabase(ctx context.Context, l config.LocalNode) (context.Co
 		return ctx, err
 	}
 
+	if err := migrateDatabase(ctx, st); err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ContextValueDatabase, st), nil
+}
+
+func processLeveldbDatabase(ctx context.Context, l config.LocalNode) (context.Context, error) {
+	conf := l.Storage().Database()
+
+	var encs *encoder.Encoders
+	if err := config.LoadEncodersContextValue(ctx, &encs); err != nil {
+		return ctx, err
+	}
+
+	st, err := leveldbstorage.NewDatabaseFromURI(conf.URI().String(), encs)
+	if err != nil {
+		return ctx, err
+	}
+
+	if err := st.Initialize(); err != nil {
+		return ctx, err
+	}
+
+	if err := migrateDatabase(ctx, st); err != nil {
+		return ctx, err
+	}
+
 	return context.WithValue(ctx, ContextValueDatabase, st), nil
 }
+
+// DatabaseMigrations returns the registered schema migrations of the database.
+func DatabaseMigrations(db storage.Database) (*storage.Migrations, error) {
+	switch db.(type) {
+	case *mongodbstorage.Database:
+		return mongodbstorage.Migrations, nil
+	case *leveldbstorage.Database:
+		return leveldbstorage.Migrations, nil
+	default:
+		return nil, errors.Errorf("unknown database, %T", db)
+	}
+}
+
+// migrateDatabase upgrades the schema of database to the latest version. If
+// ContextValueSkipDatabaseMigration is true, it is skipped; the migrate
+// command runs the migrations by itself.
+func migrateDatabase(ctx context.Context, db storage.Database) error {
+	var skip bool
+	if err := LoadSkipDatabaseMigrationContextValue(ctx, &skip); err != nil {
+		if !errors.Is(err, util.ContextValueNotFoundError) {
+			return err
+		}
+	}
+
+	if skip {
+		return nil
+	}
+
+	ms, err := DatabaseMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	mr := storage.NewMigrator(db, ms, false)
+
+	var log *logging.Logging
+	switch err := config.LoadLogContextValue(ctx, &log); {
+	case err == nil:
+		_ = mr.SetLogging(log)
+	case !errors.Is(err, util.ContextValueNotFoundError):
+		return err
+	}
+
+	_, err = mr.Migrate(ctx, ms.Latest())
+
+	return err
+}
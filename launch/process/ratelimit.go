@@ -10,6 +10,7 @@ import (
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 	"github.com/ulule/limiter/v3"
 	"github.com/ulule/limiter/v3/drivers/middleware/stdlib"
 	limitermemory "github.com/ulule/limiter/v3/drivers/store/memory"
@@ -101,6 +102,8 @@ func (mw *RateLimitMiddleware) limit(w http.ResponseWriter, r *http.Request) boo
 
 		return false
 	} else if rate.Limit < 1 || rate.Period < 1 { // NOTE block all requests
+		metrics.RateLimitRejected.WithLabelValues(metrics.HandlerLabel(r)).Inc()
+
 		network.HTTPError(w, http.StatusTooManyRequests)
 
 		return true
@@ -116,6 +119,8 @@ func (mw *RateLimitMiddleware) limit(w http.ResponseWriter, r *http.Request) boo
 	w.Header().Add("X-RateLimit-Reset", strconv.FormatInt(rctx.Reset, 10))
 
 	if rctx.Reached {
+		metrics.RateLimitRejected.WithLabelValues(metrics.HandlerLabel(r)).Inc()
+
 		stdlib.DefaultLimitReachedHandler(w, r)
 
 		return true
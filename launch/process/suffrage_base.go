@@ -17,12 +17,35 @@ type BaseSuffrage struct {
 	sync.RWMutex
 	*logging.Logging
 	name           string
-	nodes          []base.Address
 	numberOfActing uint
 	cacheSize      int
 	cache          *lru.TwoQueueCache
 	electFunc      ActinfSuffrageElectFunc
-	nodesMap       map[string]struct{}
+	// NOTE history keeps the suffrage nodes by height.
+	history []suffrageNodes
+	// NOTE height is the current height, which is being agreed; IsInside and
+	// Nodes use the suffrage nodes of it.
+	height base.Height
+}
+
+type suffrageNodes struct {
+	height   base.Height
+	nodes    []base.Address
+	nodesMap map[string]struct{}
+}
+
+func newSuffrageNodes(height base.Height, nodes []base.Address) suffrageNodes {
+	ns := make([]base.Address, len(nodes))
+	copy(ns, nodes)
+
+	base.SortAddresses(ns)
+
+	nm := map[string]struct{}{}
+	for i := range ns {
+		nm[ns[i].String()] = struct{}{}
+	}
+
+	return suffrageNodes{height: height, nodes: ns, nodesMap: nm}
 }
 
 func NewBaseSuffrage(
@@ -36,11 +59,6 @@ func NewBaseSuffrage(
 		return nil, errors.Errorf("nodes is under number of acting, %d < %d", len(nodes), numberOfActing)
 	}
 
-	nm := map[string]struct{}{}
-	for i := range nodes {
-		nm[nodes[i].String()] = struct{}{}
-	}
-
 	var cache *lru.TwoQueueCache
 	if cacheSize > 0 {
 		cache, _ = lru.New2Q(cacheSize)
@@ -52,12 +70,12 @@ func NewBaseSuffrage(
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
 			return c.Str("module", name)
 		}),
-		nodes:          nodes,
 		numberOfActing: numberOfActing,
 		cacheSize:      cacheSize,
 		cache:          cache,
 		electFunc:      electFunc,
-		nodesMap:       nm,
+		history:        []suffrageNodes{newSuffrageNodes(base.NilHeight, nodes)},
+		height:         base.NilHeight,
 	}, nil
 }
 
@@ -78,7 +96,10 @@ func (sf *BaseSuffrage) Name() string {
 }
 
 func (sf *BaseSuffrage) IsInside(a base.Address) bool {
-	_, found := sf.nodesMap[a.String()]
+	sf.RLock()
+	defer sf.RUnlock()
+
+	_, found := sf.nodesByHeight(sf.height).nodesMap[a.String()]
 
 	return found
 }
@@ -118,8 +139,71 @@ func (sf *BaseSuffrage) IsProposer(height base.Height, round base.Round, n base.
 	return af.Proposer().Equal(n), nil
 }
 
+// Nodes returns the suffrage nodes at the current height.
 func (sf *BaseSuffrage) Nodes() []base.Address {
-	return sf.nodes
+	sf.RLock()
+	defer sf.RUnlock()
+
+	return sf.nodesByHeight(sf.height).nodes
+}
+
+// NodesByHeight returns the suffrage nodes at the given height.
+func (sf *BaseSuffrage) NodesByHeight(height base.Height) []base.Address {
+	sf.RLock()
+	defer sf.RUnlock()
+
+	return sf.nodesByHeight(height).nodes
+}
+
+// SetHeight sets the current height. The lower height than the current is
+// ignored.
+func (sf *BaseSuffrage) SetHeight(height base.Height) {
+	sf.Lock()
+	defer sf.Unlock()
+
+	if height > sf.height {
+		sf.height = height
+	}
+}
+
+func (sf *BaseSuffrage) nodesByHeight(height base.Height) suffrageNodes {
+	for i := len(sf.history) - 1; i > 0; i-- {
+		if height >= sf.history[i].height {
+			return sf.history[i]
+		}
+	}
+
+	return sf.history[0]
+}
+
+// SetNodes sets the new suffrage nodes, which will be used from the given
+// height. The height should be higher than the height of the current nodes.
+func (sf *BaseSuffrage) SetNodes(height base.Height, nodes []base.Address) error {
+	sf.Lock()
+	defer sf.Unlock()
+
+	if len(nodes) < 1 {
+		return errors.Errorf("empty suffrage nodes")
+	}
+
+	if last := sf.history[len(sf.history)-1]; height <= last.height {
+		return errors.Errorf("suffrage nodes already set at higher height, %d >= %d", last.height, height)
+	}
+
+	sf.history = append(sf.history, newSuffrageNodes(height, nodes))
+
+	// NOTE acting suffrage from the height should be elected again
+	if sf.cache != nil {
+		for _, k := range sf.cache.Keys() {
+			if i, found := sf.cache.Peek(k); found && i.(base.ActingSuffrage).Height() >= height {
+				sf.cache.Remove(k)
+			}
+		}
+	}
+
+	sf.Log().Debug().Int64("height", height.Int64()).Interface("nodes", nodes).Msg("new suffrage nodes set")
+
+	return nil
 }
 
 func (*BaseSuffrage) cacheKey(height base.Height, round base.Round) string {
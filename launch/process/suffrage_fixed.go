@@ -67,13 +67,28 @@ func NewFixedSuffrage(
 }
 
 func (sf *FixedSuffrage) electWithProposer(height base.Height, round base.Round) (base.ActingSuffrage, error) {
-	return base.NewActingSuffrage(height, round, sf.proposer, sf.Nodes()), nil
+	nodes := sf.NodesByHeight(height)
+
+	// NOTE if proposer left suffrage, proposer is elected from nodes
+	for i := range nodes {
+		if nodes[i].Equal(sf.proposer) {
+			return base.NewActingSuffrage(height, round, sf.proposer, nodes), nil
+		}
+	}
+
+	return sf.elect(height, round)
 }
 
 func (sf *FixedSuffrage) elect(height base.Height, round base.Round) (base.ActingSuffrage, error) {
-	pos := (uint64(height) + round.Uint64()) % uint64(sf.NumberOfActing())
+	nodes := sf.NodesByHeight(height)
+
+	na := sf.NumberOfActing()
+	if n := uint(len(nodes)); n < na {
+		na = n
+	}
+
+	pos := (uint64(height) + round.Uint64()) % uint64(na)
 
-	nodes := sf.Nodes()
 	return base.NewActingSuffrage(height, round, nodes[pos], nodes), nil
 }
 
@@ -83,7 +98,7 @@ func (sf *FixedSuffrage) Verbose() string {
 		"cache_size":       sf.CacheSize(),
 		"number_of_acting": sf.NumberOfActing(),
 		"proposer":         sf.proposer,
-		"nodes":            sf.nodes,
+		"nodes":            sf.Nodes(),
 	}
 
 	b, err := jsonenc.Marshal(m)
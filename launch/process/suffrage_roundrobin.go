@@ -39,7 +39,7 @@ func NewRoundrobinSuffrage(
 }
 
 func (sf *RoundrobinSuffrage) elect(height base.Height, round base.Round) (base.ActingSuffrage, error) {
-	nodes := sf.Nodes()
+	nodes := sf.NodesByHeight(height)
 
 	na := int(sf.numberOfActing)
 	if len(nodes) < na {
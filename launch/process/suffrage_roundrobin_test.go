@@ -75,6 +75,38 @@ func (t *testRoundrobinSuffrage) TestActingSuffrageNotSufficient() {
 	t.Contains(err.Error(), "under number of acting")
 }
 
+func (t *testRoundrobinSuffrage) TestSetNodesByHeight() {
+	nodes := t.nodes(3)
+
+	sf, err := NewRoundrobinSuffrage(nodes, 3, 10, nil)
+	t.NoError(err)
+
+	sf.SetHeight(base.Height(33))
+
+	joiner := base.RandomStringAddress()
+	newNodes := []base.Address{nodes[1], nodes[2], joiner}
+	t.NoError(sf.SetNodes(base.Height(34), newNodes))
+
+	// NOTE before the height, joining node is not inside and leaving node is
+	// still inside
+	t.False(sf.IsInside(joiner))
+	t.True(sf.IsInside(nodes[0]))
+	t.Equal(3, len(sf.Nodes()))
+	t.Equal(3, len(sf.NodesByHeight(base.Height(34))))
+
+	sf.SetHeight(base.Height(34))
+	t.True(sf.IsInside(joiner))
+	t.False(sf.IsInside(nodes[0]))
+
+	// NOTE lower height is ignored
+	sf.SetHeight(base.Height(33))
+	t.True(sf.IsInside(joiner))
+
+	for _, n := range sf.NodesByHeight(base.Height(33)) {
+		t.False(n.Equal(joiner))
+	}
+}
+
 func TestRoundrobinSuffrage(t *testing.T) {
 	suite.Run(t, new(testRoundrobinSuffrage))
 }
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
@@ -42,32 +43,12 @@ func FetchBlockdataThruChannel(handler BlockdataHandler, item block.BlockdataMap
 		return nil, errors.Errorf("%q not yet supported", u.Scheme)
 	}
 
-	b, err := io.ReadAll(ro)
-	if err != nil {
-		return nil, storage.MergeFSError(err)
-	}
-	bo := bytes.NewReader(b)
-
-	// NOTE check checksum
-	if i, err := util.GenerateChecksum(bo); err != nil {
-		return nil, err
-	} else if item.Checksum() != i {
-		return nil, errors.Errorf("block data, %q checksum does not match; %q != %q", item.Type(), item.Checksum(), i)
-	} else if _, err := bo.Seek(0, 0); err != nil {
-		return nil, err
-	}
-
-	rc := util.NewNilReadCloser(bo)
-
-	// NOTE is compressed?
-	switch ext := filepath.Ext(item.URL()); ext {
-	case ".gz":
-		return util.NewGzipReader(rc)
-	default:
-		return rc, nil
-	}
+	return readBlockdata(item, ro)
 }
 
+// FetchBlockdataFromRemote fetches the block data item from the remote url like
+// the object storage. Like FetchBlockdataThruChannel, the checksum is checked
+// and the compressed data is decompressed.
 func FetchBlockdataFromRemote(ctx context.Context, item block.BlockdataMapItem) (io.ReadCloser, error) {
 	u, err := ParseURL(item.URL(), false)
 	if err != nil {
@@ -78,7 +59,16 @@ func FetchBlockdataFromRemote(ctx context.Context, item block.BlockdataMapItem)
 	case "file":
 		return nil, errors.Errorf("%q is not remote", u.String())
 	case "http", "https": // nolint:goconst
-		return FetchBlockdataFromHTTP(ctx, item)
+		r, err := FetchBlockdataFromHTTP(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			_ = r.Close()
+		}()
+
+		return readBlockdata(item, r)
 	default:
 		return nil, errors.Errorf("%q not yet supported", u.Scheme)
 	}
@@ -123,3 +113,73 @@ func FetchBlockdataFromHTTP(ctx context.Context, item block.BlockdataMapItem) (i
 		return nil, errors.Errorf("failed to request blockdata: %q", res.Status)
 	}
 }
+
+func readBlockdata(item block.BlockdataMapItem, r io.Reader) (io.ReadCloser, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, storage.MergeFSError(err)
+	}
+	bo := bytes.NewReader(b)
+
+	// NOTE check checksum
+	if i, err := util.GenerateChecksum(bo); err != nil {
+		return nil, err
+	} else if item.Checksum() != i {
+		return nil, errors.Errorf("block data, %q checksum does not match; %q != %q", item.Type(), item.Checksum(), i)
+	} else if _, err := bo.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	rc := util.NewNilReadCloser(bo)
+
+	// NOTE is compressed?
+	switch ext := filepath.Ext(item.URL()); ext {
+	case ".gz":
+		return util.NewGzipReader(rc)
+	default:
+		return rc, nil
+	}
+}
+
+// ReadBlockdataRange reads the part of the raw block data item thru
+// BlockdataByChecksumHandler. It returns the total size of item with the
+// bytes.
+func ReadBlockdataRange(
+	handler BlockdataByChecksumHandler,
+	height base.Height,
+	item block.BlockdataMapItem,
+	offset, length int64,
+) (io.ReadCloser, int64, error) {
+	f, err := handler(height, item.Type(), item.Checksum())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, storage.MergeFSError(err)
+	}
+
+	if offset < 0 || (offset > 0 && offset >= size) {
+		return nil, 0, errors.Errorf("invalid offset, %d of block data, %q; size=%d", offset, item.Type(), size)
+	}
+
+	if length < 1 || offset+length > size {
+		length = size - offset
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, storage.MergeFSError(err)
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(f, b); err != nil {
+		return nil, 0, storage.MergeFSError(err)
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), size, nil
+}
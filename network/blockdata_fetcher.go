@@ -0,0 +1,777 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+var (
+	// DefaultBlockdataChunkSize is the size of the part of block data item,
+	// which is fetched from one channel by BlockdataFetcher.
+	DefaultBlockdataChunkSize int64 = 1 << 22 // NOTE 4MiB
+	// BlockdataChunkTimeout is the timeout to fetch one chunk; the slow
+	// channel is dropped.
+	BlockdataChunkTimeout = time.Second * 30
+)
+
+var blockdataChunkSizeFile = "size"
+
+// BlockdataFetcher fetches the block data item from the multiple channels at
+// once; the item is split into chunks by it's size and the chunks are fetched
+// from the channels by Channel.BlockdataRange. The size of item is asked to all
+// the channels and the size reported by more channels is tried first. The
+// fetched chunks are kept in the directory by the checksum of item, so the
+// interrupted fetching can be resumed; the directory is removed when the
+// fetching is finished or failed. The channel, which fails to give the chunk
+// in time, is dropped and is not used again by the fetcher. The channel, which
+// gives the wrong size or bytes, is dropped only after the bytes of item are
+// proven by the checksum.
+type BlockdataFetcher struct {
+	sync.RWMutex
+	*logging.Logging
+	dir       string
+	chunkSize int64
+	dropped   map[string]error
+}
+
+// NewBlockdataFetcher returns new BlockdataFetcher. If dir is empty, the
+// chunks are not kept.
+func NewBlockdataFetcher(dir string, chunkSize int64) *BlockdataFetcher {
+	if chunkSize < 1 {
+		chunkSize = DefaultBlockdataChunkSize
+	}
+
+	return &BlockdataFetcher{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "blockdata-fetcher")
+		}),
+		dir:       dir,
+		chunkSize: chunkSize,
+		dropped:   map[string]error{},
+	}
+}
+
+// Fetch fetches the block data item from the channels and checks the
+// checksum. Like FetchBlockdataThruChannel, the compressed data is
+// decompressed.
+func (bf *BlockdataFetcher) Fetch(
+	ctx context.Context,
+	height base.Height,
+	item block.BlockdataMapItem,
+	chs []Channel,
+) (io.ReadCloser, error) {
+	if err := isValidChecksumPath(item.Checksum()); err != nil {
+		return nil, err
+	}
+
+	f := &blockdataFetch{
+		bf:     bf,
+		height: height,
+		item:   item,
+		origin: bf.filterDropped(chs),
+		sizes:  map[string]int64{},
+		given:  map[string]map[int64][sha256.Size]byte{},
+	}
+
+	if len(f.origin) < 1 {
+		return nil, errors.Errorf("no available channels to fetch block data")
+	}
+
+	f.all = f.origin
+
+	b, err := f.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return readBlockdata(item, bytes.NewReader(b))
+}
+
+// Dropped returns the dropped channels and the reasons.
+func (bf *BlockdataFetcher) Dropped() map[string]error {
+	bf.RLock()
+	defer bf.RUnlock()
+
+	m := map[string]error{}
+	for i := range bf.dropped {
+		m[i] = bf.dropped[i]
+	}
+
+	return m
+}
+
+func (bf *BlockdataFetcher) drop(ch Channel, err error) {
+	bf.Lock()
+	defer bf.Unlock()
+
+	bf.dropped[ch.ConnInfo().String()] = err
+
+	bf.Log().Debug().Err(err).Stringer("channel", ch.ConnInfo()).Msg("channel dropped")
+}
+
+func (bf *BlockdataFetcher) isDropped(ch Channel) bool {
+	bf.RLock()
+	defer bf.RUnlock()
+
+	_, found := bf.dropped[ch.ConnInfo().String()]
+
+	return found
+}
+
+func (bf *BlockdataFetcher) filterDropped(chs []Channel) []Channel {
+	var filtered []Channel
+	for i := range chs {
+		if chs[i] == nil || bf.isDropped(chs[i]) {
+			continue
+		}
+
+		filtered = append(filtered, chs[i])
+	}
+
+	return filtered
+}
+
+// errBlockdataSizeMismatch is returned when the channel reports the different
+// size of item from the size being fetched.
+var errBlockdataSizeMismatch = util.NewError("size of block data does not match")
+
+type blockdataFetch struct {
+	sync.Mutex
+	bf     *BlockdataFetcher
+	height base.Height
+	item   block.BlockdataMapItem
+	// NOTE origin is the given channels, all is the channels, which are not
+	// failed and chs is the channels for the current size.
+	origin []Channel
+	all    []Channel
+	chs    []Channel
+	size   int64
+	chunks map[int64][]byte
+	owners map[int64]string
+	// NOTE sizes is the reported size by channel and given is the hashes of
+	// chunks by channel; they are checked after the bytes are proven.
+	sizes map[string]int64
+	given map[string]map[int64][sha256.Size]byte
+}
+
+func (f *blockdataFetch) fetch(ctx context.Context) ([]byte, error) {
+	defer f.clean()
+
+	if err := f.loadSizes(ctx); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, size := range f.candidateSizes() {
+		f.setSize(size)
+
+		b, err := f.fetchBySize(ctx)
+		if err == nil {
+			f.dropWrongChannels(b)
+
+			return b, nil
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		lastErr = err
+
+		f.clean()
+	}
+
+	if lastErr == nil {
+		lastErr = errors.Errorf("no available channels to fetch block data")
+	}
+
+	return nil, errors.Wrap(lastErr, "failed to fetch block data")
+}
+
+// fetchBySize fetches the chunks from the channels, which reported the current
+// size.
+func (f *blockdataFetch) fetchBySize(ctx context.Context) ([]byte, error) {
+	if err := f.fetchChunks(ctx); err != nil {
+		return nil, err
+	}
+
+	b, err := f.verify()
+	if err == nil {
+		return b, nil
+	}
+
+	// NOTE the bytes do not match with checksum; one of the channels gives
+	// wrong bytes. The chunks of each channel are fetched again from the other
+	// channels, until the bytes match. The channel is not dropped here, because
+	// which one is wrong is not known yet.
+	chs := f.channels()
+	owners := f.chunkOwners()
+	for i := range owners {
+		owner := owners[i]
+
+		f.setChannels(chs)
+		f.removeChannel(owner)
+		f.removeChunksOf(owner)
+
+		if len(f.channels()) < 1 {
+			continue
+		}
+
+		if err := f.fetchChunks(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if b, err := f.verify(); err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, err
+}
+
+// loadSizes asks the size of item to the all channels.
+func (f *blockdataFetch) loadSizes(ctx context.Context) error {
+	chs := f.allChannels()
+
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+
+	for i := range chs {
+		ch := chs[i]
+
+		go func() {
+			defer wg.Done()
+
+			size, err := f.fetchSize(ctx, ch)
+			if err != nil {
+				f.failed(ch, err)
+
+				return
+			}
+
+			f.Lock()
+			f.sizes[ch.ConnInfo().String()] = size
+			f.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(f.allChannels()) < 1 {
+		return errors.Errorf("no available channels to fetch block data")
+	}
+
+	return nil
+}
+
+// candidateSizes returns the reported sizes; the size reported by more
+// channels comes first.
+func (f *blockdataFetch) candidateSizes() []int64 {
+	f.Lock()
+	defer f.Unlock()
+
+	counts := map[int64]int{}
+	for i := range f.sizes {
+		counts[f.sizes[i]]++
+	}
+
+	sizes := make([]int64, 0, len(counts))
+	for i := range counts {
+		sizes = append(sizes, i)
+	}
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if counts[sizes[i]] == counts[sizes[j]] {
+			return sizes[i] < sizes[j]
+		}
+
+		return counts[sizes[i]] > counts[sizes[j]]
+	})
+
+	return sizes
+}
+
+// setSize sets the size to be fetched and the channels, which reported the
+// size. The chunks of previous fetching are loaded only when they have the
+// same size.
+func (f *blockdataFetch) setSize(size int64) {
+	f.Lock()
+
+	f.size = size
+	f.chunks = map[int64][]byte{}
+	f.owners = map[int64]string{}
+
+	var chs []Channel
+	for i := range f.all {
+		if s, found := f.sizes[f.all[i].ConnInfo().String()]; found && s == size {
+			chs = append(chs, f.all[i])
+		}
+	}
+	f.chs = chs
+
+	f.Unlock()
+
+	if s, found := f.loadSizeFile(); found && s == size {
+		f.loadChunks()
+	} else {
+		f.clean()
+	}
+
+	f.saveSizeFile()
+}
+
+// dropWrongChannels drops the channels, which reported the wrong size or gave
+// the wrong chunks; the given bytes are proven by the checksum.
+func (f *blockdataFetch) dropWrongChannels(b []byte) {
+	f.Lock()
+	defer f.Unlock()
+
+	size := int64(len(b))
+
+	for i := range f.origin {
+		ch := f.origin[i]
+		s := ch.ConnInfo().String()
+
+		if reported, found := f.sizes[s]; found && reported != size {
+			f.bf.drop(ch, errors.Errorf("gave wrong size of block data, %q; %d != %d", f.item.Checksum(), reported, size))
+
+			continue
+		}
+
+		for index, h := range f.given[s] {
+			offset := index * f.bf.chunkSize
+			end := offset + f.bf.chunkSize
+			if end > size {
+				end = size
+			}
+
+			if offset > size || sha256.Sum256(b[offset:end]) != h {
+				f.bf.drop(ch, errors.Errorf("gave wrong chunk, %d of block data, %q", index, f.item.Checksum()))
+
+				break
+			}
+		}
+	}
+}
+
+// fetchChunks fetches the missing chunks from the channels concurrently; each
+// channel fetches the next chunk after the previous one, so the faster channel
+// fetches more chunks. The chunk of the failed channel is fetched again from
+// the other channels.
+func (f *blockdataFetch) fetchChunks(ctx context.Context) error {
+	for {
+		missing := f.missingChunks()
+		if len(missing) < 1 {
+			return nil
+		}
+
+		chs := f.channels()
+		if len(chs) < 1 {
+			return errors.Errorf("no available channels to fetch block data")
+		}
+
+		queue := make(chan int64, len(missing))
+		for i := range missing {
+			queue <- missing[i]
+		}
+		close(queue)
+
+		var wg sync.WaitGroup
+		wg.Add(len(chs))
+
+		for i := range chs {
+			ch := chs[i]
+
+			go func() {
+				defer wg.Done()
+
+				for index := range queue {
+					if ctx.Err() != nil {
+						return
+					}
+
+					b, err := f.fetchChunk(ctx, ch, index)
+					if err != nil {
+						f.failed(ch, err)
+
+						return
+					}
+
+					f.setChunk(index, b, ch.ConnInfo().String())
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// failed removes the channel from this fetching. If the channel does not have
+// the item or reports the different size, it is not dropped from
+// BlockdataFetcher.
+func (f *blockdataFetch) failed(ch Channel, err error) {
+	switch {
+	case errors.Is(err, util.NotFoundError):
+	case errors.Is(err, errBlockdataSizeMismatch):
+	case errors.Is(err, context.Canceled):
+	default:
+		f.bf.drop(ch, err)
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	s := ch.ConnInfo().String()
+	f.all = filterChannel(f.all, s)
+	f.chs = filterChannel(f.chs, s)
+}
+
+func (f *blockdataFetch) fetchSize(ctx context.Context, ch Channel) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, BlockdataChunkTimeout)
+	defer cancel()
+
+	r, size, err := ch.BlockdataRange(ctx, f.height, f.item, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = r.Close()
+
+	if size < 0 {
+		return 0, errors.Errorf("invalid size of block data, %d", size)
+	}
+
+	return size, nil
+}
+
+func (f *blockdataFetch) fetchChunk(ctx context.Context, ch Channel, index int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, BlockdataChunkTimeout)
+	defer cancel()
+
+	offset := index * f.bf.chunkSize
+
+	r, size, err := ch.BlockdataRange(ctx, f.height, f.item, offset, f.bf.chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if size != f.size {
+		f.Lock()
+		f.sizes[ch.ConnInfo().String()] = size
+		f.Unlock()
+
+		return nil, errBlockdataSizeMismatch.Errorf("%d != %d", size, f.size)
+	}
+
+	expected := f.bf.chunkSize
+	if offset+expected > size {
+		expected = size - offset
+	}
+
+	if int64(len(b)) != expected {
+		return nil, errors.Errorf("size of chunk does not match; %d != %d", len(b), expected)
+	}
+
+	return b, nil
+}
+
+func (f *blockdataFetch) verify() ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	buf := bytes.NewBuffer(make([]byte, 0, f.size))
+	for i := int64(0); i < f.numberOfChunks(); i++ {
+		_, _ = buf.Write(f.chunks[i])
+	}
+
+	b := buf.Bytes()
+	switch checksum, err := util.GenerateChecksum(bytes.NewReader(b)); {
+	case err != nil:
+		return nil, err
+	case checksum != f.item.Checksum():
+		return nil, errors.Errorf("block data, %q checksum does not match; %q != %q",
+			f.item.Type(), f.item.Checksum(), checksum)
+	default:
+		return b, nil
+	}
+}
+
+func (f *blockdataFetch) numberOfChunks() int64 {
+	n := f.size / f.bf.chunkSize
+	if f.size%f.bf.chunkSize > 0 || n < 1 {
+		n++
+	}
+
+	return n
+}
+
+func (f *blockdataFetch) missingChunks() []int64 {
+	f.Lock()
+	defer f.Unlock()
+
+	var missing []int64
+	for i := int64(0); i < f.numberOfChunks(); i++ {
+		if _, found := f.chunks[i]; !found {
+			missing = append(missing, i)
+		}
+	}
+
+	return missing
+}
+
+func (f *blockdataFetch) setChunk(index int64, b []byte, owner string) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.chunks[index] = b
+	f.owners[index] = owner
+
+	if len(owner) > 0 {
+		if _, found := f.given[owner]; !found {
+			f.given[owner] = map[int64][sha256.Size]byte{}
+		}
+
+		f.given[owner][index] = sha256.Sum256(b)
+	}
+
+	if d := f.directory(); len(d) > 0 {
+		p := filepath.Join(d, strconv.FormatInt(index, 10))
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			f.bf.Log().Debug().Err(err).Str("path", p).Msg("failed to save chunk")
+		}
+	}
+}
+
+func (f *blockdataFetch) removeChunksOf(owner string) {
+	f.Lock()
+	defer f.Unlock()
+
+	for i := range f.owners {
+		if f.owners[i] != owner {
+			continue
+		}
+
+		delete(f.chunks, i)
+		delete(f.owners, i)
+
+		if d := f.directory(); len(d) > 0 {
+			_ = os.Remove(filepath.Join(d, strconv.FormatInt(i, 10)))
+		}
+	}
+}
+
+// chunkOwners returns the channels, which gave chunks; the resumed chunks
+// have empty owner and it comes first.
+func (f *blockdataFetch) chunkOwners() []string {
+	f.Lock()
+	defer f.Unlock()
+
+	founds := map[string]struct{}{}
+	owners := []string{""}
+	founds[""] = struct{}{}
+	for i := int64(0); i < f.numberOfChunks(); i++ {
+		o := f.owners[i]
+		if _, found := founds[o]; found {
+			continue
+		}
+
+		founds[o] = struct{}{}
+		owners = append(owners, o)
+	}
+
+	return owners
+}
+
+func (f *blockdataFetch) allChannels() []Channel {
+	f.Lock()
+	defer f.Unlock()
+
+	chs := make([]Channel, len(f.all))
+	copy(chs, f.all)
+
+	return chs
+}
+
+func (f *blockdataFetch) channels() []Channel {
+	f.Lock()
+	defer f.Unlock()
+
+	chs := make([]Channel, len(f.chs))
+	copy(chs, f.chs)
+
+	return chs
+}
+
+// setChannels sets the channels for the current size except the failed ones.
+func (f *blockdataFetch) setChannels(chs []Channel) {
+	f.Lock()
+	defer f.Unlock()
+
+	var filtered []Channel
+	for i := range chs {
+		for j := range f.all {
+			if chs[i] == f.all[j] {
+				filtered = append(filtered, chs[i])
+
+				break
+			}
+		}
+	}
+
+	f.chs = filtered
+}
+
+func (f *blockdataFetch) removeChannel(s string) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.chs = filterChannel(f.chs, s)
+}
+
+func filterChannel(chs []Channel, s string) []Channel {
+	var filtered []Channel
+	for i := range chs {
+		if chs[i].ConnInfo().String() != s {
+			filtered = append(filtered, chs[i])
+		}
+	}
+
+	return filtered
+}
+
+// directory returns the directory to keep the chunks. If the directory of
+// BlockdataFetcher is empty, it returns empty string.
+func (f *blockdataFetch) directory() string {
+	if len(f.bf.dir) < 1 {
+		return ""
+	}
+
+	d := filepath.Join(f.bf.dir, f.item.Checksum())
+	if err := os.MkdirAll(d, 0o700); err != nil {
+		f.bf.Log().Debug().Err(err).Str("directory", d).Msg("failed to create chunk directory")
+
+		return ""
+	}
+
+	return d
+}
+
+func (f *blockdataFetch) loadSizeFile() (int64, bool) {
+	d := f.directory()
+	if len(d) < 1 {
+		return 0, false
+	}
+
+	b, err := os.ReadFile(filepath.Join(d, blockdataChunkSizeFile))
+	if err != nil {
+		return 0, false
+	}
+
+	size, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || size < 0 {
+		return 0, false
+	}
+
+	return size, true
+}
+
+func (f *blockdataFetch) saveSizeFile() {
+	d := f.directory()
+	if len(d) < 1 {
+		return
+	}
+
+	_ = os.WriteFile(filepath.Join(d, blockdataChunkSizeFile), []byte(strconv.FormatInt(f.size, 10)), 0o600)
+}
+
+// loadChunks loads the chunks, which were fetched before.
+func (f *blockdataFetch) loadChunks() {
+	f.Lock()
+	defer f.Unlock()
+
+	d := f.directory()
+	if len(d) < 1 {
+		return
+	}
+
+	for i := int64(0); i < f.numberOfChunks(); i++ {
+		if _, found := f.chunks[i]; found {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(d, strconv.FormatInt(i, 10)))
+		if err != nil {
+			continue
+		}
+
+		expected := f.bf.chunkSize
+		if (i+1)*f.bf.chunkSize > f.size {
+			expected = f.size - i*f.bf.chunkSize
+		}
+
+		if int64(len(b)) != expected {
+			continue
+		}
+
+		f.chunks[i] = b
+		f.owners[i] = ""
+	}
+}
+
+func (f *blockdataFetch) clean() {
+	if len(f.bf.dir) < 1 {
+		return
+	}
+
+	if err := os.RemoveAll(filepath.Join(f.bf.dir, f.item.Checksum())); err != nil {
+		f.bf.Log().Debug().Err(storage.MergeFSError(err)).Msg("failed to clean chunks")
+	}
+}
+
+func isValidChecksumPath(s string) error {
+	switch {
+	case len(s) < 1:
+		return errors.Errorf("empty checksum")
+	case filepath.Base(s) != s, s == ".", s == "..":
+		return util.WrongTypeError.Errorf("invalid checksum, %q", s)
+	default:
+		return nil
+	}
+}
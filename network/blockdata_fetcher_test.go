@@ -0,0 +1,345 @@
+// +build test
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type bytesReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (bytesReadSeekCloser) Close() error {
+	return nil
+}
+
+type slowBlockdataChannel struct {
+	*DummyChannel
+	delay time.Duration
+}
+
+func (ch *slowBlockdataChannel) BlockdataRange(
+	ctx context.Context,
+	height base.Height,
+	item block.BlockdataMapItem,
+	offset, length int64,
+) (io.ReadCloser, int64, error) {
+	select {
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	case <-time.After(ch.delay):
+	}
+
+	return ch.DummyChannel.BlockdataRange(ctx, height, item, offset, length)
+}
+
+type testBlockdataFetcher struct {
+	suite.Suite
+}
+
+func (t *testBlockdataFetcher) newItem(size int) ([]byte, block.BlockdataMapItem) {
+	b := bytes.Repeat(util.UUID().Bytes(), size/16+1)[:size]
+
+	checksum, err := util.GenerateChecksum(bytes.NewReader(b))
+	t.NoError(err)
+
+	return b, block.NewBaseBlockdataMapItem(block.BlockdataManifest, checksum, "file:///0/manifest.jsonld")
+}
+
+func (t *testBlockdataFetcher) newChannel(name string, b []byte, counter *int64, l *sync.Mutex) *DummyChannel {
+	ch := NewDummyChannel(NewNilConnInfo(name))
+	ch.SetBlockdataByChecksumHandler(func(_ base.Height, _, _ string) (io.ReadSeekCloser, error) {
+		if counter != nil {
+			l.Lock()
+			*counter++
+			l.Unlock()
+		}
+
+		return bytesReadSeekCloser{Reader: bytes.NewReader(b)}, nil
+	})
+
+	return ch
+}
+
+func (t *testBlockdataFetcher) fetch(bf *BlockdataFetcher, item block.BlockdataMapItem, chs []Channel) ([]byte, error) {
+	r, err := bf.Fetch(context.Background(), base.Height(33), item, chs)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return io.ReadAll(r)
+}
+
+func (t *testBlockdataFetcher) TestFetch() {
+	b, item := t.newItem(1000)
+
+	var l sync.Mutex
+	counters := make([]int64, 3)
+	chs := make([]Channel, len(counters))
+	for i := range chs {
+		chs[i] = t.newChannel(strconv.Itoa(i), b, &counters[i], &l)
+	}
+
+	bf := NewBlockdataFetcher(t.T().TempDir(), 100)
+
+	rb, err := t.fetch(bf, item, chs)
+	t.NoError(err)
+	t.Equal(b, rb)
+
+	// NOTE the size is asked to the all channels
+	var total int64
+	for i := range counters {
+		total += counters[i]
+	}
+	t.Equal(int64(10+len(chs)), total)
+	t.Empty(bf.Dropped())
+}
+
+func (t *testBlockdataFetcher) TestSmallerThanChunk() {
+	b, item := t.newItem(33)
+
+	bf := NewBlockdataFetcher("", 100)
+
+	rb, err := t.fetch(bf, item, []Channel{t.newChannel("a", b, nil, nil)})
+	t.NoError(err)
+	t.Equal(b, rb)
+}
+
+func (t *testBlockdataFetcher) TestWrongBytes() {
+	b, item := t.newItem(1000)
+
+	// NOTE every chunk of bad channel is wrong
+	wrong := make([]byte, len(b))
+	copy(wrong, b)
+	for i := 0; i < len(wrong); i += 100 {
+		wrong[i] ^= 0xff
+	}
+
+	// NOTE good channel is a little slower, so bad channel surely gives chunks
+	good := &slowBlockdataChannel{DummyChannel: t.newChannel("good", b, nil, nil), delay: time.Millisecond * 10}
+	bad := t.newChannel("bad", wrong, nil, nil)
+
+	dir := t.T().TempDir()
+	bf := NewBlockdataFetcher(dir, 100)
+
+	rb, err := t.fetch(bf, item, []Channel{bad, good})
+	t.NoError(err)
+	t.Equal(b, rb)
+
+	// NOTE only the channel, which gave the proven wrong bytes, is dropped
+	dropped := bf.Dropped()
+	t.Equal(1, len(dropped))
+	t.Contains(dropped, bad.ConnInfo().String())
+	t.NotContains(dropped, good.ConnInfo().String())
+
+	_, err = os.Stat(filepath.Join(dir, item.Checksum()))
+	t.True(os.IsNotExist(err))
+
+	// NOTE dropped channel is not used again
+	_, err = t.fetch(bf, item, []Channel{bad})
+	t.Error(err)
+	t.Contains(err.Error(), "no available channels")
+}
+
+func (t *testBlockdataFetcher) TestAllWrongBytes() {
+	b, item := t.newItem(1000)
+
+	wrong := make([]byte, len(b))
+	copy(wrong, b)
+	wrong[0] ^= 0xff
+
+	bf := NewBlockdataFetcher(t.T().TempDir(), 100)
+
+	_, err := t.fetch(bf, item, []Channel{t.newChannel("bad", wrong, nil, nil)})
+	t.Error(err)
+	t.Contains(err.Error(), "checksum does not match")
+
+	_, err = os.Stat(filepath.Join(bf.dir, item.Checksum()))
+	t.True(os.IsNotExist(err))
+
+	// NOTE the wrong bytes are not proven, so the channel is not dropped
+	t.Empty(bf.Dropped())
+}
+
+func (t *testBlockdataFetcher) TestWrongSize() {
+	b, item := t.newItem(1000)
+
+	// NOTE the wrong size is reported by more channels than the right size
+	wrong := append(append([]byte{}, b...), util.UUID().Bytes()...)
+
+	liars := []Channel{t.newChannel("liar0", wrong, nil, nil), t.newChannel("liar1", wrong, nil, nil)}
+	honest := t.newChannel("honest", b, nil, nil)
+
+	dir := t.T().TempDir()
+	bf := NewBlockdataFetcher(dir, 100)
+
+	rb, err := t.fetch(bf, item, []Channel{liars[0], honest, liars[1]})
+	t.NoError(err)
+	t.Equal(b, rb)
+
+	dropped := bf.Dropped()
+	t.Equal(2, len(dropped))
+	t.Contains(dropped, liars[0].ConnInfo().String())
+	t.Contains(dropped, liars[1].ConnInfo().String())
+	t.Contains(dropped[liars[0].ConnInfo().String()].Error(), "wrong size")
+
+	_, err = os.Stat(filepath.Join(dir, item.Checksum()))
+	t.True(os.IsNotExist(err))
+}
+
+func (t *testBlockdataFetcher) TestFailedCleanChunks() {
+	b, item := t.newItem(1000)
+
+	dir := t.T().TempDir()
+	bf := NewBlockdataFetcher(dir, 100)
+
+	// NOTE the wrong size file of the previous fetching
+	d := filepath.Join(dir, item.Checksum())
+	t.NoError(os.MkdirAll(d, 0o700))
+	t.NoError(os.WriteFile(filepath.Join(d, blockdataChunkSizeFile), []byte(strconv.Itoa(len(b)+1)), 0o600))
+
+	var l sync.Mutex
+	var counter int64
+	failing := NewDummyChannel(NewNilConnInfo("failing"))
+	failing.SetBlockdataByChecksumHandler(func(base.Height, string, string) (io.ReadSeekCloser, error) {
+		l.Lock()
+		defer l.Unlock()
+
+		counter++
+		if counter > 2 {
+			return nil, util.NotFoundError.Errorf("gone")
+		}
+
+		return bytesReadSeekCloser{Reader: bytes.NewReader(b)}, nil
+	})
+
+	_, err := t.fetch(bf, item, []Channel{failing})
+	t.Error(err)
+
+	_, err = os.Stat(d)
+	t.True(os.IsNotExist(err))
+}
+
+func (t *testBlockdataFetcher) TestSlowChannel() {
+	b, item := t.newItem(1000)
+
+	original := BlockdataChunkTimeout
+	BlockdataChunkTimeout = time.Millisecond * 100
+	defer func() {
+		BlockdataChunkTimeout = original
+	}()
+
+	slow := &slowBlockdataChannel{DummyChannel: t.newChannel("slow", b, nil, nil), delay: time.Second}
+	fast := t.newChannel("fast", b, nil, nil)
+
+	bf := NewBlockdataFetcher("", 100)
+
+	rb, err := t.fetch(bf, item, []Channel{slow, fast})
+	t.NoError(err)
+	t.Equal(b, rb)
+
+	dropped := bf.Dropped()
+	t.Equal(1, len(dropped))
+	t.Contains(dropped, slow.ConnInfo().String())
+}
+
+func (t *testBlockdataFetcher) TestNotFound() {
+	b, item := t.newItem(1000)
+
+	notfound := NewDummyChannel(NewNilConnInfo("notfound"))
+	notfound.SetBlockdataByChecksumHandler(func(base.Height, string, string) (io.ReadSeekCloser, error) {
+		return nil, util.NotFoundError.Errorf("not found")
+	})
+
+	bf := NewBlockdataFetcher("", 100)
+
+	rb, err := t.fetch(bf, item, []Channel{notfound, t.newChannel("a", b, nil, nil)})
+	t.NoError(err)
+	t.Equal(b, rb)
+
+	// NOTE the channel, which does not have the item, is not dropped
+	t.Empty(bf.Dropped())
+}
+
+func (t *testBlockdataFetcher) TestResume() {
+	b, item := t.newItem(1000)
+
+	dir := t.T().TempDir()
+	bf := NewBlockdataFetcher(dir, 100)
+
+	// NOTE prepare the chunks of the previous fetching
+	d := filepath.Join(dir, item.Checksum())
+	t.NoError(os.MkdirAll(d, 0o700))
+	t.NoError(os.WriteFile(filepath.Join(d, blockdataChunkSizeFile), []byte(strconv.Itoa(len(b))), 0o600))
+	for i := 0; i < 7; i++ {
+		t.NoError(os.WriteFile(filepath.Join(d, strconv.Itoa(i)), b[i*100:(i+1)*100], 0o600))
+	}
+
+	var l sync.Mutex
+	var counter int64
+	rb, err := t.fetch(bf, item, []Channel{t.newChannel("a", b, &counter, &l)})
+	t.NoError(err)
+	t.Equal(b, rb)
+
+	// NOTE asking size and the missing 3 chunks
+	t.Equal(int64(4), counter)
+
+	_, err = os.Stat(d)
+	t.True(os.IsNotExist(err))
+}
+
+func (t *testBlockdataFetcher) TestResumeWithWrongChunk() {
+	b, item := t.newItem(1000)
+
+	dir := t.T().TempDir()
+	bf := NewBlockdataFetcher(dir, 100)
+
+	d := filepath.Join(dir, item.Checksum())
+	t.NoError(os.MkdirAll(d, 0o700))
+	t.NoError(os.WriteFile(filepath.Join(d, blockdataChunkSizeFile), []byte(strconv.Itoa(len(b))), 0o600))
+
+	wrong := make([]byte, 100)
+	copy(wrong, b[200:300])
+	wrong[3] ^= 0xff
+	t.NoError(os.WriteFile(filepath.Join(d, "2"), wrong, 0o600))
+
+	ch := t.newChannel("a", b, nil, nil)
+
+	rb, err := t.fetch(bf, item, []Channel{ch})
+	t.NoError(err)
+	t.Equal(b, rb)
+	t.Empty(bf.Dropped())
+}
+
+func (t *testBlockdataFetcher) TestInvalidChecksum() {
+	b, _ := t.newItem(100)
+
+	bf := NewBlockdataFetcher(t.T().TempDir(), 100)
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, "../findme", "file:///0/manifest.jsonld")
+	_, err := t.fetch(bf, item, []Channel{t.newChannel("a", b, nil, nil)})
+	t.Error(err)
+	t.Contains(err.Error(), "invalid checksum")
+}
+
+func TestBlockdataFetcher(t *testing.T) {
+	suite.Run(t, new(testBlockdataFetcher))
+}
@@ -3,8 +3,11 @@
 package network
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/spikeekips/mitum/base/block"
@@ -16,20 +19,84 @@ type testBlockdataFetchRemote struct {
 	suite.Suite
 }
 
+func (t *testBlockdataFetchRemote) newServer(b []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/notfound.jsonld.gz" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write(b)
+	}))
+}
+
+func (t *testBlockdataFetchRemote) compressed(body []byte) ([]byte, string) {
+	var buf bytes.Buffer
+	gw := util.NewGzipWriter(&buf)
+	_, err := gw.Write(body)
+	t.NoError(err)
+	t.NoError(gw.Close())
+
+	checksum, err := util.GenerateChecksum(bytes.NewReader(buf.Bytes()))
+	t.NoError(err)
+
+	return buf.Bytes(), checksum
+}
+
 func (t *testBlockdataFetchRemote) TestNew() {
-	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), "http://google.com")
+	body := util.UUID().Bytes()
+	b, checksum := t.compressed(body)
+
+	ts := t.newServer(b)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, checksum, ts.URL+"/a.jsonld.gz")
 	r, err := FetchBlockdataFromRemote(context.Background(), item)
 	t.NoError(err)
 
-	b, err := ioutil.ReadAll(r)
+	rb, err := ioutil.ReadAll(r)
 	t.NoError(err)
-	t.NotNil(b)
+	t.Equal(body, rb)
 }
 
-func (t *testBlockdataFetchRemote) TestHTTPS() {
-	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), "https://google.com")
-	_, err := FetchBlockdataFromRemote(context.Background(), item)
+func (t *testBlockdataFetchRemote) TestNotCompressed() {
+	body := util.UUID().Bytes()
+	checksum, err := util.GenerateChecksum(bytes.NewReader(body))
+	t.NoError(err)
+
+	ts := t.newServer(body)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, checksum, ts.URL+"/a.jsonld")
+	r, err := FetchBlockdataFromRemote(context.Background(), item)
 	t.NoError(err)
+
+	rb, err := ioutil.ReadAll(r)
+	t.NoError(err)
+	t.Equal(body, rb)
+}
+
+func (t *testBlockdataFetchRemote) TestWrongChecksum() {
+	b, _ := t.compressed(util.UUID().Bytes())
+
+	ts := t.newServer(b)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), ts.URL+"/a.jsonld.gz")
+	_, err := FetchBlockdataFromRemote(context.Background(), item)
+	t.Error(err)
+	t.Contains(err.Error(), "checksum does not match")
+}
+
+func (t *testBlockdataFetchRemote) TestNotFound() {
+	ts := t.newServer(nil)
+	defer ts.Close()
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataManifest, util.UUID().String(), ts.URL+"/notfound.jsonld.gz")
+	_, err := FetchBlockdataFromRemote(context.Background(), item)
+	t.Error(err)
+	t.Contains(err.Error(), "404")
 }
 
 func (t *testBlockdataFetchRemote) TestUnknownScheme() {
@@ -20,12 +20,14 @@ type NodepoolDelegate struct {
 	nodepool          *network.Nodepool
 	encs              *encoder.Encoders
 	connectionTimeout time.Duration
+	chConf            quicnetwork.ChannelConfig
 }
 
 func NewNodepoolDelegate(
 	nodepool *network.Nodepool,
 	encs *encoder.Encoders,
 	connectionTimeout time.Duration,
+	chConf quicnetwork.ChannelConfig,
 ) *NodepoolDelegate {
 	return &NodepoolDelegate{
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
@@ -34,6 +36,7 @@ func NewNodepoolDelegate(
 		nodepool:          nodepool,
 		encs:              encs,
 		connectionTimeout: connectionTimeout,
+		chConf:            chConf,
 	}
 }
 
@@ -176,7 +179,7 @@ func (dg *NodepoolDelegate) notifyUpdate(ci NodeConnInfo) {
 }
 
 func (dg *NodepoolDelegate) channel(ci NodeConnInfo) (network.Channel, error) {
-	return LoadNodeChannel(ci, dg.encs, dg.connectionTimeout)
+	return LoadNodeChannel(ci, dg.encs, dg.connectionTimeout, dg.chConf)
 }
 
 func (dg *NodepoolDelegate) isNewConnInfo(ci NodeConnInfo) bool {
@@ -197,6 +200,7 @@ func LoadNodeChannel(
 	connInfo network.ConnInfo,
 	encs *encoder.Encoders,
 	connectionTimeout time.Duration,
+	conf quicnetwork.ChannelConfig,
 ) (network.Channel, error) {
 	if err := connInfo.IsValid(nil); err != nil {
 		return nil, err
@@ -212,7 +216,7 @@ func LoadNodeChannel(
 	}
 
 	switch connInfo.URL().Scheme {
-	case "https":
+	case "https", network.TCPURLScheme:
 		quicConfig := &quic.Config{HandshakeIdleTimeout: connectionTimeout}
 		ch, err := quicnetwork.NewChannel(
 			connInfo,
@@ -224,6 +228,9 @@ func LoadNodeChannel(
 		if err != nil {
 			return nil, err
 		}
+
+		ch.SetConfig(conf)
+
 		return ch, nil
 	default:
 		return nil, errors.Errorf("not supported publish URL, %v", connInfo)
@@ -10,13 +10,14 @@ import (
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 )
 
 func (t *testDiscovery) nodepoolDelegate(
 	local *dummyNode,
 	nodes map[string]*dummyNode,
 ) (*discovery.NodepoolDelegate, *network.Nodepool, error) {
-	ch, err := discovery.LoadNodeChannel(local.connInfo, t.encs, time.Second*5)
+	ch, err := discovery.LoadNodeChannel(local.connInfo, t.encs, time.Second*5, quicnetwork.ChannelConfig{})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -32,7 +33,7 @@ func (t *testDiscovery) nodepoolDelegate(
 		}
 	}
 
-	dg := discovery.NewNodepoolDelegate(np, t.encs, time.Second*5)
+	dg := discovery.NewNodepoolDelegate(np, t.encs, time.Second*5, quicnetwork.ChannelConfig{})
 	// dg.SetLogging(logging.TestLogging)
 
 	return dg, np, nil
@@ -22,6 +22,8 @@ type DummyChannel struct {
 	nodeInfoHandler            NodeInfoHandler
 	blockdataMapsHandler       BlockdataMapsHandler
 	blockdataHandler           BlockdataHandler
+	blockdataByChecksumHandler BlockdataByChecksumHandler
+	snapshotHandler            SnapshotHandler
 	startHandover              StartHandoverHandler
 	pingHandover               PingHandoverHandler
 	endHandover                EndHandoverHandler
@@ -71,12 +73,12 @@ func (ch *DummyChannel) SetGetStagedOperationsHandler(f GetStagedOperationsHandl
 	ch.getStagedOperationsHandler = f
 }
 
-func (ch *DummyChannel) State(_ context.Context, key string) (state.State, bool, error) {
+func (ch *DummyChannel) State(_ context.Context, key string, height base.Height) (state.State, bool, error) {
 	if ch.getStateHandler == nil {
 		return nil, false, ch.notSupported()
 	}
 
-	return ch.getStateHandler(key)
+	return ch.getStateHandler(key, height)
 }
 
 func (ch *DummyChannel) SetGetStateHandler(f GetStateHandler) {
@@ -119,6 +121,35 @@ func (ch *DummyChannel) SetBlockdataHandler(f BlockdataHandler) {
 	ch.blockdataHandler = f
 }
 
+func (ch *DummyChannel) BlockdataRange(
+	_ context.Context,
+	height base.Height,
+	item block.BlockdataMapItem,
+	offset, length int64,
+) (io.ReadCloser, int64, error) {
+	if ch.blockdataByChecksumHandler == nil {
+		return nil, 0, ch.notSupported()
+	}
+
+	return ReadBlockdataRange(ch.blockdataByChecksumHandler, height, item, offset, length)
+}
+
+func (ch *DummyChannel) SetBlockdataByChecksumHandler(f BlockdataByChecksumHandler) {
+	ch.blockdataByChecksumHandler = f
+}
+
+func (ch *DummyChannel) Snapshot(_ context.Context, height base.Height) (block.SnapshotHeader, error) {
+	if ch.snapshotHandler == nil {
+		return nil, ch.notSupported()
+	}
+
+	return ch.snapshotHandler(height)
+}
+
+func (ch *DummyChannel) SetSnapshotHandler(f SnapshotHandler) {
+	ch.snapshotHandler = f
+}
+
 func (ch *DummyChannel) StartHandover(_ context.Context, sl StartHandoverSeal) (bool, error) {
 	if ch.startHandover == nil {
 		return false, ch.notSupported()
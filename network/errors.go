@@ -8,6 +8,7 @@ import (
 var (
 	NetworkError          = util.NewError("network error")
 	HandoverRejectedError = util.NewError("handover failed")
+	PrunedHeightError     = util.NewError("pruned height")
 )
 
 func MergeError(err error) error {
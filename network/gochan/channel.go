@@ -12,6 +12,7 @@ import (
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/logging"
 	"github.com/spikeekips/mitum/util/valuehash"
 )
@@ -26,6 +27,8 @@ type Channel struct {
 	nodeInfo                   network.NodeInfoHandler
 	getBlockdataMaps           network.BlockdataMapsHandler
 	getBlockdata               network.BlockdataHandler
+	getBlockdataByChecksum     network.BlockdataByChecksumHandler
+	getSnapshot                network.SnapshotHandler
 	startHandover              network.StartHandoverHandler
 	pingHandover               network.PingHandoverHandler
 	endHandover                network.EndHandoverHandler
@@ -83,8 +86,16 @@ func (ch *Channel) SetGetProposalHandler(f network.GetProposalHandler) {
 	ch.getProposalHandler = f
 }
 
-func (ch *Channel) State(_ context.Context, key string) (state.State, bool, error) {
-	return ch.getState(key)
+func (ch *Channel) State(_ context.Context, key string, height base.Height) (state.State, bool, error) {
+	if ch.getState == nil {
+		return nil, false, errors.Errorf("getState is missing")
+	}
+
+	return ch.getState(key, height)
+}
+
+func (ch *Channel) SetGetStateHandler(f network.GetStateHandler) {
+	ch.getState = f
 }
 
 func (ch *Channel) NodeInfo(_ context.Context) (network.NodeInfo, error) {
@@ -133,6 +144,47 @@ func (ch *Channel) SetBlockdataHandler(f network.BlockdataHandler) {
 	ch.getBlockdata = f
 }
 
+func (ch *Channel) BlockdataRange(
+	_ context.Context,
+	height base.Height,
+	item block.BlockdataMapItem,
+	offset, length int64,
+) (io.ReadCloser, int64, error) {
+	if ch.getBlockdataByChecksum == nil {
+		return nil, 0, errors.Errorf("not supported")
+	}
+
+	return network.ReadBlockdataRange(ch.getBlockdataByChecksum, height, item, offset, length)
+}
+
+func (ch *Channel) SetBlockdataByChecksumHandler(f network.BlockdataByChecksumHandler) {
+	ch.getBlockdataByChecksum = f
+}
+
+func (ch *Channel) Snapshot(_ context.Context, height base.Height) (block.SnapshotHeader, error) {
+	if ch.getSnapshot == nil {
+		return nil, errors.Errorf("not supported")
+	}
+
+	sh, err := ch.getSnapshot(height)
+	switch {
+	case err != nil:
+		return nil, err
+	case sh == nil:
+		return nil, util.NotFoundError.Errorf("snapshot not found")
+	}
+
+	if err := sh.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return sh, nil
+}
+
+func (ch *Channel) SetSnapshotHandler(f network.SnapshotHandler) {
+	ch.getSnapshot = f
+}
+
 func (ch *Channel) StartHandover(_ context.Context, sl network.StartHandoverSeal) (bool, error) {
 	if ch.startHandover == nil {
 		return false, errors.Errorf("not supported")
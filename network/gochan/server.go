@@ -53,13 +53,17 @@ func (sv *Server) SetNewSealHandler(f network.NewSealHandler) {
 }
 func (*Server) SetGetProposalHandler(network.GetProposalHandler) {}
 
-func (*Server) SetNodeInfoHandler(network.NodeInfoHandler)           {}
-func (*Server) NodeInfoHandler() network.NodeInfoHandler             { return nil }
-func (*Server) SetBlockdataMapsHandler(network.BlockdataMapsHandler) {}
-func (*Server) SetBlockdataHandler(network.BlockdataHandler)         {}
-func (*Server) SetStartHandoverHandler(network.StartHandoverHandler) {}
-func (*Server) SetPingHandoverHandler(network.PingHandoverHandler)   {}
-func (*Server) SetEndHandoverHandler(network.EndHandoverHandler)     {}
+func (*Server) SetGetStateHandler(network.GetStateHandler) {}
+
+func (*Server) SetNodeInfoHandler(network.NodeInfoHandler)                       {}
+func (*Server) NodeInfoHandler() network.NodeInfoHandler                         { return nil }
+func (*Server) SetBlockdataMapsHandler(network.BlockdataMapsHandler)             {}
+func (*Server) SetBlockdataHandler(network.BlockdataHandler)                     {}
+func (*Server) SetBlockdataByChecksumHandler(network.BlockdataByChecksumHandler) {}
+func (*Server) SetSnapshotHandler(network.SnapshotHandler)                       {}
+func (*Server) SetStartHandoverHandler(network.StartHandoverHandler)             {}
+func (*Server) SetPingHandoverHandler(network.PingHandoverHandler)               {}
+func (*Server) SetEndHandoverHandler(network.EndHandoverHandler)                 {}
 
 func (sv *Server) run(ctx context.Context) error {
 end:
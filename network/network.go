@@ -18,10 +18,14 @@ type (
 	NewSealHandler             func(seal.Seal) error
 	GetStagedOperationsHandler func([]valuehash.Hash) ([]operation.Operation, error)
 	GetProposalHandler         func(valuehash.Hash) (base.Proposal, error)
-	GetStateHandler            func(string) (state.State, bool, error)
+	GetStateHandler            func(string, base.Height) (state.State, bool, error)
 	NodeInfoHandler            func() (NodeInfo, error)
 	BlockdataMapsHandler       func([]base.Height) ([]block.BlockdataMap, error)
 	BlockdataHandler           func(string) (io.Reader, func() error, error)
+	// NOTE BlockdataByChecksumHandler returns the raw block data item, which
+	// has the checksum. The height and data type are used to find the item.
+	BlockdataByChecksumHandler func(base.Height, string /* data type */, string /* checksum */) (io.ReadSeekCloser, error)
+	SnapshotHandler            func(base.Height) (block.SnapshotHeader, error)
 	StartHandoverHandler       func(StartHandoverSeal) (bool, error)
 	PingHandoverHandler        func(PingHandoverSeal) (bool, error)
 	EndHandoverHandler         func(EndHandoverSeal) (bool, error)
@@ -33,10 +37,13 @@ type Server interface {
 	SetNewSealHandler(NewSealHandler)
 	SetGetStagedOperationsHandler(GetStagedOperationsHandler)
 	SetGetProposalHandler(GetProposalHandler)
+	SetGetStateHandler(GetStateHandler)
 	NodeInfoHandler() NodeInfoHandler
 	SetNodeInfoHandler(NodeInfoHandler)
 	SetBlockdataMapsHandler(BlockdataMapsHandler)
 	SetBlockdataHandler(BlockdataHandler)
+	SetBlockdataByChecksumHandler(BlockdataByChecksumHandler)
+	SetSnapshotHandler(SnapshotHandler)
 	SetStartHandoverHandler(StartHandoverHandler)
 	SetPingHandoverHandler(PingHandoverHandler)
 	SetEndHandoverHandler(EndHandoverHandler)
@@ -54,6 +61,8 @@ var (
 	ChannelTimeoutNodeInfo     = time.Second * 2
 	ChannelTimeoutBlockdataMap = time.Second * 2
 	ChannelTimeoutBlockdata    = time.Second * 30
+	ChannelTimeoutSnapshot     = time.Second * 2
+	ChannelTimeoutState        = time.Second * 2
 	ChannelTimeoutHandover     = time.Second * 2
 )
 
@@ -63,9 +72,26 @@ type Channel interface {
 	StagedOperations(context.Context, []valuehash.Hash) ([]operation.Operation, error)
 	SendSeal(context.Context, ConnInfo /* from ConnInfo */, seal.Seal) error
 	Proposal(context.Context, valuehash.Hash) (base.Proposal, error)
+	// NOTE State returns the state of key at the given height, that is, the
+	// latest state not higher than the height. If height is base.NilHeight,
+	// the latest state is returned.
+	State(context.Context, string /* key */, base.Height) (state.State, bool, error)
 	NodeInfo(context.Context) (NodeInfo, error)
 	BlockdataMaps(context.Context, []base.Height) ([]block.BlockdataMap, error)
 	Blockdata(context.Context, block.BlockdataMapItem) (io.ReadCloser, error)
+	// NOTE BlockdataRange returns the part of the raw block data item from the
+	// offset with the length and the total size of item; the item is addressed
+	// by it's checksum, so any node, which has the same bytes can serve it. If
+	// length is less than 1, it reads to the end. The returned bytes are not
+	// checked and not decompressed.
+	BlockdataRange(
+		context.Context, base.Height, block.BlockdataMapItem, int64 /* offset */, int64, /* length */
+	) (io.ReadCloser, int64 /* total size */, error)
+	// NOTE Snapshot returns the header of state snapshot. If height is
+	// base.NilHeight, the last snapshot is returned; if not found,
+	// util.NotFoundError is returned. The states of snapshot can be fetched by
+	// Blockdata with the item of header.
+	Snapshot(context.Context, base.Height) (block.SnapshotHeader, error)
 	StartHandover(context.Context, StartHandoverSeal) (bool, error)
 	PingHandover(context.Context, PingHandoverSeal) (bool, error)
 	EndHandover(context.Context, EndHandoverSeal) (bool, error)
@@ -27,6 +27,9 @@ type NodeInfo interface {
 	ConnInfo() ConnInfo
 	Policy() map[string]interface{}
 	Nodes() []RemoteNode // Only contains suffrage nodes
+	// PrunedHeight is the height, which the lower blocks are pruned. The
+	// pruned blocks can not be synced from the node.
+	PrunedHeight() base.Height
 }
 
 type NodeInfoV0 struct {
@@ -39,6 +42,7 @@ type NodeInfoV0 struct {
 	policy    map[string]interface{}
 	nodes     []RemoteNode
 	ci        ConnInfo
+	pruned    base.Height
 }
 
 func NewNodeInfoV0(
@@ -66,6 +70,7 @@ func NewNodeInfoV0(
 		policy:     policy,
 		nodes:      nodes,
 		ci:         ci,
+		pruned:     base.NilHeight,
 	}
 }
 
@@ -121,6 +126,16 @@ func (ni NodeInfoV0) Nodes() []RemoteNode {
 	return ni.nodes
 }
 
+func (ni NodeInfoV0) PrunedHeight() base.Height {
+	return ni.pruned
+}
+
+func (ni NodeInfoV0) SetPrunedHeight(height base.Height) NodeInfoV0 {
+	ni.pruned = height
+
+	return ni
+}
+
 type RemoteNode struct {
 	Address   base.Address
 	Publickey key.Publickey
@@ -11,14 +11,15 @@ import (
 
 func (ni NodeInfoV0) MarshalBSON() ([]byte, error) {
 	return bsonenc.Marshal(bsonenc.MergeBSONM(bsonenc.NewHintedDoc(ni.Hint()), bson.M{
-		"node":       ni.node,
-		"network_id": ni.networkID,
-		"state":      ni.state,
-		"last_block": ni.lastBlock,
-		"version":    ni.version,
-		"policy":     ni.policy,
-		"suffrage":   ni.nodes,
-		"conninfo":   ni.ci,
+		"node":          ni.node,
+		"network_id":    ni.networkID,
+		"state":         ni.state,
+		"last_block":    ni.lastBlock,
+		"version":       ni.version,
+		"policy":        ni.policy,
+		"suffrage":      ni.nodes,
+		"conninfo":      ni.ci,
+		"pruned_height": ni.pruned,
 	}))
 }
 
@@ -31,6 +32,7 @@ type NodeInfoV0UnpackerBSON struct {
 	PO  map[string]interface{} `bson:"policy"`
 	SF  []bson.Raw             `bson:"suffrage"`
 	CI  bson.Raw               `bson:"conninfo"`
+	PH  base.Height            `bson:"pruned_height"`
 }
 
 func (ni *NodeInfoV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
@@ -49,7 +51,7 @@ func (ni *NodeInfoV0) UnpackBSON(b []byte, enc *bsonenc.Encoder) error {
 		sf[i] = r
 	}
 
-	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI)
+	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI, nni.PH)
 }
 
 func (no RemoteNode) MarshalBSON() ([]byte, error) {
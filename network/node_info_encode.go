@@ -17,6 +17,7 @@ func (ni *NodeInfoV0) unpack(
 	co map[string]interface{},
 	sf []RemoteNode,
 	bci []byte,
+	ph base.Height,
 ) error {
 	if err := encoder.Decode(bnode, enc, &ni.node); err != nil {
 		return err
@@ -34,6 +35,7 @@ func (ni *NodeInfoV0) unpack(
 	ni.version = vs
 	ni.policy = co
 	ni.nodes = sf
+	ni.pruned = ph
 
 	return encoder.Decode(bci, enc, &ni.ci)
 }
@@ -20,6 +20,7 @@ type NodeInfoV0PackerJSON struct {
 	PO  map[string]interface{} `json:"policy"`
 	SF  []RemoteNode           `json:"suffrage"`
 	CI  ConnInfo               `json:"conninfo"`
+	PH  base.Height            `json:"pruned_height"`
 }
 
 func (ni NodeInfoV0) JSONPacker() NodeInfoV0PackerJSON {
@@ -33,6 +34,7 @@ func (ni NodeInfoV0) JSONPacker() NodeInfoV0PackerJSON {
 		PO:         ni.policy,
 		SF:         ni.nodes,
 		CI:         ni.ci,
+		PH:         ni.pruned,
 	}
 }
 
@@ -49,6 +51,7 @@ type NodeInfoV0UnpackerJSON struct {
 	PO  map[string]interface{} `json:"policy"`
 	SF  []json.RawMessage      `json:"suffrage"`
 	CI  json.RawMessage        `json:"conninfo"`
+	PH  base.Height            `json:"pruned_height"`
 }
 
 func (ni *NodeInfoV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
@@ -67,7 +70,7 @@ func (ni *NodeInfoV0) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
 		sf[i] = r
 	}
 
-	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI)
+	return ni.unpack(enc, nni.ND, nni.NID, nni.ST, nni.LB, nni.VS, nni.PO, sf, nni.CI, nni.PH)
 }
 
 func (no RemoteNode) MarshalJSON() ([]byte, error) {
@@ -104,6 +104,7 @@ func (t *testNodeInfo) TestNew() {
 
 	t.Equal(expectedNodes, regs)
 	t.True(ni.ConnInfo().Equal(localConnInfo))
+	t.Equal(base.NilHeight, ni.PrunedHeight())
 }
 
 func (t *testNodeInfo) TestEmptyNetworkID() {
@@ -227,6 +228,7 @@ func (t *testNodeInfo) TestJSON() {
 		t.newConnInfo("n0", true),
 	)
 	ni.BaseHinter = hint.NewBaseHinter(hint.NewHint(NodeInfoType, "v0.0.9"))
+	ni = ni.SetPrunedHeight(base.Height(22))
 	t.NoError(ni.IsValid(nil))
 
 	b, err := jsonenc.Marshal(ni)
@@ -255,6 +257,7 @@ func (t *testNodeInfo) TestBSON() {
 		t.newConnInfo("n0", true),
 	)
 	ni.BaseHinter = hint.NewBaseHinter(hint.NewHint(NodeInfoType, "v0.0.9"))
+	ni = ni.SetPrunedHeight(base.Height(22))
 	t.NoError(ni.IsValid(nil))
 
 	b, err := bsonenc.Marshal(ni)
@@ -30,6 +30,8 @@ type Nodepool struct {
 	nodes   map[string]base.Node
 	chs     map[string]Channel
 	pts     *cache.GCache // passthrough
+	gossip  *gossip
+	scores  *PeerScores
 }
 
 func NewNodepool(local node.Local, ch Channel) *Nodepool {
@@ -48,7 +50,8 @@ func NewNodepool(local node.Local, ch Channel) *Nodepool {
 		chs: map[string]Channel{
 			addr: ch,
 		},
-		pts: pts,
+		pts:    pts,
+		scores: NewDefaultPeerScores(),
 	}
 }
 
@@ -202,18 +205,93 @@ func (np *Nodepool) TraverseAliveRemotes(callback func(base.Node, Channel) bool)
 	}
 }
 
-func (np *Nodepool) Broadcast( // revive:disable-line:function-length
+func (np *Nodepool) PeerScores() *PeerScores {
+	np.RLock()
+	defer np.RUnlock()
+
+	return np.scores
+}
+
+func (np *Nodepool) SetPeerScores(ps *PeerScores) {
+	np.Lock()
+	defer np.Unlock()
+
+	np.scores = ps
+}
+
+// Penalize adds penalty to the node. It returns true if the node is banned.
+func (np *Nodepool) Penalize(address base.Address, penalty PeerPenalty, reason string) bool {
+	if address.Equal(np.local.Address()) {
+		return false
+	}
+
+	return np.PeerScores().Penalize(address.String(), penalty, reason)
+}
+
+// IsBanned checks whether the node is banned by it's address. The transport
+// layer also bans the node by it's address under mutual TLS.
+func (np *Nodepool) IsBanned(address base.Address) bool {
+	return np.PeerScores().IsBanned(address.String())
+}
+
+// Broadcast sends seal to the alive remote nodes and the passthroughs. In
+// gossip mode, seal is sent to the randomly selected nodes instead of all the
+// nodes; see SetGossip. The banned nodes are excluded.
+func (np *Nodepool) Broadcast(
 	ctx context.Context,
 	sl seal.Seal,
 	filter func(base.Node) bool,
 ) ([]error, error) {
-	l := np.Log().With().Stringer("seal_hash", sl.Hash()).Logger()
-
 	var localci ConnInfo
 	if ch := np.LocalChannel(); ch != nil {
 		localci = ch.ConnInfo()
 	}
 
+	var targets []broadcastTarget
+	if g := np.gossipConfig(); g != nil {
+		_ = g.markSeen(sl)
+
+		targets = np.gossipTargets(g, filter)
+	} else {
+		np.TraverseAliveRemotes(func(no base.Node, ch Channel) bool {
+			if np.IsBanned(no.Address()) {
+				return true
+			}
+
+			if filter == nil || filter(no) {
+				targets = append(targets, broadcastTarget{no: no, ch: ch})
+			}
+
+			return true
+		})
+	}
+
+	np.passthroughs(func(ch Channel, filter func(PassthroughedSeal) bool) bool {
+		if filter != nil && !filter(NewPassthroughedSealFromConnInfo(sl, localci)) {
+			return true
+		}
+
+		targets = append(targets, broadcastTarget{ch: ch})
+
+		return true
+	})
+
+	return np.broadcast(ctx, localci, sl, targets)
+}
+
+type broadcastTarget struct {
+	no base.Node // NOTE nil for passthrough
+	ch Channel
+}
+
+func (np *Nodepool) broadcast(
+	ctx context.Context,
+	localci ConnInfo,
+	sl seal.Seal,
+	targets []broadcastTarget,
+) ([]error, error) {
+	l := np.Log().With().Stringer("seal_hash", sl.Hash()).Int("targets", len(targets)).Logger()
+
 	errch := make(chan error)
 	wk := util.NewDistributeWorker(ctx, 100, errch)
 	defer wk.Close()
@@ -230,20 +308,11 @@ func (np *Nodepool) Broadcast( // revive:disable-line:function-length
 		donech <- errs
 	}()
 
-	targetch := make(chan [2]interface{})
-
 	go func() {
-		for i := range targetch {
-			x, y := i[0], i[1]
-
-			var no base.Node
-			if x != nil {
-				no = x.(base.Node)
-			}
-			ch := y.(Channel)
-
+		for i := range targets {
+			t := targets[i]
 			if err := wk.NewJob(func(context.Context, uint64) error {
-				return np.send(ctx, localci, no, ch, sl)
+				return np.send(ctx, localci, t.no, t.ch, sl)
 			}); err != nil {
 				l.Trace().Err(err).Msg("something wrong to broadcast")
 
@@ -254,28 +323,6 @@ func (np *Nodepool) Broadcast( // revive:disable-line:function-length
 		wk.Done()
 	}()
 
-	np.TraverseAliveRemotes(func(no base.Node, ch Channel) bool {
-		if filter != nil && !filter(no) {
-			return true
-		}
-
-		targetch <- [2]interface{}{no, ch}
-
-		return true
-	})
-
-	np.passthroughs(func(ch Channel, filter func(PassthroughedSeal) bool) bool {
-		if filter != nil && !filter(NewPassthroughedSealFromConnInfo(sl, localci)) {
-			return true
-		}
-
-		targetch <- [2]interface{}{nil, ch}
-
-		return true
-	})
-
-	close(targetch)
-
 	if err := wk.Wait(); err != nil {
 		close(errch)
 
@@ -0,0 +1,136 @@
+package network
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/util/cache"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+var DefaultGossipTTL = time.Minute
+
+// gossipSeenKeyPrefix separates the keys of seen seals from the seal hashes of
+// the seal cache, so the seal cache can be used as the seen cache.
+const gossipSeenKeyPrefix = "gossip-seen-"
+
+type gossip struct {
+	sync.Mutex
+	fanout int
+	ttl    time.Duration
+	seen   cache.Cache
+}
+
+// markSeen marks seal as seen. If already seen, it returns false.
+func (g *gossip) markSeen(sl seal.Seal) bool {
+	g.Lock()
+	defer g.Unlock()
+
+	k := gossipSeenKeyPrefix + sl.Hash().String()
+	if g.seen.Has(k) {
+		return false
+	}
+
+	_ = g.seen.Set(k, struct{}{}, g.ttl)
+
+	return true
+}
+
+// SetGossip enables the gossip mode. Broadcast sends seal to the fanout
+// number of randomly selected nodes instead of all the nodes and the nodes,
+// which receive the seal, relay it by Gossip. The seen cache keeps the seals,
+// which are already sent, so each node relays the same seal only once; the
+// seal cache can be shared as the seen cache. The seal, which is signed before
+// ttl, is not relayed.
+func (np *Nodepool) SetGossip(fanout int, ttl time.Duration, seen cache.Cache) error {
+	switch {
+	case fanout < 1:
+		return errors.Errorf("fanout of gossip should be over 0, %d", fanout)
+	case ttl <= 0:
+		return errors.Errorf("ttl of gossip should be over 0, %v", ttl)
+	case seen == nil:
+		return errors.Errorf("empty seen cache of gossip")
+	}
+
+	np.Lock()
+	defer np.Unlock()
+
+	np.gossip = &gossip{fanout: fanout, ttl: ttl, seen: seen}
+
+	return nil
+}
+
+func (np *Nodepool) IsGossip() bool {
+	return np.gossipConfig() != nil
+}
+
+// Gossip relays the received seal to the randomly selected nodes. It does
+// nothing if the gossip mode is disabled, seal is already seen or expired.
+// Unlike Broadcast, the passthroughs are not included.
+func (np *Nodepool) Gossip(
+	ctx context.Context,
+	sl seal.Seal,
+	filter func(base.Node) bool,
+) ([]error, error) {
+	g := np.gossipConfig()
+	if g == nil {
+		return nil, nil
+	}
+
+	if localtime.UTCNow().Sub(sl.SignedAt()) > g.ttl {
+		return nil, nil
+	}
+
+	if !g.markSeen(sl) {
+		return nil, nil
+	}
+
+	targets := np.gossipTargets(g, filter)
+	if len(targets) < 1 {
+		return nil, nil
+	}
+
+	var localci ConnInfo
+	if ch := np.LocalChannel(); ch != nil {
+		localci = ch.ConnInfo()
+	}
+
+	return np.broadcast(ctx, localci, sl, targets)
+}
+
+func (np *Nodepool) gossipConfig() *gossip {
+	np.RLock()
+	defer np.RUnlock()
+
+	return np.gossip
+}
+
+func (np *Nodepool) gossipTargets(g *gossip, filter func(base.Node) bool) []broadcastTarget {
+	var targets []broadcastTarget
+	np.TraverseAliveRemotes(func(no base.Node, ch Channel) bool {
+		if np.IsBanned(no.Address()) {
+			return true
+		}
+
+		if filter == nil || filter(no) {
+			targets = append(targets, broadcastTarget{no: no, ch: ch})
+		}
+
+		return true
+	})
+
+	if len(targets) <= g.fanout {
+		return targets
+	}
+
+	rand.Shuffle(len(targets), func(i, j int) { // nolint:gosec
+		targets[i], targets[j] = targets[j], targets[i]
+	})
+
+	return targets[:g.fanout]
+}
@@ -0,0 +1,277 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/util/cache"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/stretchr/testify/suite"
+)
+
+type testNodepoolGossip struct {
+	suite.Suite
+	local node.Local
+}
+
+func (t *testNodepoolGossip) SetupSuite() {
+	t.local = node.RandomLocal("local")
+}
+
+func (t *testNodepoolGossip) newNodepool(n int, fanout int) (*Nodepool, *sync.Map) {
+	ns := NewNodepool(t.local, nil)
+
+	received := &sync.Map{}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("n%d", i)
+
+		ch := NilConnInfoChannel(name)
+		ch.SetNewSealHandler(func(sl seal.Seal) error {
+			received.Store(name, sl)
+
+			return nil
+		})
+
+		t.NoError(ns.Add(node.RandomLocal(name), ch))
+	}
+
+	if fanout > 0 {
+		seen, err := cache.NewGCache("lru", 100, time.Minute)
+		t.NoError(err)
+
+		t.NoError(ns.SetGossip(fanout, time.Minute, seen))
+	}
+
+	return ns, received
+}
+
+func (t *testNodepoolGossip) count(received *sync.Map) int {
+	var c int
+	received.Range(func(interface{}, interface{}) bool {
+		c++
+
+		return true
+	})
+
+	return c
+}
+
+func (t *testNodepoolGossip) TestSetGossip() {
+	ns := NewNodepool(t.local, nil)
+	t.False(ns.IsGossip())
+
+	seen, err := cache.NewGCache("lru", 100, time.Minute)
+	t.NoError(err)
+
+	err = ns.SetGossip(0, time.Minute, seen)
+	t.Contains(err.Error(), "fanout of gossip should be over 0")
+
+	err = ns.SetGossip(3, 0, seen)
+	t.Contains(err.Error(), "ttl of gossip should be over 0")
+
+	err = ns.SetGossip(3, time.Minute, nil)
+	t.Contains(err.Error(), "empty seen cache")
+
+	t.NoError(ns.SetGossip(3, time.Minute, seen))
+	t.True(ns.IsGossip())
+}
+
+func (t *testNodepoolGossip) TestBroadcastWithoutGossip() {
+	ns, received := t.newNodepool(5, 0)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Broadcast(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+
+	t.Equal(5, t.count(received))
+}
+
+func (t *testNodepoolGossip) TestBroadcast() {
+	ns, received := t.newNodepool(5, 2)
+
+	passed := &sync.Map{}
+	pt := NilConnInfoChannel("passthrough")
+	pt.SetNewSealHandler(func(sl seal.Seal) error {
+		passed.Store("passthrough", sl)
+
+		return nil
+	})
+	t.NoError(ns.SetPassthrough(pt, nil, 0))
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Broadcast(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+
+	t.Equal(2, t.count(received))
+
+	// NOTE passthroughs receive all the seals
+	t.Equal(1, t.count(passed))
+}
+
+func (t *testNodepoolGossip) TestBroadcastFilter() {
+	ns, received := t.newNodepool(5, 3)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Broadcast(context.Background(), sl, func(no base.Node) bool {
+		return no.Address().Equal(base.MustNewStringAddress("n-n0"))
+	})
+	t.NoError(err)
+	t.Empty(errs)
+
+	t.Equal(1, t.count(received))
+	_, found := received.Load("n0")
+	t.True(found)
+}
+
+func (t *testNodepoolGossip) TestGossip() {
+	ns, received := t.newNodepool(5, 2)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+	t.Equal(2, t.count(received))
+
+	// NOTE seen seal is not relayed again
+	ns0, received0 := t.newNodepool(5, 2)
+	ns0.gossip = ns.gossip
+
+	errs, err = ns0.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+	t.Equal(0, t.count(received0))
+}
+
+func (t *testNodepoolGossip) TestGossipSharedSealCache() {
+	ns, received := t.newNodepool(5, 2)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	// NOTE the received seal is already in the seal cache
+	t.NoError(ns.gossip.seen.Set(sl.Hash().String(), struct{}{}, 0))
+
+	errs, err := ns.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+	t.Equal(2, t.count(received))
+}
+
+func (t *testNodepoolGossip) TestGossipBroadcastedSeal() {
+	ns, received := t.newNodepool(5, 2)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	_, err := ns.Broadcast(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Equal(2, t.count(received))
+
+	// NOTE the seal came back from the other node is not relayed
+	received.Range(func(k interface{}, _ interface{}) bool {
+		received.Delete(k)
+
+		return true
+	})
+
+	_, err = ns.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Equal(0, t.count(received))
+}
+
+func (t *testNodepoolGossip) TestGossipExpired() {
+	ns, received := t.newNodepool(5, 2)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+	sl.CreatedAt = localtime.UTCNow().Add(time.Minute * -2)
+
+	errs, err := ns.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+	t.Equal(0, t.count(received))
+}
+
+func (t *testNodepoolGossip) TestGossipDisabled() {
+	ns, received := t.newNodepool(5, 0)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+	t.Equal(0, t.count(received))
+}
+
+func (t *testNodepoolGossip) TestBroadcastBanned() {
+	ns, received := t.newNodepool(5, 0)
+
+	// NOTE banned by address
+	for i := 0; i < int(DefaultPeerScoreThreshold/uint(PeerPenaltyInvalidProposal)); i++ {
+		_ = ns.Penalize(base.MustNewStringAddress("n-n0"), PeerPenaltyInvalidProposal, "findme")
+	}
+	t.True(ns.IsBanned(base.MustNewStringAddress("n-n0")))
+
+	// NOTE the host of channel is not banned
+	u, _ := url.Parse("https://n5:54321")
+	ch := NewDummyChannel(NewHTTPConnInfo(u, true))
+	ch.SetNewSealHandler(func(sl seal.Seal) error {
+		received.Store("n5", sl)
+
+		return nil
+	})
+	t.NoError(ns.Add(node.RandomLocal("n5"), ch))
+
+	ns.PeerScores().Ban("n5", "showme", time.Minute)
+	t.False(ns.IsBanned(base.MustNewStringAddress("n-n5")))
+
+	ns.PeerScores().Ban(base.MustNewStringAddress("n-n5").String(), "showme", time.Minute)
+	t.True(ns.IsBanned(base.MustNewStringAddress("n-n5")))
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Broadcast(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+
+	t.Equal(4, t.count(received))
+	_, found := received.Load("n0")
+	t.False(found)
+	_, found = received.Load("n5")
+	t.False(found)
+
+	// NOTE local is not penalized
+	t.False(ns.Penalize(t.local.Address(), PeerPenaltyInvalidProposal, "findme"))
+	t.Equal(uint(0), ns.PeerScores().Score(t.local.Address().String()))
+}
+
+func (t *testNodepoolGossip) TestGossipBanned() {
+	ns, received := t.newNodepool(5, 5)
+
+	ns.PeerScores().Ban(base.MustNewStringAddress("n-n0").String(), "findme", time.Minute)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	errs, err := ns.Gossip(context.Background(), sl, nil)
+	t.NoError(err)
+	t.Empty(errs)
+
+	t.Equal(4, t.count(received))
+	_, found := received.Load("n0")
+	t.False(found)
+}
+
+func TestNodepoolGossip(t *testing.T) {
+	suite.Run(t, new(testNodepoolGossip))
+}
@@ -0,0 +1,229 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PeerPenalty is the score added to the peer, which sends the invalid data.
+type PeerPenalty uint
+
+var (
+	PeerPenaltyInvalidSeal     PeerPenalty = 10
+	PeerPenaltyInvalidProposal PeerPenalty = 20
+)
+
+var (
+	DefaultPeerScoreThreshold uint = 100
+	DefaultPeerScoreWindow         = time.Minute * 10
+	DefaultPeerBanDuration         = time.Minute * 10
+)
+
+// PeerBan is the banned peer.
+type PeerBan struct {
+	Peer   string    `json:"peer"`
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until"`
+}
+
+type peerPenalty struct {
+	penalty PeerPenalty
+	at      time.Time
+}
+
+// PeerScores tracks the penalties of peers. The peer is identified by node
+// address. When the sum of penalties of the peer in
+// window crosses threshold, the peer is banned for the ban duration.
+type PeerScores struct {
+	sync.Mutex
+	threshold   uint
+	window      time.Duration
+	banDuration time.Duration
+	penalties   map[string][]peerPenalty
+	bans        map[string]PeerBan
+}
+
+func NewPeerScores(threshold uint, window, banDuration time.Duration) (*PeerScores, error) {
+	switch {
+	case threshold < 1:
+		return nil, errors.Errorf("threshold of peer score should be over 0, %d", threshold)
+	case window <= 0:
+		return nil, errors.Errorf("window of peer score should be over 0, %v", window)
+	case banDuration <= 0:
+		return nil, errors.Errorf("ban duration of peer should be over 0, %v", banDuration)
+	}
+
+	return &PeerScores{
+		threshold:   threshold,
+		window:      window,
+		banDuration: banDuration,
+		penalties:   map[string][]peerPenalty{},
+		bans:        map[string]PeerBan{},
+	}, nil
+}
+
+func NewDefaultPeerScores() *PeerScores {
+	ps, _ := NewPeerScores(DefaultPeerScoreThreshold, DefaultPeerScoreWindow, DefaultPeerBanDuration)
+
+	return ps
+}
+
+// Penalize adds penalty to the peer. If the score of peer crosses threshold,
+// the peer is banned and returns true.
+func (ps *PeerScores) Penalize(peer string, penalty PeerPenalty, reason string) bool {
+	if len(peer) < 1 {
+		return false
+	}
+
+	ps.Lock()
+	defer ps.Unlock()
+
+	if _, found := ps.ban(peer); found {
+		return false
+	}
+
+	now := time.Now()
+	ps.penalties[peer] = append(ps.filterPenalties(peer, now), peerPenalty{penalty: penalty, at: now})
+
+	if ps.score(peer) < ps.threshold {
+		return false
+	}
+
+	delete(ps.penalties, peer)
+	ps.bans[peer] = PeerBan{Peer: peer, Reason: reason, Until: now.Add(ps.banDuration)}
+
+	return true
+}
+
+// Score returns the sum of penalties of peer in window.
+func (ps *PeerScores) Score(peer string) uint {
+	ps.Lock()
+	defer ps.Unlock()
+
+	l := ps.filterPenalties(peer, time.Now())
+	if len(l) < 1 {
+		delete(ps.penalties, peer)
+
+		return 0
+	}
+
+	ps.penalties[peer] = l
+
+	return ps.score(peer)
+}
+
+func (ps *PeerScores) IsBanned(peer string) bool {
+	if len(peer) < 1 {
+		return false
+	}
+
+	ps.Lock()
+	defer ps.Unlock()
+
+	_, found := ps.ban(peer)
+
+	return found
+}
+
+// Ban bans the peer for duration regardless of it's score.
+func (ps *PeerScores) Ban(peer, reason string, duration time.Duration) {
+	ps.Lock()
+	defer ps.Unlock()
+
+	delete(ps.penalties, peer)
+	ps.bans[peer] = PeerBan{Peer: peer, Reason: reason, Until: time.Now().Add(duration)}
+}
+
+// Unban clears the ban and the penalties of peer. It returns false if the peer
+// is not banned.
+func (ps *PeerScores) Unban(peer string) bool {
+	ps.Lock()
+	defer ps.Unlock()
+
+	delete(ps.penalties, peer)
+
+	if _, found := ps.ban(peer); !found {
+		return false
+	}
+
+	delete(ps.bans, peer)
+
+	return true
+}
+
+// Clear clears all the bans and penalties.
+func (ps *PeerScores) Clear() {
+	ps.Lock()
+	defer ps.Unlock()
+
+	ps.penalties = map[string][]peerPenalty{}
+	ps.bans = map[string]PeerBan{}
+}
+
+// Bans returns the banned peers sorted by peer.
+func (ps *PeerScores) Bans() []PeerBan {
+	ps.Lock()
+	defer ps.Unlock()
+
+	bans := make([]PeerBan, 0, len(ps.bans))
+	for k := range ps.bans {
+		if b, found := ps.ban(k); found {
+			bans = append(bans, b)
+		}
+	}
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Peer < bans[j].Peer
+	})
+
+	return bans
+}
+
+func (ps *PeerScores) ban(peer string) (PeerBan, bool) {
+	b, found := ps.bans[peer]
+	switch {
+	case !found:
+		return b, false
+	case time.Now().After(b.Until):
+		delete(ps.bans, peer)
+
+		return b, false
+	default:
+		return b, true
+	}
+}
+
+func (ps *PeerScores) filterPenalties(peer string, now time.Time) []peerPenalty {
+	ps.cleanPenalties(now)
+
+	var filtered []peerPenalty
+	for _, p := range ps.penalties[peer] {
+		if now.Sub(p.at) <= ps.window {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
+
+// cleanPenalties removes the peers, whose penalties are all expired.
+func (ps *PeerScores) cleanPenalties(now time.Time) {
+	for k := range ps.penalties {
+		l := ps.penalties[k]
+		if len(l) < 1 || now.Sub(l[len(l)-1].at) > ps.window {
+			delete(ps.penalties, k)
+		}
+	}
+}
+
+func (ps *PeerScores) score(peer string) uint {
+	var s uint
+	for _, p := range ps.penalties[peer] {
+		s += uint(p.penalty)
+	}
+
+	return s
+}
@@ -0,0 +1,116 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type testPeerScores struct {
+	suite.Suite
+}
+
+func (t *testPeerScores) TestNew() {
+	_, err := NewPeerScores(0, time.Minute, time.Minute)
+	t.Contains(err.Error(), "threshold of peer score should be over 0")
+
+	_, err = NewPeerScores(10, 0, time.Minute)
+	t.Contains(err.Error(), "window of peer score should be over 0")
+
+	_, err = NewPeerScores(10, time.Minute, 0)
+	t.Contains(err.Error(), "ban duration of peer should be over 0")
+
+	ps := NewDefaultPeerScores()
+	t.NotNil(ps)
+	t.Empty(ps.Bans())
+}
+
+func (t *testPeerScores) TestPenalize() {
+	ps, err := NewPeerScores(30, time.Minute, time.Minute)
+	t.NoError(err)
+
+	t.False(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+	t.False(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+	t.Equal(uint(20), ps.Score("a"))
+	t.False(ps.IsBanned("a"))
+
+	t.True(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+	t.True(ps.IsBanned("a"))
+	t.False(ps.IsBanned("b"))
+
+	// NOTE banned peer is not penalized again
+	t.False(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+
+	bans := ps.Bans()
+	t.Equal(1, len(bans))
+	t.Equal("a", bans[0].Peer)
+	t.Equal("findme", bans[0].Reason)
+	t.True(bans[0].Until.After(time.Now()))
+}
+
+func (t *testPeerScores) TestEmptyPeer() {
+	ps, err := NewPeerScores(1, time.Minute, time.Minute)
+	t.NoError(err)
+
+	t.False(ps.Penalize("", PeerPenaltyInvalidSeal, "findme"))
+	t.False(ps.IsBanned(""))
+}
+
+func (t *testPeerScores) TestWindow() {
+	ps, err := NewPeerScores(20, time.Millisecond*100, time.Minute)
+	t.NoError(err)
+
+	t.False(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+
+	<-time.After(time.Millisecond * 200)
+
+	// NOTE expired penalty is not counted
+	t.Equal(uint(0), ps.Score("a"))
+	t.False(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+	t.False(ps.IsBanned("a"))
+	t.Equal(uint(10), ps.Score("a"))
+}
+
+func (t *testPeerScores) TestBanExpired() {
+	ps, err := NewPeerScores(10, time.Minute, time.Millisecond*100)
+	t.NoError(err)
+
+	t.True(ps.Penalize("a", PeerPenaltyInvalidSeal, "findme"))
+	t.True(ps.IsBanned("a"))
+
+	<-time.After(time.Millisecond * 200)
+
+	t.False(ps.IsBanned("a"))
+	t.Empty(ps.Bans())
+	t.Equal(uint(0), ps.Score("a"))
+}
+
+func (t *testPeerScores) TestUnban() {
+	ps, err := NewPeerScores(10, time.Minute, time.Minute)
+	t.NoError(err)
+
+	t.True(ps.Penalize("a", PeerPenaltyInvalidProposal, "findme"))
+	ps.Ban("b", "showme", time.Minute)
+
+	t.True(ps.IsBanned("a"))
+	t.True(ps.IsBanned("b"))
+
+	bans := ps.Bans()
+	t.Equal(2, len(bans))
+	t.Equal("a", bans[0].Peer)
+	t.Equal("b", bans[1].Peer)
+
+	t.True(ps.Unban("a"))
+	t.False(ps.Unban("a"))
+	t.False(ps.IsBanned("a"))
+	t.True(ps.IsBanned("b"))
+
+	ps.Clear()
+	t.False(ps.IsBanned("b"))
+	t.Empty(ps.Bans())
+}
+
+func TestPeerScores(t *testing.T) {
+	suite.Run(t, new(testPeerScores))
+}
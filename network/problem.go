@@ -14,6 +14,9 @@ const (
 	ProblemMimetype    = "application/problem+json; charset=utf-8"
 	ProblemNamespace   = "https://github.com/spikeekips/mitum/problems"
 	DefaultProblemType = "others"
+	// PrunedHeightProblemType is the problem type for the requests of the
+	// pruned heights.
+	PrunedHeightProblemType = "pruned-height"
 )
 
 var (
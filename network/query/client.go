@@ -0,0 +1,220 @@
+package querynetwork
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// Client requests to the query server. With the proofs from the query server,
+// light client can check the operations and states without downloading
+// blocks.
+type Client struct {
+	u      *url.URL
+	enc    *jsonenc.Encoder
+	client *http.Client
+}
+
+func NewClient(u *url.URL, enc *jsonenc.Encoder, client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Client{u: u, enc: enc, client: client}
+}
+
+func (cl *Client) Manifest(ctx context.Context, height base.Height) (block.Manifest, error) {
+	b, err := cl.get(ctx, fmt.Sprintf("/block/%d", height))
+	if err != nil {
+		return nil, err
+	}
+
+	hinter, err := cl.enc.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := hinter.(block.Manifest)
+	if !ok {
+		return nil, errors.Errorf("not block.Manifest, %T", hinter)
+	}
+
+	return m, nil
+}
+
+// OperationProof requests the proof of operation in the block of height.
+func (cl *Client) OperationProof(
+	ctx context.Context,
+	height base.Height,
+	fact valuehash.Hash,
+) (OperationProofResponse, error) {
+	var res OperationProofResponse
+
+	b, err := cl.get(ctx, fmt.Sprintf("/block/%d/operation/%s/proof", height, fact.String()))
+	if err != nil {
+		return res, err
+	}
+
+	if err := res.UnpackJSON(b, cl.enc); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// StateProof requests the state and it's proof in the block of height. The
+// state should be changed in the block.
+func (cl *Client) StateProof(ctx context.Context, height base.Height, key string) (StateProofResponse, error) {
+	var res StateProofResponse
+
+	b, err := cl.get(ctx, fmt.Sprintf("/block/%d/state/%s/proof", height, url.PathEscape(key)))
+	if err != nil {
+		return res, err
+	}
+
+	if err := res.UnpackJSON(b, cl.enc); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// VerifyOperation requests the manifest and the proof of operation, and then
+// checks the proof. The manifest from the query server is not trusted; it
+// should be valid and have the trusted block hash, which the caller knows from
+// the other source like the voteproof or the other nodes.
+func (cl *Client) VerifyOperation(
+	ctx context.Context,
+	networkID base.NetworkID,
+	height base.Height,
+	blockHash valuehash.Hash,
+	fact valuehash.Hash,
+) error {
+	m, err := cl.trustedManifest(ctx, networkID, height, blockHash)
+	if err != nil {
+		return err
+	}
+
+	res, err := cl.OperationProof(ctx, height, fact)
+	if err != nil {
+		return err
+	}
+
+	return res.Verify(m)
+}
+
+// VerifyState requests the manifest and the state with it's proof, and then
+// checks the proof like VerifyOperation. The verified state is returned.
+func (cl *Client) VerifyState(
+	ctx context.Context,
+	networkID base.NetworkID,
+	height base.Height,
+	blockHash valuehash.Hash,
+	key string,
+) (state.State, error) {
+	m, err := cl.trustedManifest(ctx, networkID, height, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cl.StateProof(ctx, height, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.State == nil {
+		return nil, tree.InvalidProofError.Errorf("empty state")
+	} else if res.State.Key() != key {
+		return nil, tree.InvalidProofError.Errorf("state key does not match, %q != %q", res.State.Key(), key)
+	}
+
+	if err := res.Verify(m); err != nil {
+		return nil, err
+	}
+
+	return res.State, nil
+}
+
+func (cl *Client) trustedManifest(
+	ctx context.Context,
+	networkID base.NetworkID,
+	height base.Height,
+	blockHash valuehash.Hash,
+) (block.Manifest, error) {
+	if blockHash == nil {
+		return nil, errors.Errorf("empty trusted block hash")
+	}
+
+	m, err := cl.Manifest(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	switch err := m.IsValid(networkID); {
+	case err != nil:
+		return nil, errors.Wrap(err, "invalid manifest")
+	case m.Height() != height:
+		return nil, isvalid.InvalidError.Errorf("manifest height does not match, %d != %d", m.Height(), height)
+	case !m.Hash().Equal(blockHash):
+		return nil, isvalid.InvalidError.Errorf("manifest hash does not match, %q != %q", m.Hash(), blockHash)
+	default:
+		return m, nil
+	}
+}
+
+func (cl *Client) get(ctx context.Context, p string) ([]byte, error) {
+	u := *cl.u
+	u.Path = strings.TrimRight(u.Path, "/") + p
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cl.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errorFromResponse(res)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+func errorFromResponse(res *http.Response) error {
+	var err error
+	if pr, perr := network.LoadProblemFromResponse(res); perr == nil {
+		err = pr
+	} else {
+		err = errors.Errorf("unexpected status, %d", res.StatusCode)
+	}
+
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		return util.NotFoundError.Wrap(err)
+	case http.StatusBadRequest:
+		return BadRequestError.Wrap(err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,6 @@
+/*
+Package querynetwork provides the http API to query blocks, operations and states, to submit operations and to subscribe
+the events of new blocks and consensus states. The inclusion proofs of operations and states can be requested and checked
+by Client without downloading blocks.
+*/
+package querynetwork
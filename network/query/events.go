@@ -0,0 +1,424 @@
+package querynetwork
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// EventsKeepAliveInterval is the interval to send comment to keep the event
+// stream alive.
+var EventsKeepAliveInterval = time.Second * 15
+
+// EventsBufferSize is the number of the event batches, which can be queued
+// for each subscriber. If subscriber is too slow, it will be disconnected and
+// it should resume from the last received block.
+var EventsBufferSize = 1 << 10
+
+type EventType string
+
+const (
+	EventTypeBlock          EventType = "block"
+	EventTypeOperation      EventType = "operation"
+	EventTypeConsensusState EventType = "consensus_state"
+)
+
+// Event is pushed to the subscribers. Height is base.NilHeight for the events,
+// which are not related with block.
+type Event struct {
+	Type   EventType
+	Height base.Height
+	Data   interface{}
+}
+
+// BlockEvent is sent after the operation events of the same block, so the
+// block event marks that all the events of block are delivered.
+type BlockEvent struct {
+	Manifest block.Manifest `json:"manifest"`
+}
+
+// OperationEvent is the processed result of operation. States has the keys of
+// the states, which are changed by the operation.
+type OperationEvent struct {
+	Height  base.Height           `json:"height"`
+	Fact    valuehash.Hash        `json:"fact"`
+	Hash    valuehash.Hash        `json:"hash"`
+	InState bool                  `json:"in_state"`
+	Reason  operation.ReasonError `json:"reason,omitempty"`
+	States  []string              `json:"states"`
+}
+
+type ConsensusStateEvent struct {
+	From base.State `json:"from"`
+	To   base.State `json:"to"`
+}
+
+// PublishBlocks pushes the events of the saved blocks to the subscribers.
+func (sv *Server) PublishBlocks(blks []block.Block) error {
+	for i := range blks {
+		blk := blks[i]
+
+		evs, err := newBlockEvents(blk.Manifest(), blk.Operations(), blk.OperationsTree(), blk.States())
+		if err != nil {
+			return err
+		}
+
+		sv.events.publish(evs)
+	}
+
+	return nil
+}
+
+// PublishConsensusState pushes the consensus state change to the subscribers.
+func (sv *Server) PublishConsensusState(from, to base.State) {
+	sv.events.publish([]Event{{
+		Type:   EventTypeConsensusState,
+		Height: base.NilHeight,
+		Data:   ConsensusStateEvent{From: from, To: to},
+	}})
+}
+
+// handleEvents streams the events thru server-sent events. With "height" query
+// parameter or "Last-Event-ID" header, the events of the stored blocks from the
+// height are sent before the new events. The id of event is the height of
+// block event, so "Last-Event-ID" is the height of the last delivered block.
+func (sv *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sv.writeError(w, errors.Errorf("streaming not supported"))
+
+		return
+	}
+
+	from, err := parseEventsHeight(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	ch, cancel := sv.events.subscribe()
+	defer cancel()
+
+	if ch == nil {
+		network.WritePoblem(w, http.StatusServiceUnavailable,
+			network.NewProblem(network.DefaultProblemType, "query server stopped"))
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// NOTE the new events under last are already sent by replaying.
+	last := base.NilHeight
+	if from > base.NilHeight {
+		i, err := sv.replayEvents(r.Context(), w, flusher, from)
+		if err != nil {
+			sv.Log().Error().Err(err).Int64("from", from.Int64()).Msg("failed to replay events")
+
+			return
+		}
+		last = i
+	}
+
+	ticker := time.NewTicker(EventsKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case evs, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			for i := range evs {
+				if evs[i].Height > base.NilHeight && evs[i].Height <= last {
+					continue
+				}
+
+				if err := writeEvent(w, evs[i]); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// replayEvents sends the events of the stored blocks from the given height and
+// returns the height of the last sent block.
+func (sv *Server) replayEvents(
+	ctx context.Context,
+	w http.ResponseWriter,
+	flusher http.Flusher,
+	from base.Height,
+) (base.Height, error) {
+	last := base.NilHeight
+	switch m, found, err := sv.database.LastManifest(); {
+	case err != nil:
+		return last, err
+	case !found:
+		return last, nil
+	default:
+		last = m.Height()
+	}
+
+	for h := from; h <= last; h++ {
+		if err := ctx.Err(); err != nil {
+			return last, err
+		}
+
+		evs, err := sv.blockEvents(ctx, h)
+		if err != nil {
+			return last, err
+		}
+
+		for i := range evs {
+			if err := writeEvent(w, evs[i]); err != nil {
+				return last, err
+			}
+		}
+		flusher.Flush()
+	}
+
+	return last, nil
+}
+
+// blockEvents loads the events of the stored block.
+func (sv *Server) blockEvents(ctx context.Context, height base.Height) ([]Event, error) {
+	var m block.Manifest
+	switch i, found, err := sv.database.ManifestByHeight(height); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, util.NotFoundError.Errorf("block, %d not found", height)
+	default:
+		m = i
+	}
+
+	ops, err := sv.operations(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	tr, err := sv.operationsTree(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	sts, err := sv.states(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBlockEvents(m, ops, tr, sts)
+}
+
+func newBlockEvents(
+	m block.Manifest,
+	ops []operation.Operation,
+	tr tree.FixedTree,
+	sts []state.State,
+) ([]Event, error) {
+	keys := map[string][]string{}
+	for i := range sts {
+		facts := sts[i].Operations()
+		for j := range facts {
+			k := facts[j].String()
+			keys[k] = append(keys[k], sts[i].Key())
+		}
+	}
+
+	evs := make([]Event, len(ops)+1)
+	for i := range ops {
+		fact := ops[i].Fact().Hash()
+
+		node, err := operationTreeNode(tr, fact)
+		if err != nil {
+			return nil, err
+		}
+
+		stkeys := keys[fact.String()]
+		if stkeys == nil {
+			stkeys = []string{}
+		}
+
+		evs[i] = Event{
+			Type:   EventTypeOperation,
+			Height: m.Height(),
+			Data: OperationEvent{
+				Height:  m.Height(),
+				Fact:    fact,
+				Hash:    ops[i].Hash(),
+				InState: node.InState(),
+				Reason:  node.Reason(),
+				States:  stkeys,
+			},
+		}
+	}
+
+	evs[len(ops)] = Event{Type: EventTypeBlock, Height: m.Height(), Data: BlockEvent{Manifest: m}}
+
+	return evs, nil
+}
+
+// operationTreeNode finds the operations tree node by fact hash.
+func operationTreeNode(tr tree.FixedTree, fact valuehash.Hash) (operation.FixedTreeNode, error) {
+	var node operation.FixedTreeNode
+	var found bool
+	if err := tr.Traverse(func(n tree.FixedTreeNode) (bool, error) {
+		if !bytes.Equal(n.Key(), fact.Bytes()) {
+			return true, nil
+		}
+
+		i, ok := n.(operation.FixedTreeNode)
+		if !ok {
+			return false, errors.Errorf("not operation.FixedTreeNode, %T", n)
+		}
+		node = i
+		found = true
+
+		return false, nil
+	}); err != nil {
+		return node, err
+	}
+
+	if !found {
+		return node, util.NotFoundError.Errorf("operation, %q not found in operations tree", fact)
+	}
+
+	return node, nil
+}
+
+func writeEvent(w io.Writer, ev Event) error {
+	b, err := jsonenc.Marshal(ev.Data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if ev.Type == EventTypeBlock {
+		_, _ = fmt.Fprintf(&buf, "id: %d\n", ev.Height)
+	}
+	_, _ = fmt.Fprintf(&buf, "event: %s\ndata: %s\n\n", ev.Type, b)
+
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+// parseEventsHeight parses the height to resume the events. The "height" query
+// parameter is inclusive and "Last-Event-ID" is exclusive.
+func parseEventsHeight(r *http.Request) (base.Height, error) {
+	if s := strings.TrimSpace(r.URL.Query().Get("height")); len(s) > 0 {
+		i, err := base.NewHeightFromString(s)
+		if err != nil {
+			return base.NilHeight, BadRequestError.Wrap(err)
+		}
+
+		return i, nil
+	}
+
+	if s := strings.TrimSpace(r.Header.Get("Last-Event-ID")); len(s) > 0 {
+		i, err := base.NewHeightFromString(s)
+		if err != nil {
+			return base.NilHeight, BadRequestError.Wrap(err)
+		}
+
+		return i + 1, nil
+	}
+
+	return base.NilHeight, nil
+}
+
+type eventBroker struct {
+	sync.Mutex
+	subscribers map[chan []Event]struct{}
+	closed      bool
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subscribers: map[chan []Event]struct{}{}}
+}
+
+// subscribe returns the channel of event batches. The channel is closed when
+// broker is closed or subscriber is too slow. If broker is already closed, nil
+// channel is returned.
+func (eb *eventBroker) subscribe() (chan []Event, func()) {
+	eb.Lock()
+	defer eb.Unlock()
+
+	if eb.closed {
+		return nil, func() {}
+	}
+
+	ch := make(chan []Event, EventsBufferSize)
+	eb.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		eb.Lock()
+		defer eb.Unlock()
+
+		if _, found := eb.subscribers[ch]; found {
+			delete(eb.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (eb *eventBroker) publish(evs []Event) {
+	if len(evs) < 1 {
+		return
+	}
+
+	eb.Lock()
+	defer eb.Unlock()
+
+	for ch := range eb.subscribers {
+		select {
+		case ch <- evs:
+		default:
+			delete(eb.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (eb *eventBroker) close() {
+	eb.Lock()
+	defer eb.Unlock()
+
+	for ch := range eb.subscribers {
+		close(ch)
+	}
+
+	eb.subscribers = map[chan []Event]struct{}{}
+	eb.closed = true
+}
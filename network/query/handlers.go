@@ -0,0 +1,772 @@
+package querynetwork
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	DefaultLimit                      = 20
+	MaxLimit                          = 100
+	MaxSubmitOperationsBodySize int64 = 1 << 22 // 4MiB
+)
+
+var BadRequestError = util.NewError("bad request")
+
+func (sv *Server) handleLastBlock(w http.ResponseWriter, _ *http.Request) {
+	switch m, found, err := sv.database.LastManifest(); {
+	case err != nil:
+		sv.writeError(w, err)
+	case !found:
+		sv.writeError(w, util.NotFoundError.Errorf("last block not found"))
+	default:
+		sv.writeJSON(w, m)
+	}
+}
+
+func (sv *Server) handleManifestByHeight(w http.ResponseWriter, r *http.Request) {
+	height, err := parseHeight(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	switch m, found, err := sv.database.ManifestByHeight(height); {
+	case err != nil:
+		sv.writeError(w, err)
+	case !found:
+		sv.writeError(w, util.NotFoundError.Errorf("block, %d not found", height))
+	default:
+		sv.writeJSON(w, m)
+	}
+}
+
+func (sv *Server) handleManifestByHash(w http.ResponseWriter, r *http.Request) {
+	h, err := parseHash(r, "hash")
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	switch m, found, err := sv.database.Manifest(h); {
+	case err != nil:
+		sv.writeError(w, err)
+	case !found:
+		sv.writeError(w, util.NotFoundError.Errorf("block, %q not found", h))
+	default:
+		sv.writeJSON(w, m)
+	}
+}
+
+func (sv *Server) handleOperations(w http.ResponseWriter, r *http.Request) {
+	height, err := parseHeight(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	offset, limit, err := parsePage(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	ops, err := sv.operations(r.Context(), height)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	res := OperationsResponse{
+		Height:     height,
+		Offset:     offset,
+		Limit:      limit,
+		Total:      len(ops),
+		Operations: []operation.Operation{},
+	}
+
+	if offset < len(ops) {
+		end := offset + limit
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		res.Operations = ops[offset:end]
+	}
+
+	sv.writeJSON(w, res)
+}
+
+func (sv *Server) handleSuffrageInfo(w http.ResponseWriter, r *http.Request) {
+	height, err := parseHeight(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	var si block.SuffrageInfo
+	if err := sv.readBlockdata(
+		r.Context(),
+		height,
+		func(m block.BlockdataMap) block.BlockdataMapItem { return m.SuffrageInfo() },
+		func(r io.Reader) error {
+			i, err := sv.blockdata.Writer().ReadSuffrageInfo(r)
+			if err != nil {
+				return err
+			}
+			si = i
+
+			return nil
+		},
+	); err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	sv.writeJSON(w, si)
+}
+
+func (sv *Server) handleOperationProof(w http.ResponseWriter, r *http.Request) {
+	height, err := parseHeight(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	fact, err := parseHash(r, "fact")
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	tr, err := sv.operationsTree(r.Context(), height)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	node, err := operationTreeNode(tr, fact)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	pr, err := tr.Proof(node.Index())
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	sv.writeJSON(w, OperationProofResponse{Height: height, Fact: fact, Proof: pr})
+}
+
+func (sv *Server) handleStateProof(w http.ResponseWriter, r *http.Request) {
+	height, err := parseHeight(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	key, err := parseStateKey(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	sts, err := sv.states(r.Context(), height)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	var st state.State
+	for i := range sts {
+		if sts[i].Key() == key {
+			st = sts[i]
+
+			break
+		}
+	}
+
+	if st == nil {
+		sv.writeError(w, util.NotFoundError.Errorf("state, %q not found in block, %d", key, height))
+
+		return
+	}
+
+	var tr tree.FixedTree
+	if err := sv.readBlockdata(
+		r.Context(),
+		height,
+		func(m block.BlockdataMap) block.BlockdataMapItem { return m.StatesTree() },
+		func(r io.Reader) error {
+			i, err := sv.blockdata.Writer().ReadStatesTree(r)
+			if err != nil {
+				return err
+			}
+			tr = i
+
+			return nil
+		},
+	); err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	index := -1
+	if err := tr.Traverse(func(n tree.FixedTreeNode) (bool, error) {
+		if !bytes.Equal(n.Key(), st.Hash().Bytes()) {
+			return true, nil
+		}
+		index = int(n.Index())
+
+		return false, nil
+	}); err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	if index < 0 {
+		sv.writeError(w, util.NotFoundError.Errorf("state, %q not found in states tree, %d", key, height))
+
+		return
+	}
+
+	pr, err := tr.Proof(uint64(index))
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	sv.writeJSON(w, StateProofResponse{Height: height, State: st, Proof: pr})
+}
+
+func (sv *Server) handleOperation(w http.ResponseWriter, r *http.Request) {
+	fact, err := parseHash(r, "fact")
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	var height base.Height
+	switch i, found, err := sv.database.OperationFactHeight(fact); {
+	case err != nil:
+		sv.writeError(w, err)
+
+		return
+	case !found:
+		sv.writeError(w, util.NotFoundError.Errorf("operation, %q not found", fact))
+
+		return
+	default:
+		height = i
+	}
+
+	ops, err := sv.operations(r.Context(), height)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	for i := range ops {
+		if ops[i].Fact().Hash().Equal(fact) {
+			sv.writeJSON(w, OperationResponse{Height: height, Operation: ops[i]})
+
+			return
+		}
+	}
+
+	sv.writeError(w, util.NotFoundError.Errorf("operation, %q not found in block data, %d", fact, height))
+}
+
+func (sv *Server) handleOperationStatus(w http.ResponseWriter, r *http.Request) {
+	fact, err := parseHash(r, "fact")
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	res, err := sv.operationStatus(r.Context(), fact)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	sv.writeJSON(w, res)
+}
+
+func (sv *Server) handleSubmitOperations(w http.ResponseWriter, r *http.Request) {
+	if sv.submitOperations == nil {
+		network.WritePoblem(w, http.StatusServiceUnavailable,
+			network.NewProblem(network.DefaultProblemType, "operation submission not supported"))
+
+		return
+	}
+
+	ops, err := sv.loadSubmittedOperations(w, r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	if err := sv.submitOperations(ops); err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	receipts := make([]OperationReceipt, len(ops))
+	for i := range ops {
+		fact := ops[i].Fact().Hash()
+		_ = sv.submitted.Set(fact.String(), struct{}{}, 0)
+
+		receipts[i] = OperationReceipt{
+			Fact:   fact,
+			Hash:   ops[i].Hash(),
+			Status: strings.Replace(QueryPathOperationStatus, "{fact}", fact.String(), 1),
+		}
+	}
+
+	sv.writeJSONWithStatus(w, http.StatusAccepted, receipts)
+}
+
+func (sv *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	key, err := parseStateKey(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	switch st, found, err := sv.database.State(key); {
+	case err != nil:
+		sv.writeError(w, err)
+	case !found:
+		sv.writeError(w, util.NotFoundError.Errorf("state, %q not found", key))
+	default:
+		sv.writeJSON(w, st)
+	}
+}
+
+func (sv *Server) handleStateHistory(w http.ResponseWriter, r *http.Request) {
+	key, err := parseStateKey(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	height := base.NilHeight
+	if s := strings.TrimSpace(r.URL.Query().Get("height")); len(s) > 0 {
+		i, err := base.NewHeightFromString(s)
+		if err != nil {
+			sv.writeError(w, BadRequestError.Wrap(err))
+
+			return
+		}
+		height = i
+	}
+
+	_, limit, err := parsePage(r)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	res := StateHistoryResponse{Key: key, States: []state.State{}}
+	if err := sv.database.StateHistory(key, height, int64(limit), func(st state.State) (bool, error) {
+		res.States = append(res.States, st)
+
+		return true, nil
+	}); err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	if n := len(res.States); n == limit {
+		next := res.States[n-1].Height()
+		res.Next = &next
+	}
+
+	sv.writeJSON(w, res)
+}
+
+func (sv *Server) loadSubmittedOperations(w http.ResponseWriter, r *http.Request) ([]operation.Operation, error) {
+	b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxSubmitOperationsBodySize))
+	if err != nil {
+		return nil, BadRequestError.Wrap(err)
+	}
+
+	hinters, err := sv.enc.DecodeSlice(b)
+	if err != nil {
+		return nil, BadRequestError.Wrap(err)
+	}
+
+	if len(hinters) < 1 {
+		return nil, BadRequestError.Errorf("empty operations")
+	}
+
+	ops := make([]operation.Operation, len(hinters))
+	for i := range hinters {
+		op, ok := hinters[i].(operation.Operation)
+		if !ok {
+			return nil, BadRequestError.Errorf("not operation, %T", hinters[i])
+		}
+		ops[i] = op
+	}
+
+	return ops, nil
+}
+
+// operationStatus finds the status of operation. The operation in block is
+// checked first, and then the proposals and the staged operations are checked.
+// The submitted operation, which is dropped from the staged operations without
+// inclusion, is rejected.
+func (sv *Server) operationStatus(ctx context.Context, fact valuehash.Hash) (OperationStatusResponse, error) {
+	res := OperationStatusResponse{Fact: fact}
+
+	switch height, found, err := sv.database.OperationFactHeight(fact); {
+	case err != nil:
+		return res, err
+	case found:
+		return sv.operationStatusInBlock(ctx, fact, height)
+	}
+
+	staged, err := sv.database.HasStagedOperation(fact)
+	if err != nil {
+		return res, err
+	}
+
+	pr, err := sv.proposalByOperation(fact)
+	if err != nil {
+		return res, err
+	}
+
+	switch {
+	case pr != nil:
+		res.Status = OperationStatusProposed
+		res.Proposal = pr.Fact().Hash()
+	case staged:
+		res.Status = OperationStatusStaged
+	case sv.submitted.Has(fact.String()):
+		res.Status = OperationStatusRejected
+		res.Reason = operation.NewBaseReasonError("dropped from staged operations without inclusion")
+	default:
+		return res, util.NotFoundError.Errorf("operation, %q not found", fact)
+	}
+
+	return res, nil
+}
+
+func (sv *Server) operationStatusInBlock(
+	ctx context.Context,
+	fact valuehash.Hash,
+	height base.Height,
+) (OperationStatusResponse, error) {
+	res := OperationStatusResponse{Fact: fact, Height: &height}
+
+	tr, err := sv.operationsTree(ctx, height)
+	if err != nil {
+		return res, err
+	}
+
+	node, err := operationTreeNode(tr, fact)
+	if err != nil {
+		return res, err
+	}
+
+	if node.InState() {
+		res.Status = OperationStatusIncluded
+	} else {
+		res.Status = OperationStatusRejected
+		res.Reason = node.Reason()
+	}
+
+	return res, nil
+}
+
+// proposalByOperation finds the proposal, which contains the operation and is
+// not yet stored in block. The proposals are iterated by descending height, so
+// only the proposals above the last block are checked.
+func (sv *Server) proposalByOperation(fact valuehash.Hash) (base.Proposal, error) {
+	height := base.PreGenesisHeight
+	switch m, found, err := sv.database.LastManifest(); {
+	case err != nil:
+		return nil, err
+	case found:
+		height = m.Height()
+	}
+
+	var pr base.Proposal
+	if err := sv.database.Proposals(func(i base.Proposal) (bool, error) {
+		if i.Fact().Height() <= height {
+			return false, nil
+		}
+
+		ops := i.Fact().Operations()
+		for j := range ops {
+			if ops[j].Equal(fact) {
+				pr = i
+
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}, false); err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (sv *Server) operations(ctx context.Context, height base.Height) ([]operation.Operation, error) {
+	var ops []operation.Operation
+	if err := sv.readBlockdata(
+		ctx,
+		height,
+		func(m block.BlockdataMap) block.BlockdataMapItem { return m.Operations() },
+		func(r io.Reader) error {
+			i, err := sv.blockdata.Writer().ReadOperations(r)
+			if err != nil {
+				return err
+			}
+			ops = i
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return ops, nil
+}
+
+func (sv *Server) states(ctx context.Context, height base.Height) ([]state.State, error) {
+	var sts []state.State
+	if err := sv.readBlockdata(
+		ctx,
+		height,
+		func(m block.BlockdataMap) block.BlockdataMapItem { return m.States() },
+		func(r io.Reader) error {
+			i, err := sv.blockdata.Writer().ReadStates(r)
+			if err != nil {
+				return err
+			}
+			sts = i
+
+			return nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	return sts, nil
+}
+
+func (sv *Server) operationsTree(ctx context.Context, height base.Height) (tree.FixedTree, error) {
+	var tr tree.FixedTree
+	if err := sv.readBlockdata(
+		ctx,
+		height,
+		func(m block.BlockdataMap) block.BlockdataMapItem { return m.OperationsTree() },
+		func(r io.Reader) error {
+			i, err := sv.blockdata.Writer().ReadOperationsTree(r)
+			if err != nil {
+				return err
+			}
+			tr = i
+
+			return nil
+		},
+	); err != nil {
+		return tr, err
+	}
+
+	return tr, nil
+}
+
+// readBlockdata reads the block data item of the given height; the item can be
+// stored in local or remote.
+func (sv *Server) readBlockdata(
+	ctx context.Context,
+	height base.Height,
+	itemf func(block.BlockdataMap) block.BlockdataMapItem,
+	callback func(io.Reader) error,
+) error {
+	var item block.BlockdataMapItem
+	switch m, found, err := sv.database.BlockdataMap(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("block data map, %d not found", height)
+	default:
+		item = itemf(m)
+	}
+
+	var r io.ReadCloser
+	if block.IsLocalBlockdataItem(item.URL()) {
+		i, err := network.FetchBlockdataThruChannel(sv.openBlockdata, item)
+		if err != nil {
+			return err
+		}
+		r = i
+	} else {
+		i, err := network.FetchBlockdataFromRemote(ctx, item)
+		if err != nil {
+			return err
+		}
+		r = i
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return callback(r)
+}
+
+func (sv *Server) openBlockdata(p string) (io.Reader, func() error, error) {
+	i, err := sv.blockdata.FS().Open(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, util.NotFoundError.Wrap(err)
+		}
+
+		return nil, nil, err
+	}
+
+	return i, i.Close, nil
+}
+
+func (sv *Server) writeJSON(w http.ResponseWriter, i interface{}) {
+	sv.writeJSONWithStatus(w, http.StatusOK, i)
+}
+
+func (sv *Server) writeJSONWithStatus(w http.ResponseWriter, status int, i interface{}) {
+	b, err := jsonenc.Marshal(i)
+	if err != nil {
+		sv.writeError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func (sv *Server) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, BadRequestError):
+		status = http.StatusBadRequest
+	case errors.Is(err, util.NotFoundError):
+		status = http.StatusNotFound
+	default:
+		sv.Log().Error().Err(err).Msg("failed to query")
+	}
+
+	network.WritePoblem(w, status, network.NewProblem(network.DefaultProblemType, err.Error()))
+}
+
+func parseHeight(r *http.Request) (base.Height, error) {
+	i, err := base.NewHeightFromString(mux.Vars(r)["height"])
+	if err != nil {
+		return base.NilHeight, BadRequestError.Wrap(err)
+	}
+
+	return i, nil
+}
+
+func parseHash(r *http.Request, name string) (valuehash.Hash, error) {
+	h := valuehash.NewBytesFromString(strings.TrimSpace(mux.Vars(r)[name]))
+	if err := h.IsValid(nil); err != nil {
+		return nil, BadRequestError.Wrap(err)
+	}
+
+	return h, nil
+}
+
+func parseStateKey(r *http.Request) (string, error) {
+	key := strings.TrimSpace(mux.Vars(r)["key"])
+	if len(key) < 1 {
+		return "", BadRequestError.Errorf("empty state key")
+	}
+
+	return key, nil
+}
+
+// parsePage parses the "offset" and "limit" query parameters. limit should be
+// between 1 and MaxLimit; if not set, DefaultLimit is used.
+func parsePage(r *http.Request) (int, int, error) {
+	q := r.URL.Query()
+
+	offset := 0
+	if s := strings.TrimSpace(q.Get("offset")); len(s) > 0 {
+		i, err := strconv.Atoi(s)
+		switch {
+		case err != nil:
+			return 0, 0, BadRequestError.Wrap(err)
+		case i < 0:
+			return 0, 0, BadRequestError.Errorf("negative offset, %d", i)
+		}
+		offset = i
+	}
+
+	limit := DefaultLimit
+	if s := strings.TrimSpace(q.Get("limit")); len(s) > 0 {
+		i, err := strconv.Atoi(s)
+		switch {
+		case err != nil:
+			return 0, 0, BadRequestError.Wrap(err)
+		case i < 1 || i > MaxLimit:
+			return 0, 0, BadRequestError.Errorf("limit out of range, 1 <= %d <= %d", i, MaxLimit)
+		}
+		limit = i
+	}
+
+	return offset, limit, nil
+}
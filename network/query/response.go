@@ -0,0 +1,100 @@
+package querynetwork
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+// OperationResponse has the operation with the height of block, which
+// contains the operation.
+type OperationResponse struct {
+	Height    base.Height         `json:"height"`
+	Operation operation.Operation `json:"operation"`
+}
+
+// OperationsResponse is the page of operations in block.
+type OperationsResponse struct {
+	Height     base.Height           `json:"height"`
+	Offset     int                   `json:"offset"`
+	Limit      int                   `json:"limit"`
+	Total      int                   `json:"total"`
+	Operations []operation.Operation `json:"operations"`
+}
+
+// StateHistoryResponse is the page of state history by descending height. If
+// Next is not nil, the next page can be requested with Next as height.
+type StateHistoryResponse struct {
+	Key    string        `json:"key"`
+	States []state.State `json:"states"`
+	Next   *base.Height  `json:"next,omitempty"`
+}
+
+type OperationStatus string
+
+const (
+	OperationStatusStaged   OperationStatus = "staged"
+	OperationStatusProposed OperationStatus = "proposed"
+	OperationStatusIncluded OperationStatus = "included"
+	OperationStatusRejected OperationStatus = "rejected"
+)
+
+// OperationReceipt is returned for each submitted operation. The current
+// status of operation can be requested thru Status.
+type OperationReceipt struct {
+	Fact   valuehash.Hash `json:"fact"`
+	Hash   valuehash.Hash `json:"hash"`
+	Status string         `json:"status"`
+}
+
+// OperationStatusResponse is the status of operation by fact hash.
+// - OperationStatusStaged: Proposal and Height are empty.
+// - OperationStatusProposed: Proposal is the fact hash of proposal, which
+// contains the operation.
+// - OperationStatusIncluded: Height is the height of block, which contains
+// the operation.
+// - OperationStatusRejected: Height is set like OperationStatusIncluded and
+// Reason is the reason of rejection. If the submitted operation is dropped
+// from the staged operations without inclusion, Height is empty.
+type OperationStatusResponse struct {
+	Fact     valuehash.Hash        `json:"fact"`
+	Status   OperationStatus       `json:"status"`
+	Proposal valuehash.Hash        `json:"proposal,omitempty"`
+	Height   *base.Height          `json:"height,omitempty"`
+	Reason   operation.ReasonError `json:"reason,omitempty"`
+}
+
+// OperationProofResponse has the proof of operation in the operations tree of
+// block. The proof can be checked by Verify with the manifest of block.
+type OperationProofResponse struct {
+	Height base.Height          `json:"height"`
+	Fact   valuehash.Hash       `json:"fact"`
+	Proof  []tree.FixedTreeNode `json:"proof"`
+}
+
+func (res OperationProofResponse) Verify(m block.Manifest) error {
+	if res.Height != m.Height() {
+		return tree.InvalidProofError.Errorf("height does not match, %d != %d", res.Height, m.Height())
+	}
+
+	return block.VerifyOperationProof(m, res.Fact, res.Proof)
+}
+
+// StateProofResponse has the state and it's proof in the states tree of block.
+// The proof can be checked by Verify with the manifest of block.
+type StateProofResponse struct {
+	Height base.Height          `json:"height"`
+	State  state.State          `json:"state"`
+	Proof  []tree.FixedTreeNode `json:"proof"`
+}
+
+func (res StateProofResponse) Verify(m block.Manifest) error {
+	if res.Height != m.Height() {
+		return tree.InvalidProofError.Errorf("height does not match, %d != %d", res.Height, m.Height())
+	}
+
+	return block.VerifyStateProof(m, res.State, res.Proof)
+}
@@ -0,0 +1,95 @@
+package querynetwork
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/state"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+type OperationProofResponseJSONUnpacker struct {
+	HT base.Height     `json:"height"`
+	FC valuehash.Bytes `json:"fact"`
+	PR json.RawMessage `json:"proof"`
+}
+
+func (res *OperationProofResponse) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ures OperationProofResponseJSONUnpacker
+	if err := jsonenc.Unmarshal(b, &ures); err != nil {
+		return err
+	}
+
+	pr, err := decodeProof(ures.PR, enc)
+	if err != nil {
+		return err
+	}
+
+	res.Height = ures.HT
+	res.Fact = ures.FC
+	res.Proof = pr
+
+	return nil
+}
+
+type StateProofResponseJSONUnpacker struct {
+	HT base.Height     `json:"height"`
+	ST json.RawMessage `json:"state"`
+	PR json.RawMessage `json:"proof"`
+}
+
+func (res *StateProofResponse) UnpackJSON(b []byte, enc *jsonenc.Encoder) error {
+	var ures StateProofResponseJSONUnpacker
+	if err := jsonenc.Unmarshal(b, &ures); err != nil {
+		return err
+	}
+
+	var st state.State
+	switch hinter, err := enc.Decode(ures.ST); {
+	case err != nil:
+		return err
+	case hinter == nil:
+	default:
+		i, ok := hinter.(state.State)
+		if !ok {
+			return errors.Errorf("not state.State, %T", hinter)
+		}
+		st = i
+	}
+
+	pr, err := decodeProof(ures.PR, enc)
+	if err != nil {
+		return err
+	}
+
+	res.Height = ures.HT
+	res.State = st
+	res.Proof = pr
+
+	return nil
+}
+
+func decodeProof(b []byte, enc *jsonenc.Encoder) ([]tree.FixedTreeNode, error) {
+	hinters, err := enc.DecodeSlice(b)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := make([]tree.FixedTreeNode, len(hinters))
+	for i := range hinters {
+		if hinters[i] == nil {
+			continue
+		}
+
+		j, ok := hinters[i].(tree.FixedTreeNode)
+		if !ok {
+			return nil, errors.Errorf("not tree.FixedTreeNode, %T", hinters[i])
+		}
+		pr[i] = j
+	}
+
+	return pr, nil
+}
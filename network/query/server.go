@@ -0,0 +1,156 @@
+package querynetwork
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/cache"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+var (
+	QueryPathLastBlock        = "/block/last"
+	QueryPathManifestByHeight = "/block/{height:[0-9]+}"
+	QueryPathManifestByHash   = "/block/hash/{hash}"
+	QueryPathOperations       = "/block/{height:[0-9]+}/operations"
+	QueryPathSuffrageInfo     = "/block/{height:[0-9]+}/suffrage"
+	QueryPathOperationProof   = "/block/{height:[0-9]+}/operation/{fact}/proof"
+	QueryPathStateProof       = "/block/{height:[0-9]+}/state/{key}/proof"
+	QueryPathOperation        = "/operation/{fact}"
+	QueryPathOperationStatus  = "/operation/{fact}/status"
+	QueryPathSubmitOperations = "/operations"
+	QueryPathState            = "/state/{key}"
+	QueryPathStateHistory     = "/state/{key}/history"
+	QueryPathEvents           = "/events"
+)
+
+var (
+	SubmittedOperationsCacheSize   = 1 << 16
+	SubmittedOperationsCacheExpire = time.Hour * 24
+)
+
+// SubmitOperationsHandler handles the operations submitted by client. If the
+// operations are not acceptable, it should return BadRequestError.
+type SubmitOperationsHandler func([]operation.Operation) error
+
+// Server serves the query API through plain http. The data comes from
+// storage.Database and blockdata.Blockdata. The operations can be submitted
+// only when SubmitOperationsHandler is set.
+type Server struct {
+	*logging.Logging
+	*util.ContextDaemon
+	bind             string
+	listener         net.Listener
+	enc              *jsonenc.Encoder
+	database         storage.Database
+	blockdata        blockdata.Blockdata
+	router           *mux.Router
+	submitOperations SubmitOperationsHandler
+	events           *eventBroker
+	// NOTE submitted keeps the facts of submitted operations to know the
+	// operation, which is dropped from staged operations without inclusion.
+	submitted *cache.GCache
+}
+
+func NewServer(bind string, enc *jsonenc.Encoder, db storage.Database, bd blockdata.Blockdata) *Server {
+	submitted, _ := cache.NewGCache("lru", SubmittedOperationsCacheSize, SubmittedOperationsCacheExpire)
+
+	sv := &Server{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "query-server")
+		}),
+		bind:      bind,
+		enc:       enc,
+		database:  db,
+		blockdata: bd,
+		router:    mux.NewRouter(),
+		events:    newEventBroker(),
+		submitted: submitted,
+	}
+
+	sv.ContextDaemon = util.NewContextDaemon("query-server", sv.run)
+
+	sv.setHandlers()
+
+	return sv
+}
+
+func (sv *Server) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = sv.ContextDaemon.SetLogging(l)
+
+	return sv.Logging.SetLogging(l)
+}
+
+func (sv *Server) SetSubmitOperationsHandler(f SubmitOperationsHandler) *Server {
+	sv.submitOperations = f
+
+	return sv
+}
+
+func (sv *Server) Handler() http.Handler {
+	return sv.router
+}
+
+func (sv *Server) Start() error {
+	listener, err := net.Listen("tcp", sv.bind)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open query server, %q", sv.bind)
+	}
+
+	sv.listener = listener
+
+	return sv.ContextDaemon.Start()
+}
+
+func (sv *Server) run(ctx context.Context) error {
+	server := &http.Server{Handler: sv.router, ReadHeaderTimeout: time.Second * 3}
+
+	errch := make(chan error, 1)
+	go func() {
+		if err := server.Serve(sv.listener); !errors.Is(err, http.ErrServerClosed) {
+			sv.Log().Error().Err(err).Msg("query server failed")
+
+			errch <- err
+		}
+	}()
+
+	sv.Log().Debug().Str("bind", sv.bind).Msg("query server started")
+
+	select {
+	case err := <-errch:
+		return err
+	case <-ctx.Done():
+		sv.events.close()
+
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		return server.Shutdown(sctx) // nolint:contextcheck
+	}
+}
+
+func (sv *Server) setHandlers() {
+	_ = sv.router.HandleFunc(QueryPathLastBlock, sv.handleLastBlock).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathManifestByHeight, sv.handleManifestByHeight).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathManifestByHash, sv.handleManifestByHash).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathOperations, sv.handleOperations).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathSuffrageInfo, sv.handleSuffrageInfo).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathOperationProof, sv.handleOperationProof).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathStateProof, sv.handleStateProof).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathOperation, sv.handleOperation).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathOperationStatus, sv.handleOperationStatus).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathSubmitOperations, sv.handleSubmitOperations).Methods("POST")
+	_ = sv.router.HandleFunc(QueryPathState, sv.handleState).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathStateHistory, sv.handleStateHistory).Methods("GET")
+	_ = sv.router.HandleFunc(QueryPathEvents, sv.handleEvents).Methods("GET")
+}
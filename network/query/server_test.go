@@ -0,0 +1,757 @@
+package querynetwork
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/ballot"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testServer struct {
+	isaac.BaseTest
+	local *isaac.Local
+	sv    *Server
+}
+
+func (t *testServer) SetupSuite() {
+	t.BaseTest.SetupSuite()
+
+	_ = t.Encs.TestAddHinter(network.ProblemHinter)
+	_ = t.Encs.TestAddHinter(operation.BaseReasonError{})
+}
+
+func (t *testServer) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	t.local = t.Locals(1)[0]
+	t.sv = NewServer("", t.JSONEnc, t.local.Database(), t.local.Blockdata())
+}
+
+func (t *testServer) newState(key string, height base.Height) state.State {
+	v, err := state.NewStringValue(height.String())
+	t.NoError(err)
+
+	st, err := state.NewStateV0(key, v, height)
+	t.NoError(err)
+
+	i, err := st.SetHash(st.GenerateHash())
+	t.NoError(err)
+
+	return i
+}
+
+// saveBlock stores the next block with the given operations and states.
+func (t *testServer) saveBlock(ops []operation.Operation, sts []state.State) block.Block {
+	return t.saveBlockWithReasons(ops, sts, nil)
+}
+
+// saveBlockWithReasons stores the next block like saveBlock, but the
+// operations, which have reason, are not in state.
+func (t *testServer) saveBlockWithReasons(
+	ops []operation.Operation,
+	sts []state.State,
+	reasons map[int]error,
+) block.Block {
+	m := t.LastManifest(t.local.Database())
+	_, prev, err := localfs.LoadBlock(t.local.Blockdata().(*localfs.Blockdata), m.Height())
+	t.NoError(err)
+
+	height := m.Height() + 1
+
+	otg := tree.NewFixedTreeGenerator(uint64(len(ops)))
+	for i := range ops {
+		reason := reasons[i]
+		t.NoError(otg.Add(operation.NewFixedTreeNode(uint64(i), ops[i].Fact().Hash().Bytes(), reason == nil, reason)))
+	}
+	opsTree, err := otg.Tree()
+	t.NoError(err)
+
+	stg := tree.NewFixedTreeGenerator(uint64(len(sts)))
+	for i := range sts {
+		t.NoError(stg.Add(state.NewFixedTreeNode(uint64(i), sts[i].Hash().Bytes())))
+	}
+	statesTree, err := stg.Tree()
+	t.NoError(err)
+
+	si := block.NewSuffrageInfoV0(t.local.Node().Address(), []base.Node{t.local.Node()})
+
+	var opsHash, statesHash valuehash.Hash
+	if opsTree.Len() > 0 {
+		opsHash = valuehash.NewBytes(opsTree.Root())
+	}
+
+	if statesTree.Len() > 0 {
+		statesHash = valuehash.NewBytes(statesTree.Root())
+	}
+
+	blk, err := block.NewBlockV0(
+		si, height, base.Round(0),
+		valuehash.RandomSHA256(), m.Hash(),
+		opsHash, statesHash,
+		localtime.UTCNow(),
+	)
+	t.NoError(err)
+
+	nodes := []base.Address{t.local.Node().Address()}
+
+	i := (interface{})(blk).(block.BlockUpdater)
+	i = i.SetINITVoteproof(base.NewVoteproofV0(height, base.Round(0), nodes, base.ThresholdRatio(100), base.StageINIT))
+	i = i.SetACCEPTVoteproof(base.NewVoteproofV0(height, base.Round(0), nodes, base.ThresholdRatio(100), base.StageACCEPT))
+	i = i.SetOperations(ops).SetOperationsTree(opsTree)
+	i = i.SetStates(sts).SetStatesTree(statesTree)
+	i = i.SetProposal(prev.ConsensusInfo().Proposal())
+
+	nblk := i.(block.Block)
+
+	bs, err := t.local.Database().NewSession(nblk)
+	t.NoError(err)
+	defer func() {
+		_ = bs.Close()
+	}()
+
+	session, err := t.local.Blockdata().NewSession(height)
+	t.NoError(err)
+	t.NoError(session.SetBlock(nblk))
+
+	bd, err := t.local.Blockdata().SaveSession(session)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), nblk))
+	t.NoError(bs.Commit(context.Background(), bd))
+
+	return nblk
+}
+
+func (t *testServer) request(p string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", p, nil)
+	w := httptest.NewRecorder()
+
+	t.sv.Handler().ServeHTTP(w, r)
+
+	return w
+}
+
+func (t *testServer) requestStatus(fact valuehash.Hash) (int, OperationStatusResponse) {
+	w := t.request("/operation/" + fact.String() + "/status")
+	if w.Code != http.StatusOK {
+		return w.Code, OperationStatusResponse{}
+	}
+
+	var res struct {
+		Fact     valuehash.Bytes `json:"fact"`
+		Status   OperationStatus `json:"status"`
+		Proposal valuehash.Bytes `json:"proposal"`
+		Height   *base.Height    `json:"height"`
+		Reason   json.RawMessage `json:"reason"`
+	}
+	t.NoError(json.Unmarshal(w.Body.Bytes(), &res))
+
+	ures := OperationStatusResponse{Fact: res.Fact, Status: res.Status, Height: res.Height}
+	if res.Proposal != nil {
+		ures.Proposal = res.Proposal
+	}
+
+	if len(res.Reason) > 0 {
+		hinter, err := t.JSONEnc.Decode(res.Reason)
+		t.NoError(err)
+		ures.Reason = hinter.(operation.ReasonError)
+	}
+
+	return w.Code, ures
+}
+
+func (t *testServer) TestLastBlock() {
+	m := t.LastManifest(t.local.Database())
+
+	w := t.request(QueryPathLastBlock)
+	t.Equal(http.StatusOK, w.Code)
+	t.Equal("application/json", w.Header().Get("Content-Type"))
+
+	hinter, err := t.JSONEnc.Decode(w.Body.Bytes())
+	t.NoError(err)
+
+	um, ok := hinter.(block.Manifest)
+	t.True(ok)
+	t.CompareManifest(m, um)
+}
+
+func (t *testServer) TestManifestByHeight() {
+	m, found, err := t.local.Database().ManifestByHeight(base.Height(1))
+	t.NoError(err)
+	t.True(found)
+
+	w := t.request("/block/1")
+	t.Equal(http.StatusOK, w.Code)
+
+	hinter, err := t.JSONEnc.Decode(w.Body.Bytes())
+	t.NoError(err)
+	t.CompareManifest(m, hinter.(block.Manifest))
+
+	{ // unknown height
+		w := t.request("/block/100")
+		t.Equal(http.StatusNotFound, w.Code)
+		t.Equal(network.ProblemMimetype, w.Header().Get("Content-Type"))
+	}
+}
+
+func (t *testServer) TestManifestByHash() {
+	m, found, err := t.local.Database().ManifestByHeight(base.Height(1))
+	t.NoError(err)
+	t.True(found)
+
+	w := t.request("/block/hash/" + m.Hash().String())
+	t.Equal(http.StatusOK, w.Code)
+
+	hinter, err := t.JSONEnc.Decode(w.Body.Bytes())
+	t.NoError(err)
+	t.CompareManifest(m, hinter.(block.Manifest))
+
+	{ // unknown hash
+		w := t.request("/block/hash/" + valuehash.RandomSHA256().String())
+		t.Equal(http.StatusNotFound, w.Code)
+	}
+}
+
+func (t *testServer) TestOperation() {
+	ops := t.NewOperations(t.local, 3)
+	blk := t.saveBlock(ops, nil)
+
+	w := t.request("/operation/" + ops[1].Fact().Hash().String())
+	t.Equal(http.StatusOK, w.Code)
+
+	var res struct {
+		Height    base.Height     `json:"height"`
+		Operation json.RawMessage `json:"operation"`
+	}
+	t.NoError(json.Unmarshal(w.Body.Bytes(), &res))
+	t.Equal(blk.Height(), res.Height)
+
+	hinter, err := t.JSONEnc.Decode(res.Operation)
+	t.NoError(err)
+
+	uop, ok := hinter.(operation.Operation)
+	t.True(ok)
+	t.True(ops[1].Hash().Equal(uop.Hash()))
+
+	{ // unknown fact
+		w := t.request("/operation/" + valuehash.RandomSHA256().String())
+		t.Equal(http.StatusNotFound, w.Code)
+	}
+}
+
+func (t *testServer) TestOperations() {
+	ops := t.NewOperations(t.local, 5)
+	blk := t.saveBlock(ops, nil)
+
+	load := func(offset, limit int) (int, []operation.Operation) {
+		q := url.Values{}
+		q.Set("offset", fmt.Sprintf("%d", offset))
+		q.Set("limit", fmt.Sprintf("%d", limit))
+
+		w := t.request(fmt.Sprintf("/block/%d/operations?%s", blk.Height(), q.Encode()))
+		t.Equal(http.StatusOK, w.Code)
+
+		var res struct {
+			Height     base.Height       `json:"height"`
+			Total      int               `json:"total"`
+			Operations []json.RawMessage `json:"operations"`
+		}
+		t.NoError(json.Unmarshal(w.Body.Bytes(), &res))
+		t.Equal(blk.Height(), res.Height)
+
+		uops := make([]operation.Operation, len(res.Operations))
+		for i := range res.Operations {
+			hinter, err := t.JSONEnc.Decode(res.Operations[i])
+			t.NoError(err)
+			uops[i] = hinter.(operation.Operation)
+		}
+
+		return res.Total, uops
+	}
+
+	total, uops := load(0, 2)
+	t.Equal(5, total)
+	t.Equal(2, len(uops))
+	t.True(ops[0].Hash().Equal(uops[0].Hash()))
+	t.True(ops[1].Hash().Equal(uops[1].Hash()))
+
+	_, uops = load(4, 2)
+	t.Equal(1, len(uops))
+	t.True(ops[4].Hash().Equal(uops[0].Hash()))
+
+	_, uops = load(5, 2)
+	t.Empty(uops)
+
+	{ // wrong limit
+		w := t.request(fmt.Sprintf("/block/%d/operations?limit=%d", blk.Height(), MaxLimit+1))
+		t.Equal(http.StatusBadRequest, w.Code)
+	}
+}
+
+func (t *testServer) TestSuffrageInfo() {
+	w := t.request("/block/1/suffrage")
+	t.Equal(http.StatusOK, w.Code)
+
+	hinter, err := t.JSONEnc.Decode(w.Body.Bytes())
+	t.NoError(err)
+
+	si, ok := hinter.(block.SuffrageInfo)
+	t.True(ok)
+	t.True(si.Proposer().Equal(t.local.Node().Address()))
+}
+
+func (t *testServer) TestState() {
+	key := util.UUID().String()
+
+	for i := 0; i < 3; i++ {
+		m := t.LastManifest(t.local.Database())
+		t.saveBlock(nil, []state.State{t.newState(key, m.Height()+1)})
+	}
+
+	m := t.LastManifest(t.local.Database())
+
+	w := t.request("/state/" + key)
+	t.Equal(http.StatusOK, w.Code)
+
+	hinter, err := t.JSONEnc.Decode(w.Body.Bytes())
+	t.NoError(err)
+
+	st, ok := hinter.(state.State)
+	t.True(ok)
+	t.Equal(key, st.Key())
+	t.Equal(m.Height(), st.Height())
+
+	{ // unknown key
+		w := t.request("/state/" + util.UUID().String())
+		t.Equal(http.StatusNotFound, w.Code)
+	}
+
+	load := func(p string) ([]base.Height, *base.Height) {
+		w := t.request(p)
+		t.Equal(http.StatusOK, w.Code)
+
+		var res struct {
+			Key    string            `json:"key"`
+			States []json.RawMessage `json:"states"`
+			Next   *base.Height      `json:"next"`
+		}
+		t.NoError(json.Unmarshal(w.Body.Bytes(), &res))
+		t.Equal(key, res.Key)
+
+		heights := make([]base.Height, len(res.States))
+		for i := range res.States {
+			hinter, err := t.JSONEnc.Decode(res.States[i])
+			t.NoError(err)
+			heights[i] = hinter.(state.State).Height()
+		}
+
+		return heights, res.Next
+	}
+
+	heights, next := load(fmt.Sprintf("/state/%s/history?limit=2", key))
+	t.Equal([]base.Height{m.Height(), m.Height() - 1}, heights)
+	t.NotNil(next)
+	t.Equal(m.Height()-1, *next)
+
+	heights, next = load(fmt.Sprintf("/state/%s/history?limit=2&height=%d", key, *next))
+	t.Equal([]base.Height{m.Height() - 2}, heights)
+	t.Nil(next)
+}
+
+func (t *testServer) TestSubmitOperations() {
+	ops := t.NewOperations(t.local, 2)
+
+	b, err := jsonenc.Marshal(ops)
+	t.NoError(err)
+
+	{ // without handler
+		w := httptest.NewRecorder()
+		t.sv.Handler().ServeHTTP(w, httptest.NewRequest("POST", QueryPathSubmitOperations, bytes.NewReader(b)))
+		t.Equal(http.StatusServiceUnavailable, w.Code)
+	}
+
+	var submitted []operation.Operation
+	_ = t.sv.SetSubmitOperationsHandler(func(ops []operation.Operation) error {
+		for i := range ops {
+			if err := ops[i].IsValid(isaac.TestNetworkID); err != nil {
+				return BadRequestError.Wrap(err)
+			}
+		}
+
+		submitted = ops
+
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	t.sv.Handler().ServeHTTP(w, httptest.NewRequest("POST", QueryPathSubmitOperations, bytes.NewReader(b)))
+	t.Equal(http.StatusAccepted, w.Code)
+	t.Equal("application/json", w.Header().Get("Content-Type"))
+
+	var receipts []struct {
+		Fact   valuehash.Bytes `json:"fact"`
+		Hash   valuehash.Bytes `json:"hash"`
+		Status string          `json:"status"`
+	}
+	t.NoError(json.Unmarshal(w.Body.Bytes(), &receipts))
+	t.Equal(len(ops), len(receipts))
+	t.Equal(len(ops), len(submitted))
+
+	for i := range ops {
+		t.True(ops[i].Hash().Equal(submitted[i].Hash()))
+		t.True(ops[i].Fact().Hash().Equal(receipts[i].Fact))
+		t.True(ops[i].Hash().Equal(receipts[i].Hash))
+		t.Equal("/operation/"+ops[i].Fact().Hash().String()+"/status", receipts[i].Status)
+	}
+
+	{ // wrong body
+		w := httptest.NewRecorder()
+		t.sv.Handler().ServeHTTP(w, httptest.NewRequest("POST", QueryPathSubmitOperations, bytes.NewReader([]byte("[]"))))
+		t.Equal(http.StatusBadRequest, w.Code)
+	}
+
+	{ // invalid operation
+		b, err := jsonenc.Marshal(t.NewOperations(t.local, 1))
+		t.NoError(err)
+
+		_ = t.sv.SetSubmitOperationsHandler(func([]operation.Operation) error {
+			return BadRequestError.Errorf("invalid")
+		})
+
+		w := httptest.NewRecorder()
+		t.sv.Handler().ServeHTTP(w, httptest.NewRequest("POST", QueryPathSubmitOperations, bytes.NewReader(b)))
+		t.Equal(http.StatusBadRequest, w.Code)
+		t.Equal(network.ProblemMimetype, w.Header().Get("Content-Type"))
+	}
+}
+
+func (t *testServer) TestOperationStatus() {
+	sl, ops := t.NewOperationSeal(t.local, 3)
+	t.NoError(t.local.Database().NewOperationSeals([]operation.Seal{sl}))
+
+	{ // staged
+		code, res := t.requestStatus(ops[0].Fact().Hash())
+		t.Equal(http.StatusOK, code)
+		t.Equal(OperationStatusStaged, res.Status)
+		t.Nil(res.Height)
+		t.Nil(res.Proposal)
+	}
+
+	{ // proposed
+		pr := t.NewProposal(t.local, base.Round(0), []valuehash.Hash{ops[1].Fact().Hash()}, nil)
+		t.NoError(t.local.Database().NewProposal(pr))
+
+		code, res := t.requestStatus(ops[1].Fact().Hash())
+		t.Equal(http.StatusOK, code)
+		t.Equal(OperationStatusProposed, res.Status)
+		t.True(pr.Fact().Hash().Equal(res.Proposal))
+	}
+
+	blk := t.saveBlockWithReasons(ops[1:], nil, map[int]error{
+		1: operation.NewBaseReasonError("showme"),
+	})
+
+	{ // included
+		code, res := t.requestStatus(ops[1].Fact().Hash())
+		t.Equal(http.StatusOK, code)
+		t.Equal(OperationStatusIncluded, res.Status)
+		t.NotNil(res.Height)
+		t.Equal(blk.Height(), *res.Height)
+		t.Nil(res.Reason)
+	}
+
+	{ // rejected
+		code, res := t.requestStatus(ops[2].Fact().Hash())
+		t.Equal(http.StatusOK, code)
+		t.Equal(OperationStatusRejected, res.Status)
+		t.Equal(blk.Height(), *res.Height)
+		t.NotNil(res.Reason)
+		t.Contains(res.Reason.Msg(), "showme")
+	}
+
+	{ // NOTE proposal under last block is ignored
+		pr, err := ballot.NewProposal(
+			ballot.NewProposalFact(blk.Height(), base.Round(1), t.local.Node().Address(), []valuehash.Hash{ops[0].Fact().Hash()}),
+			t.local.Node().Address(),
+			nil,
+			t.local.Node().Privatekey(), t.local.Policy().NetworkID(),
+		)
+		t.NoError(err)
+		t.NoError(t.local.Database().NewProposal(pr))
+
+		code, res := t.requestStatus(ops[0].Fact().Hash())
+		t.Equal(http.StatusOK, code)
+		t.Equal(OperationStatusStaged, res.Status)
+		t.Nil(res.Proposal)
+	}
+
+	{ // dropped from staged operations
+		_ = t.sv.SetSubmitOperationsHandler(func([]operation.Operation) error {
+			return nil
+		})
+
+		dropped := t.NewOperations(t.local, 1)
+		b, err := jsonenc.Marshal(dropped)
+		t.NoError(err)
+
+		w := httptest.NewRecorder()
+		t.sv.Handler().ServeHTTP(w, httptest.NewRequest("POST", QueryPathSubmitOperations, bytes.NewReader(b)))
+		t.Equal(http.StatusAccepted, w.Code)
+
+		code, res := t.requestStatus(dropped[0].Fact().Hash())
+		t.Equal(http.StatusOK, code)
+		t.Equal(OperationStatusRejected, res.Status)
+		t.Nil(res.Height)
+		t.NotNil(res.Reason)
+		t.Contains(res.Reason.Msg(), "dropped")
+	}
+
+	{ // unknown
+		code, _ := t.requestStatus(valuehash.RandomSHA256())
+		t.Equal(http.StatusNotFound, code)
+	}
+}
+
+type testEvent struct {
+	id    string
+	event string
+	data  []byte
+}
+
+func (t *testServer) readEvent(r *bufio.Reader) testEvent {
+	var ev testEvent
+	for {
+		line, err := r.ReadString('\n')
+		t.NoError(err)
+
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case len(line) < 1:
+			if len(ev.event) < 1 {
+				continue
+			}
+
+			return ev
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = []byte(strings.TrimPrefix(line, "data: "))
+		}
+	}
+}
+
+func (t *testServer) TestEvents() {
+	ops := t.NewOperations(t.local, 2)
+
+	m := t.LastManifest(t.local.Database())
+	st := t.newState(util.UUID().String(), m.Height()+1).SetOperation([]valuehash.Hash{ops[0].Fact().Hash()})
+	st, err := st.SetHash(st.GenerateHash())
+	t.NoError(err)
+
+	blk := t.saveBlockWithReasons(ops, []state.State{st}, map[int]error{
+		1: operation.NewBaseReasonError("showme"),
+	})
+
+	ts := httptest.NewServer(t.sv.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s?height=%d", ts.URL, QueryPathEvents, blk.Height()), nil)
+	t.NoError(err)
+
+	res, err := ts.Client().Do(req)
+	t.NoError(err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	t.Equal(http.StatusOK, res.StatusCode)
+	t.Equal("text/event-stream", res.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(res.Body)
+
+	readOperation := func() (string, bool, []string, bool) {
+		ev := t.readEvent(r)
+		t.Equal(string(EventTypeOperation), ev.event)
+		t.Empty(ev.id)
+
+		var uev struct {
+			Fact    valuehash.Bytes `json:"fact"`
+			InState bool            `json:"in_state"`
+			Reason  json.RawMessage `json:"reason"`
+			States  []string        `json:"states"`
+		}
+		t.NoError(json.Unmarshal(ev.data, &uev))
+
+		return uev.Fact.String(), uev.InState, uev.States, len(uev.Reason) > 0
+	}
+
+	readBlock := func(height base.Height) {
+		ev := t.readEvent(r)
+		t.Equal(string(EventTypeBlock), ev.event)
+		t.Equal(height.String(), ev.id)
+
+		var uev struct {
+			Manifest json.RawMessage `json:"manifest"`
+		}
+		t.NoError(json.Unmarshal(ev.data, &uev))
+
+		hinter, err := t.JSONEnc.Decode(uev.Manifest)
+		t.NoError(err)
+		t.Equal(height, hinter.(block.Manifest).Height())
+	}
+
+	// NOTE replayed from stored block
+	fact, inState, keys, hasReason := readOperation()
+	t.Equal(ops[0].Fact().Hash().String(), fact)
+	t.True(inState)
+	t.Equal([]string{st.Key()}, keys)
+	t.False(hasReason)
+
+	fact, inState, keys, hasReason = readOperation()
+	t.Equal(ops[1].Fact().Hash().String(), fact)
+	t.False(inState)
+	t.Empty(keys)
+	t.True(hasReason)
+
+	readBlock(blk.Height())
+
+	// NOTE already sent block is ignored
+	t.NoError(t.sv.PublishBlocks([]block.Block{blk}))
+
+	t.sv.PublishConsensusState(base.StateSyncing, base.StateConsensus)
+
+	ev := t.readEvent(r)
+	t.Equal(string(EventTypeConsensusState), ev.event)
+
+	var sev ConsensusStateEvent
+	t.NoError(json.Unmarshal(ev.data, &sev))
+	t.Equal(base.StateSyncing, sev.From)
+	t.Equal(base.StateConsensus, sev.To)
+
+	nblk := t.saveBlock(nil, nil)
+	t.NoError(t.sv.PublishBlocks([]block.Block{nblk}))
+
+	readBlock(nblk.Height())
+}
+
+func (t *testServer) TestProof() {
+	ops := t.NewOperations(t.local, 5)
+
+	m := t.LastManifest(t.local.Database())
+	sts := make([]state.State, 3)
+	for i := range sts {
+		sts[i] = t.newState(util.UUID().String(), m.Height()+1)
+	}
+
+	blk := t.saveBlock(ops, sts)
+
+	ts := httptest.NewServer(t.sv.Handler())
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	t.NoError(err)
+
+	cl := NewClient(u, t.JSONEnc, ts.Client())
+
+	um, err := cl.Manifest(context.Background(), blk.Height())
+	t.NoError(err)
+	t.CompareManifest(blk.Manifest(), um)
+
+	for i := range ops {
+		res, err := cl.OperationProof(context.Background(), blk.Height(), ops[i].Fact().Hash())
+		t.NoError(err)
+		t.NoError(res.Verify(um))
+
+		t.NoError(cl.VerifyOperation(context.Background(), t.local.Policy().NetworkID(), blk.Height(), blk.Hash(), ops[i].Fact().Hash()))
+	}
+
+	for i := range sts {
+		res, err := cl.StateProof(context.Background(), blk.Height(), sts[i].Key())
+		t.NoError(err)
+		t.Equal(sts[i].Key(), res.State.Key())
+		t.NoError(res.Verify(um))
+
+		st, err := cl.VerifyState(context.Background(), t.local.Policy().NetworkID(), blk.Height(), blk.Hash(), sts[i].Key())
+		t.NoError(err)
+		t.True(sts[i].Hash().Equal(st.Hash()))
+	}
+
+	{ // untrusted block hash
+		err := cl.VerifyOperation(context.Background(), t.local.Policy().NetworkID(), blk.Height(), valuehash.RandomSHA256(), ops[0].Fact().Hash())
+		t.True(errors.Is(err, isvalid.InvalidError))
+		t.Contains(err.Error(), "hash does not match")
+
+		_, err = cl.VerifyState(context.Background(), t.local.Policy().NetworkID(), blk.Height(), valuehash.RandomSHA256(), sts[0].Key())
+		t.True(errors.Is(err, isvalid.InvalidError))
+	}
+
+	{ // proof with other block
+		res, err := cl.OperationProof(context.Background(), blk.Height(), ops[0].Fact().Hash())
+		t.NoError(err)
+
+		nblk := t.saveBlock(t.NewOperations(t.local, 2), nil)
+		res.Height = nblk.Height()
+
+		err = res.Verify(nblk.Manifest())
+		t.True(errors.Is(err, tree.InvalidProofError))
+	}
+
+	{ // wrong state value
+		res, err := cl.StateProof(context.Background(), blk.Height(), sts[0].Key())
+		t.NoError(err)
+
+		v, err := state.NewStringValue(util.UUID().String())
+		t.NoError(err)
+
+		st, err := res.State.SetValue(v)
+		t.NoError(err)
+		st, err = st.SetHash(st.GenerateHash())
+		t.NoError(err)
+		res.State = st
+
+		err = res.Verify(um)
+		t.True(errors.Is(err, tree.InvalidProofError))
+	}
+
+	{ // unknown operation
+		_, err := cl.OperationProof(context.Background(), blk.Height(), valuehash.RandomSHA256())
+		t.True(errors.Is(err, util.NotFoundError))
+	}
+
+	{ // unknown state
+		_, err := cl.StateProof(context.Background(), blk.Height(), util.UUID().String())
+		t.True(errors.Is(err, util.NotFoundError))
+	}
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(testServer))
+}
@@ -1,12 +1,16 @@
 package quicnetwork
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +21,7 @@ import (
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/seal"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
@@ -26,18 +31,38 @@ import (
 	"github.com/spikeekips/mitum/util/valuehash"
 )
 
+// ChannelConfig is the optional settings of Channel to the other nodes.
+type ChannelConfig struct {
+	// Certificates are the client certificates for the mutual TLS.
+	Certificates []tls.Certificate
+	// VerifyServer verifies the server certificate instead of the system
+	// roots.
+	VerifyServer ServerCertificateVerifier
+	// WireEncoder is the encoder for seal and the preferred encoder for the
+	// responses. If nil, the default encoder is used. bson is slower than json
+	// without saving bytes, see BenchmarkWireEncodingConsensusRound.
+	WireEncoder encoder.Encoder
+	// CompactWire sends seal and requests the responses in WireFormatCompact.
+	CompactWire bool
+}
+
 type Channel struct {
 	*logging.Logging
 	recvChan               chan seal.Seal
 	connInfo               network.ConnInfo
 	encs                   *encoder.Encoders
 	enc                    encoder.Encoder
+	wireEnc                *util.LockedItem
+	compactWire            *util.LockedItem
 	sendSealURL            string
 	getStagedOperationsURL string
 	getProposalURL         url.URL
 	nodeInfoURL            string
 	getBlockdataMaps       string
 	getBlockdata           url.URL
+	getBlockdataByChecksum url.URL
+	getSnapshot            url.URL
+	getState               url.URL
 	startHandover          string
 	pingHandover           string
 	endHandover            string
@@ -55,10 +80,12 @@ func NewChannel(
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
 			return c.Str("module", "quic-network-channel")
 		}),
-		recvChan: make(chan seal.Seal, bufsize),
-		connInfo: connInfo,
-		encs:     encs,
-		enc:      enc,
+		recvChan:    make(chan seal.Seal, bufsize),
+		connInfo:    connInfo,
+		encs:        encs,
+		enc:         enc,
+		wireEnc:     util.NewLockedItem(enc),
+		compactWire: util.NewLockedItem(false),
 	}
 
 	addr := connInfo.URL().String()
@@ -74,6 +101,18 @@ func NewChannel(
 		_, u := mustQuicURL(addr, QuicHandlerPathGetBlockdata)
 		ch.getBlockdata = *u
 	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetBlockdataChecksum)
+		ch.getBlockdataByChecksum = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetSnapshot)
+		ch.getSnapshot = *u
+	}
+	{
+		_, u := mustQuicURL(addr, QuicHandlerPathGetState)
+		ch.getState = *u
+	}
 	ch.startHandover, _ = mustQuicURL(addr, QuicHandlerPathStartHandoverPattern)
 	ch.pingHandover, _ = mustQuicURL(addr, QuicHandlerPathPingHandoverPattern)
 	ch.endHandover, _ = mustQuicURL(addr, QuicHandlerPathEndHandoverPattern)
@@ -97,6 +136,39 @@ func (ch *Channel) SetLogging(l *logging.Logging) *logging.Logging {
 	return ch.Logging.SetLogging(l)
 }
 
+// SetConfig applies ChannelConfig. It should be called before the requests.
+func (ch *Channel) SetConfig(conf ChannelConfig) {
+	ch.client.SetTLS(conf.Certificates, conf.VerifyServer)
+
+	if conf.WireEncoder != nil {
+		ch.SetWireEncoder(conf.WireEncoder)
+	}
+
+	ch.SetCompactWire(conf.CompactWire)
+}
+
+// SetWireEncoder sets the encoder for seal and the preferred encoder for the
+// responses of operations, block data maps and proposal. If the remote does
+// not support it, the default encoder is used.
+func (ch *Channel) SetWireEncoder(enc encoder.Encoder) {
+	_ = ch.wireEnc.Set(enc)
+}
+
+func (ch *Channel) WireEncoder() encoder.Encoder {
+	return ch.wireEnc.Value().(encoder.Encoder)
+}
+
+// SetCompactWire sets whether seal is sent and the responses of operations,
+// block data maps and proposal are requested in WireFormatCompact. If the
+// remote does not support it, seal is sent as it is.
+func (ch *Channel) SetCompactWire(b bool) {
+	_ = ch.compactWire.Set(b)
+}
+
+func (ch *Channel) CompactWire() bool {
+	return ch.compactWire.Value().(bool)
+}
+
 func (ch *Channel) ConnInfo() network.ConnInfo {
 	return ch.connInfo
 }
@@ -148,21 +220,28 @@ func (ch *Channel) SendSeal(ctx context.Context, ci network.ConnInfo, sl seal.Se
 	ctx, cancel := ch.timeoutContext(ctx, timeout)
 	defer cancel()
 
-	b, err := ch.enc.Marshal(sl)
+	enc := ch.WireEncoder()
+	compact := ch.CompactWire()
+
+	res, err := ch.sendSeal(ctx, timeout*2, enc, compact, ci, sl)
 	if err != nil {
 		return err
 	}
 
-	headers := http.Header{}
-	headers.Set(QuicEncoderHintHeader, ch.enc.Hint().String())
-	if ci != nil {
-		headers.Set(SendSealFromConnInfoHeader, ci.String())
-	}
+	if res.StatusCode == http.StatusUnsupportedMediaType && (compact || !enc.Hint().Equal(ch.enc.Hint())) {
+		_ = res.Close()
 
-	res, err := ch.client.Send(ctx, timeout*2, ch.sendSealURL, b, headers)
-	if err != nil {
-		return err
+		l.Debug().Stringer("encoder", enc.Hint()).Bool("compact", compact).
+			Msg("wire encoder or wire format not supported by remote; use default encoder")
+
+		ch.SetWireEncoder(ch.enc)
+		ch.SetCompactWire(false)
+
+		if res, err = ch.sendSeal(ctx, timeout*2, ch.enc, false, ci, sl); err != nil {
+			return err
+		}
 	}
+
 	defer func() {
 		_ = res.Close()
 
@@ -172,6 +251,31 @@ func (ch *Channel) SendSeal(ctx context.Context, ci network.ConnInfo, sl seal.Se
 	return nil
 }
 
+func (ch *Channel) sendSeal(
+	ctx context.Context,
+	timeout time.Duration,
+	enc encoder.Encoder,
+	compact bool,
+	ci network.ConnInfo,
+	sl seal.Seal,
+) (*QuicResponse, error) {
+	b, err := marshalWire(enc, compact, sl)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	headers.Set(QuicEncoderHintHeader, enc.Hint().String())
+	if compact {
+		headers.Set(QuicWireFormatHeader, WireFormatCompact)
+	}
+	if ci != nil {
+		headers.Set(SendSealFromConnInfoHeader, ci.String())
+	}
+
+	return ch.client.Send(ctx, timeout, ch.sendSealURL, b, headers)
+}
+
 func (ch *Channel) Proposal(ctx context.Context, h valuehash.Hash) (base.Proposal, error) {
 	ctx, cancel := ch.timeoutContext(ctx, network.ChannelTimeoutSeal)
 	defer cancel()
@@ -180,6 +284,7 @@ func (ch *Channel) Proposal(ctx context.Context, h valuehash.Hash) (base.Proposa
 
 	headers := http.Header{}
 	headers.Set(QuicEncoderHintHeader, ch.enc.Hint().String())
+	ch.setAcceptWireHeaders(headers)
 
 	u := ch.getProposalURL
 	u.Path = u.Path + "/" + h.String()
@@ -204,6 +309,11 @@ func (ch *Channel) Proposal(ctx context.Context, h valuehash.Hash) (base.Proposa
 		return nil, err
 	}
 
+	compact, err := IsCompactWireFromHeader(response.Header)
+	if err != nil {
+		return nil, err
+	}
+
 	b, err := response.Bytes()
 	if err != nil {
 		ch.Log().Error().Err(err).Msg("failed to get bytes from response body")
@@ -212,10 +322,117 @@ func (ch *Channel) Proposal(ctx context.Context, h valuehash.Hash) (base.Proposa
 	}
 
 	var pr base.Proposal
-	err = encoder.Decode(b, enc, &pr)
+	err = decodeWire(enc, compact, b, &pr)
 	return pr, err
 }
 
+func (ch *Channel) Snapshot(ctx context.Context, height base.Height) (block.SnapshotHeader, error) {
+	ctx, cancel := ch.timeoutContext(ctx, network.ChannelTimeoutSnapshot)
+	defer cancel()
+
+	ch.Log().Trace().Int64("height", height.Int64()).Msg("request snapshot")
+
+	headers := http.Header{}
+	headers.Set(QuicEncoderHintHeader, ch.enc.Hint().String())
+
+	u := ch.getSnapshot
+	u.Path = u.Path + "/" + height.String()
+
+	response, err := ch.client.Get(ctx, network.ChannelTimeoutSnapshot, u.String(), nil, headers)
+	defer func() {
+		if response == nil {
+			return
+		}
+
+		_ = response.Close()
+	}()
+
+	if err != nil {
+		return nil, err
+	} else if err = response.Error(); err != nil {
+		return nil, err
+	}
+
+	enc, err := EncoderFromHeader(response.Header, ch.encs, ch.enc)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := response.Bytes()
+	if err != nil {
+		ch.Log().Error().Err(err).Msg("failed to get bytes from response body")
+
+		return nil, err
+	}
+
+	var sh block.SnapshotHeader
+	if err := encoder.Decode(b, enc, &sh); err != nil {
+		return nil, err
+	}
+
+	if err := sh.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	return sh, nil
+}
+
+func (ch *Channel) State(ctx context.Context, key string, height base.Height) (state.State, bool, error) {
+	ctx, cancel := ch.timeoutContext(ctx, network.ChannelTimeoutState)
+	defer cancel()
+
+	ch.Log().Trace().Str("key", key).Int64("height", height.Int64()).Msg("request state")
+
+	headers := http.Header{}
+	headers.Set(QuicEncoderHintHeader, ch.enc.Hint().String())
+
+	u := ch.getState
+	u.Path = u.Path + "/" + height.String()
+	u.RawQuery = url.Values{"key": []string{key}}.Encode()
+
+	response, err := ch.client.Get(ctx, network.ChannelTimeoutState, u.String(), nil, headers)
+	defer func() {
+		if response == nil {
+			return
+		}
+
+		_ = response.Close()
+	}()
+
+	if err != nil {
+		return nil, false, err
+	} else if err = response.Error(); err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	enc, err := EncoderFromHeader(response.Header, ch.encs, ch.enc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b, err := response.Bytes()
+	if err != nil {
+		ch.Log().Error().Err(err).Msg("failed to get bytes from response body")
+
+		return nil, false, err
+	}
+
+	var st state.State
+	if err := encoder.Decode(b, enc, &st); err != nil {
+		return nil, false, err
+	}
+
+	if err := st.IsValid(nil); err != nil {
+		return nil, false, err
+	}
+
+	return st, true, nil
+}
+
 func (ch *Channel) NodeInfo(ctx context.Context) (network.NodeInfo, error) {
 	timeout := network.ChannelTimeoutNodeInfo
 	ctx, cancel := ch.timeoutContext(ctx, timeout)
@@ -309,6 +526,58 @@ func (ch *Channel) Blockdata(ctx context.Context, item block.BlockdataMapItem) (
 	)
 }
 
+func (ch *Channel) BlockdataRange(
+	ctx context.Context,
+	height base.Height,
+	item block.BlockdataMapItem,
+	offset, length int64,
+) (io.ReadCloser, int64, error) {
+	ctx, cancel := ch.timeoutContext(ctx, network.ChannelTimeoutBlockdata)
+	defer cancel()
+
+	if offset < 0 {
+		return nil, 0, errors.Errorf("invalid offset, %d", offset)
+	}
+
+	headers := http.Header{}
+	if length > 0 {
+		headers.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else {
+		headers.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	u := ch.getBlockdataByChecksum
+	u.Path = path.Join(u.Path, height.String(), url.PathEscape(item.Type()), url.PathEscape(item.Checksum()))
+
+	response, err := ch.client.Get(ctx, network.ChannelTimeoutBlockdata, u.String(), nil, headers)
+	defer func() {
+		if response == nil {
+			return
+		}
+
+		_ = response.Close()
+	}()
+
+	if err != nil {
+		return nil, 0, err
+	} else if err = response.Error(); err != nil {
+		return nil, 0, err
+	}
+
+	size, err := parseBlockdataRangeSize(response.Response)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// NOTE the part is read in the timeout of request.
+	b, err := response.Bytes()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), size, nil
+}
+
 func (ch *Channel) StartHandover(ctx context.Context, sl network.StartHandoverSeal) (bool, error) {
 	return ch.sendHandoverSeal(ctx, ch.startHandover, sl)
 }
@@ -362,6 +631,7 @@ func (ch *Channel) doRequestHinters(
 
 	headers := http.Header{}
 	headers.Set(QuicEncoderHintHeader, ch.enc.Hint().String())
+	ch.setAcceptWireHeaders(headers)
 
 	response, err := f(ctx, timeout, u, b, headers)
 	defer func() {
@@ -383,28 +653,37 @@ func (ch *Channel) doRequestHinters(
 		return nil, err
 	}
 
-	var ss []json.RawMessage
-	if b, err := response.Bytes(); err != nil {
+	compact, err := IsCompactWireFromHeader(response.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err = response.Bytes()
+	if err != nil {
 		ch.Log().Error().Err(err).Msg("failed to get bytes from response body")
 
-		return nil, err
-	} else if err := enc.Unmarshal(b, &ss); err != nil {
-		ch.Log().Error().Err(err).Msg("failed to unmarshal manifest slice")
 		return nil, err
 	}
 
-	hinters := make([]hint.Hinter, len(ss))
-	for i := range ss {
-		hinter, err := enc.Decode(ss[i])
-		if err != nil {
-			return nil, err
-		}
-		hinters[i] = hinter
+	hinters, err := unmarshalWireHinters(enc, compact, b)
+	if err != nil {
+		ch.Log().Error().Err(err).Msg("failed to unmarshal hinters")
+
+		return nil, err
 	}
 
 	return hinters, nil
 }
 
+// setAcceptWireHeaders sets the preferred encoder and wire format for the
+// response.
+func (ch *Channel) setAcceptWireHeaders(headers http.Header) {
+	headers.Set(QuicAcceptEncoderHintHeader, ch.WireEncoder().Hint().String())
+	if ch.CompactWire() {
+		headers.Set(QuicAcceptWireFormatHeader, WireFormatCompact)
+	}
+}
+
 func (*Channel) timeoutContext(ctx context.Context, timeout time.Duration) (context.Context, func()) {
 	switch {
 	case ctx != context.TODO():
@@ -479,3 +758,30 @@ func stripSlashFilePath(p string) string {
 
 	return string(b)
 }
+
+// parseBlockdataRangeSize returns the total size of block data item from the
+// response of range request.
+func parseBlockdataRangeSize(res *http.Response) (int64, error) {
+	if res.StatusCode != http.StatusPartialContent {
+		if res.ContentLength < 0 {
+			return 0, errors.Errorf("unknown size of block data")
+		}
+
+		return res.ContentLength, nil
+	}
+
+	// NOTE Content-Range: bytes <start>-<end>/<size>
+	cr := res.Header.Get("Content-Range")
+
+	i := strings.LastIndex(cr, "/")
+	if i < 0 || !strings.HasPrefix(cr, "bytes ") {
+		return 0, errors.Errorf("invalid Content-Range, %q", cr)
+	}
+
+	size, err := strconv.ParseInt(cr[i+1:], 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid Content-Range, %q", cr)
+	}
+
+	return size, nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"io"
 	"net/http"
 	"strings"
@@ -18,12 +19,19 @@ import (
 	"github.com/spikeekips/mitum/util/logging"
 )
 
+// ServerCertificateVerifier verifies the certificate of server.
+type ServerCertificateVerifier func(*x509.Certificate) error
+
 type clientDoRequestFunc func(context.Context, time.Duration, string, []byte, http.Header) (*QuicResponse, error)
 
 type QuicClient struct {
 	*logging.Logging
-	insecure   bool
-	quicConfig *quic.Config
+	insecure     bool
+	quicConfig   *quic.Config
+	certs        []tls.Certificate
+	verifyServer ServerCertificateVerifier
+	tcpOnce      sync.Once
+	tcpTransport *http.Transport
 }
 
 func NewQuicClient(insecure bool, quicConfig *quic.Config) (*QuicClient, error) {
@@ -48,6 +56,42 @@ func NewQuicClient(insecure bool, quicConfig *quic.Config) (*QuicClient, error)
 	}, nil
 }
 
+// SetTLS sets the client certificates for the mutual TLS and the verifier of
+// server certificate. If verify is not nil, the server certificate is verified
+// by verify instead of the system roots. It should be called before the
+// requests.
+func (cl *QuicClient) SetTLS(certs []tls.Certificate, verify ServerCertificateVerifier) {
+	cl.certs = certs
+	cl.verifyServer = verify
+}
+
+func (cl *QuicClient) tlsConfig() *tls.Config {
+	c := &tls.Config{
+		InsecureSkipVerify: cl.insecure, // nolint
+		MinVersion:         tls.VersionTLS13,
+		Certificates:       cl.certs,
+	}
+
+	if cl.verifyServer != nil {
+		// NOTE the server certificate is verified by VerifyPeerCertificate
+		c.InsecureSkipVerify = true // nolint
+		c.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) < 1 {
+				return errors.Errorf("empty server certificate")
+			}
+
+			cert, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				return errors.Wrap(err, "failed to parse server certificate")
+			}
+
+			return cl.verifyServer(cert)
+		}
+	}
+
+	return c
+}
+
 func (cl *QuicClient) Get(
 	ctx context.Context, timeout time.Duration,
 	url string, b []byte, headers http.Header,
@@ -86,7 +130,15 @@ func (cl *QuicClient) Request(
 	b []byte,
 	headers http.Header,
 ) (*http.Response, func() error, error) {
-	client, closefunc := cl.newClient(timeout)
+	var client *http.Client
+	var closefunc func() error
+
+	if i, isTCP := tcpRequestURL(url); isTCP {
+		url = i
+		client, closefunc = cl.newTCPClient()
+	} else {
+		client, closefunc = cl.newClient(timeout)
+	}
 
 	i, err := cl.makeRequest(url, method, b, headers)
 	if err != nil {
@@ -142,9 +194,7 @@ func (cl *QuicClient) newClient(maxIdleTimeout time.Duration) (*http.Client, fun
 	}
 
 	r := RoundTripperPoolGet()
-	r.TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: cl.insecure, // nolint
-	}
+	r.TLSClientConfig = cl.tlsConfig()
 	r.QuicConfig = qcconfig
 
 	c := HTTPClientPoolGet()
@@ -158,6 +208,39 @@ func (cl *QuicClient) newClient(maxIdleTimeout time.Duration) (*http.Client, fun
 	}
 }
 
+func (cl *QuicClient) newTCPClient() (*http.Client, func() error /* close func */) {
+	// NOTE unlike QUIC, the TCP connections are kept and reused
+	cl.tcpOnce.Do(func() {
+		cl.tcpTransport = &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			ForceAttemptHTTP2:   true,
+			TLSClientConfig:     cl.tlsConfig(),
+			TLSHandshakeTimeout: time.Second * 3,
+			IdleConnTimeout:     time.Second * 30,
+			MaxIdleConnsPerHost: 10,
+		}
+	})
+
+	c := HTTPClientPoolGet()
+	c.Transport = cl.tcpTransport
+
+	return c, func() error {
+		HTTPClientPoolPut(c)
+
+		return nil
+	}
+}
+
+// tcpRequestURL converts the url of network.TCPURLScheme to https url.
+func tcpRequestURL(s string) (string, bool) {
+	prefix := network.TCPURLScheme + "://"
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+
+	return "https://" + s[len(prefix):], true
+}
+
 type QuicResponse struct {
 	sync.Mutex
 	*http.Response
@@ -170,7 +253,7 @@ func NewQuicResponse(response *http.Response, closeFunc func() error) *QuicRespo
 }
 
 func (qr *QuicResponse) OK() bool {
-	return qr.StatusCode == 200 || qr.StatusCode == 201
+	return qr.StatusCode == 200 || qr.StatusCode == 201 || qr.StatusCode == 206
 }
 
 func (qr *QuicResponse) Bytes() ([]byte, error) {
@@ -196,6 +279,11 @@ func (qr *QuicResponse) Error() error {
 		return network.MergeError(
 			util.NotFoundError.Errorf("request not found: %d", qr.StatusCode),
 		)
+	} else if network.IsProblemFromResponse(qr.Response) {
+		if pr, err := network.LoadProblemFromResponse(qr.Response); err == nil &&
+			pr.Type() == network.PrunedHeightProblemType {
+			return network.MergeError(network.PrunedHeightError.Wrap(pr))
+		}
 	}
 
 	return network.NetworkError.Errorf("failed to request: %d", qr.StatusCode)
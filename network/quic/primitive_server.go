@@ -3,10 +3,15 @@ package quicnetwork
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	stdlog "log"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/lucas-clemente/quic-go"
 	"github.com/lucas-clemente/quic-go/http3"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -15,10 +20,19 @@ import (
 	"github.com/spikeekips/mitum/util/encoder"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 )
 
 const QuicEncoderHintHeader string = "X-MITUM-ENCODER-HINT"
 
+// clientPeerContextKey keeps the identity of the authenticated client in the
+// request context.
+const clientPeerContextKey util.ContextKey = "client-peer"
+
+// ClientCertificateAuthenticator authenticates the client certificate and
+// returns the identity of the client.
+type ClientCertificateAuthenticator func(*x509.Certificate) (string, error)
+
 type PrimitiveQuicServer struct {
 	*logging.Logging
 	*util.ContextDaemon
@@ -27,6 +41,11 @@ type PrimitiveQuicServer struct {
 	stoppedChan chan struct{}
 	router      *mux.Router
 	httpLog     *logging.Logging
+	auth        ClientCertificateAuthenticator
+	peers       *clientPeers
+	publics     []string
+	tcp         bool
+	scores      *network.PeerScores
 }
 
 func NewPrimitiveQuicServer(
@@ -38,6 +57,32 @@ func NewPrimitiveQuicServer(
 		return nil, errors.Wrapf(err, "failed to open quic server, %q", bind)
 	}
 
+	return newPrimitiveServer(bind, certs, httpLog), nil
+}
+
+// NewPrimitiveTCPServer serves the same handlers with PrimitiveQuicServer by
+// HTTP/2 over TCP. It is for the networks, which does not allow UDP.
+func NewPrimitiveTCPServer(
+	bind string,
+	certs []tls.Certificate,
+	httpLog *logging.Logging,
+) (*PrimitiveQuicServer, error) {
+	if err := network.CheckBindIsOpen("tcp", bind, time.Second*1); err != nil {
+		return nil, errors.Wrapf(err, "failed to open tcp server, %q", bind)
+	}
+
+	qs := newPrimitiveServer(bind, certs, httpLog)
+	qs.tcp = true
+	qs.tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+
+	return qs, nil
+}
+
+func newPrimitiveServer(
+	bind string,
+	certs []tls.Certificate,
+	httpLog *logging.Logging,
+) *PrimitiveQuicServer {
 	qs := &PrimitiveQuicServer{
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
 			return c.Str("module", "network-quic-primitive-server")
@@ -52,6 +97,10 @@ func NewPrimitiveQuicServer(
 		httpLog:     httpLog,
 	}
 
+	qs.router.Use(metrics.HTTPMiddleware)
+	qs.router.Use(qs.clientAuthMiddleware)
+	qs.router.Use(qs.peerBanMiddleware)
+
 	root := qs.router.Name("root")
 	root.Path("/").HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -61,7 +110,7 @@ func NewPrimitiveQuicServer(
 
 	qs.ContextDaemon = util.NewContextDaemon("network-quic-primitive-server", qs.run)
 
-	return qs, nil
+	return qs
 }
 
 func (qs *PrimitiveQuicServer) Handler(prefix string) *mux.Route {
@@ -91,6 +140,185 @@ func (qs *PrimitiveQuicServer) SetLogging(l *logging.Logging) *logging.Logging {
 	return qs.Logging.SetLogging(l)
 }
 
+// EnableMutualTLS requests the client certificate and authenticates it by
+// auth. The connection with the unknown client certificate is rejected and the
+// client without certificate can request only the public handlers. It should
+// be called before Start().
+func (qs *PrimitiveQuicServer) EnableMutualTLS(auth ClientCertificateAuthenticator, publics ...string) {
+	qs.auth = auth
+	qs.AddPublicHandlers(publics...)
+
+	base := qs.tlsConfig.Clone()
+	base.ClientAuth = tls.RequestClientCert
+	base.SessionTicketsDisabled = true
+
+	if qs.tcp {
+		// NOTE with TCP, the client certificate is kept in the request
+		base.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) < 1 {
+				return nil
+			}
+
+			_, err := auth(cs.PeerCertificates[0])
+
+			return err
+		}
+
+		qs.tlsConfig = base
+
+		return
+	}
+
+	qs.peers = newClientPeers()
+	qs.tlsConfig = &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		Certificates: base.Certificates,
+		NextProtos:   base.NextProtos,
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			remote := hello.Conn.RemoteAddr().String()
+
+			c := base.Clone()
+			c.VerifyConnection = func(cs tls.ConnectionState) error {
+				if len(cs.PeerCertificates) < 1 {
+					qs.peers.remove(remote)
+
+					return nil
+				}
+
+				id, err := auth(cs.PeerCertificates[0])
+				if err != nil {
+					qs.peers.remove(remote)
+
+					qs.Log().Debug().Err(err).Str("remote", remote).Msg("unknown client certificate")
+
+					return err
+				}
+
+				qs.peers.set(remote, id)
+
+				return nil
+			}
+
+			return c, nil
+		},
+	}
+}
+
+// AddPublicHandlers adds the path prefixes of handlers, which can be requested
+// without the client certificate under mutual TLS.
+func (qs *PrimitiveQuicServer) AddPublicHandlers(prefixes ...string) {
+	qs.publics = append(qs.publics, prefixes...)
+}
+
+func (qs *PrimitiveQuicServer) isPublicHandler(p string) bool {
+	for i := range qs.publics {
+		prefix := qs.publics[i]
+		if p == prefix {
+			return true
+		}
+
+		// NOTE "/" matches only itself
+		if t := strings.TrimSuffix(prefix, "/"); len(t) > 0 && strings.HasPrefix(p, t+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (qs *PrimitiveQuicServer) clientAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if qs.auth == nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if id, found := qs.clientPeer(r); found {
+			r = r.WithContext(context.WithValue(r.Context(), clientPeerContextKey, id))
+		} else if !qs.isPublicHandler(r.URL.Path) {
+			network.HTTPError(w, http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientPeer returns the identity of the authenticated client of request.
+func (qs *PrimitiveQuicServer) clientPeer(r *http.Request) (string, bool) {
+	switch {
+	case qs.auth == nil:
+		return "", false
+	case qs.tcp:
+		if r.TLS == nil || len(r.TLS.PeerCertificates) < 1 {
+			return "", false
+		}
+
+		id, err := qs.auth(r.TLS.PeerCertificates[0])
+		if err != nil {
+			return "", false
+		}
+
+		return id, true
+	default:
+		return qs.peers.get(r.RemoteAddr)
+	}
+}
+
+// SetPeerScores sets the PeerScores; the requests from the banned peers are
+// rejected except the public handlers. It should be called before Start().
+func (qs *PrimitiveQuicServer) SetPeerScores(ps *network.PeerScores) {
+	qs.scores = ps
+}
+
+// Penalize adds penalty to the node of request. The client, which is not
+// authenticated by mutual TLS, is not penalized, because the bans are kept by
+// node address.
+func (qs *PrimitiveQuicServer) Penalize(r *http.Request, penalty network.PeerPenalty, reason string) {
+	if qs.scores == nil {
+		return
+	}
+
+	peer, found := qs.RemotePeer(r)
+	if !found {
+		return
+	}
+
+	if qs.scores.Penalize(peer, penalty, reason) {
+		qs.Log().Warn().Str("peer", peer).Str("reason", reason).Msg("peer banned")
+	}
+}
+
+// RemotePeer returns the identity of the client, which is authenticated by
+// mutual TLS.
+func (qs *PrimitiveQuicServer) RemotePeer(r *http.Request) (string, bool) {
+	if id, ok := r.Context().Value(clientPeerContextKey).(string); ok {
+		return id, true
+	}
+
+	return qs.clientPeer(r)
+}
+
+func (qs *PrimitiveQuicServer) peerBanMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if qs.scores == nil || qs.isPublicHandler(r.URL.Path) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if peer, found := qs.RemotePeer(r); found && qs.scores.IsBanned(peer) {
+			network.HTTPError(w, http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (qs *PrimitiveQuicServer) StoppedChan() <-chan struct{} {
 	return qs.stoppedChan
 }
@@ -105,12 +333,28 @@ func (qs *PrimitiveQuicServer) run(ctx context.Context) error {
 		log = qs.Log()
 	}
 
-	server := &http3.Server{
-		Server: &http.Server{
+	handler := network.HTTPLogHandler(qs.router, log)
+
+	var server primitiveServer
+	if qs.tcp {
+		server = &tcpServer{Server: &http.Server{
 			Addr:      qs.bind,
 			TLSConfig: qs.tlsConfig,
-			Handler:   network.HTTPLogHandler(qs.router, log),
-		},
+			Handler:   handler,
+			ErrorLog:  stdlog.New(qs.Log(), "", 0),
+		}}
+	} else {
+		var quicConfig *quic.Config
+		if qs.peers != nil {
+			// NOTE the authenticated client is removed when it's connection
+			// is closed
+			quicConfig = &quic.Config{Tracer: clientPeersTracer{peers: qs.peers}}
+		}
+
+		server = &http3.Server{
+			Server:     &http.Server{Addr: qs.bind, TLSConfig: qs.tlsConfig, Handler: handler},
+			QuicConfig: quicConfig,
+		}
 	}
 
 	errChan := make(chan error)
@@ -144,7 +388,7 @@ func (qs *PrimitiveQuicServer) run(ctx context.Context) error {
 	return nil
 }
 
-func (*PrimitiveQuicServer) stop(server *http3.Server) error {
+func (*PrimitiveQuicServer) stop(server primitiveServer) error {
 	if err := server.Close(); err != nil {
 		return err
 	}
@@ -155,6 +399,21 @@ func (*PrimitiveQuicServer) stop(server *http3.Server) error {
 	return server.Shutdown(ctx)
 }
 
+type primitiveServer interface {
+	ListenAndServe() error
+	Close() error
+	Shutdown(context.Context) error
+}
+
+type tcpServer struct {
+	*http.Server
+}
+
+func (sv *tcpServer) ListenAndServe() error {
+	// NOTE certificates are already in TLSConfig
+	return sv.Server.ListenAndServeTLS("", "")
+}
+
 func EncoderFromHeader(header http.Header, encs *encoder.Encoders, enc encoder.Encoder) (encoder.Encoder, error) {
 	s := header.Get(QuicEncoderHintHeader)
 	if len(s) < 1 {
@@ -166,3 +425,39 @@ func EncoderFromHeader(header http.Header, encs *encoder.Encoders, enc encoder.E
 		return encs.Encoder(ht.Type(), ht.Version())
 	}
 }
+
+// clientPeers keeps the authenticated client by it's remote address. The
+// client certificate is not kept in the http3 request, so it is checked in
+// TLS handshake and the request is matched by the remote address until the
+// connection is closed.
+type clientPeers struct {
+	sync.RWMutex
+	m map[string]string
+}
+
+func newClientPeers() *clientPeers {
+	return &clientPeers{m: map[string]string{}}
+}
+
+func (cp *clientPeers) set(remote, id string) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	cp.m[remote] = id
+}
+
+func (cp *clientPeers) get(remote string) (string, bool) {
+	cp.RLock()
+	defer cp.RUnlock()
+
+	id, found := cp.m[remote]
+
+	return id, found
+}
+
+func (cp *clientPeers) remove(remote string) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	delete(cp.m, remote)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/lucas-clemente/quic-go"
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util"
@@ -49,11 +51,22 @@ func (t *testPrimitiveQuicServer) readyServer(handlers map[string]network.HTTPHa
 		qn.SetHandlerFunc(prefix, handler)
 	}
 
+	t.start(qn)
+
+	return qn
+}
+
+func (t *testPrimitiveQuicServer) start(qn *PrimitiveQuicServer) {
 	t.NoError(qn.Start())
 
 	_, port, err := net.SplitHostPort(t.bind)
 	t.NoError(err)
 
+	proto := "udp"
+	if qn.tcp {
+		proto = "tcp"
+	}
+
 	maxRetries := 3
 	var retries int
 	for {
@@ -62,14 +75,12 @@ func (t *testPrimitiveQuicServer) readyServer(handlers map[string]network.HTTPHa
 			break
 		}
 
-		if err := util.CheckPort("udp", fmt.Sprintf("127.0.0.1:%s", port), time.Millisecond*50); err == nil {
+		if err := util.CheckPort(proto, fmt.Sprintf("127.0.0.1:%s", port), time.Millisecond*50); err == nil {
 			break
 		}
 		<-time.After(time.Millisecond * 10)
 		retries++
 	}
-
-	return qn
 }
 
 func (t *testPrimitiveQuicServer) TestGet() {
@@ -138,6 +149,162 @@ func (t *testPrimitiveQuicServer) TestSend() {
 	}
 }
 
+func (t *testPrimitiveQuicServer) TestTCP() {
+	qn, err := NewPrimitiveTCPServer(t.bind, t.certs, nil)
+	t.NoError(err)
+
+	var data int = 33
+	qn.SetHandlerFunc("/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			network.HTTPError(w, http.StatusHTTPVersionNotSupported)
+
+			return
+		}
+
+		_, _ = w.Write(util.IntToBytes(data))
+	})
+
+	t.start(qn)
+	defer qn.Stop()
+
+	client, err := NewQuicClient(true, nil)
+	t.NoError(err)
+
+	u := &url.URL{Scheme: network.TCPURLScheme, Host: t.bind, Path: "/get"}
+
+	response, err := client.Get(context.Background(), time.Second*3, u.String(), nil, nil)
+	t.NoError(err)
+	defer response.Close()
+
+	t.True(response.OK())
+
+	b, err := response.Bytes()
+	t.NoError(err)
+	received, err := util.BytesToInt(b)
+	t.NoError(err)
+	t.Equal(data, received)
+
+	// NOTE quic request to tcp server fails
+	client, err = NewQuicClient(true, &quic.Config{HandshakeIdleTimeout: time.Millisecond * 300})
+	t.NoError(err)
+
+	_, err = client.Get(context.Background(), 0, t.url.String()+"/get", nil, nil)
+	t.Error(err)
+}
+
+func (t *testPrimitiveQuicServer) TestMutualTLS() {
+	t.testMutualTLS(false)
+}
+
+func (t *testPrimitiveQuicServer) TestMutualTLSOverTCP() {
+	t.testMutualTLS(true)
+}
+
+func (t *testPrimitiveQuicServer) testMutualTLS(tcp bool) {
+	priv, err := util.GenerateED25519Privatekey()
+	t.NoError(err)
+
+	known, err := util.GenerateTLSCerts("localhost", priv)
+	t.NoError(err)
+
+	newServer, u := NewPrimitiveQuicServer, *t.url
+	if tcp {
+		newServer = NewPrimitiveTCPServer
+		u.Scheme = network.TCPURLScheme
+	}
+
+	qn, err := newServer(t.bind, t.certs, nil)
+	t.NoError(err)
+
+	qn.EnableMutualTLS(func(cert *x509.Certificate) (string, error) {
+		if !bytes.Equal(cert.Raw, known[0].Certificate[0]) {
+			return "", errors.Errorf("unknown")
+		}
+
+		return "known", nil
+	}, "/public")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}
+	qn.SetHandlerFunc("/public", handler)
+	qn.SetHandlerFunc("/public/sub", handler)
+	qn.SetHandlerFunc("/private", handler)
+
+	t.start(qn)
+	defer qn.Stop()
+
+	get := func(p string, certs []tls.Certificate, verify ServerCertificateVerifier) (*QuicResponse, error) {
+		client, err := NewQuicClient(true, nil)
+		t.NoError(err)
+
+		client.SetTLS(certs, verify)
+
+		return client.Get(context.Background(), time.Second*3, u.String()+p, nil, nil)
+	}
+
+	t.Run("without certificate", func() {
+		for _, p := range []string{"/public", "/public/sub"} {
+			res, err := get(p, nil, nil)
+			t.NoError(err)
+			t.True(res.OK())
+			_ = res.Close()
+		}
+
+		res, err := get("/private", nil, nil)
+		t.NoError(err)
+		t.Equal(http.StatusUnauthorized, res.StatusCode)
+		_ = res.Close()
+	})
+
+	t.Run("known certificate", func() {
+		res, err := get("/private", known, nil)
+		t.NoError(err)
+		t.True(res.OK())
+		_ = res.Close()
+
+		if tcp {
+			return
+		}
+
+		// NOTE the authenticated client is removed after the connection is
+		// closed
+		t.Eventually(func() bool {
+			qn.peers.RLock()
+			defer qn.peers.RUnlock()
+
+			return len(qn.peers.m) < 1
+		}, time.Second*3, time.Millisecond*100)
+	})
+
+	t.Run("unknown certificate", func() {
+		unknown, err := util.GenerateTLSCerts("localhost", priv)
+		t.NoError(err)
+
+		_, err = get("/public", unknown, nil)
+		t.Error(err)
+	})
+
+	t.Run("verify server certificate", func() {
+		res, err := get("/private", known, func(cert *x509.Certificate) error {
+			if !bytes.Equal(cert.Raw, t.certs[0].Certificate[0]) {
+				return errors.Errorf("unknown server")
+			}
+
+			return nil
+		})
+		t.NoError(err)
+		t.True(res.OK())
+		_ = res.Close()
+
+		_, err = get("/private", known, func(*x509.Certificate) error {
+			return errors.Errorf("unknown server")
+		})
+		t.Error(err)
+		t.Contains(err.Error(), "unknown server")
+	})
+}
+
 func TestPrimitiveQuicServer(t *testing.T) {
 	suite.Run(t, new(testPrimitiveQuicServer))
 }
@@ -16,25 +16,35 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
 	"github.com/spikeekips/mitum/base/seal"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/isvalid"
 	"github.com/spikeekips/mitum/util/logging"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"golang.org/x/sync/singleflight"
 )
 
 var (
-	DefaultPort                         = "54321"
-	QuicHandlerPathGetStagedOperations  = "/operations"
-	QuicHandlerPathSendSeal             = "/seal"
-	QuicHandlerPathGetProposal          = "/proposal"
-	QuicHandlerPathGetProposalPattern   = "/proposal" + "/{hash:.*}"
-	QuicHandlerPathGetBlockdataMaps     = "/blockdatamaps"
-	QuicHandlerPathGetBlockdata         = "/blockdata"
-	QuicHandlerPathGetBlockdataPattern  = QuicHandlerPathGetBlockdata + "/{path:.*}"
+	DefaultPort                                = "54321"
+	QuicHandlerPathGetStagedOperations         = "/operations"
+	QuicHandlerPathSendSeal                    = "/seal"
+	QuicHandlerPathGetProposal                 = "/proposal"
+	QuicHandlerPathGetProposalPattern          = "/proposal" + "/{hash:.*}"
+	QuicHandlerPathGetBlockdataMaps            = "/blockdatamaps"
+	QuicHandlerPathGetBlockdata                = "/blockdata"
+	QuicHandlerPathGetBlockdataPattern         = QuicHandlerPathGetBlockdata + "/{path:.*}"
+	QuicHandlerPathGetBlockdataChecksum        = "/blockdata-checksum"
+	QuicHandlerPathGetBlockdataChecksumPattern = QuicHandlerPathGetBlockdataChecksum +
+		"/{height}/{type}/{checksum}"
+	QuicHandlerPathGetSnapshot          = "/snapshot"
+	QuicHandlerPathGetSnapshotPattern   = QuicHandlerPathGetSnapshot + "/{height:.*}"
+	QuicHandlerPathGetState             = "/state"
+	QuicHandlerPathGetStatePattern      = QuicHandlerPathGetState + "/{height:.*}"
 	QuicHandlerPathPingHandoverPattern  = "/handover"
 	QuicHandlerPathStartHandoverPattern = QuicHandlerPathPingHandoverPattern + "/start"
 	QuicHandlerPathEndHandoverPattern   = QuicHandlerPathPingHandoverPattern + "/end"
@@ -46,6 +56,10 @@ var (
 	NotSupportedErorr = util.NewError("not supported")
 )
 
+// DefaultPublicHandlers is the handlers, which can be requested without node
+// certificate under mutual TLS.
+var DefaultPublicHandlers = []string{QuicHandlerPathNodeInfo}
+
 var LimitRequestByHeights = 20 // max number of reqeust heights
 
 var cacheKeyNodeInfo = [2]byte{0x00, 0x00}
@@ -65,6 +79,9 @@ type Server struct {
 	nodeInfoHandler            network.NodeInfoHandler
 	blockdataMapsHandler       network.BlockdataMapsHandler
 	blockdataHandler           network.BlockdataHandler
+	blockdataByChecksumHandler network.BlockdataByChecksumHandler
+	snapshotHandler            network.SnapshotHandler
+	getStateHandler            network.GetStateHandler
 	startHandoverHandler       network.StartHandoverHandler
 	pingHandoverHandler        network.PingHandoverHandler
 	endHandoverHandler         network.EndHandoverHandler
@@ -154,6 +171,18 @@ func (sv *Server) SetBlockdataHandler(fn network.BlockdataHandler) {
 	sv.blockdataHandler = fn
 }
 
+func (sv *Server) SetBlockdataByChecksumHandler(fn network.BlockdataByChecksumHandler) {
+	sv.blockdataByChecksumHandler = fn
+}
+
+func (sv *Server) SetSnapshotHandler(fn network.SnapshotHandler) {
+	sv.snapshotHandler = fn
+}
+
+func (sv *Server) SetGetStateHandler(fn network.GetStateHandler) {
+	sv.getStateHandler = fn
+}
+
 func (sv *Server) SetStartHandoverHandler(fn network.StartHandoverHandler) {
 	sv.startHandoverHandler = fn
 }
@@ -172,6 +201,10 @@ func (sv *Server) setHandlers() {
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetProposalPattern, sv.handleGetProposal).Methods("GET")
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetBlockdataMaps, sv.handleGetBlockdataMaps).Methods("POST")
 	_ = sv.SetHandlerFunc(QuicHandlerPathGetBlockdataPattern, sv.handleGetBlockdata).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetBlockdataChecksumPattern, sv.handleGetBlockdataByChecksum).
+		Methods("GET", "HEAD")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetSnapshotPattern, sv.handleGetSnapshot).Methods("GET")
+	_ = sv.SetHandlerFunc(QuicHandlerPathGetStatePattern, sv.handleGetState).Methods("GET")
 	_ = sv.SetHandlerFunc(QuicHandlerPathNodeInfo, sv.handleNodeInfo)
 	_ = sv.SetHandlerFunc(QuicHandlerPathPingHandoverPattern, sv.handlePingHandover)
 	_ = sv.SetHandlerFunc(QuicHandlerPathStartHandoverPattern, sv.handleStartHandover)
@@ -213,18 +246,20 @@ func (sv *Server) handleGetStagedOperations(w http.ResponseWriter, r *http.Reque
 		args.Sort()
 	}
 
-	if v, err, _ := sv.rg.Do("GetStagedOperations-"+args.String(), func() (interface{}, error) {
+	renc, compact := sv.acceptWire(r)
+
+	if v, err, _ := sv.rg.Do("GetStagedOperations-"+wireKey(renc, compact)+args.String(), func() (interface{}, error) {
 		i, err := sv.getStagedOperationsHandler(args.Hashes)
 		if err != nil {
 			return nil, err
 		}
-		return sv.enc.Marshal(i)
+		return marshalWireHinters(renc, compact, i)
 	}); err != nil {
 		sv.Log().Error().Interface("hashes", args.Hashes).Err(err).Msg("failed to get operationss")
 
 		handleError(w, err)
 	} else {
-		w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+		setWireHeaders(w.Header(), renc, compact)
 		_, _ = w.Write(v.([]byte))
 	}
 }
@@ -240,14 +275,23 @@ func (sv *Server) handleNewSeal(w http.ResponseWriter, r *http.Request) {
 
 	enc, err := EncoderFromHeader(r.Header, sv.encs, sv.enc)
 	if err != nil {
-		network.HTTPError(w, http.StatusBadRequest)
+		// NOTE the client can retry with the other encoder
+		network.HTTPError(w, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	compact, err := IsCompactWireFromHeader(r.Header)
+	if err != nil {
+		network.HTTPError(w, http.StatusUnsupportedMediaType)
 		return
 	}
 
 	var sl seal.Seal
-	if err := encoder.Decode(body.Bytes(), enc, &sl); err != nil {
+	if err := decodeWire(enc, compact, body.Bytes(), &sl); err != nil {
 		sv.Log().Error().Err(err).Stringer("body", body).Msg("invalid seal found")
 
+		sv.Penalize(r, network.PeerPenaltyInvalidSeal, "undecodable seal")
+
 		network.HTTPError(w, http.StatusBadRequest)
 
 		return
@@ -268,6 +312,10 @@ func (sv *Server) handleNewSeal(w http.ResponseWriter, r *http.Request) {
 		seal.LogEventSeal(sl, "seal", sv.Log().Error(), sv.IsTraceLog()).
 			Err(err).Msg("failed to receive new seal")
 
+		if isInvalidSealError(err) {
+			sv.Penalize(r, network.PeerPenaltyInvalidSeal, err.Error())
+		}
+
 		network.HTTPError(w, http.StatusInternalServerError)
 
 		return
@@ -297,14 +345,16 @@ func (sv *Server) handleGetProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	v, err, _ := sv.rg.Do("GetPropossal-"+h.String(), func() (interface{}, error) {
+	renc, compact := sv.acceptWire(r)
+
+	v, err, _ := sv.rg.Do("GetPropossal-"+wireKey(renc, compact)+h.String(), func() (interface{}, error) {
 		switch i, err := sv.getProposalHandler(h); {
 		case err != nil:
 			return nil, err
 		case i == nil:
 			return nil, nil
 		default:
-			return sv.enc.Marshal(i)
+			return marshalWire(renc, compact, i)
 		}
 	})
 
@@ -320,6 +370,89 @@ func (sv *Server) handleGetProposal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setWireHeaders(w.Header(), renc, compact)
+	_, _ = w.Write(v.([]byte))
+}
+
+func (sv *Server) handleGetSnapshot(w http.ResponseWriter, r *http.Request) {
+	if sv.snapshotHandler == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	height, e := base.NewHeightFromString(strings.TrimSpace(mux.Vars(r)["height"]))
+	if e != nil || height < base.NilHeight {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	v, err, _ := sv.rg.Do("GetSnapshot-"+height.String(), func() (interface{}, error) {
+		switch i, err := sv.snapshotHandler(height); {
+		case err != nil:
+			return nil, err
+		case i == nil:
+			return nil, util.NotFoundError.Errorf("snapshot not found")
+		default:
+			return sv.enc.Marshal(i)
+		}
+	})
+	if err != nil {
+		if !errors.Is(err, util.NotFoundError) {
+			sv.Log().Error().Int64("height", height.Int64()).Err(err).Msg("failed to get snapshot")
+		}
+
+		handleError(w, err)
+
+		return
+	}
+
+	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+	_, _ = w.Write(v.([]byte))
+}
+
+func (sv *Server) handleGetState(w http.ResponseWriter, r *http.Request) {
+	if sv.getStateHandler == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	key := strings.TrimSpace(r.URL.Query().Get("key"))
+	if len(key) < 1 {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	height, e := base.NewHeightFromString(strings.TrimSpace(mux.Vars(r)["height"]))
+	if e != nil || height < base.NilHeight {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	v, err, _ := sv.rg.Do("GetState-"+height.String()+"-"+key, func() (interface{}, error) {
+		switch i, found, err := sv.getStateHandler(key, height); {
+		case err != nil:
+			return nil, err
+		case !found:
+			return nil, util.NotFoundError.Errorf("state not found")
+		default:
+			return sv.enc.Marshal(i)
+		}
+	})
+	if err != nil {
+		if !errors.Is(err, util.NotFoundError) && !errors.Is(err, network.PrunedHeightError) {
+			sv.Log().Error().Str("key", key).Int64("height", height.Int64()).Err(err).Msg("failed to get state")
+		}
+
+		handleError(w, err)
+
+		return
+	}
+
 	w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
 	_, _ = w.Write(v.([]byte))
 }
@@ -399,18 +532,20 @@ func (sv *Server) handleGetBlockdataMaps(w http.ResponseWriter, r *http.Request)
 		args.Sort()
 	}
 
-	if v, err, _ := sv.rg.Do("GetBlockdataMaps-"+args.String(), func() (interface{}, error) {
+	renc, compact := sv.acceptWire(r)
+
+	if v, err, _ := sv.rg.Do("GetBlockdataMaps-"+wireKey(renc, compact)+args.String(), func() (interface{}, error) {
 		sls, err := sv.blockdataMapsHandler(args.Heights)
 		if err != nil {
 			return nil, err
 		}
-		return sv.enc.Marshal(sls)
+		return marshalWireHinters(renc, compact, sls)
 	}); err != nil {
 		sv.Log().Error().Err(err).Interface("heights", args.Heights).Msg("failed to get block data maps")
 
 		handleError(w, err)
 	} else {
-		w.Header().Set(QuicEncoderHintHeader, sv.enc.Hint().String())
+		setWireHeaders(w.Header(), renc, compact)
 		_, _ = w.Write(v.([]byte))
 	}
 }
@@ -469,6 +604,52 @@ func (sv *Server) handleGetBlockdata(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(v.([]byte))
 }
 
+// handleGetBlockdataByChecksum serves the raw block data item, which has the
+// checksum; the range request is supported.
+func (sv *Server) handleGetBlockdataByChecksum(w http.ResponseWriter, r *http.Request) {
+	if sv.blockdataByChecksumHandler == nil {
+		network.HTTPError(w, http.StatusInternalServerError)
+
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	height, e := base.NewHeightFromString(strings.TrimSpace(vars["height"]))
+	if e != nil || height < base.PreGenesisHeight {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	dataType := strings.TrimSpace(vars["type"])
+	checksum := strings.TrimSpace(vars["checksum"])
+	if len(dataType) < 1 || len(checksum) < 1 {
+		network.HTTPError(w, http.StatusBadRequest)
+
+		return
+	}
+
+	f, err := sv.blockdataByChecksumHandler(height, dataType, checksum)
+	if err != nil {
+		if !errors.Is(err, util.NotFoundError) {
+			sv.Log().Error().Err(err).
+				Int64("height", height.Int64()).Str("data_type", dataType).Str("checksum", checksum).
+				Msg("failed to get block data by checksum")
+		}
+
+		handleError(w, err)
+
+		return
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	http.ServeContent(w, r, "", time.Time{}, f)
+}
+
 func (sv *Server) handleStartHandover(w http.ResponseWriter, r *http.Request) {
 	sl, ok := sv.loadHandoverSeal(w, r)
 	if !ok {
@@ -555,6 +736,7 @@ func (sv *Server) logNilHanders() {
 		{sv.nodeInfoHandler, "nodeInfoHandler"},
 		{sv.blockdataMapsHandler, "blockdataMapsHandler"},
 		{sv.blockdataHandler, "blockdataHandler"},
+		{sv.blockdataByChecksumHandler, "blockdataByChecksumHandler"},
 	}
 
 	var enables, disables []string
@@ -598,6 +780,34 @@ func (sv *Server) doPassthroughs(r *http.Request, sl seal.Seal) error {
 	)
 }
 
+// acceptWire returns the encoder and whether to use WireFormatCompact for
+// response, which the client prefers.
+func (sv *Server) acceptWire(r *http.Request) (encoder.Encoder, bool) {
+	return AcceptEncoderFromHeader(r.Header, sv.encs, sv.enc),
+		r.Header.Get(QuicAcceptWireFormatHeader) == WireFormatCompact
+}
+
+func setWireHeaders(header http.Header, enc encoder.Encoder, compact bool) {
+	header.Set(QuicEncoderHintHeader, enc.Hint().String())
+	if compact {
+		header.Set(QuicWireFormatHeader, WireFormatCompact)
+	}
+}
+
+func wireKey(enc encoder.Encoder, compact bool) string {
+	if compact {
+		return enc.Hint().String() + "-" + WireFormatCompact
+	}
+
+	return enc.Hint().String()
+}
+
+// isInvalidSealError checks whether the error of NewSealHandler is caused by
+// the invalid seal, not by the local node.
+func isInvalidSealError(err error) bool {
+	return errors.Is(err, isvalid.InvalidError) || errors.Is(err, key.SignatureVerificationFailedError)
+}
+
 func mustQuicURL(u, p string) (string, *url.URL) {
 	uu, err := network.ParseURL(u, false)
 	if err != nil {
@@ -610,6 +820,12 @@ func mustQuicURL(u, p string) (string, *url.URL) {
 }
 
 func handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, network.PrunedHeightError) {
+		network.WritePoblem(w, http.StatusGone, network.NewProblem(network.PrunedHeightProblemType, err.Error()))
+
+		return
+	}
+
 	status := http.StatusInternalServerError
 	if errors.Is(err, util.NotFoundError) {
 		status = http.StatusNotFound
@@ -1,12 +1,15 @@
 package quicnetwork
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"testing"
@@ -25,7 +28,9 @@ import (
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
 	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
 	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/isvalid"
 	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/stretchr/testify/suite"
@@ -87,10 +92,25 @@ func (t *testQuicServer) readyServer() *Server {
 	qs, err := NewPrimitiveQuicServer(t.bind, t.certs, nil)
 	t.NoError(err)
 
+	return t.startServer(qs, t.connInfo)
+}
+
+func (t *testQuicServer) readyTCPServer() (*Server, network.HTTPConnInfo) {
+	qs, err := NewPrimitiveTCPServer(t.bind, t.certs, nil)
+	t.NoError(err)
+
+	u := *t.connInfo.URL()
+	u.Scheme = network.TCPURLScheme
+	connInfo := network.NewHTTPConnInfo(&u, true)
+
+	return t.startServer(qs, connInfo), connInfo
+}
+
+func (t *testQuicServer) startServer(qs *PrimitiveQuicServer, connInfo network.HTTPConnInfo) *Server {
 	ca, err := cache.NewGCache("lru", 100, time.Second*3)
 	t.NoError(err)
 
-	qn, err := NewServer(qs, t.encs, t.enc, ca, t.connInfo, nil)
+	qn, err := NewServer(qs, t.encs, t.enc, ca, connInfo, nil)
 	t.NoError(err)
 
 	t.NoError(qn.Start())
@@ -98,6 +118,11 @@ func (t *testQuicServer) readyServer() *Server {
 	_, port, err := net.SplitHostPort(t.bind)
 	t.NoError(err)
 
+	proto := "udp"
+	if qs.tcp {
+		proto = "tcp"
+	}
+
 	maxRetries := 3
 	var retries int
 	for {
@@ -106,7 +131,7 @@ func (t *testQuicServer) readyServer() *Server {
 			break
 		}
 
-		if err := util.CheckPort("udp", fmt.Sprintf("127.0.0.1:%s", port), time.Millisecond*50); err == nil {
+		if err := util.CheckPort(proto, fmt.Sprintf("127.0.0.1:%s", port), time.Millisecond*50); err == nil {
 			break
 		}
 		<-time.After(time.Millisecond * 10)
@@ -160,6 +185,127 @@ func (t *testQuicServer) TestSendSeal() {
 	t.NoError(qc.SendSeal(context.TODO(), nil, sl))
 }
 
+func (t *testQuicServer) TestPeerBanned() {
+	qs, err := NewPrimitiveQuicServer(t.bind, t.certs, nil)
+	t.NoError(err)
+
+	ps, err := network.NewPeerScores(uint(network.PeerPenaltyInvalidSeal)*2, time.Minute, time.Minute)
+	t.NoError(err)
+	qs.SetPeerScores(ps)
+
+	// NOTE the peer is identified by mutual TLS
+	priv, err := util.GenerateED25519Privatekey()
+	t.NoError(err)
+
+	known, err := util.GenerateTLSCerts("localhost", priv)
+	t.NoError(err)
+
+	qs.EnableMutualTLS(func(cert *x509.Certificate) (string, error) {
+		if !bytes.Equal(cert.Raw, known[0].Certificate[0]) {
+			return "", errors.Errorf("unknown")
+		}
+
+		return "n0", nil
+	}, DefaultPublicHandlers...)
+
+	qn := t.startServer(qs, t.connInfo)
+	defer qn.Stop()
+
+	invalid := util.NewLockedItem(false)
+	received := make(chan seal.Seal, 10)
+	qn.SetNewSealHandler(func(sl seal.Seal) error {
+		received <- sl
+
+		if invalid.Value().(bool) {
+			return isvalid.InvalidError.Errorf("findme")
+		}
+
+		return nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetConfig(ChannelConfig{Certificates: known})
+
+	send := func() bool {
+		_ = qc.SendSeal(context.TODO(), nil, seal.NewDummySeal(key.NewBasePrivatekey().Publickey()))
+
+		select {
+		case <-time.After(time.Millisecond * 300):
+			return false
+		case <-received:
+			return true
+		}
+	}
+
+	// NOTE valid seal is not penalized
+	t.True(send())
+	t.Empty(ps.Bans())
+
+	_ = invalid.Set(true)
+	t.True(send())
+	t.Empty(ps.Bans())
+	t.True(send())
+
+	bans := ps.Bans()
+	t.Equal(1, len(bans))
+	t.Equal("n0", bans[0].Peer)
+	t.Contains(bans[0].Reason, "findme")
+
+	// NOTE banned peer is rejected
+	_ = invalid.Set(false)
+	t.False(send())
+
+	t.True(ps.Unban(bans[0].Peer))
+	t.True(send())
+}
+
+func (t *testQuicServer) TestOverTCP() {
+	qn, connInfo := t.readyTCPServer()
+	defer qn.Stop()
+
+	received := make(chan seal.Seal, 10)
+	qn.SetNewSealHandler(func(sl seal.Seal) error {
+		received <- sl
+		return nil
+	})
+
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	ni := network.NewNodeInfoV0(
+		node.RandomNode("n0"),
+		[]byte("test-network-id"),
+		base.StateBooting,
+		blk.Manifest(),
+		util.Version("0.1.1"),
+		map[string]interface{}{"showme": 1.1},
+		nil,
+		base.NewFixedSuffrage(base.RandomStringAddress(), nil),
+		connInfo,
+	)
+	qn.SetNodeInfoHandler(func() (network.NodeInfo, error) {
+		return ni, nil
+	})
+
+	qc, err := NewChannel(connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+
+	nni, err := qc.NodeInfo(context.TODO())
+	t.NoError(err)
+	network.CompareNodeInfo(t.T(), ni, nni)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+	t.NoError(qc.SendSeal(context.TODO(), nil, sl))
+
+	select {
+	case <-time.After(time.Second):
+		t.NoError(errors.Errorf("failed to receive respond"))
+	case r := <-received:
+		t.True(sl.Hash().Equal(r.Hash()))
+	}
+}
+
 func (t *testQuicServer) TestGetStagedOperations() {
 	qn := t.readyServer()
 	defer qn.Stop()
@@ -308,6 +454,361 @@ func (t *testQuicServer) TestGetProposal() {
 	}
 }
 
+func (t *testQuicServer) bsonEncoder() encoder.Encoder {
+	be := bsonenc.NewEncoder()
+	t.NoError(t.encs.AddEncoder(be))
+
+	return be
+}
+
+func (t *testQuicServer) TestSendSealBSON() {
+	be := t.bsonEncoder()
+
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	received := make(chan seal.Seal, 10)
+	qn.SetNewSealHandler(func(sl seal.Seal) error {
+		received <- sl
+		return nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetWireEncoder(be)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	t.NoError(qc.SendSeal(context.TODO(), nil, sl))
+
+	select {
+	case <-time.After(time.Second):
+		t.NoError(errors.Errorf("failed to receive respond"))
+	case r := <-received:
+		t.True(sl.Hash().Equal(r.Hash()))
+		t.True(sl.Signer().Equal(r.Signer()))
+		t.True(localtime.Equal(sl.SignedAt(), r.SignedAt()))
+	}
+
+	t.True(qc.WireEncoder().Hint().Equal(be.Hint()))
+}
+
+func (t *testQuicServer) TestSendSealUnsupportedEncoder() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	received := make(chan seal.Seal, 10)
+	qn.SetNewSealHandler(func(sl seal.Seal) error {
+		received <- sl
+		return nil
+	})
+
+	// NOTE server does not know bson encoder
+	be := bsonenc.NewEncoder()
+	t.NoError(be.Add(seal.DummySeal{}))
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetWireEncoder(be)
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	t.NoError(qc.SendSeal(context.TODO(), nil, sl))
+
+	select {
+	case <-time.After(time.Second):
+		t.NoError(errors.Errorf("failed to receive respond"))
+	case r := <-received:
+		t.True(sl.Hash().Equal(r.Hash()))
+	}
+
+	// NOTE channel falls back to default encoder
+	t.True(qc.WireEncoder().Hint().Equal(t.enc.Hint()))
+}
+
+func (t *testQuicServer) TestGetStagedOperationsBSON() {
+	be := t.bsonEncoder()
+
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	var hs []valuehash.Hash
+	var ops []operation.Operation
+	for i := 0; i < 3; i++ {
+		op, err := operation.NewKVOperation(key.NewBasePrivatekey(), util.UUID().Bytes(), util.UUID().String(), util.UUID().Bytes(), nil)
+		t.NoError(err)
+
+		ops = append(ops, op)
+		hs = append(hs, op.Fact().Hash())
+	}
+
+	qn.SetGetStagedOperationsHandler(func([]valuehash.Hash) ([]operation.Operation, error) {
+		return ops, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetWireEncoder(be)
+
+	l, err := qc.StagedOperations(context.TODO(), hs)
+	t.NoError(err)
+	t.Equal(len(ops), len(l))
+
+	for i := range ops {
+		t.True(ops[i].Hash().Equal(l[i].Hash()))
+		t.True(ops[i].Fact().Hash().Equal(l[i].Fact().Hash()))
+	}
+
+	// NOTE response is encoded by accepted encoder
+	b, err := t.enc.Marshal(NewHashesArgs(hs))
+	t.NoError(err)
+
+	headers := http.Header{}
+	headers.Set(QuicEncoderHintHeader, t.enc.Hint().String())
+	headers.Set(QuicAcceptEncoderHintHeader, be.Hint().String())
+
+	res, err := qc.client.Send(context.TODO(), time.Second*2, qc.getStagedOperationsURL, b, headers)
+	t.NoError(err)
+	defer res.Close()
+
+	t.NoError(res.Error())
+	t.Equal(be.Hint().String(), res.Header.Get(QuicEncoderHintHeader))
+
+	// NOTE unknown accept encoder; responds with default encoder
+	headers.Set(QuicAcceptEncoderHintHeader, "unknown-encoder-v0.0.1")
+
+	res, err = qc.client.Send(context.TODO(), time.Second*2, qc.getStagedOperationsURL, b, headers)
+	t.NoError(err)
+	defer res.Close()
+
+	t.NoError(res.Error())
+	t.Equal(t.enc.Hint().String(), res.Header.Get(QuicEncoderHintHeader))
+}
+
+func (t *testQuicServer) TestGetProposalBSON() {
+	be := t.bsonEncoder()
+
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	fact := ballot.NewProposalFact(
+		base.Height(33),
+		base.Round(0),
+		base.RandomStringAddress(),
+		[]valuehash.Hash{valuehash.RandomSHA256(), valuehash.RandomSHA256()},
+	)
+	bvp := base.NewDummyVoteproof(fact.Height(), fact.Round(), base.StageINIT, base.VoteResultMajority)
+
+	pr, err := ballot.NewProposal(fact, fact.Proposer(), bvp, key.NewBasePrivatekey(), nil)
+	t.NoError(err)
+
+	qn.SetGetProposalHandler(func(valuehash.Hash) (base.Proposal, error) {
+		return pr, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetWireEncoder(be)
+
+	upr, err := qc.Proposal(context.TODO(), fact.Hash())
+	t.NoError(err)
+	t.NoError(upr.IsValid(nil))
+
+	t.True(pr.Hash().Equal(upr.Hash()))
+	t.True(pr.Fact().Hash().Equal(upr.Fact().Hash()))
+	t.True(pr.FactSign().Signer().Equal(upr.FactSign().Signer()))
+}
+
+func (t *testQuicServer) TestBlockdataMapsBSON() {
+	be := t.bsonEncoder()
+
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	bd := block.NewBaseBlockdataMap(block.TestBlockdataWriterHint, 33)
+	bd = bd.SetBlock(valuehash.RandomSHA256())
+
+	for _, k := range block.Blockdata {
+		bd, _ = bd.SetItem(block.NewBaseBlockdataMapItem(k, util.UUID().String(), "file://"+util.UUID().String()))
+	}
+	{
+		i, err := bd.UpdateHash()
+		t.NoError(err)
+		bd = i
+	}
+
+	qn.SetBlockdataMapsHandler(func([]base.Height) ([]block.BlockdataMap, error) {
+		return []block.BlockdataMap{bd}, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetWireEncoder(be)
+
+	bds, err := qc.BlockdataMaps(context.TODO(), []base.Height{33, 34})
+	t.NoError(err)
+	t.Equal(1, len(bds))
+
+	block.CompareBlockdataMap(t.Assert(), bd, bds[0])
+}
+
+func (t *testQuicServer) TestSendSealCompact() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	received := make(chan seal.Seal, 10)
+	qn.SetNewSealHandler(func(sl seal.Seal) error {
+		received <- sl
+		return nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetConfig(ChannelConfig{CompactWire: true})
+
+	sl := seal.NewDummySeal(key.NewBasePrivatekey().Publickey())
+
+	t.NoError(qc.SendSeal(context.TODO(), nil, sl))
+
+	select {
+	case <-time.After(time.Second):
+		t.NoError(errors.Errorf("failed to receive respond"))
+	case r := <-received:
+		t.True(sl.Hash().Equal(r.Hash()))
+		t.True(sl.Signer().Equal(r.Signer()))
+	}
+
+	t.True(qc.CompactWire())
+
+	// NOTE unknown wire format
+	b, err := t.enc.Marshal(sl)
+	t.NoError(err)
+
+	headers := http.Header{}
+	headers.Set(QuicEncoderHintHeader, t.enc.Hint().String())
+	headers.Set(QuicWireFormatHeader, "unknown")
+
+	res, err := qc.client.Send(context.TODO(), time.Second*2, qc.sendSealURL, b, headers)
+	t.NoError(err)
+	defer res.Close()
+
+	t.Equal(http.StatusUnsupportedMediaType, res.StatusCode)
+}
+
+func (t *testQuicServer) TestGetStagedOperationsCompact() {
+	be := t.bsonEncoder()
+
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	var hs []valuehash.Hash
+	var ops []operation.Operation
+	for i := 0; i < 3; i++ {
+		op, err := operation.NewKVOperation(key.NewBasePrivatekey(), util.UUID().Bytes(), util.UUID().String(), util.UUID().Bytes(), nil)
+		t.NoError(err)
+
+		ops = append(ops, op)
+		hs = append(hs, op.Fact().Hash())
+	}
+
+	qn.SetGetStagedOperationsHandler(func([]valuehash.Hash) ([]operation.Operation, error) {
+		return ops, nil
+	})
+
+	for _, enc := range []encoder.Encoder{t.enc, be} {
+		qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+		t.NoError(err)
+		qc.SetConfig(ChannelConfig{WireEncoder: enc, CompactWire: true})
+
+		l, err := qc.StagedOperations(context.TODO(), hs)
+		t.NoError(err)
+		t.Equal(len(ops), len(l))
+
+		for i := range ops {
+			t.True(ops[i].Hash().Equal(l[i].Hash()))
+			t.True(ops[i].Fact().Hash().Equal(l[i].Fact().Hash()))
+		}
+
+		// NOTE response is formatted by accepted wire format
+		b, err := t.enc.Marshal(NewHashesArgs(hs))
+		t.NoError(err)
+
+		headers := http.Header{}
+		headers.Set(QuicEncoderHintHeader, t.enc.Hint().String())
+		qc.setAcceptWireHeaders(headers)
+
+		res, err := qc.client.Send(context.TODO(), time.Second*2, qc.getStagedOperationsURL, b, headers)
+		t.NoError(err)
+		defer res.Close()
+
+		t.NoError(res.Error())
+		t.Equal(enc.Hint().String(), res.Header.Get(QuicEncoderHintHeader))
+		t.Equal(WireFormatCompact, res.Header.Get(QuicWireFormatHeader))
+	}
+}
+
+func (t *testQuicServer) TestGetProposalCompact() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	fact := ballot.NewProposalFact(
+		base.Height(33),
+		base.Round(0),
+		base.RandomStringAddress(),
+		[]valuehash.Hash{valuehash.RandomSHA256(), valuehash.RandomSHA256()},
+	)
+	bvp := base.NewDummyVoteproof(fact.Height(), fact.Round(), base.StageINIT, base.VoteResultMajority)
+
+	pr, err := ballot.NewProposal(fact, fact.Proposer(), bvp, key.NewBasePrivatekey(), nil)
+	t.NoError(err)
+
+	qn.SetGetProposalHandler(func(valuehash.Hash) (base.Proposal, error) {
+		return pr, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetConfig(ChannelConfig{CompactWire: true})
+
+	upr, err := qc.Proposal(context.TODO(), fact.Hash())
+	t.NoError(err)
+	t.NoError(upr.IsValid(nil))
+
+	t.True(pr.Hash().Equal(upr.Hash()))
+	t.True(pr.Fact().Hash().Equal(upr.Fact().Hash()))
+}
+
+func (t *testQuicServer) TestBlockdataMapsCompact() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	bd := block.NewBaseBlockdataMap(block.TestBlockdataWriterHint, 33)
+	bd = bd.SetBlock(valuehash.RandomSHA256())
+
+	for _, k := range block.Blockdata {
+		bd, _ = bd.SetItem(block.NewBaseBlockdataMapItem(k, util.UUID().String(), "file://"+util.UUID().String()))
+	}
+	{
+		i, err := bd.UpdateHash()
+		t.NoError(err)
+		bd = i
+	}
+
+	qn.SetBlockdataMapsHandler(func([]base.Height) ([]block.BlockdataMap, error) {
+		return []block.BlockdataMap{bd}, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+	qc.SetConfig(ChannelConfig{CompactWire: true})
+
+	bds, err := qc.BlockdataMaps(context.TODO(), []base.Height{33, 34})
+	t.NoError(err)
+	t.Equal(1, len(bds))
+
+	block.CompareBlockdataMap(t.Assert(), bd, bds[0])
+}
+
 func (t *testQuicServer) TestNodeInfo() {
 	qn := t.readyServer()
 	defer qn.Stop()
@@ -397,6 +898,78 @@ func (t *testQuicServer) TestBlockdataMaps() {
 	block.CompareBlockdataMap(t.Assert(), bd, bds[0])
 }
 
+func (t *testQuicServer) TestBlockdataMapsPrunedHeight() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	qn.SetBlockdataMapsHandler(func(hs []base.Height) ([]block.BlockdataMap, error) {
+		return nil, network.PrunedHeightError.Errorf("heights lower than 40 were pruned")
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+
+	_, err = qc.BlockdataMaps(context.TODO(), []base.Height{33, 34})
+	t.True(errors.Is(err, network.PrunedHeightError))
+	t.Contains(err.Error(), "heights lower than 40 were pruned")
+}
+
+func (t *testQuicServer) TestGetState() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	key := util.UUID().String()
+
+	sts := map[base.Height]state.State{}
+	for _, i := range []base.Height{33, 35} {
+		v, err := state.NewBytesValue([]byte(i.String()))
+		t.NoError(err)
+
+		st, err := state.NewStateV0(key, v, i)
+		t.NoError(err)
+
+		sts[i], err = st.SetHash(st.GenerateHash())
+		t.NoError(err)
+	}
+
+	qn.SetGetStateHandler(func(k string, height base.Height) (state.State, bool, error) {
+		switch {
+		case k != key:
+			return nil, false, nil
+		case height == base.NilHeight || height >= 35:
+			return sts[35], true, nil
+		case height >= 33:
+			return sts[33], true, nil
+		case height >= 30:
+			return nil, false, nil
+		default:
+			return nil, false, network.PrunedHeightError.Errorf("states lower than 30 were pruned")
+		}
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+
+	for height, expected := range map[base.Height]base.Height{base.NilHeight: 35, 34: 33, 36: 35} {
+		st, found, err := qc.State(context.TODO(), key, height)
+		t.NoError(err)
+		t.True(found)
+		t.True(st.Hash().Equal(sts[expected].Hash()))
+		t.Equal(expected, st.Height())
+	}
+
+	_, found, err := qc.State(context.TODO(), key, base.Height(31))
+	t.NoError(err)
+	t.False(found)
+
+	_, found, err = qc.State(context.TODO(), util.UUID().String(), base.Height(34))
+	t.NoError(err)
+	t.False(found)
+
+	_, _, err = qc.State(context.TODO(), key, base.Height(29))
+	t.True(errors.Is(err, network.PrunedHeightError))
+}
+
 func (t *testQuicServer) TestEmptyBlockdata() {
 	qn := t.readyServer()
 	defer qn.Stop()
@@ -469,6 +1042,56 @@ func (t *testQuicServer) TestGetBlockdata() {
 	t.Equal(data, b)
 }
 
+func (t *testQuicServer) TestGetBlockdataRange() {
+	qn := t.readyServer()
+	defer qn.Stop()
+
+	data := []byte(util.UUID().String() + util.UUID().String())
+	checksum, err := util.GenerateChecksum(bytes.NewReader(data))
+	t.NoError(err)
+
+	qn.SetBlockdataByChecksumHandler(func(height base.Height, dataType, c string) (io.ReadSeekCloser, error) {
+		if height != base.Height(33) || dataType != "findme" || c != checksum {
+			return nil, util.NotFoundError.Errorf("not found")
+		}
+
+		return readSeekNopCloser{Reader: bytes.NewReader(data)}, nil
+	})
+
+	qc, err := NewChannel(t.connInfo, 2, nil, t.encs, t.enc)
+	t.NoError(err)
+
+	item := block.NewBaseBlockdataMapItem("findme", checksum, "file:///showme/findme")
+
+	for _, c := range [][2]int64{{0, 10}, {10, 20}, {60, 10}, {0, 0}, {30, 0}} {
+		r, size, err := qc.BlockdataRange(context.Background(), base.Height(33), item, c[0], c[1])
+		t.NoError(err)
+		t.Equal(int64(len(data)), size)
+
+		b, err := io.ReadAll(r)
+		t.NoError(err)
+		_ = r.Close()
+
+		end := int64(len(data))
+		if c[1] > 0 && c[0]+c[1] < end {
+			end = c[0] + c[1]
+		}
+
+		t.Equal(data[c[0]:end], b)
+	}
+
+	_, _, err = qc.BlockdataRange(context.Background(), base.Height(34), item, 0, 10)
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+type readSeekNopCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekNopCloser) Close() error {
+	return nil
+}
+
 func (t *testQuicServer) TestPassthroughs() {
 	qn := t.readyServer()
 	defer qn.Stop()
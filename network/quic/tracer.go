@@ -0,0 +1,108 @@
+package quicnetwork
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/lucas-clemente/quic-go/logging"
+)
+
+// clientPeersTracer removes the authenticated client from clientPeers when the
+// connection is closed.
+type clientPeersTracer struct {
+	peers *clientPeers
+}
+
+func (t clientPeersTracer) TracerForConnection(
+	_ context.Context, p logging.Perspective, _ logging.ConnectionID,
+) logging.ConnectionTracer {
+	if p != logging.PerspectiveServer {
+		return nil
+	}
+
+	return &clientPeersConnectionTracer{peers: t.peers}
+}
+
+func (clientPeersTracer) SentPacket(net.Addr, *logging.Header, logging.ByteCount, []logging.Frame) {}
+
+func (clientPeersTracer) DroppedPacket(net.Addr, logging.PacketType, logging.ByteCount, logging.PacketDropReason) {
+}
+
+type clientPeersConnectionTracer struct {
+	nilConnectionTracer
+	peers  *clientPeers
+	remote string
+}
+
+func (t *clientPeersConnectionTracer) StartedConnection(_, remote net.Addr, _, _ logging.ConnectionID) {
+	t.remote = remote.String()
+}
+
+func (t *clientPeersConnectionTracer) Close() {
+	if len(t.remote) > 0 {
+		t.peers.remove(t.remote)
+	}
+}
+
+// nilConnectionTracer implements logging.ConnectionTracer, which does nothing.
+type nilConnectionTracer struct{}
+
+func (nilConnectionTracer) StartedConnection(_, _ net.Addr, _, _ logging.ConnectionID) {}
+
+func (nilConnectionTracer) NegotiatedVersion(logging.VersionNumber, []logging.VersionNumber, []logging.VersionNumber) {
+}
+
+func (nilConnectionTracer) ClosedConnection(error) {}
+
+func (nilConnectionTracer) SentTransportParameters(*logging.TransportParameters) {}
+
+func (nilConnectionTracer) ReceivedTransportParameters(*logging.TransportParameters) {}
+
+func (nilConnectionTracer) RestoredTransportParameters(*logging.TransportParameters) {}
+
+func (nilConnectionTracer) SentPacket(*logging.ExtendedHeader, logging.ByteCount, *logging.AckFrame, []logging.Frame) {
+}
+
+func (nilConnectionTracer) ReceivedVersionNegotiationPacket(*logging.Header, []logging.VersionNumber) {
+}
+
+func (nilConnectionTracer) ReceivedRetry(*logging.Header) {}
+
+func (nilConnectionTracer) ReceivedPacket(*logging.ExtendedHeader, logging.ByteCount, []logging.Frame) {
+}
+
+func (nilConnectionTracer) BufferedPacket(logging.PacketType) {}
+
+func (nilConnectionTracer) DroppedPacket(logging.PacketType, logging.ByteCount, logging.PacketDropReason) {
+}
+
+func (nilConnectionTracer) UpdatedMetrics(*logging.RTTStats, logging.ByteCount, logging.ByteCount, int) {
+}
+
+func (nilConnectionTracer) AcknowledgedPacket(logging.EncryptionLevel, logging.PacketNumber) {}
+
+func (nilConnectionTracer) LostPacket(logging.EncryptionLevel, logging.PacketNumber, logging.PacketLossReason) {
+}
+
+func (nilConnectionTracer) UpdatedCongestionState(logging.CongestionState) {}
+
+func (nilConnectionTracer) UpdatedPTOCount(uint32) {}
+
+func (nilConnectionTracer) UpdatedKeyFromTLS(logging.EncryptionLevel, logging.Perspective) {}
+
+func (nilConnectionTracer) UpdatedKey(logging.KeyPhase, bool) {}
+
+func (nilConnectionTracer) DroppedEncryptionLevel(logging.EncryptionLevel) {}
+
+func (nilConnectionTracer) DroppedKey(logging.KeyPhase) {}
+
+func (nilConnectionTracer) SetLossTimer(logging.TimerType, logging.EncryptionLevel, time.Time) {}
+
+func (nilConnectionTracer) LossTimerExpired(logging.TimerType, logging.EncryptionLevel) {}
+
+func (nilConnectionTracer) LossTimerCanceled() {}
+
+func (nilConnectionTracer) Close() {}
+
+func (nilConnectionTracer) Debug(string, string) {}
@@ -0,0 +1,235 @@
+package quicnetwork
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
+	"github.com/spikeekips/mitum/util/hint"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// QuicAcceptEncoderHintHeader is the encoder, which the client prefers for
+	// the response body. If the server does not know it, the server responds
+	// with it's default encoder, so the client should decode the response by
+	// QuicEncoderHintHeader of response.
+	QuicAcceptEncoderHintHeader string = "X-MITUM-ACCEPT-ENCODER-HINT"
+	// QuicWireFormatHeader is the format of body. If empty, the body is
+	// encoded by the encoder of QuicEncoderHintHeader as it is.
+	QuicWireFormatHeader string = "X-MITUM-WIRE-FORMAT"
+	// QuicAcceptWireFormatHeader is the format, which the client prefers for
+	// the response body. If the server does not know it, the body is not
+	// formatted, so the client should check QuicWireFormatHeader of response.
+	QuicAcceptWireFormatHeader string = "X-MITUM-ACCEPT-WIRE-FORMAT"
+	// WireFormatCompact is the length prefixed frames of the encoded hinted
+	// instances, which are compressed by deflate.
+	WireFormatCompact string = "compact"
+)
+
+// MaxCompactWireSize limits the size of decompressed compact body.
+var MaxCompactWireSize int64 = 1 << 27
+
+var compactWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := flate.NewWriter(nil, flate.BestSpeed)
+
+		return w
+	},
+}
+
+type hintersBSON struct {
+	Hinters bson.RawValue `bson:"hinters"`
+}
+
+// AcceptEncoderFromHeader returns the encoder for the response body by
+// QuicAcceptEncoderHintHeader. If not found, returns the default encoder.
+func AcceptEncoderFromHeader(header http.Header, encs *encoder.Encoders, enc encoder.Encoder) encoder.Encoder {
+	s := header.Get(QuicAcceptEncoderHintHeader)
+	if len(s) < 1 {
+		return enc
+	}
+
+	ht, err := hint.ParseHint(s)
+	if err != nil {
+		return enc
+	}
+
+	i, err := encs.Encoder(ht.Type(), ht.Version())
+	if err != nil {
+		return enc
+	}
+
+	return i
+}
+
+// IsCompactWireFromHeader checks whether the body is formatted by
+// WireFormatCompact. The unknown format returns error.
+func IsCompactWireFromHeader(header http.Header) (bool, error) {
+	switch s := header.Get(QuicWireFormatHeader); s {
+	case "":
+		return false, nil
+	case WireFormatCompact:
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown wire format, %q", s)
+	}
+}
+
+// marshalHinters marshals the slice of hinted instances. bson does not allow
+// array at the top level, so the slice is wrapped by document.
+func marshalHinters(enc encoder.Encoder, v interface{}) ([]byte, error) {
+	if enc.Hint().Type() != bsonenc.BSONEncoderType {
+		return enc.Marshal(v)
+	}
+
+	return enc.Marshal(bson.M{"hinters": v})
+}
+
+func unmarshalHinters(enc encoder.Encoder, b []byte) ([]hint.Hinter, error) {
+	if enc.Hint().Type() != bsonenc.BSONEncoderType {
+		return enc.DecodeSlice(b)
+	}
+
+	var uh hintersBSON
+	if err := enc.Unmarshal(b, &uh); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal hinters")
+	}
+
+	if uh.Hinters.Type != bson.TypeArray {
+		return nil, nil
+	}
+
+	return enc.DecodeSlice(uh.Hinters.Value)
+}
+
+func marshalWireHinters(enc encoder.Encoder, compact bool, v interface{}) ([]byte, error) {
+	if compact {
+		return marshalCompact(enc, v)
+	}
+
+	return marshalHinters(enc, v)
+}
+
+func unmarshalWireHinters(enc encoder.Encoder, compact bool, b []byte) ([]hint.Hinter, error) {
+	if compact {
+		return unmarshalCompact(enc, b)
+	}
+
+	return unmarshalHinters(enc, b)
+}
+
+func marshalWire(enc encoder.Encoder, compact bool, v interface{}) ([]byte, error) {
+	if compact {
+		return marshalCompact(enc, v)
+	}
+
+	return enc.Marshal(v)
+}
+
+// decodeWire decodes the single hinted instance.
+func decodeWire(enc encoder.Encoder, compact bool, b []byte, target interface{}) error {
+	if !compact {
+		return encoder.Decode(b, enc, target)
+	}
+
+	switch hs, err := unmarshalCompact(enc, b); {
+	case err != nil:
+		return err
+	case len(hs) < 1:
+		return nil
+	case len(hs) > 1:
+		return errors.Errorf("expected one hinter, but %d", len(hs))
+	default:
+		return util.InterfaceSetValue(hs[0], target)
+	}
+}
+
+// marshalCompact marshals the hinted instance or each item of the slice of
+// hinted instances by enc, and writes them as the length prefixed frames
+// compressed by deflate. Unlike marshalHinters, the slice is not wrapped, so
+// the receiver decodes the items one by one.
+func marshalCompact(enc encoder.Encoder, v interface{}) ([]byte, error) {
+	var items []interface{}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	} else if v != nil {
+		items = []interface{}{v}
+	}
+
+	buf := &bytes.Buffer{}
+
+	w := compactWriterPool.Get().(*flate.Writer)
+	defer compactWriterPool.Put(w)
+
+	w.Reset(buf)
+
+	var l [binary.MaxVarintLen64]byte
+	for i := range items {
+		b, err := enc.Marshal(items[i])
+		if err != nil {
+			return nil, err
+		}
+
+		n := binary.PutUvarint(l[:], uint64(len(b)))
+		if _, err := w.Write(l[:n]); err != nil {
+			return nil, err
+		}
+
+		if _, err := w.Write(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unmarshalCompact(enc encoder.Encoder, b []byte) ([]hint.Hinter, error) {
+	r := flate.NewReader(bytes.NewReader(b))
+	defer func() {
+		_ = r.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(r, MaxCompactWireSize+1))
+	switch {
+	case err != nil:
+		return nil, errors.Wrap(err, "failed to decompress compact body")
+	case int64(len(body)) > MaxCompactWireSize:
+		return nil, errors.Errorf("too large compact body, > %d", MaxCompactWireSize)
+	}
+
+	var hs []hint.Hinter
+	for len(body) > 0 {
+		l, n := binary.Uvarint(body)
+		if n < 1 || l > uint64(len(body)-n) {
+			return nil, errors.Errorf("invalid frame of compact body")
+		}
+
+		body = body[n:]
+
+		h, err := enc.Decode(body[:l])
+		if err != nil {
+			return nil, err
+		}
+		hs = append(hs, h)
+
+		body = body[l:]
+	}
+
+	return hs, nil
+}
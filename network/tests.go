@@ -27,6 +27,7 @@ func CompareNodeInfo(t *testing.T, a, b NodeInfo) {
 	assert.True(t, a.LastBlock().StatesHash().Equal(b.LastBlock().StatesHash()))
 
 	assert.Equal(t, a.Policy(), b.Policy())
+	assert.Equal(t, a.PrunedHeight(), b.PrunedHeight())
 
 	as := a.Nodes()
 	bs := b.Nodes()
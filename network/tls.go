@@ -0,0 +1,99 @@
+package network
+
+import (
+	"crypto/ed25519"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/util"
+)
+
+// NodeCertificateExtensionOID is the object identifier of the x509 extension,
+// which keeps the node address and signature in the node certificate.
+var NodeCertificateExtensionOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 58209, 1, 1}
+
+var UnknownNodeCertificateError = util.NewError("unknown node certificate")
+
+type nodeCertificateExtension struct {
+	Address   string
+	Signature []byte
+}
+
+// GenerateNodeTLSCerts generates the self-signed certificate of the local
+// node. The certificate has the node address and the signature of the
+// certificate publickey, which is signed by the node privatekey, so the other
+// nodes can authenticate it with the node publickey.
+func GenerateNodeTLSCerts(host string, local node.Local, networkID base.NetworkID) ([]tls.Certificate, error) {
+	priv, err := util.GenerateED25519Privatekey()
+	if err != nil {
+		return nil, err
+	}
+
+	spki, err := x509.MarshalPKIXPublicKey(priv.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := local.Privatekey().Sign(util.ConcatBytesSlice(spki, networkID))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign node certificate")
+	}
+
+	b, err := asn1.Marshal(nodeCertificateExtension{
+		Address:   local.Address().String(),
+		Signature: sig,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return util.GenerateTLSCerts(host, priv, pkix.Extension{Id: NodeCertificateExtensionOID, Value: b})
+}
+
+// NodeFromCertificate finds the node of the node certificate in Nodepool. The
+// signature in certificate should be verified by the publickey of the node.
+func NodeFromCertificate(cert *x509.Certificate, nodepool *Nodepool, networkID base.NetworkID) (base.Node, error) {
+	var ext nodeCertificateExtension
+	for i := range cert.Extensions {
+		e := cert.Extensions[i]
+		if !e.Id.Equal(NodeCertificateExtensionOID) {
+			continue
+		}
+
+		switch rest, err := asn1.Unmarshal(e.Value, &ext); {
+		case err != nil:
+			return nil, UnknownNodeCertificateError.Wrap(err)
+		case len(rest) > 0:
+			return nil, UnknownNodeCertificateError.Errorf("trailing data in node certificate extension")
+		}
+
+		break
+	}
+
+	if len(ext.Address) < 1 {
+		return nil, UnknownNodeCertificateError.Errorf("not node certificate")
+	}
+
+	nodepool.RLock()
+	no, found := nodepool.nodes[ext.Address]
+	nodepool.RUnlock()
+
+	if !found {
+		return nil, UnknownNodeCertificateError.Errorf("node, %q not in nodepool", ext.Address)
+	}
+
+	if err := no.Publickey().Verify(
+		util.ConcatBytesSlice(cert.RawSubjectPublicKeyInfo, networkID),
+		key.Signature(ext.Signature),
+	); err != nil {
+		return nil, UnknownNodeCertificateError.Wrap(errors.Wrapf(err, "wrong signature of node, %q", ext.Address))
+	}
+
+	return no, nil
+}
@@ -0,0 +1,99 @@
+// +build test
+
+package network
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testNodeTLSCerts struct {
+	suite.Suite
+	local     node.Local
+	networkID base.NetworkID
+}
+
+func (t *testNodeTLSCerts) SetupSuite() {
+	t.local = node.RandomLocal("local")
+	t.networkID = base.NetworkID(util.UUID().Bytes())
+}
+
+func (t *testNodeTLSCerts) certificate(no node.Local, networkID base.NetworkID) *x509.Certificate {
+	certs, err := GenerateNodeTLSCerts("localhost", no, networkID)
+	t.NoError(err)
+	t.Equal(1, len(certs))
+
+	cert, err := x509.ParseCertificate(certs[0].Certificate[0])
+	t.NoError(err)
+
+	return cert
+}
+
+func (t *testNodeTLSCerts) TestNodeFromCertificate() {
+	n0 := node.RandomLocal("n0")
+
+	np := NewNodepool(t.local, nil)
+	t.NoError(np.Add(n0, nil))
+
+	no, err := NodeFromCertificate(t.certificate(n0, t.networkID), np, t.networkID)
+	t.NoError(err)
+	t.True(n0.Address().Equal(no.Address()))
+}
+
+func (t *testNodeTLSCerts) TestUnknownNode() {
+	np := NewNodepool(t.local, nil)
+
+	_, err := NodeFromCertificate(t.certificate(node.RandomLocal("n0"), t.networkID), np, t.networkID)
+	t.True(errors.Is(err, UnknownNodeCertificateError))
+	t.Contains(err.Error(), "not in nodepool")
+}
+
+func (t *testNodeTLSCerts) TestWrongPublickey() {
+	n0 := node.RandomLocal("n0")
+
+	np := NewNodepool(t.local, nil)
+	t.NoError(np.Add(n0, nil))
+
+	// NOTE same address, but different privatekey
+	other := node.NewLocal(n0.Address(), node.RandomLocal("n0").Privatekey())
+
+	_, err := NodeFromCertificate(t.certificate(other, t.networkID), np, t.networkID)
+	t.True(errors.Is(err, UnknownNodeCertificateError))
+	t.Contains(err.Error(), "wrong signature")
+}
+
+func (t *testNodeTLSCerts) TestWrongNetworkID() {
+	n0 := node.RandomLocal("n0")
+
+	np := NewNodepool(t.local, nil)
+	t.NoError(np.Add(n0, nil))
+
+	_, err := NodeFromCertificate(t.certificate(n0, base.NetworkID(util.UUID().Bytes())), np, t.networkID)
+	t.True(errors.Is(err, UnknownNodeCertificateError))
+	t.Contains(err.Error(), "wrong signature")
+}
+
+func (t *testNodeTLSCerts) TestNotNodeCertificate() {
+	priv, err := util.GenerateED25519Privatekey()
+	t.NoError(err)
+
+	certs, err := util.GenerateTLSCerts("localhost", priv)
+	t.NoError(err)
+
+	cert, err := x509.ParseCertificate(certs[0].Certificate[0])
+	t.NoError(err)
+
+	_, err = NodeFromCertificate(cert, NewNodepool(t.local, nil), t.networkID)
+	t.True(errors.Is(err, UnknownNodeCertificateError))
+	t.Contains(err.Error(), "not node certificate")
+}
+
+func TestNodeTLSCerts(t *testing.T) {
+	suite.Run(t, new(testNodeTLSCerts))
+}
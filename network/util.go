@@ -53,6 +53,20 @@ func ParseURL(s string, allowEmpty bool) (*url.URL, error) { // nolint:unparam
 	return url.Parse(s)
 }
 
+// TCPURLScheme is the url scheme of the node, which serves the network
+// handlers by HTTP/2 over TCP instead of QUIC.
+const TCPURLScheme = "https+tcp"
+
+// IsTCPURL checks whether the node of url is reached by TCP.
+func IsTCPURL(u *url.URL) bool {
+	return u != nil && u.Scheme == TCPURLScheme
+}
+
+// IsNodeURLScheme checks whether the scheme is allowed for the node url.
+func IsNodeURLScheme(s string) bool {
+	return s == "https" || s == TCPURLScheme
+}
+
 func NormalizeURLString(s string) (*url.URL, error) {
 	u, err := ParseURL(s, false)
 	if err != nil {
@@ -88,7 +102,7 @@ func NormalizeURL(u *url.URL) *url.URL {
 	port := uu.Port()
 	if port == "" {
 		switch uu.Scheme {
-		case "https":
+		case "https", TCPURLScheme:
 			port = "443"
 		case "http":
 			port = "80"
@@ -22,6 +22,7 @@ func (t *testURL) TestNormalizeURL() {
 		{name: "full", s: "https://findme:334/show/me?a=b#f", expected: "https://findme:334/show/me?a=b#f"},
 		{name: "full, empty https port", s: "https://findme/show/me?a=b#f", expected: "https://findme:443/show/me?a=b#f"},
 		{name: "full, empty http port", s: "http://findme/show/me?a=b#f", expected: "http://findme:80/show/me?a=b#f"},
+		{name: "full, empty https+tcp port", s: "https+tcp://findme/show/me?a=b#f", expected: "https+tcp://findme:443/show/me?a=b#f"},
 		{name: "full, empty unknown port", s: "what://findme/show/me?a=b#f", expected: "what://findme:0/show/me?a=b#f"},
 		{name: "/ path", s: "https://findme/", expected: "https://findme:443"},
 		{name: "blank path", s: "https://findme", expected: "https://findme:443"},
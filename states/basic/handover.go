@@ -13,6 +13,7 @@ import (
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
@@ -144,6 +145,7 @@ type Handover struct {
 	checkDuplicatedNodeFunc                func() (network.Channel, network.NodeInfo, error)
 	st                                     *handoverState
 	rchs                                   map[string]network.Channel
+	chConf                                 quicnetwork.ChannelConfig
 	intervalKeepVerifyDuplicatedNode       time.Duration
 	maxFailedCountKeepVerifyDuplicatedNode uint
 	intervalPingHandover                   time.Duration
@@ -156,12 +158,13 @@ func NewHandoverWithDiscoveryURL(
 	nodepool *network.Nodepool,
 	suffrage base.Suffrage,
 	cis []network.ConnInfo,
+	chConf quicnetwork.ChannelConfig,
 ) (*Handover, error) {
 	rchs := map[string]network.Channel{}
 	for i := range cis {
 		ci := cis[i]
 
-		ch, err := discovery.LoadNodeChannel(ci, encs, policy.NetworkConnectionTimeout())
+		ch, err := discovery.LoadNodeChannel(ci, encs, policy.NetworkConnectionTimeout(), chConf)
 		if err != nil {
 			return nil, err
 		}
@@ -171,6 +174,7 @@ func NewHandoverWithDiscoveryURL(
 
 	hd := NewHandover(localci, encs, policy, nodepool, suffrage)
 	hd.rchs = rchs
+	hd.chConf = chConf
 
 	return hd, nil
 }
@@ -488,7 +492,7 @@ func (hd *Handover) defaultCheckDuplicatedNode() (network.Channel, network.NodeI
 
 func (hd *Handover) findDuplicatedNodeFromNodeInfo(ctx context.Context, ni network.NodeInfo) (network.Channel, bool) {
 	if ni.Address().Equal(hd.nodepool.LocalNode().Address()) {
-		ch, err := discovery.LoadNodeChannel(ni.ConnInfo(), hd.encs, hd.policy.NetworkConnectionTimeout())
+		ch, err := hd.loadChannel(ni.ConnInfo())
 		if err != nil {
 			return nil, false
 		}
@@ -518,7 +522,7 @@ func (hd *Handover) findDuplicatedNodeFromNodeInfo(ctx context.Context, ni netwo
 		return nil, false
 	}
 
-	ch, err := discovery.LoadNodeChannel(dup, hd.encs, hd.policy.NetworkConnectionTimeout())
+	ch, err := hd.loadChannel(dup)
 	if err != nil {
 		return nil, false
 	}
@@ -658,7 +662,7 @@ func (hd *Handover) updateNodes(nodes []network.RemoteNode) error {
 			continue
 		}
 
-		ch, err := discovery.LoadNodeChannel(no.ConnInfo(), hd.encs, hd.policy.NetworkConnectionTimeout())
+		ch, err := hd.loadChannel(no.ConnInfo())
 		if err != nil {
 			return fmt.Errorf("failed to load channel from nodeinfo: %w", err)
 		}
@@ -740,7 +744,7 @@ func (hd *Handover) addRemoteChannels(chs []network.Channel) (bool, error) {
 }
 
 func (hd *Handover) loadChannel(ci network.ConnInfo) (network.Channel, error) {
-	return discovery.LoadNodeChannel(ci, hd.encs, hd.policy.NetworkConnectionTimeout())
+	return discovery.LoadNodeChannel(ci, hd.encs, hd.policy.NetworkConnectionTimeout(), hd.chConf)
 }
 
 func (hd *Handover) whenFound(ctx context.Context, ch network.Channel, ni network.NodeInfo) error {
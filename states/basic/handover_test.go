@@ -11,6 +11,7 @@ import (
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	channetwork "github.com/spikeekips/mitum/network/gochan"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/util"
 	"github.com/stretchr/testify/suite"
 )
@@ -37,6 +38,7 @@ func (t *testHandover) TestEmptyDiscoveryURL() {
 		t.local.Nodes(),
 		t.Suffrage(t.local),
 		nil,
+		quicnetwork.ChannelConfig{},
 	)
 	t.NoError(err)
 }
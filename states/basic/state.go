@@ -182,7 +182,9 @@ func (st *BaseState) syncableChannels() map[string]network.Channel {
 
 	pn := map[string]network.Channel{}
 	st.States.nodepool.TraverseAliveRemotes(func(no base.Node, ch network.Channel) bool {
-		pn[no.String()] = ch
+		if !st.States.nodepool.IsBanned(no.Address()) {
+			pn[no.String()] = ch
+		}
 
 		return true
 	})
@@ -361,9 +361,12 @@ func (st *SyncingState) syncFromVoteproof(voteproof base.Voteproof, to base.Heig
 	var sourceNodes []base.Node
 	for i := range voteproof.Votes() {
 		nf := voteproof.Votes()[i]
-		if n, _, found := st.nodepool.Node(nf.FactSign().Node()); !found {
+		switch n, _, found := st.nodepool.Node(nf.FactSign().Node()); {
+		case !found:
 			return errors.Errorf("node, %q in voteproof is not known node", nf.FactSign().Node())
-		} else if !n.Address().Equal(st.nodepool.LocalNode().Address()) {
+		case n.Address().Equal(st.nodepool.LocalNode().Address()):
+		case st.nodepool.IsBanned(n.Address()):
+		default:
 			sourceNodes = append(sourceNodes, n)
 		}
 	}
@@ -453,21 +456,19 @@ func (st *SyncingState) whenNewHeight(height base.Height) error {
 		return nil
 	}
 
-	n := st.nodepool.LenRemoteAlives()
-	if n < 1 {
-		return nil
-	}
-
-	sources := make([]base.Node, n)
-
-	var i int
+	var sources []base.Node
 	st.nodepool.TraverseAliveRemotes(func(no base.Node, _ network.Channel) bool {
-		sources[i] = no
-		i++
+		if !st.nodepool.IsBanned(no.Address()) {
+			sources = append(sources, no)
+		}
 
 		return true
 	})
 
+	if len(sources) < 1 {
+		return nil
+	}
+
 	if _, err := st.syncs.Add(height, sources); err != nil {
 		st.Log().Error().Err(err).Int64("height", height.Int64()).Msg("failed to add syncers")
 
@@ -515,7 +516,9 @@ func (st *SyncingState) syncableChannelsOfNodepool() map[string]network.Channel
 	pn := map[string]network.Channel{}
 
 	st.nodepool.TraverseAliveRemotes(func(no base.Node, ch network.Channel) bool {
-		pn[no.String()] = ch
+		if !st.nodepool.IsBanned(no.Address()) {
+			pn[no.String()] = ch
+		}
 
 		return true
 	})
@@ -19,8 +19,10 @@ import (
 	"github.com/spikeekips/mitum/states"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/isvalid"
 	"github.com/spikeekips/mitum/util/localtime"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/metrics"
 )
 
 const (
@@ -50,6 +52,7 @@ type States struct {
 	lvp                base.Voteproof
 	livp               base.Voteproof
 	blockSavedHook     *pm.Hooks
+	stateSwitchedHook  *pm.Hooks
 	isNoneSuffrageNode bool
 	hd                 *Handover
 	dis                *states.DiscoveryJoiner
@@ -87,9 +90,10 @@ func NewStates( // revive:disable-line:argument-limit
 			TimerIDSyncingWaitVoteproof,
 			TimerIDFindProposal,
 		}, false),
-		blockSavedHook: pm.NewHooks("block-saved"),
-		dis:            dis,
-		hd:             hd,
+		blockSavedHook:    pm.NewHooks("block-saved"),
+		stateSwitchedHook: pm.NewHooks("state-switched"),
+		dis:               dis,
+		hd:                hd,
 	}
 
 	sts := map[base.State]State{
@@ -220,6 +224,9 @@ func (ss *States) SetLastVoteproof(voteproof base.Voteproof) bool {
 		ss.livp = voteproof
 	}
 
+	metrics.ConsensusHeight.Set(float64(voteproof.Height().Int64()))
+	metrics.ConsensusRound.Set(float64(voteproof.Round().Uint64()))
+
 	ss.Log().Debug().Str("voteproof_id", voteproof.ID()).Msg("last voteproof updated")
 
 	return true
@@ -231,6 +238,10 @@ func (ss *States) NewSeal(sl seal.Seal) error {
 	l.Debug().Dict("seal", LogSeal(sl)).Msg("seal received")
 	l.Trace().Interface("seal", sl).Msg("seal received")
 
+	if blt, ok := sl.(base.Ballot); ok {
+		metrics.Ballots.WithLabelValues(blt.RawFact().Stage().String()).Inc()
+	}
+
 	var err error
 	switch t := sl.(type) {
 	case base.Proposal:
@@ -281,10 +292,16 @@ func (ss *States) BlockSavedHook() *pm.Hooks {
 	return ss.blockSavedHook
 }
 
+func (ss *States) StateSwitchedHook() *pm.Hooks {
+	return ss.stateSwitchedHook
+}
+
 func (ss *States) setState(state base.State) {
 	ss.statelock.Lock()
 	defer ss.statelock.Unlock()
 
+	metrics.SetConsensusState(ss.state.String(), state.String())
+
 	ss.state = state
 }
 
@@ -449,6 +466,13 @@ func (ss *States) switchState(sctx StateSwitchContext) error {
 
 	l.Debug().Msg("state switched")
 
+	if err == nil {
+		ctx := context.WithValue(context.Background(), ContextValueStateSwitchContext, sctx)
+		if herr := ss.stateSwitchedHook.Run(ctx); herr != nil {
+			l.Error().Err(herr).Msg("failed to run state switched hook")
+		}
+	}
+
 	return err
 }
 
@@ -524,6 +548,8 @@ func (ss *States) processVoteproofInternal(voteproof base.Voteproof) error {
 	l := ss.Log().With().Str("voteproof_id", voteproof.ID()).Logger()
 	l.Debug().Object("voteproof", voteproof).Msg("new voteproof")
 
+	metrics.Voteproofs.WithLabelValues(voteproof.Stage().String(), voteproof.Result().String()).Inc()
+
 	vc := NewVoteproofChecker(ss.database, ss.suffrage, ss.nodepool, ss.LastVoteproof(), voteproof)
 	_ = vc.SetLogging(ss.Logging)
 
@@ -587,6 +613,8 @@ func (ss *States) processProposal(proposal base.Proposal) error {
 	}).Check(); err != nil {
 		l.Error().Err(err).Msg("propossal validation failed")
 
+		ss.penalizeProposer(proposal, err)
+
 		return util.IgnoreError.Wrap(err)
 	}
 
@@ -684,6 +712,8 @@ func (ss *States) validateProposal(proposal base.Proposal) error {
 		case errors.Is(err, isaac.KnownSealError):
 		case errors.Is(err, util.IgnoreError):
 		default:
+			ss.penalizeProposer(proposal, err)
+
 			return err
 		}
 
@@ -692,6 +722,24 @@ func (ss *States) validateProposal(proposal base.Proposal) error {
 	return nil
 }
 
+// penalizeProposer penalizes the proposer of invalid proposal. The proposer is
+// penalized only when the proposal is signed by the proposer; the proposal
+// signed by the others can not blame the proposer.
+func (ss *States) penalizeProposer(proposal base.Proposal, err error) {
+	if !errors.Is(err, isvalid.InvalidError) {
+		return
+	}
+
+	proposer := proposal.Fact().Proposer()
+	if n, _, found := ss.nodepool.Node(proposer); !found || !proposal.FactSign().Signer().Equal(n.Publickey()) {
+		return
+	}
+
+	if ss.nodepool.Penalize(proposer, network.PeerPenaltyInvalidProposal, err.Error()) {
+		ss.Log().Warn().Stringer("proposer", proposer).Err(err).Msg("proposer banned by invalid proposal")
+	}
+}
+
 func (ss *States) validateBallot(blt base.Ballot) error {
 	bc := NewBallotChecker(blt, ss.LastVoteproof())
 	_ = bc.SetLogging(ss.Logging)
@@ -842,6 +890,12 @@ func (ss *States) detectStuck(ctx context.Context) {
 			}
 
 			if changed {
+				if stucked {
+					metrics.ConsensusStuck.Set(1)
+				} else {
+					metrics.ConsensusStuck.Set(0)
+				}
+
 				l := ss.Log().With().Stringer("state", state).Dur("endure", endure).Object("last_voteproof", lvp).Logger()
 				if stucked {
 					l.Error().Err(err).Msg("consensus stuck")
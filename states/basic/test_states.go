@@ -13,6 +13,7 @@ import (
 	"github.com/spikeekips/mitum/isaac"
 	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/network/discovery"
+	quicnetwork "github.com/spikeekips/mitum/network/quic"
 	"github.com/spikeekips/mitum/util/localtime"
 )
 
@@ -111,7 +112,7 @@ func (t *baseTestState) newChannel(s string) network.Channel {
 	ci, err := network.NewHTTPConnInfoFromString(s, true)
 	t.NoError(err)
 
-	ch, err := discovery.LoadNodeChannel(ci, t.Encs, time.Second*2)
+	ch, err := discovery.LoadNodeChannel(ci, t.Encs, time.Second*2, quicnetwork.ChannelConfig{})
 	t.NoError(err)
 
 	return ch
@@ -13,6 +13,7 @@ type States interface {
 	State() base.State
 	NewSeal(seal.Seal) error
 	BlockSavedHook() *pm.Hooks
+	StateSwitchedHook() *pm.Hooks
 	LastVoteproof() base.Voteproof
 	LastINITVoteproof() base.Voteproof
 	Handover() Handover
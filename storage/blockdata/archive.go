@@ -0,0 +1,463 @@
+package blockdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+)
+
+// ArchiveMagic marks the start and the end of block data archive. The last
+// digit is the version of archive format.
+var ArchiveMagic = []byte("MTBDARC1")
+
+var archiveFooterSize = int64(len(ArchiveMagic) + 16)
+
+// ArchiveIndex is the index of block data archive. The items of blocks are
+// sorted by height and the order of block.Blockdata.
+type ArchiveIndex struct {
+	Writer hint.Hint      `json:"writer"`
+	From   base.Height    `json:"from"`
+	To     base.Height    `json:"to"`
+	Blocks []ArchiveBlock `json:"blocks"`
+}
+
+type ArchiveBlock struct {
+	Height base.Height   `json:"height"`
+	Items  []ArchiveItem `json:"items"`
+}
+
+// ArchiveItem points the compressed block data item in archive. Checksum is
+// the checksum of the compressed bytes like block.BlockdataMapItem.
+type ArchiveItem struct {
+	Type     string `json:"type"`
+	Offset   int64  `json:"offset"`
+	Length   int64  `json:"length"`
+	Checksum string `json:"checksum"`
+}
+
+// ArchiveWriter packs the blocks of contiguous heights into one file. The
+// layout is,
+//
+//	<ArchiveMagic>
+//	<gzipped items of blocks>
+//	<gzipped json of ArchiveIndex>
+//	<offset of index: uint64><length of index: uint64><ArchiveMagic>
+//
+// The items are written by Writer, so ArchiveReader should have the
+// compatible Writer.
+type ArchiveWriter struct {
+	w      io.Writer
+	writer Writer
+	offset int64
+	index  ArchiveIndex
+	closed bool
+}
+
+func NewArchiveWriter(w io.Writer, writer Writer) (*ArchiveWriter, error) {
+	aw := &ArchiveWriter{
+		w:      w,
+		writer: writer,
+		index: ArchiveIndex{
+			Writer: writer.Hint(),
+			From:   base.NilHeight,
+			To:     base.NilHeight,
+		},
+	}
+
+	if err := aw.write(ArchiveMagic); err != nil {
+		return nil, err
+	}
+
+	return aw, nil
+}
+
+// Add appends block. The height of block should be the next of the last
+// added block.
+func (aw *ArchiveWriter) Add(blk block.Block) error {
+	if aw.closed {
+		return errors.Errorf("archive already closed")
+	}
+
+	if aw.index.To > base.NilHeight && blk.Height() != aw.index.To+1 {
+		return errors.Errorf("not contiguous height, %d; last is %d", blk.Height(), aw.index.To)
+	}
+
+	ab := ArchiveBlock{Height: blk.Height(), Items: make([]ArchiveItem, len(block.Blockdata))}
+
+	for i := range block.Blockdata {
+		dataType := block.Blockdata[i]
+
+		var buf bytes.Buffer
+		gw := util.NewGzipWriter(&buf)
+		if err := writeBlockdataItem(aw.writer, gw, blk, dataType); err != nil {
+			return errors.Wrapf(err, "failed to write %q of block, %d", dataType, blk.Height())
+		}
+
+		if err := gw.Close(); err != nil {
+			return err
+		}
+
+		checksum, err := util.GenerateChecksum(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			return err
+		}
+
+		ab.Items[i] = ArchiveItem{
+			Type:     dataType,
+			Offset:   aw.offset,
+			Length:   int64(buf.Len()),
+			Checksum: checksum,
+		}
+
+		if err := aw.write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if aw.index.From == base.NilHeight {
+		aw.index.From = blk.Height()
+	}
+	aw.index.To = blk.Height()
+	aw.index.Blocks = append(aw.index.Blocks, ab)
+
+	return nil
+}
+
+func (aw *ArchiveWriter) Index() ArchiveIndex {
+	return aw.index
+}
+
+// Close writes the index and footer. Close does not close the underlying
+// io.Writer.
+func (aw *ArchiveWriter) Close() error {
+	if aw.closed {
+		return nil
+	}
+
+	if len(aw.index.Blocks) < 1 {
+		return errors.Errorf("empty archive")
+	}
+
+	b, err := jsonenc.Marshal(aw.index)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	gw := util.NewGzipWriter(&buf)
+	if _, err := gw.Write(b); err != nil {
+		return err
+	}
+
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	footer := make([]byte, archiveFooterSize)
+	binary.BigEndian.PutUint64(footer[:8], uint64(aw.offset))
+	binary.BigEndian.PutUint64(footer[8:16], uint64(buf.Len()))
+	copy(footer[16:], ArchiveMagic)
+
+	if err := aw.write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	if err := aw.write(footer); err != nil {
+		return err
+	}
+
+	aw.closed = true
+
+	return nil
+}
+
+func (aw *ArchiveWriter) write(b []byte) error {
+	n, err := aw.w.Write(b)
+	aw.offset += int64(n)
+
+	return err
+}
+
+// ArchiveReader reads the blocks from archive. Only the index is loaded at
+// opening and each item is read when it is requested.
+type ArchiveReader struct {
+	r      io.ReaderAt
+	writer Writer
+	index  ArchiveIndex
+}
+
+func NewArchiveReader(r io.ReaderAt, size int64, writer Writer) (*ArchiveReader, error) {
+	index, err := readArchiveIndex(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writer.Hint().IsCompatible(index.Writer); err != nil {
+		return nil, errors.Wrap(err, "writer of archive is not compatible")
+	}
+
+	return &ArchiveReader{r: r, writer: writer, index: index}, nil
+}
+
+func (ar *ArchiveReader) Index() ArchiveIndex {
+	return ar.index
+}
+
+// From returns the lowest height in archive.
+func (ar *ArchiveReader) From() base.Height {
+	return ar.index.From
+}
+
+// To returns the highest height in archive.
+func (ar *ArchiveReader) To() base.Height {
+	return ar.index.To
+}
+
+// Open returns the decompressed block data item after checking checksum.
+func (ar *ArchiveReader) Open(height base.Height, dataType string) (io.ReadCloser, error) {
+	item, err := ar.item(height, dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, item.Length)
+	if _, err := ar.r.ReadAt(b, item.Offset); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %q of block, %d", dataType, height)
+	}
+
+	switch i, err := util.GenerateChecksum(bytes.NewReader(b)); {
+	case err != nil:
+		return nil, err
+	case i != item.Checksum:
+		return nil, errors.Errorf(
+			"block data, %q of block, %d checksum does not match; %q != %q", dataType, height, item.Checksum, i)
+	}
+
+	return util.NewGzipReader(bytes.NewReader(b))
+}
+
+// Block loads block by height.
+func (ar *ArchiveReader) Block(height base.Height) (block.Block, error) {
+	blk := (interface{})(block.EmptyBlockV0()).(block.BlockUpdater)
+
+	for i := range block.Blockdata {
+		dataType := block.Blockdata[i]
+
+		if err := func() error {
+			r, err := ar.Open(height, dataType)
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				_ = r.Close()
+			}()
+
+			j, err := readBlockdataItem(ar.writer, r, blk, dataType)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %q of block, %d", dataType, height)
+			}
+			blk = j
+
+			return nil
+		}(); err != nil {
+			return nil, err
+		}
+	}
+
+	return blk, nil
+}
+
+func (ar *ArchiveReader) item(height base.Height, dataType string) (ArchiveItem, error) {
+	if height < ar.index.From || height > ar.index.To {
+		return ArchiveItem{}, util.NotFoundError.Errorf("block, %d not in archive", height)
+	}
+
+	ab := ar.index.Blocks[(height - ar.index.From).Int64()]
+	for i := range ab.Items {
+		if ab.Items[i].Type == dataType {
+			return ab.Items[i], nil
+		}
+	}
+
+	return ArchiveItem{}, util.NotFoundError.Errorf("block data, %q of block, %d not in archive", dataType, height)
+}
+
+func readArchiveIndex(r io.ReaderAt, size int64) (ArchiveIndex, error) {
+	var index ArchiveIndex
+
+	if size < int64(len(ArchiveMagic))+archiveFooterSize {
+		return index, errors.Errorf("too small for archive")
+	}
+
+	header := make([]byte, len(ArchiveMagic))
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return index, errors.Wrap(err, "failed to read archive header")
+	} else if !bytes.Equal(header, ArchiveMagic) {
+		return index, errors.Errorf("not block data archive")
+	}
+
+	footer := make([]byte, archiveFooterSize)
+	if _, err := r.ReadAt(footer, size-archiveFooterSize); err != nil {
+		return index, errors.Wrap(err, "failed to read archive footer")
+	} else if !bytes.Equal(footer[16:], ArchiveMagic) {
+		return index, errors.Errorf("invalid archive footer")
+	}
+
+	offset := int64(binary.BigEndian.Uint64(footer[:8]))
+	length := int64(binary.BigEndian.Uint64(footer[8:16]))
+	if offset < int64(len(ArchiveMagic)) || length < 1 || offset+length != size-archiveFooterSize {
+		return index, errors.Errorf("invalid archive index position")
+	}
+
+	gr, err := util.NewGzipReader(io.NewSectionReader(r, offset, length))
+	if err != nil {
+		return index, errors.Wrap(err, "failed to read archive index")
+	}
+
+	defer func() {
+		_ = gr.Close()
+	}()
+
+	b, err := io.ReadAll(gr)
+	if err != nil {
+		return index, errors.Wrap(err, "failed to read archive index")
+	}
+
+	if err := jsonenc.Unmarshal(b, &index); err != nil {
+		return index, errors.Wrap(err, "failed to decode archive index")
+	}
+
+	if err := checkArchiveIndex(index); err != nil {
+		return index, err
+	}
+
+	return index, nil
+}
+
+func checkArchiveIndex(index ArchiveIndex) error {
+	if len(index.Blocks) < 1 {
+		return errors.Errorf("empty archive index")
+	}
+
+	if !sort.SliceIsSorted(index.Blocks, func(i, j int) bool {
+		return index.Blocks[i].Height < index.Blocks[j].Height
+	}) {
+		return errors.Errorf("blocks of archive index not sorted")
+	}
+
+	if index.Blocks[0].Height != index.From || index.Blocks[len(index.Blocks)-1].Height != index.To ||
+		(index.To-index.From).Int64()+1 != int64(len(index.Blocks)) {
+		return errors.Errorf("heights of archive index not contiguous")
+	}
+
+	return nil
+}
+
+func writeBlockdataItem(writer Writer, w io.Writer, blk block.Block, dataType string) error {
+	switch dataType {
+	case block.BlockdataManifest:
+		return writer.WriteManifest(w, blk.Manifest())
+	case block.BlockdataOperations:
+		return writer.WriteOperations(w, blk.Operations())
+	case block.BlockdataOperationsTree:
+		return writer.WriteOperationsTree(w, blk.OperationsTree())
+	case block.BlockdataStates:
+		return writer.WriteStates(w, blk.States())
+	case block.BlockdataStatesTree:
+		return writer.WriteStatesTree(w, blk.StatesTree())
+	case block.BlockdataINITVoteproof:
+		return writer.WriteINITVoteproof(w, blk.ConsensusInfo().INITVoteproof())
+	case block.BlockdataACCEPTVoteproof:
+		return writer.WriteACCEPTVoteproof(w, blk.ConsensusInfo().ACCEPTVoteproof())
+	case block.BlockdataSuffrageInfo:
+		return writer.WriteSuffrageInfo(w, blk.ConsensusInfo().SuffrageInfo())
+	case block.BlockdataProposal:
+		return writer.WriteProposal(w, blk.ConsensusInfo().Proposal())
+	default:
+		return errors.Errorf("unknown data type, %q", dataType)
+	}
+}
+
+func readBlockdataItem( // nolint:gocyclo
+	writer Writer,
+	r io.Reader,
+	blk block.BlockUpdater,
+	dataType string,
+) (block.BlockUpdater, error) {
+	switch dataType {
+	case block.BlockdataManifest:
+		i, err := writer.ReadManifest(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetManifest(i), nil
+	case block.BlockdataOperations:
+		i, err := writer.ReadOperations(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetOperations(i), nil
+	case block.BlockdataOperationsTree:
+		i, err := writer.ReadOperationsTree(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetOperationsTree(i), nil
+	case block.BlockdataStates:
+		i, err := writer.ReadStates(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetStates(i), nil
+	case block.BlockdataStatesTree:
+		i, err := writer.ReadStatesTree(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetStatesTree(i), nil
+	case block.BlockdataINITVoteproof:
+		i, err := writer.ReadINITVoteproof(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetINITVoteproof(i), nil
+	case block.BlockdataACCEPTVoteproof:
+		i, err := writer.ReadACCEPTVoteproof(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetACCEPTVoteproof(i), nil
+	case block.BlockdataSuffrageInfo:
+		i, err := writer.ReadSuffrageInfo(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetSuffrageInfo(i), nil
+	case block.BlockdataProposal:
+		i, err := writer.ReadProposal(r)
+		if err != nil {
+			return blk, err
+		}
+
+		return blk.SetProposal(i), nil
+	default:
+		return blk, errors.Errorf("unknown data type, %q", dataType)
+	}
+}
@@ -0,0 +1,168 @@
+// +build test
+
+package blockdata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/key"
+	"github.com/spikeekips/mitum/base/node"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/tree"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testArchive struct {
+	suite.Suite
+	JSONEnc *jsonenc.Encoder
+	writer  Writer
+}
+
+func (t *testArchive) SetupSuite() {
+	encs := encoder.NewEncoders()
+	t.JSONEnc = jsonenc.NewEncoder()
+	_ = encs.AddEncoder(t.JSONEnc)
+
+	_ = encs.TestAddHinter(base.StringAddressHinter)
+	_ = encs.TestAddHinter(block.BlockV0Hinter)
+	_ = encs.TestAddHinter(block.BlockConsensusInfoV0Hinter)
+	_ = encs.TestAddHinter(block.ManifestV0Hinter)
+	_ = encs.TestAddHinter(block.SuffrageInfoV0Hinter)
+	_ = encs.TestAddHinter(key.BasePublickey{})
+	_ = encs.TestAddHinter(node.BaseV0Hinter)
+	_ = encs.TestAddHinter(tree.FixedTreeHinter)
+
+	t.writer = NewDefaultWriter(t.JSONEnc)
+}
+
+func (t *testArchive) blocks(from base.Height, n int) []block.Block {
+	blks := make([]block.Block, n)
+
+	prev := valuehash.RandomSHA256()
+	for i := 0; i < n; i++ {
+		blk, err := block.NewTestBlockV0(from+base.Height(i), base.Round(0), valuehash.RandomSHA256(), prev)
+		t.NoError(err)
+
+		blks[i] = blk
+		prev = blk.Hash()
+	}
+
+	return blks
+}
+
+func (t *testArchive) archive(blks []block.Block) []byte {
+	var buf bytes.Buffer
+	aw, err := NewArchiveWriter(&buf, t.writer)
+	t.NoError(err)
+
+	for i := range blks {
+		t.NoError(aw.Add(blks[i]))
+	}
+	t.NoError(aw.Close())
+
+	return buf.Bytes()
+}
+
+func (t *testArchive) TestNew() {
+	blks := t.blocks(33, 3)
+	b := t.archive(blks)
+
+	ar, err := NewArchiveReader(bytes.NewReader(b), int64(len(b)), t.writer)
+	t.NoError(err)
+
+	t.Equal(base.Height(33), ar.From())
+	t.Equal(base.Height(35), ar.To())
+	t.True(t.writer.Hint().Equal(ar.Index().Writer))
+
+	for i := range blks {
+		blk, err := ar.Block(blks[i].Height())
+		t.NoError(err)
+
+		t.True(blks[i].Hash().Equal(blk.Hash()))
+		t.Equal(blks[i].Height(), blk.Height())
+		t.True(blks[i].PreviousBlock().Equal(blk.PreviousBlock()))
+		t.True(blks[i].ConsensusInfo().SuffrageInfo().Proposer().Equal(blk.ConsensusInfo().SuffrageInfo().Proposer()))
+	}
+}
+
+func (t *testArchive) TestNotContiguous() {
+	blks := t.blocks(33, 3)
+
+	var buf bytes.Buffer
+	aw, err := NewArchiveWriter(&buf, t.writer)
+	t.NoError(err)
+
+	t.NoError(aw.Add(blks[0]))
+	err = aw.Add(blks[2])
+	t.Error(err)
+	t.Contains(err.Error(), "not contiguous height")
+}
+
+func (t *testArchive) TestEmpty() {
+	var buf bytes.Buffer
+	aw, err := NewArchiveWriter(&buf, t.writer)
+	t.NoError(err)
+
+	err = aw.Close()
+	t.Error(err)
+	t.Contains(err.Error(), "empty archive")
+}
+
+func (t *testArchive) TestNotArchive() {
+	b := util.UUID().Bytes()
+	b = append(b, b...)
+
+	_, err := NewArchiveReader(bytes.NewReader(b), int64(len(b)), t.writer)
+	t.Error(err)
+	t.Contains(err.Error(), "not block data archive")
+
+	// NOTE truncated
+	b = t.archive(t.blocks(33, 1))
+	b = b[:len(b)-3]
+	_, err = NewArchiveReader(bytes.NewReader(b), int64(len(b)), t.writer)
+	t.Error(err)
+	t.Contains(err.Error(), "invalid archive footer")
+}
+
+func (t *testArchive) TestUnknownHeight() {
+	b := t.archive(t.blocks(33, 2))
+
+	ar, err := NewArchiveReader(bytes.NewReader(b), int64(len(b)), t.writer)
+	t.NoError(err)
+
+	_, err = ar.Block(35)
+	t.True(errors.Is(err, util.NotFoundError))
+
+	_, err = ar.Open(32, block.BlockdataManifest)
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+func (t *testArchive) TestWrongChecksum() {
+	b := t.archive(t.blocks(33, 2))
+
+	ar, err := NewArchiveReader(bytes.NewReader(b), int64(len(b)), t.writer)
+	t.NoError(err)
+
+	item, err := ar.item(34, block.BlockdataManifest)
+	t.NoError(err)
+
+	b[item.Offset+item.Length-1]++
+
+	_, err = ar.Block(34)
+	t.Error(err)
+	t.Contains(err.Error(), "checksum does not match")
+
+	_, err = ar.Block(33)
+	t.NoError(err)
+}
+
+func TestArchive(t *testing.T) {
+	suite.Run(t, new(testArchive))
+}
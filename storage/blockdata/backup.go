@@ -0,0 +1,394 @@
+package blockdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	// BackupManifestFile is the file name of BackupManifest in backup
+	// directory.
+	BackupManifestFile = "backup.json"
+	// BackupArchiveFile is the file name of block data archive in backup
+	// directory.
+	BackupArchiveFile = "blockdata.archive"
+)
+
+// BackupManifest describes the backup. The backup is the block data archive of
+// the blocks from base.PreGenesisHeight to the last block, so the database can
+// be rebuilt from it by restore.
+type BackupManifest struct {
+	Writer    hint.Hint       `json:"writer"`
+	From      base.Height     `json:"from"`
+	To        base.Height     `json:"to"`
+	Block     valuehash.Bytes `json:"block"`
+	Archive   string          `json:"archive"`
+	Size      int64           `json:"size"`
+	Checksum  string          `json:"checksum"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (bm BackupManifest) IsValid([]byte) error {
+	if err := bm.Writer.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid writer of backup manifest")
+	}
+
+	switch {
+	case bm.From < base.PreGenesisHeight:
+		return errors.Errorf("invalid from height of backup manifest, %d", bm.From)
+	case bm.To < bm.From:
+		return errors.Errorf("to height of backup manifest, %d is lower than from, %d", bm.To, bm.From)
+	case bm.Block == nil || bm.Block.IsEmpty():
+		return errors.Errorf("empty block hash of backup manifest")
+	case len(bm.Archive) < 1 || filepath.Base(bm.Archive) != bm.Archive:
+		return errors.Errorf("invalid archive of backup manifest, %q", bm.Archive)
+	case bm.Size < 1:
+		return errors.Errorf("empty archive size of backup manifest")
+	case len(bm.Checksum) < 1:
+		return errors.Errorf("empty checksum of backup manifest")
+	default:
+		return nil
+	}
+}
+
+// Backup writes the blocks from base.PreGenesisHeight to the last block of
+// database into w as block data archive. The last block is decided when
+// Backup starts and the stored blocks are not changed, so the consensus does
+// not need to be stopped. Each block is loaded thru the BlockdataMap of
+// database and its hash is checked with the map. The pruned database is
+// rejected, because it does not have all the blocks. The returned
+// BackupManifest has empty Archive.
+func Backup(ctx context.Context, db storage.Database, bd Blockdata, w io.Writer) (BackupManifest, error) {
+	var last block.Manifest
+	switch m, found, err := db.LastManifest(); {
+	case err != nil:
+		return BackupManifest{}, err
+	case !found:
+		return BackupManifest{}, util.NotFoundError.Errorf("last block not found")
+	default:
+		last = m
+	}
+
+	// NOTE the blocks of pruned database are not complete from
+	// base.PreGenesisHeight, so it can not be restored from the backup.
+	switch h, err := storage.PrunedHeight(db); {
+	case err != nil:
+		return BackupManifest{}, err
+	case h > base.PreGenesisHeight:
+		return BackupManifest{}, errors.Errorf(
+			"pruned database can not be backed up; blocks lower than %d are pruned", h)
+	}
+
+	sha := sha256.New()
+	cw := &countWriter{w: io.MultiWriter(w, sha)}
+
+	aw, err := NewArchiveWriter(cw, bd.Writer())
+	if err != nil {
+		return BackupManifest{}, err
+	}
+
+	for height := base.PreGenesisHeight; height <= last.Height(); height++ {
+		if err := ctx.Err(); err != nil {
+			return BackupManifest{}, err
+		}
+
+		blk, err := LoadBlockByMap(ctx, db, bd, height)
+		if err != nil {
+			return BackupManifest{}, errors.Wrapf(err, "failed to load block, %d", height)
+		}
+
+		if err := aw.Add(blk); err != nil {
+			return BackupManifest{}, err
+		}
+	}
+
+	if err := aw.Close(); err != nil {
+		return BackupManifest{}, err
+	}
+
+	return BackupManifest{
+		Writer:    bd.Writer().Hint(),
+		From:      base.PreGenesisHeight,
+		To:        last.Height(),
+		Block:     valuehash.NewBytes(last.Hash().Bytes()),
+		Size:      cw.n,
+		Checksum:  fmt.Sprintf("%x", sha.Sum(nil)),
+		CreatedAt: localtime.UTCNow(),
+	}, nil
+}
+
+// BackupToDirectory writes the backup into the directory. The directory
+// should be empty or not exist.
+func BackupToDirectory(ctx context.Context, db storage.Database, bd Blockdata, dir string) (BackupManifest, error) {
+	if err := PrepareBackupDirectory(dir); err != nil {
+		return BackupManifest{}, err
+	}
+
+	// NOTE archive is written to temporary file and renamed after closed.
+	p := filepath.Join(dir, BackupArchiveFile)
+	tmp := p + ".tmp"
+
+	f, err := os.OpenFile(filepath.Clean(tmp), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return BackupManifest{}, errors.Wrapf(err, "failed to create archive file, %q", tmp)
+	}
+
+	bm, err := func() (BackupManifest, error) {
+		defer func() {
+			_ = f.Close()
+		}()
+
+		bm, err := Backup(ctx, db, bd, f)
+		if err != nil {
+			return bm, err
+		}
+
+		return bm, f.Sync()
+	}()
+	if err != nil {
+		_ = os.Remove(tmp)
+
+		return BackupManifest{}, err
+	}
+
+	if err := os.Rename(tmp, p); err != nil {
+		return BackupManifest{}, err
+	}
+
+	bm.Archive = BackupArchiveFile
+
+	if err := SaveBackupManifest(dir, bm); err != nil {
+		return BackupManifest{}, err
+	}
+
+	return bm, nil
+}
+
+// SaveBackupManifest writes BackupManifest into the directory.
+func SaveBackupManifest(dir string, bm BackupManifest) error {
+	if err := bm.IsValid(nil); err != nil {
+		return err
+	}
+
+	b, err := jsonenc.Marshal(bm)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, BackupManifestFile), b, 0o600)
+}
+
+// LoadBackup loads the BackupManifest from the backup directory and verifies
+// the archive with it; the size, checksum, heights and the last block hash
+// should match. The returned file should be closed after ArchiveReader is
+// used.
+func LoadBackup(dir string, writer Writer) (BackupManifest, *os.File, *ArchiveReader, error) {
+	var bm BackupManifest
+
+	b, err := os.ReadFile(filepath.Join(filepath.Clean(dir), BackupManifestFile))
+	if err != nil {
+		return bm, nil, nil, errors.Wrap(err, "failed to read backup manifest")
+	}
+
+	if err := jsonenc.Unmarshal(b, &bm); err != nil {
+		return bm, nil, nil, errors.Wrap(err, "failed to decode backup manifest")
+	}
+
+	if err := bm.IsValid(nil); err != nil {
+		return bm, nil, nil, err
+	}
+
+	if !bm.Writer.Equal(writer.Hint()) {
+		return bm, nil, nil, errors.Errorf("writer of backup does not match; %q != %q", bm.Writer, writer.Hint())
+	}
+
+	f, ar, err := openBackupArchive(filepath.Join(dir, bm.Archive), bm, writer)
+	if err != nil {
+		return bm, nil, nil, err
+	}
+
+	return bm, f, ar, nil
+}
+
+// LoadBlockByMap loads the block thru the BlockdataMap of database; the local
+// block data is read from Blockdata and the remote one is fetched by it's url.
+// The checksum of each item is checked.
+func LoadBlockByMap(ctx context.Context, db storage.Database, bd Blockdata, height base.Height) (block.Block, error) {
+	var bdm block.BlockdataMap
+	switch i, found, err := db.BlockdataMap(height); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, util.NotFoundError.Errorf("blockdata map, %d not found", height)
+	default:
+		bdm = i
+	}
+
+	items, ok := bdm.(interface {
+		Item(string) (block.BaseBlockdataMapItem, bool)
+	})
+	if !ok {
+		return nil, errors.Errorf("unknown blockdata map, %T", bdm)
+	}
+
+	handler := func(p string) (io.Reader, func() error, error) {
+		i, err := bd.FS().Open(p)
+		if err != nil {
+			return nil, func() error { return nil }, err
+		}
+
+		return i, i.Close, nil
+	}
+
+	blk := (interface{})(block.EmptyBlockV0()).(block.BlockUpdater)
+
+	for i := range block.Blockdata {
+		dataType := block.Blockdata[i]
+
+		item, found := items.Item(dataType)
+		if !found {
+			return nil, util.NotFoundError.Errorf("block data, %q not found in blockdata map", dataType)
+		}
+
+		if err := func() error {
+			var r io.ReadCloser
+			if block.IsLocalBlockdataItem(item.URL()) {
+				j, err := network.FetchBlockdataThruChannel(handler, item)
+				if err != nil {
+					return err
+				}
+				r = j
+			} else {
+				j, err := network.FetchBlockdataFromRemote(ctx, item)
+				if err != nil {
+					return err
+				}
+				r = j
+			}
+
+			defer func() {
+				_ = r.Close()
+			}()
+
+			j, err := readBlockdataItem(bd.Writer(), r, blk, dataType)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read %q of block, %d", dataType, height)
+			}
+			blk = j
+
+			return nil
+		}(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !blk.Hash().Equal(bdm.Block()) {
+		return nil, errors.Errorf("block hash does not match with blockdata map, %d", height)
+	}
+
+	return blk, nil
+}
+
+// PrepareBackupDirectory checks the backup directory; the directory should be
+// empty or not exist. If not exist, it will be created.
+func PrepareBackupDirectory(dir string) error {
+	switch fi, err := os.Stat(dir); {
+	case err == nil:
+		if !fi.IsDir() {
+			return errors.Errorf("backup path, %q is not directory", dir)
+		}
+
+		switch files, err := os.ReadDir(dir); {
+		case err != nil:
+			return errors.Wrapf(err, "failed to read backup directory, %q", dir)
+		case len(files) > 0:
+			return errors.Errorf("backup directory, %q is not empty", dir)
+		}
+
+		return nil
+	case os.IsNotExist(err):
+		return os.MkdirAll(dir, 0o700)
+	default:
+		return errors.Wrapf(err, "failed to access backup directory, %q", dir)
+	}
+}
+
+func openBackupArchive(p string, bm BackupManifest, writer Writer) (*os.File, *ArchiveReader, error) {
+	f, err := os.Open(filepath.Clean(p))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to open backup archive, %q", p)
+	}
+
+	ar, err := func() (*ArchiveReader, error) {
+		switch fi, err := f.Stat(); {
+		case err != nil:
+			return nil, err
+		case fi.Size() != bm.Size:
+			return nil, errors.Errorf("size of backup archive does not match; %d != %d", fi.Size(), bm.Size)
+		}
+
+		switch checksum, err := util.GenerateChecksum(f); {
+		case err != nil:
+			return nil, err
+		case checksum != bm.Checksum:
+			return nil, errors.Errorf("checksum of backup archive does not match; %q != %q", checksum, bm.Checksum)
+		}
+
+		ar, err := NewArchiveReader(f, bm.Size, writer)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case ar.From() != bm.From, ar.To() != bm.To:
+			return nil, errors.Errorf("heights of backup archive does not match; [%d, %d] != [%d, %d]",
+				ar.From(), ar.To(), bm.From, bm.To)
+		}
+
+		blk, err := ar.Block(bm.To)
+		if err != nil {
+			return nil, err
+		}
+
+		if !blk.Hash().Equal(bm.Block) {
+			return nil, errors.Errorf("last block hash of backup archive does not match")
+		}
+
+		return ar, nil
+	}()
+	if err != nil {
+		_ = f.Close()
+
+		return nil, nil, err
+	}
+
+	return f, ar, nil
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,52 @@
+// +build test
+
+package blockdata
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type dummyPrunedDatabase struct {
+	storage.Database
+	last   block.Manifest
+	pruned base.Height
+}
+
+func (db dummyPrunedDatabase) LastManifest() (block.Manifest, bool, error) {
+	return db.last, true, nil
+}
+
+func (db dummyPrunedDatabase) Info(key string) ([]byte, bool, error) {
+	if key != storage.PrunedHeightInfoKey || db.pruned <= base.NilHeight {
+		return nil, false, nil
+	}
+
+	return db.pruned.Bytes(), true, nil
+}
+
+type testBackup struct {
+	suite.Suite
+}
+
+func (t *testBackup) TestPruned() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	db := dummyPrunedDatabase{last: blk.Manifest(), pruned: base.Height(10)}
+
+	_, err = Backup(context.Background(), db, nil, io.Discard)
+	t.Error(err)
+	t.Contains(err.Error(), "pruned database can not be backed up")
+}
+
+func TestBackup(t *testing.T) {
+	suite.Run(t, new(testBackup))
+}
@@ -177,7 +177,7 @@ func (st *Blockdata) SaveSession(session blockdata.Session) (block.BlockdataMap,
 		return nil, errors.Errorf("only localfs.Session only allowed for localfs blockdata, not %T", session)
 	}
 
-	mapData, err := ss.done()
+	mapData, err := ss.Done()
 	if err != nil {
 		return nil, err
 	}
@@ -254,7 +254,9 @@ func (ss *Session) SetBlock(blk block.Block) error {
 	return nil
 }
 
-func (ss *Session) done() (block.BaseBlockdataMap, error) {
+// Done checks the items of session and returns the map with the temporary
+// files of items. The items should be moved or uploaded by Blockdata.
+func (ss *Session) Done() (block.BaseBlockdataMap, error) {
 	ss.Lock()
 	defer ss.Unlock()
 
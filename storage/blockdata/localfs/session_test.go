@@ -228,7 +228,7 @@ func (t *testSession) TestDoneError() {
 	defer ss.Cancel()
 
 	t.Equal(base.Height(10), ss.Height())
-	_, err = ss.done()
+	_, err = ss.Done()
 	t.Contains(err.Error(), "nil can not be checked")
 }
 
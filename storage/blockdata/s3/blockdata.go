@@ -0,0 +1,314 @@
+package s3
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/localtime"
+)
+
+var (
+	S3BlockdataType = hint.Type("s3-blockdata")
+	S3BlockdataHint = hint.NewHint(S3BlockdataType, "v0.0.1")
+)
+
+// BlockdataMapFile is the object name of block data map in the block
+// directory. The block of height exists only when map object exists.
+var BlockdataMapFile = "map.json"
+
+// Blockdata stores the block data items in the S3 compatible object storage.
+// The items are written by localfs.Session under the local root directory and
+// uploaded by SaveSession, so the returned block data map has the object urls,
+// which the other nodes can fetch directly. The objects keep the directory
+// layout of localfs.Blockdata.
+type Blockdata struct {
+	sync.RWMutex
+	root    string
+	client  *Client
+	encoder *jsonenc.Encoder
+	writer  blockdata.Writer
+	fs      FS
+}
+
+func NewBlockdata(root string, client *Client, encoder *jsonenc.Encoder) *Blockdata {
+	return &Blockdata{
+		root:    root,
+		client:  client,
+		encoder: encoder,
+		writer:  blockdata.NewDefaultWriter(encoder),
+		fs:      NewFS(client),
+	}
+}
+
+func (st *Blockdata) Initialize() error {
+	i, err := filepath.Abs(st.root)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+	st.root = i
+
+	if fi, err := os.Stat(st.root); err != nil {
+		return storage.MergeFSError(err)
+	} else if !fi.IsDir() {
+		return storage.FSError.Errorf("storage root, %q is not directory", st.root)
+	}
+
+	if err := st.client.HeadBucket(context.Background()); err != nil {
+		return storage.MergeStorageError(err)
+	}
+
+	return nil
+}
+
+func (*Blockdata) Hint() hint.Hint {
+	return S3BlockdataHint
+}
+
+func (*Blockdata) IsLocal() bool {
+	return false
+}
+
+func (st *Blockdata) Writer() blockdata.Writer {
+	return st.writer
+}
+
+func (st *Blockdata) Client() *Client {
+	return st.client
+}
+
+func (st *Blockdata) Exists(height base.Height) (bool, error) {
+	st.RLock()
+	defer st.RUnlock()
+
+	switch found, removed, err := st.exists(height); {
+	case err != nil:
+		return found, err
+	case removed:
+		return false, nil
+	default:
+		return found, nil
+	}
+}
+
+// Remove does not remove the objects of block, it just creates .removed object
+// like localfs.Blockdata.
+func (st *Blockdata) Remove(height base.Height) error {
+	st.Lock()
+	defer st.Unlock()
+
+	switch found, removed, err := st.exists(height); {
+	case err != nil:
+		return err
+	case removed:
+		return nil
+	case !found:
+		return util.NotFoundError.Errorf("block directory not found, %v", height)
+	}
+
+	return st.client.PutObject(
+		context.Background(),
+		path.Join(localfs.HeightDirectory(height), localfs.BlockDirectoryRemovedTouchFile),
+		[]byte(localtime.RFC3339(localtime.UTCNow())),
+	)
+}
+
+// RemoveAll removes all the objects of block.
+func (st *Blockdata) RemoveAll(height base.Height) error {
+	st.Lock()
+	defer st.Unlock()
+
+	switch found, _, err := st.exists(height); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("block directory not found, %v", height)
+	}
+
+	return st.removeObjects(localfs.HeightDirectory(height) + "/")
+}
+
+// Clean removes all the objects under the prefix and the local session files.
+// If remove is true, the local root directory is also removed.
+func (st *Blockdata) Clean(remove bool) error {
+	st.Lock()
+	defer st.Unlock()
+
+	if err := st.removeObjects(""); err != nil {
+		return err
+	}
+
+	if remove {
+		if err := os.RemoveAll(st.root); err != nil {
+			return storage.MergeFSError(err)
+		}
+
+		return nil
+	}
+
+	files, err := os.ReadDir(st.root)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+	for _, f := range files {
+		if err := os.RemoveAll(filepath.Join(st.root, f.Name())); err != nil {
+			return storage.MergeFSError(err)
+		}
+	}
+
+	return nil
+}
+
+func (st *Blockdata) NewSession(height base.Height) (blockdata.Session, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	i, err := os.MkdirTemp(st.root, ".session")
+	if err != nil {
+		return nil, err
+	}
+
+	return localfs.NewSession(i, st.writer, height)
+}
+
+// SaveSession uploads the items of session and returns the block data map
+// with the object urls. The block data map is also uploaded after the items.
+func (st *Blockdata) SaveSession(session blockdata.Session) (block.BlockdataMap, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	ss, ok := session.(*localfs.Session)
+	if !ok {
+		return nil, errors.Errorf("only localfs.Session only allowed for s3 blockdata, not %T", session)
+	}
+
+	defer func() {
+		_ = ss.Cancel()
+	}()
+
+	mapData, err := ss.Done()
+	if err != nil {
+		return nil, err
+	}
+
+	b := localfs.HeightDirectory(ss.Height())
+
+	// NOTE remove the objects of the previous block
+	if err := st.removeObjects(b + "/"); err != nil {
+		return nil, err
+	}
+
+	newMapData, err := st.uploadItems(b, mapData)
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := newMapData.UpdateHash()
+	if err != nil {
+		return nil, err
+	}
+	newMapData = i
+
+	if err := newMapData.IsValid(nil); err != nil {
+		return nil, err
+	}
+
+	mb, err := st.encoder.Marshal(newMapData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := st.client.PutObject(context.Background(), path.Join(b, BlockdataMapFile), mb); err != nil {
+		return nil, err
+	}
+
+	return newMapData, nil
+}
+
+func (st *Blockdata) FS() fs.FS {
+	return st.fs
+}
+
+func (st *Blockdata) exists(height base.Height) (exists bool, removed bool, err error) {
+	b := localfs.HeightDirectory(height)
+
+	switch found, err := st.client.HeadObject(context.Background(), path.Join(b, BlockdataMapFile)); {
+	case err != nil:
+		return false, false, err
+	case !found:
+		return false, false, nil
+	}
+
+	switch found, err := st.client.HeadObject(
+		context.Background(), path.Join(b, localfs.BlockDirectoryRemovedTouchFile)); {
+	case err != nil:
+		return true, false, err
+	default:
+		return true, found, nil
+	}
+}
+
+func (st *Blockdata) uploadItems(b string, mapData block.BaseBlockdataMap) (block.BaseBlockdataMap, error) {
+	nm := mapData
+	for dataType := range mapData.Items() {
+		item := mapData.Items()[dataType]
+
+		f := filepath.Clean(item.URLBody())
+		body, err := os.ReadFile(f)
+		if err != nil {
+			return nm, storage.MergeFSError(err)
+		}
+
+		key := path.Join(b, filepath.Base(f))
+		if err := st.client.PutObject(context.Background(), key, body); err != nil {
+			return nm, err
+		}
+
+		i, err := nm.SetItem(item.SetURL(st.client.ObjectURL(key)))
+		if err != nil {
+			return nm, err
+		}
+		nm = i
+	}
+
+	return nm, nil
+}
+
+func (st *Blockdata) removeObjects(prefix string) error {
+	var keys []string
+	if err := st.client.ListObjects(context.Background(), prefix, func(key string) (bool, error) {
+		keys = append(keys, key)
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	// NOTE map object is removed first, so the partially removed block is not
+	// regarded as existing.
+	sort.SliceStable(keys, func(i, j int) bool {
+		return strings.HasSuffix(keys[i], "/"+BlockdataMapFile) && !strings.HasSuffix(keys[j], "/"+BlockdataMapFile)
+	})
+
+	for i := range keys {
+		if err := st.client.DeleteObject(context.Background(), keys[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+// +build test
+
+package s3
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/isaac"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/storage/blockdata"
+	"github.com/spikeekips/mitum/storage/blockdata/localfs"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testBlockdata struct {
+	isaac.BaseTest
+	fake   *FakeServer
+	client *Client
+	root   string
+}
+
+func (t *testBlockdata) SetupTest() {
+	t.BaseTest.SetupTest()
+
+	prefix := util.UUID().String()
+
+	u, found := TestS3URI()
+	if found {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + prefix
+	} else {
+		t.fake = NewFakeServer("mitum", DefaultRegion, util.UUID().String(), util.UUID().String())
+		u = t.fake.URI(prefix)
+	}
+
+	client, err := NewClientFromURI(u)
+	t.NoError(err)
+	t.client = client
+
+	p, err := os.MkdirTemp("", "s3-")
+	t.NoError(err)
+	t.root = p
+}
+
+func (t *testBlockdata) TearDownTest() {
+	if t.fake != nil {
+		t.fake.Close()
+	}
+
+	_ = os.RemoveAll(t.root)
+}
+
+func (t *testBlockdata) newBlockdata() *Blockdata {
+	st := NewBlockdata(t.root, t.client, t.JSONEnc)
+	t.NoError(st.Initialize())
+
+	return st
+}
+
+func (t *testBlockdata) genesisBlock() block.Block {
+	local := t.Locals(1)[0]
+
+	_, blk, err := localfs.LoadBlock(local.Blockdata().(*localfs.Blockdata), base.GenesisHeight)
+	t.NoError(err)
+
+	return blk
+}
+
+func (t *testBlockdata) saveBlock(st *Blockdata, blk block.Block) block.BlockdataMap {
+	ss, err := st.NewSession(blk.Height())
+	t.NoError(err)
+
+	t.NoError(ss.SetBlock(blk))
+
+	bd, err := st.SaveSession(ss)
+	t.NoError(err)
+
+	return bd
+}
+
+func (t *testBlockdata) TestNew() {
+	st := t.newBlockdata()
+	t.Implements((*blockdata.Blockdata)(nil), st)
+	t.False(st.IsLocal())
+}
+
+func (t *testBlockdata) TestBucketNotFound() {
+	u := *t.client.objectURL("")
+	client := NewClient(
+		&url.URL{Scheme: u.Scheme, Host: u.Host}, nil,
+		util.UUID().String(), "", t.client.region, t.client.accessKey, t.client.secretKey, nil,
+	)
+
+	st := NewBlockdata(t.root, client, t.JSONEnc)
+	err := st.Initialize()
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+func (t *testBlockdata) TestSaveSession() {
+	st := t.newBlockdata()
+	blk := t.genesisBlock()
+
+	bd := t.saveBlock(st, blk)
+	t.NoError(bd.IsValid(nil))
+	t.False(bd.IsLocal())
+	t.True(blk.Hash().Equal(bd.Block()))
+
+	// NOTE session files are removed
+	files, err := os.ReadDir(t.root)
+	t.NoError(err)
+	t.Empty(files)
+
+	found, err := st.Exists(blk.Height())
+	t.NoError(err)
+	t.True(found)
+
+	// NOTE items can be fetched by url
+	for dataType, item := range bd.(block.BaseBlockdataMap).Items() {
+		t.True(strings.HasPrefix(item.URL(), "http"), dataType)
+
+		r, err := network.FetchBlockdataFromRemote(context.Background(), item)
+		t.NoError(err, dataType)
+		t.NoError(r.Close())
+	}
+
+	r, err := network.FetchBlockdataFromRemote(context.Background(), bd.Manifest())
+	t.NoError(err)
+	defer r.Close()
+
+	m, err := st.Writer().ReadManifest(r)
+	t.NoError(err)
+	t.True(blk.Hash().Equal(m.Hash()))
+
+	// NOTE map object
+	f, err := st.FS().Open(localfs.HeightDirectory(blk.Height()) + "/" + BlockdataMapFile)
+	t.NoError(err)
+	defer f.Close()
+
+	b, err := io.ReadAll(f)
+	t.NoError(err)
+
+	hinter, err := t.JSONEnc.Decode(b)
+	t.NoError(err)
+
+	ubd, ok := hinter.(block.BlockdataMap)
+	t.True(ok)
+	block.CompareBlockdataMap(t.Assert(), bd, ubd)
+}
+
+func (t *testBlockdata) TestNotFound() {
+	st := t.newBlockdata()
+
+	found, err := st.Exists(33)
+	t.NoError(err)
+	t.False(found)
+
+	t.True(errors.Is(st.Remove(33), util.NotFoundError))
+	t.True(errors.Is(st.RemoveAll(33), util.NotFoundError))
+
+	_, err = st.FS().Open(localfs.HeightDirectory(33) + "/" + BlockdataMapFile)
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+func (t *testBlockdata) TestRemove() {
+	st := t.newBlockdata()
+	blk := t.genesisBlock()
+	_ = t.saveBlock(st, blk)
+
+	t.NoError(st.Remove(blk.Height()))
+
+	found, removed, err := st.exists(blk.Height())
+	t.NoError(err)
+	t.True(found)
+	t.True(removed)
+
+	found, err = st.Exists(blk.Height())
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testBlockdata) TestRemoveAll() {
+	st := t.newBlockdata()
+	blk := t.genesisBlock()
+	_ = t.saveBlock(st, blk)
+
+	t.NoError(st.RemoveAll(blk.Height()))
+
+	found, removed, err := st.exists(blk.Height())
+	t.NoError(err)
+	t.False(found)
+	t.False(removed)
+
+	var keys []string
+	t.NoError(t.client.ListObjects(context.Background(), "", func(k string) (bool, error) {
+		keys = append(keys, k)
+
+		return true, nil
+	}))
+	t.Empty(keys)
+}
+
+func (t *testBlockdata) TestClean() {
+	st := t.newBlockdata()
+	blk := t.genesisBlock()
+	_ = t.saveBlock(st, blk)
+
+	t.NoError(st.Clean(false))
+
+	found, err := st.Exists(blk.Height())
+	t.NoError(err)
+	t.False(found)
+
+	_, err = os.Stat(t.root)
+	t.NoError(err)
+
+	t.NoError(st.Clean(true))
+
+	_, err = os.Stat(t.root)
+	t.True(os.IsNotExist(err))
+}
+
+func TestBlockdata(t *testing.T) {
+	suite.Run(t, new(testBlockdata))
+}
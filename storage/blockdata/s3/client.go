@@ -0,0 +1,483 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+)
+
+var (
+	DefaultRegion  = "us-east-1"
+	DefaultTimeout = time.Second * 30
+)
+
+// Client is the minimal client for S3 compatible object storage. It supports
+// only path-style requests, so bucket is always the first element of path.
+type Client struct {
+	endpoint  *url.URL
+	public    *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// NewClientFromURI parses uri like,
+// "s3://<access key>:<secret key>@<host>/<bucket>/<prefix>?region=<region>&insecure=<bool>&public=<url>".
+// - insecure: if true, http is used instead of https.
+// - public: the base url of objects for the other nodes; by default, the
+// object url of endpoint is used.
+func NewClientFromURI(u *url.URL) (*Client, error) {
+	if u.Scheme != "s3" {
+		return nil, errors.Errorf("not s3 uri, %q", u.Scheme)
+	}
+
+	if len(u.Host) < 1 {
+		return nil, errors.Errorf("empty host")
+	}
+
+	bucket, prefix := splitBucket(u.Path)
+	if len(bucket) < 1 {
+		return nil, errors.Errorf("empty bucket")
+	}
+
+	q := u.Query()
+
+	scheme := "https"
+	if util.ParseBoolInQuery(q.Get("insecure")) {
+		scheme = "http"
+	}
+
+	endpoint := &url.URL{Scheme: scheme, Host: u.Host}
+
+	var public *url.URL
+	if s := strings.TrimSpace(q.Get("public")); len(s) > 0 {
+		i, err := url.Parse(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid public url")
+		}
+		public = i
+	}
+
+	region := DefaultRegion
+	if s := strings.TrimSpace(q.Get("region")); len(s) > 0 {
+		region = s
+	}
+
+	var accessKey, secretKey string
+	if u.User != nil {
+		accessKey = u.User.Username()
+		secretKey, _ = u.User.Password()
+	}
+
+	return NewClient(endpoint, public, bucket, prefix, region, accessKey, secretKey, nil), nil
+}
+
+func NewClient( // revive:disable-line:argument-limit
+	endpoint, public *url.URL,
+	bucket, prefix, region, accessKey, secretKey string,
+	client *http.Client,
+) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return &Client{
+		endpoint:  endpoint,
+		public:    public,
+		bucket:    bucket,
+		prefix:    strings.Trim(prefix, "/"),
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    client,
+	}
+}
+
+func (cl *Client) Bucket() string {
+	return cl.bucket
+}
+
+// ObjectURL returns the url of object for the other nodes.
+func (cl *Client) ObjectURL(key string) string {
+	if cl.public != nil {
+		u := *cl.public
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + cl.objectKey(key)
+		u.RawPath = ""
+
+		return u.String()
+	}
+
+	return cl.objectURL(key).String()
+}
+
+// HeadBucket checks whether bucket exists.
+func (cl *Client) HeadBucket(ctx context.Context) error {
+	u := *cl.endpoint
+	u.Path = "/" + cl.bucket
+
+	res, err := cl.request(ctx, http.MethodHead, &u, nil)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return util.NotFoundError.Errorf("bucket, %q not found", cl.bucket)
+	default:
+		return errors.Errorf("failed to check bucket, %q: %q", cl.bucket, res.Status)
+	}
+}
+
+func (cl *Client) PutObject(ctx context.Context, key string, b []byte) error {
+	res, err := cl.request(ctx, http.MethodPut, cl.objectURL(key), b)
+	if err != nil {
+		return err
+	}
+	defer closeResponse(res)
+
+	if res.StatusCode != http.StatusOK {
+		return responseError(res, key)
+	}
+
+	return nil
+}
+
+// GetObject returns the body of object. If not found, fs.ErrNotExist is
+// returned.
+func (cl *Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	res, err := cl.request(ctx, http.MethodGet, cl.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer closeResponse(res)
+
+		return nil, responseError(res, key)
+	}
+
+	return res.Body, nil
+}
+
+func (cl *Client) HeadObject(ctx context.Context, key string) (bool, error) {
+	res, err := cl.request(ctx, http.MethodHead, cl.objectURL(key), nil)
+	if err != nil {
+		return false, err
+	}
+	defer closeResponse(res)
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, responseError(res, key)
+	}
+}
+
+func (cl *Client) DeleteObject(ctx context.Context, key string) error {
+	res, err := cl.request(ctx, http.MethodDelete, cl.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	defer closeResponse(res)
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return responseError(res, key)
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// ListObjects calls callback with the keys under prefix. The keys are
+// relative to the prefix of client.
+func (cl *Client) ListObjects(ctx context.Context, prefix string, callback func(string) (bool, error)) error {
+	base := cl.objectKey("")
+	if len(base) > 0 {
+		base += "/"
+	}
+
+	p := cl.objectKey(prefix)
+	if len(strings.TrimLeft(prefix, "/")) < 1 && len(p) > 0 {
+		p += "/"
+	}
+
+	var token string
+	for {
+		u := *cl.endpoint
+		u.Path = "/" + cl.bucket
+
+		q := url.Values{}
+		q.Set("list-type", "2")
+		q.Set("prefix", p)
+		if len(token) > 0 {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = q.Encode()
+
+		var r listBucketResult
+		if err := func() error {
+			res, err := cl.request(ctx, http.MethodGet, &u, nil)
+			if err != nil {
+				return err
+			}
+			defer closeResponse(res)
+
+			if res.StatusCode != http.StatusOK {
+				return responseError(res, prefix)
+			}
+
+			return xml.NewDecoder(res.Body).Decode(&r)
+		}(); err != nil {
+			return err
+		}
+
+		for i := range r.Contents {
+			switch keep, err := callback(strings.TrimPrefix(r.Contents[i].Key, base)); {
+			case err != nil:
+				return err
+			case !keep:
+				return nil
+			}
+		}
+
+		if !r.IsTruncated || len(r.NextContinuationToken) < 1 {
+			return nil
+		}
+
+		token = r.NextContinuationToken
+	}
+}
+
+func (cl *Client) objectKey(key string) string {
+	key = strings.TrimLeft(key, "/")
+
+	switch {
+	case len(cl.prefix) < 1:
+		return key
+	case len(key) < 1:
+		return cl.prefix
+	default:
+		return cl.prefix + "/" + key
+	}
+}
+
+func (cl *Client) objectURL(key string) *url.URL {
+	u := *cl.endpoint
+	u.Path = "/" + cl.bucket + "/" + cl.objectKey(key)
+
+	return &u
+}
+
+func (cl *Client) request(ctx context.Context, method string, u *url.URL, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.ContentLength = int64(len(body))
+	}
+
+	if len(cl.accessKey) > 0 {
+		signRequest(req, body, cl.region, cl.accessKey, cl.secretKey, time.Now().UTC())
+	}
+
+	res, err := cl.client.Do(req)
+	if err != nil {
+		return nil, storage.MergeStorageError(err)
+	}
+
+	return res, nil
+}
+
+func closeResponse(res *http.Response) {
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+}
+
+func responseError(res *http.Response, key string) error {
+	if res.StatusCode == http.StatusNotFound {
+		return util.NotFoundError.Wrap(errors.Wrapf(fs.ErrNotExist, "object, %q", key))
+	}
+
+	var body []byte
+	if res.Request.Method != http.MethodHead {
+		body, _ = io.ReadAll(io.LimitReader(res.Body, 1<<10))
+	}
+
+	return storage.StorageError.Errorf("failed to request object, %q: %q; %s", key, res.Status, string(body))
+}
+
+// signRequest signs request by AWS Signature Version 4.
+func signRequest(req *http.Request, body []byte, region, accessKey, secretKey string, t time.Time) {
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signRequestV4(
+		req, payloadHash, region, "s3", accessKey, secretKey, t,
+		[]string{"host", "x-amz-content-sha256", "x-amz-date"},
+	)
+}
+
+// signRequestV4 sets the x-amz-date and Authorization headers of request. The
+// signedHeaders are the lowercase names of headers to be signed; host comes
+// from the url of request.
+func signRequestV4(
+	req *http.Request,
+	payloadHash, region, service, accessKey, secretKey string,
+	t time.Time,
+	signedHeaders []string,
+) {
+	amzdate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("x-amz-date", amzdate)
+
+	names := make([]string, len(signedHeaders))
+	copy(names, signedHeaders)
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for i := range names {
+		v := req.Header.Get(names[i])
+		if names[i] == "host" {
+			v = req.URL.Host
+		}
+
+		canonicalHeaders.WriteString(names[i] + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	crh := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzdate,
+		scope,
+		hex.EncodeToString(crh[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signed, signature,
+	))
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(s))
+
+	return h.Sum(nil)
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var s []string
+	for i := range keys {
+		vs := q[keys[i]]
+		sort.Strings(vs)
+		for j := range vs {
+			s = append(s, escape(keys[i], true)+"="+escape(vs[j], true))
+		}
+	}
+
+	return strings.Join(s, "&")
+}
+
+func escapePath(p string) string {
+	if len(p) < 1 {
+		return "/"
+	}
+
+	return escape(p, false)
+}
+
+// escape encodes string by the rule of AWS Signature Version 4; except the
+// unreserved characters, all the characters are encoded. If encodeSlash is
+// false, "/" is not encoded.
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func splitBucket(p string) (string, string) {
+	n := strings.SplitN(strings.Trim(p, "/"), "/", 2)
+	if len(n) < 2 {
+		return n[0], ""
+	}
+
+	return n[0], n[1]
+}
@@ -0,0 +1,4 @@
+/*
+Package s3 provides block data from S3 compatible object storage.
+*/
+package s3
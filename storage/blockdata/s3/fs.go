@@ -0,0 +1,82 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/spikeekips/mitum/storage"
+)
+
+// FS reads the objects by the relative key under the prefix of client.
+type FS struct {
+	client *Client
+}
+
+func NewFS(client *Client) FS {
+	return FS{client: client}
+}
+
+// Open reads the object into memory; the block data items are small enough.
+func (f FS) Open(p string) (fs.File, error) {
+	r, err := f.client.GetObject(context.Background(), p)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, storage.MergeStorageError(err)
+	}
+
+	return &file{Reader: bytes.NewReader(buf.Bytes()), name: path.Base(p), size: int64(buf.Len())}, nil
+}
+
+type file struct {
+	*bytes.Reader
+	name string
+	size int64
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return fileInfo{name: f.name, size: f.size}, nil
+}
+
+func (*file) Close() error {
+	return nil
+}
+
+type fileInfo struct {
+	name string
+	size int64
+}
+
+func (fi fileInfo) Name() string {
+	return fi.name
+}
+
+func (fi fileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fileInfo) Mode() fs.FileMode {
+	return 0o444
+}
+
+func (fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (fileInfo) IsDir() bool {
+	return false
+}
+
+func (fileInfo) Sys() interface{} {
+	return nil
+}
@@ -0,0 +1,220 @@
+// +build test
+
+package s3
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TestS3URI returns the uri from "MITUM_TEST_S3_URI" to run the tests against
+// the real S3 compatible storage like MinIO, for example,
+// "s3://minioadmin:minioadmin@localhost:9000/mitum?insecure=true". The bucket
+// should exist and allow anonymous read.
+func TestS3URI() (*url.URL, bool) {
+	s := os.Getenv("MITUM_TEST_S3_URI")
+	if len(s) < 1 {
+		return nil, false
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return u, true
+}
+
+// FakeServer is the in-memory S3 stand-in for testing. It checks the
+// signature of request and allows anonymous GET for objects like the public
+// bucket.
+type FakeServer struct {
+	sync.RWMutex
+	*httptest.Server
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	objects   map[string][]byte
+	maxKeys   int
+}
+
+func NewFakeServer(bucket, region, accessKey, secretKey string) *FakeServer {
+	fs := &FakeServer{
+		bucket:    bucket,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		objects:   map[string][]byte{},
+		maxKeys:   3,
+	}
+
+	fs.Server = httptest.NewServer(http.HandlerFunc(fs.handle))
+
+	return fs
+}
+
+// URI returns the s3 uri for NewClientFromURI.
+func (fs *FakeServer) URI(prefix string) *url.URL {
+	u, _ := url.Parse(fs.Server.URL)
+
+	return &url.URL{
+		Scheme:   "s3",
+		User:     url.UserPassword(fs.accessKey, fs.secretKey),
+		Host:     u.Host,
+		Path:     "/" + fs.bucket + "/" + strings.Trim(prefix, "/"),
+		RawQuery: url.Values{"insecure": []string{"true"}, "region": []string{fs.region}}.Encode(),
+	}
+}
+
+// Keys returns the sorted object keys.
+func (fs *FakeServer) Keys() []string {
+	fs.RLock()
+	defer fs.RUnlock()
+
+	keys := make([]string, 0, len(fs.objects))
+	for k := range fs.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (fs *FakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	n := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if n[0] != fs.bucket {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	var key string
+	if len(n) > 1 {
+		key = n[1]
+	}
+
+	anonymous := r.Method == http.MethodGet && len(key) > 0 && len(r.Header.Get("Authorization")) < 1
+	if !anonymous && !fs.checkSignature(r, body) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	switch {
+	case len(key) < 1 && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case len(key) < 1 && r.Method == http.MethodGet:
+		fs.list(w, r)
+	case len(key) < 1:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	default:
+		fs.object(w, r, key, body)
+	}
+}
+
+func (fs *FakeServer) object(w http.ResponseWriter, r *http.Request, key string, body []byte) {
+	switch r.Method {
+	case http.MethodPut:
+		fs.Lock()
+		fs.objects[key] = body
+		fs.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		fs.RLock()
+		b, found := fs.objects[key]
+		fs.RUnlock()
+
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	case http.MethodDelete:
+		fs.Lock()
+		delete(fs.objects, key)
+		fs.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (fs *FakeServer) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	prefix := q.Get("prefix")
+	token := q.Get("continuation-token")
+
+	var keys []string
+	for _, k := range fs.Keys() {
+		if strings.HasPrefix(k, prefix) && k > token {
+			keys = append(keys, k)
+		}
+	}
+
+	var res listBucketResult
+	if len(keys) > fs.maxKeys {
+		keys = keys[:fs.maxKeys]
+		res.IsTruncated = true
+		res.NextContinuationToken = keys[len(keys)-1]
+	}
+
+	for i := range keys {
+		res.Contents = append(res.Contents, struct {
+			Key string `xml:"Key"`
+		}{Key: keys[i]})
+	}
+
+	b, err := xml.Marshal(res)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write(b)
+}
+
+// checkSignature signs the request again with the same signRequest; the
+// signRequest itself is checked with the AWS test vectors in client_test.go.
+func (fs *FakeServer) checkSignature(r *http.Request, body []byte) bool {
+	t, err := time.Parse("20060102T150405Z", r.Header.Get("x-amz-date"))
+	if err != nil {
+		return false
+	}
+
+	req, err := http.NewRequest(r.Method, fmt.Sprintf("http://%s%s", r.Host, r.URL.RequestURI()), nil)
+	if err != nil {
+		return false
+	}
+
+	signRequest(req, body, fs.region, fs.accessKey, fs.secretKey, t)
+
+	return req.Header.Get("Authorization") == r.Header.Get("Authorization")
+}
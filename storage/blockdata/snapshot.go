@@ -0,0 +1,291 @@
+package blockdata
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	"github.com/spikeekips/mitum/util/valuehash"
+)
+
+var (
+	// SnapshotURLPrefix is the path prefix of snapshot item url.
+	SnapshotURLPrefix                       = "/snapshot/"
+	snapshotFilePermission      os.FileMode = 0o644
+	snapshotDirectoryPermission os.FileMode = 0o755
+)
+
+/*
+SnapshotStore keeps the state snapshots under the local directory. Each
+snapshot has 2 files,
+
+* <height>.gz: gzipped states, which are written by Writer.WriteStates
+* <height>.json: encoded block.SnapshotHeader
+
+The states in snapshot are sorted by it's hash, so the root of the snapshot
+can be regenerated from the states.
+*/
+type SnapshotStore struct {
+	sync.RWMutex
+	root   string
+	writer Writer
+	enc    encoder.Encoder
+}
+
+func NewSnapshotStore(root string, writer Writer, enc encoder.Encoder) *SnapshotStore {
+	return &SnapshotStore{root: root, writer: writer, enc: enc}
+}
+
+func (ss *SnapshotStore) Initialize() error {
+	i, err := filepath.Abs(ss.root)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+	ss.root = i
+
+	if err := os.MkdirAll(ss.root, snapshotDirectoryPermission); err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	return nil
+}
+
+func (ss *SnapshotStore) Root() string {
+	return ss.root
+}
+
+// Save stores the given states as the snapshot of the manifest. The states
+// should be the latest states at the height of manifest.
+func (ss *SnapshotStore) Save(m block.Manifest, sts []state.State) (block.SnapshotHeader, error) {
+	ss.Lock()
+	defer ss.Unlock()
+
+	if len(sts) < 1 {
+		return nil, errors.Errorf("empty states for snapshot")
+	}
+
+	for i := range sts {
+		if sts[i].Height() > m.Height() {
+			return nil, errors.Errorf("state, %q is higher than snapshot height, %d", sts[i].Key(), m.Height())
+		}
+	}
+
+	block.SortSnapshotStates(sts)
+
+	tr, err := block.SnapshotStatesTree(sts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make snapshot states tree")
+	}
+
+	checksum, err := ss.writeStates(m.Height(), sts)
+	if err != nil {
+		return nil, err
+	}
+
+	item := block.NewBaseBlockdataMapItem(block.BlockdataSnapshot, checksum, "").
+		SetFile(SnapshotURLPrefix + ss.statesFilename(m.Height()))
+
+	sh, err := block.NewBaseSnapshotHeader(m.Height(), m.Hash(), valuehash.NewBytes(tr.Root()), uint64(len(sts)), item)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := ss.enc.Marshal(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ss.writeFile(ss.headerFilename(m.Height()), func(w io.Writer) error {
+		_, err := w.Write(b)
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return sh, nil
+}
+
+// Header returns the snapshot header of height. If height is base.NilHeight,
+// the last one is returned.
+func (ss *SnapshotStore) Header(height base.Height) (block.SnapshotHeader, bool, error) {
+	ss.RLock()
+	defer ss.RUnlock()
+
+	if height == base.NilHeight {
+		switch i, found, err := ss.lastHeight(); {
+		case err != nil:
+			return nil, false, err
+		case !found:
+			return nil, false, nil
+		default:
+			height = i
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(ss.root, ss.headerFilename(height)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, storage.MergeFSError(err)
+	}
+
+	hinter, err := ss.enc.Decode(b)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sh, ok := hinter.(block.SnapshotHeader)
+	if !ok {
+		return nil, false, errors.Errorf("not SnapshotHeader, %T", hinter)
+	}
+
+	return sh, true, nil
+}
+
+// Open opens the raw states file of snapshot by the path of item url like
+// "/snapshot/<height>.gz".
+func (ss *SnapshotStore) Open(p string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(p, SnapshotURLPrefix) {
+		return nil, util.NotFoundError.Errorf("not snapshot path, %q", p)
+	}
+
+	f, err := os.Open(filepath.Join(ss.root, filepath.Base(p)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, util.NotFoundError.Errorf("snapshot, %q not found", p)
+		}
+
+		return nil, storage.MergeFSError(err)
+	}
+
+	return f, nil
+}
+
+// States reads the states of snapshot.
+func (ss *SnapshotStore) States(height base.Height) ([]state.State, error) {
+	f, err := ss.Open(SnapshotURLPrefix + ss.statesFilename(height))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := util.NewGzipReader(f)
+	if err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	return ss.writer.ReadStates(r)
+}
+
+func (ss *SnapshotStore) writeStates(height base.Height, sts []state.State) (string, error) {
+	p := ss.statesFilename(height)
+	if err := ss.writeFile(p, func(w io.Writer) error {
+		gw := util.NewGzipWriter(w)
+		if err := ss.writer.WriteStates(gw, sts); err != nil {
+			return err
+		}
+
+		return gw.Writer.Close()
+	}); err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(filepath.Join(ss.root, p))
+	if err != nil {
+		return "", storage.MergeFSError(err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return util.GenerateChecksum(f)
+}
+
+// writeFile writes to the temporary file and renames it, so the incomplete
+// file is not exposed.
+func (ss *SnapshotStore) writeFile(name string, f func(io.Writer) error) error {
+	// NOTE the root directory can be removed by cleaning blockdata
+	if err := os.MkdirAll(ss.root, snapshotDirectoryPermission); err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	p := filepath.Join(ss.root, name)
+	tmp := p + ".tmp"
+
+	w, err := os.OpenFile(filepath.Clean(tmp), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, snapshotFilePermission)
+	if err != nil {
+		return storage.MergeFSError(err)
+	}
+
+	if err := func() error {
+		defer func() {
+			_ = w.Close()
+		}()
+
+		if err := f(w); err != nil {
+			return err
+		}
+
+		return w.Sync()
+	}(); err != nil {
+		_ = os.Remove(tmp)
+
+		return err
+	}
+
+	return storage.MergeFSError(os.Rename(tmp, p))
+}
+
+func (ss *SnapshotStore) lastHeight() (base.Height, bool, error) {
+	files, err := os.ReadDir(ss.root)
+	if err != nil {
+		return base.NilHeight, false, storage.MergeFSError(err)
+	}
+
+	last := base.NilHeight
+	for i := range files {
+		n := files[i].Name()
+		if files[i].IsDir() || filepath.Ext(n) != ".json" {
+			continue
+		}
+
+		j, err := strconv.ParseInt(strings.TrimSuffix(n, ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if h := base.Height(j); h > last {
+			last = h
+		}
+	}
+
+	return last, last > base.NilHeight, nil
+}
+
+func (*SnapshotStore) statesFilename(height base.Height) string {
+	return fmt.Sprintf("%020d.gz", height)
+}
+
+func (*SnapshotStore) headerFilename(height base.Height) string {
+	return fmt.Sprintf("%020d.json", height)
+}
@@ -0,0 +1,165 @@
+// +build test
+
+package blockdata
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testSnapshotStore struct {
+	suite.Suite
+	JSONEnc *jsonenc.Encoder
+	writer  Writer
+	root    string
+}
+
+func (t *testSnapshotStore) SetupSuite() {
+	encs := encoder.NewEncoders()
+	t.JSONEnc = jsonenc.NewEncoder()
+	_ = encs.AddEncoder(t.JSONEnc)
+
+	_ = encs.TestAddHinter(block.BaseSnapshotHeaderHinter)
+	_ = encs.TestAddHinter(state.StateV0{})
+	_ = encs.TestAddHinter(state.StringValueHinter)
+
+	t.writer = NewDefaultWriter(t.JSONEnc)
+}
+
+func (t *testSnapshotStore) SetupTest() {
+	p, err := os.MkdirTemp("", "snapshot-")
+	t.NoError(err)
+	t.root = p
+}
+
+func (t *testSnapshotStore) TearDownTest() {
+	_ = os.RemoveAll(t.root)
+}
+
+func (t *testSnapshotStore) newStates(n int, height base.Height) []state.State {
+	sts := make([]state.State, n)
+	for i := range sts {
+		v, err := state.NewStringValue(util.UUID().String())
+		t.NoError(err)
+
+		st, err := state.NewStateV0(util.UUID().String(), v, height)
+		t.NoError(err)
+
+		ust, err := st.SetHash(st.GenerateHash())
+		t.NoError(err)
+
+		sts[i] = ust
+	}
+
+	return sts
+}
+
+func (t *testSnapshotStore) newStore() *SnapshotStore {
+	ss := NewSnapshotStore(t.root, t.writer, t.JSONEnc)
+	t.NoError(ss.Initialize())
+
+	return ss
+}
+
+func (t *testSnapshotStore) TestSave() {
+	ss := t.newStore()
+
+	blk, err := block.NewTestBlockV0(33, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	sts := t.newStates(10, 32)
+	sh, err := ss.Save(blk.Manifest(), sts)
+	t.NoError(err)
+	t.NoError(sh.IsValid(nil))
+
+	t.Equal(base.Height(33), sh.Height())
+	t.True(blk.Hash().Equal(sh.Block()))
+	t.Equal(uint64(10), sh.Count())
+
+	ush, found, err := ss.Header(33)
+	t.NoError(err)
+	t.True(found)
+	t.True(sh.Hash().Equal(ush.Hash()))
+	t.Equal(sh.Item().Checksum(), ush.Item().Checksum())
+
+	usts, err := ss.States(33)
+	t.NoError(err)
+	t.NoError(block.VerifySnapshotStates(sh, usts))
+
+	// NOTE fetch thru item url like the remote node
+	r, err := network.FetchBlockdataThruChannel(func(p string) (io.Reader, func() error, error) {
+		f, err := ss.Open(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return f, f.Close, nil
+	}, sh.Item())
+	t.NoError(err)
+
+	fsts, err := t.writer.ReadStates(r)
+	t.NoError(err)
+	t.NoError(block.VerifySnapshotStates(sh, fsts))
+}
+
+func (t *testSnapshotStore) TestLast() {
+	ss := t.newStore()
+
+	_, found, err := ss.Header(base.NilHeight)
+	t.NoError(err)
+	t.False(found)
+
+	for _, height := range []base.Height{10, 30, 20} {
+		blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+		t.NoError(err)
+
+		_, err = ss.Save(blk.Manifest(), t.newStates(3, height))
+		t.NoError(err)
+	}
+
+	sh, found, err := ss.Header(base.NilHeight)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(30), sh.Height())
+
+	_, found, err = ss.Header(11)
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testSnapshotStore) TestHigherState() {
+	ss := t.newStore()
+
+	blk, err := block.NewTestBlockV0(33, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	_, err = ss.Save(blk.Manifest(), t.newStates(3, 34))
+	t.Error(err)
+	t.Contains(err.Error(), "higher than snapshot height")
+}
+
+func (t *testSnapshotStore) TestOpenUnknown() {
+	ss := t.newStore()
+
+	_, err := ss.Open(SnapshotURLPrefix + "00000000000000000033.gz")
+	t.True(errors.Is(err, util.NotFoundError))
+
+	_, err = ss.Open("/000/000/033.gz")
+	t.True(errors.Is(err, util.NotFoundError))
+}
+
+func TestSnapshotStore(t *testing.T) {
+	suite.Run(t, new(testSnapshotStore))
+}
@@ -37,6 +37,35 @@ func CleanByHeight(db storage.Database, blockdata Blockdata, height base.Height)
 	return db.CleanByHeight(height)
 }
 
+// Prune prunes Database lower than height. If 'removeBlockdata' is true, the
+// block data of the pruned heights are also removed.
+func Prune(db storage.Database, blockdata Blockdata, height base.Height, removeBlockdata bool) error {
+	pr, ok := db.(storage.Pruner)
+	if !ok {
+		return errors.Errorf("database does not support storage.Pruner, %T", db)
+	}
+
+	from, err := storage.PrunedHeight(db)
+	switch {
+	case err != nil:
+		return err
+	case height <= from:
+		return nil
+	case from < base.GenesisHeight:
+		from = base.GenesisHeight
+	}
+
+	if removeBlockdata {
+		for h := from; h < height; h++ {
+			if err := blockdata.RemoveAll(h); err != nil && !errors.Is(err, util.NotFoundError) {
+				return err
+			}
+		}
+	}
+
+	return pr.Prune(height)
+}
+
 func CheckBlock(db storage.Database, blockdata Blockdata, networkID base.NetworkID) (block.Manifest, error) {
 	m, err := storage.CheckBlock(db, networkID)
 	if err != nil {
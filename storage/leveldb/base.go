@@ -1,7 +1,10 @@
 package leveldbstorage
 
 import (
+	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -9,13 +12,17 @@ import (
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/network"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
+	jsonenc "github.com/spikeekips/mitum/util/encoder/json"
 	"github.com/spikeekips/mitum/util/hint"
 	"github.com/spikeekips/mitum/util/logging"
+	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/syndtr/goleveldb/leveldb"
+	leveldbOpt "github.com/syndtr/goleveldb/leveldb/opt"
 	leveldbStorage "github.com/syndtr/goleveldb/leveldb/storage"
 	leveldbutil "github.com/syndtr/goleveldb/leveldb/util"
 )
@@ -40,6 +47,16 @@ var (
 	keyPrefixStagedOperationFactHashReverse []byte = []byte{0x00, 0x16}
 )
 
+var leveldbKeyDelimiter = []byte{0x00}
+
+// Migrations is the registry of the schema migrations of leveldb database.
+var Migrations = storage.NewMigrations()
+
+const (
+	SchemeLeveldb = "leveldb"
+	SchemeMemory  = "memory"
+)
+
 type Database struct {
 	*logging.Logging
 	db   *leveldb.DB
@@ -63,6 +80,42 @@ func NewMemDatabase(encs *encoder.Encoders, enc encoder.Encoder) *Database {
 	return NewDatabase(db, encs, enc)
 }
 
+// NewDatabaseFromURI opens leveldb database by uri. "leveldb:///path/to/dir"
+// opens the leveldb files under the given directory and "memory://" opens the
+// in-memory database.
+func NewDatabaseFromURI(uri string, encs *encoder.Encoders) (*Database, error) {
+	parsed, err := network.ParseURL(uri, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid storge uri")
+	}
+
+	var enc encoder.Encoder
+	if e, err := encs.Encoder(jsonenc.JSONEncoderType, ""); err != nil { // NOTE get latest json encoder
+		return nil, errors.Wrap(err, "json encoder needs for leveldb")
+	} else {
+		enc = e
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case SchemeMemory:
+		return NewMemDatabase(encs, enc), nil
+	case SchemeLeveldb:
+		path := parsed.Host + parsed.Path
+		if len(strings.TrimSpace(path)) < 1 {
+			return nil, errors.Errorf("empty leveldb path, %q", uri)
+		}
+
+		db, err := leveldb.OpenFile(path, &leveldbOpt.Options{ErrorIfMissing: false})
+		if err != nil {
+			return nil, mergeError(errors.Wrapf(err, "failed to open leveldb, %q", path))
+		}
+
+		return NewDatabase(db, encs, enc), nil
+	default:
+		return nil, errors.Errorf("not leveldb uri, %q", uri)
+	}
+}
+
 func (st *Database) Initialize() error {
 	return nil
 }
@@ -82,11 +135,20 @@ func (st *Database) Close() error {
 func (st *Database) Clean() error {
 	batch := &leveldb.Batch{}
 
+	limit := 500
 	if err := st.iter(
 		nil,
 		func(key, _ []byte) (bool, error) {
 			batch.Delete(key)
 
+			if batch.Len() == limit {
+				if err := mergeError(st.db.Write(batch, nil)); err != nil {
+					return false, err
+				}
+
+				batch = &leveldb.Batch{}
+			}
+
 			return true, nil
 		},
 		false,
@@ -94,7 +156,14 @@ func (st *Database) Clean() error {
 		return err
 	}
 
-	return mergeError(st.db.Write(batch, nil))
+	if batch.Len() > 0 {
+		if err := mergeError(st.db.Write(batch, nil)); err != nil {
+			return err
+		}
+	}
+
+	// NOTE the cleaned database follows the current schema.
+	return storage.SetSchemaVersion(st, Migrations.Latest())
 }
 
 func (st *Database) CleanByHeight(height base.Height) error {
@@ -102,32 +171,226 @@ func (st *Database) CleanByHeight(height base.Height) error {
 		return st.Clean()
 	}
 
-	// NOTE not perfectly working as intended :)
 	batch := &leveldb.Batch{}
 
-	h := height
-end:
-	for {
-		switch m, found, err := st.ManifestByHeight(h); {
-		case err != nil:
+	if err := st.iterFrom(
+		keyPrefixManifestHeight,
+		leveldbManifestHeightKey(height),
+		func(key, value []byte) (bool, error) {
+			h, err := st.loadHash(value)
+			if err != nil {
+				return false, err
+			}
+
+			ht, err := leveldbHeightFromKey(keyPrefixManifestHeight, key)
+			if err != nil {
+				return false, err
+			}
+
+			batch.Delete(key)
+			batch.Delete(leveldbBlockHeightKey(ht))
+			batch.Delete(leveldbBlockHashKey(h))
+			batch.Delete(leveldbManifestKey(h))
+
+			if err := st.cleanOperationsByHeight(batch, ht); err != nil {
+				return false, err
+			}
+
+			if err := st.cleanStatesByHeight(batch, ht); err != nil {
+				return false, err
+			}
+
+			return true, nil
+		},
+	); err != nil {
+		return err
+	}
+
+	for _, k := range [][]byte{
+		leveldbVoteproofKey(height, base.StageINIT),
+		leveldbVoteproofKey(height, base.StageACCEPT),
+		leveldbBlockdataMapKey(height),
+	} {
+		if err := st.iterFrom(k[:2], k, func(key, _ []byte) (bool, error) {
+			batch.Delete(key)
+
+			return true, nil
+		}); err != nil {
 			return err
-		case !found:
-			break end
-		default:
-			batch.Delete(leveldbBlockHeightKey(h))
-			batch.Delete(leveldbBlockHashKey(m.Hash()))
-			batch.Delete(leveldbManifestHeightKey(h))
-			batch.Delete(leveldbManifestKey(m.Hash()))
-			batch.Delete(leveldbBlockOperationsKey(m))
-			batch.Delete(leveldbBlockStatesKey(m))
 		}
+	}
+
+	if err := st.iterFrom(
+		keyPrefixProposalFacts,
+		util.ConcatBytesSlice(keyPrefixProposalFacts, leveldbHeightBytes(height)),
+		func(key, value []byte) (bool, error) {
+			batch.Delete(key)
+			batch.Delete(value)
 
-		h++
+			return true, nil
+		},
+	); err != nil {
+		return err
 	}
 
 	return mergeError(st.db.Write(batch, nil))
 }
 
+func (st *Database) cleanOperationsByHeight(batch *leveldb.Batch, height base.Height) error {
+	key := leveldbBlockOperationsKey(height)
+
+	raw, err := st.get(key)
+	if err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return nil
+		}
+
+		return err
+	}
+
+	batch.Delete(key)
+
+	var tr tree.FixedTree
+	switch i, err := st.loadHinter(raw); {
+	case err != nil:
+		return err
+	case i == nil:
+		return nil
+	default:
+		j, ok := i.(tree.FixedTree)
+		if !ok {
+			return errors.Errorf("not tree.FixedTree: %T", i)
+		}
+		tr = j
+	}
+
+	return tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
+		batch.Delete(leveldbOperationFactHashKey(valuehash.NewBytes(no.Key())))
+
+		return true, nil
+	})
+}
+
+func (st *Database) cleanStatesByHeight(batch *leveldb.Batch, height base.Height) error {
+	prefix := util.ConcatBytesSlice(keyPrefixBlockStates, leveldbHeightBytes(height))
+
+	return st.iter(
+		prefix,
+		func(key, _ []byte) (bool, error) {
+			batch.Delete(key)
+			batch.Delete(leveldbStateKey(string(key[len(prefix):]), height))
+
+			return true, nil
+		},
+		true,
+	)
+}
+
+// Prune removes the blocks and the old state versions lower than height. For
+// each state key, the latest version lower than height is kept. The manifests,
+// voteproofs, block data maps and operation facts are kept; the operation facts
+// are needed by HasOperationFact.
+func (st *Database) Prune(height base.Height) error {
+	switch m, found, err := st.LastManifest(); {
+	case err != nil:
+		return err
+	case !found:
+		return util.NotFoundError.Errorf("empty database")
+	case height > m.Height():
+		return errors.Errorf("prune height, %d is higher than last block, %d", height, m.Height())
+	}
+
+	switch h, err := storage.PrunedHeight(st); {
+	case err != nil:
+		return err
+	case height <= h:
+		return nil
+	}
+
+	batch := &leveldb.Batch{}
+
+	if err := st.iter(
+		keyPrefixBlockHeight,
+		func(key, value []byte) (bool, error) {
+			switch ht, err := leveldbHeightFromKey(keyPrefixBlockHeight, key); {
+			case err != nil:
+				return false, err
+			case ht >= height:
+				return false, nil
+			default:
+				h, err := st.loadHash(value)
+				if err != nil {
+					return false, err
+				}
+
+				// NOTE the height key is kept for ManifestByHeight and the
+				// operation facts are kept for HasOperationFact.
+				batch.Delete(leveldbBlockHashKey(h))
+
+				return true, nil
+			}
+		},
+		true,
+	); err != nil {
+		return err
+	}
+
+	if err := st.pruneStates(batch, height); err != nil {
+		return err
+	}
+
+	if err := mergeError(st.db.Write(batch, nil)); err != nil {
+		return err
+	}
+
+	return storage.SetPrunedHeight(st, height)
+}
+
+// pruneStates removes the state versions lower than height except the latest
+// one of each key.
+func (st *Database) pruneStates(batch *leveldb.Batch, height base.Height) error {
+	var lastKey []byte
+	var last []byte
+
+	return st.iter(
+		keyPrefixState,
+		func(k, _ []byte) (bool, error) {
+			// NOTE key is kept over the next iteration, so it is copied.
+			k = append([]byte(nil), k...)
+			sk := k[:len(k)-20]
+			h, err := leveldbHeightFromKey(sk, k)
+			if err != nil {
+				return false, err
+			}
+
+			if !bytes.Equal(sk, lastKey) {
+				lastKey = sk
+				last = nil
+			}
+
+			if h >= height {
+				return true, nil
+			}
+
+			if last != nil {
+				key := string(sk[len(keyPrefixState) : len(sk)-len(leveldbKeyDelimiter)])
+				lh, err := leveldbHeightFromKey(sk, last)
+				if err != nil {
+					return false, err
+				}
+
+				batch.Delete(last)
+				batch.Delete(leveldbBlockStatesKey(lh, key))
+			}
+
+			last = k
+
+			return true, nil
+		},
+		true,
+	)
+}
+
 func (st *Database) Copy(source storage.Database) error {
 	var sst *Database
 	if s, ok := source.(*Database); !ok {
@@ -296,15 +559,32 @@ func (st *Database) Manifests(load, reverse bool, limit int64, callback func(bas
 	var counted int64
 	return st.iter(
 		keyPrefixManifestHeight,
-		func(_, value []byte) (bool, error) {
+		func(key, value []byte) (bool, error) {
 			counted++
 
-			m, err := st.loadManifest(value)
+			height, err := leveldbHeightFromKey(keyPrefixManifestHeight, key)
+			if err != nil {
+				return false, err
+			}
+
+			h, err := st.loadHash(value)
 			if err != nil {
 				return false, err
 			}
 
-			switch keep, err := callback(m.Height(), m.Hash(), m); {
+			var m block.Manifest
+			if load {
+				switch i, found, err := st.Manifest(h); {
+				case err != nil:
+					return false, err
+				case !found:
+					return false, util.NotFoundError.Errorf("manifest, %d not found", height)
+				default:
+					m = i
+				}
+			}
+
+			switch keep, err := callback(height, h, m); {
 			case err != nil:
 				return false, err
 			case !keep:
@@ -581,6 +861,30 @@ func (st *Database) iter(
 	return mergeError(iter.Error())
 }
 
+// iterFrom iterates the keys of prefix in ascending order, starting from the
+// given key.
+func (st *Database) iterFrom(
+	prefix []byte,
+	start []byte,
+	callback func([]byte /* key */, []byte /* value */) (bool, error),
+) error {
+	r := leveldbutil.BytesPrefix(prefix)
+	r.Start = start
+
+	iter := st.db.NewIterator(r, nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		if keep, err := callback(util.CopyBytes(iter.Key()), util.CopyBytes(iter.Value())); err != nil {
+			return err
+		} else if !keep {
+			break
+		}
+	}
+
+	return mergeError(iter.Error())
+}
+
 func (st *Database) HasStagedOperation(fact valuehash.Hash) (bool, error) {
 	found, err := st.db.Has(st.newStagedOperationReverseKey(fact), nil)
 
@@ -643,13 +947,18 @@ func (st *Database) UnstagedOperations(facts []valuehash.Hash) error {
 	return mergeError(st.db.Write(batch, nil))
 }
 
+// Proposals iterates the proposals by the order of height through the proposal
+// facts index.
 func (st *Database) Proposals(callback func(base.Proposal) (bool, error), sort bool) error {
 	return st.iter(
-		keyPrefixProposal,
+		keyPrefixProposalFacts,
 		func(_, value []byte) (bool, error) {
-			if proposal, err := st.loadProposal(value); err != nil {
+			switch proposal, found, err := st.proposalByKey(value); {
+			case err != nil:
 				return false, err
-			} else {
+			case !found:
+				return true, nil
+			default:
 				return callback(proposal)
 			}
 		},
@@ -666,7 +975,12 @@ func (st *Database) proposalKey(h valuehash.Hash) []byte {
 }
 
 func (st *Database) proposalFactsKey(height base.Height, round base.Round, proposer base.Address) []byte {
-	return util.ConcatBytesSlice(keyPrefixProposalFacts, height.Bytes(), round.Bytes(), proposer.Bytes())
+	return util.ConcatBytesSlice(
+		keyPrefixProposalFacts,
+		leveldbHeightBytes(height),
+		[]byte(fmt.Sprintf("%020d", round.Uint64())),
+		proposer.Bytes(),
+	)
 }
 
 func (st *Database) NewProposal(proposal base.Proposal) error {
@@ -711,28 +1025,173 @@ func (st *Database) ProposalByPoint(height base.Height, round base.Round, propos
 }
 
 func (st *Database) State(key string) (state.State, bool, error) {
-	b, err := st.get(leveldbStateKey(key))
+	var raw []byte
+	if err := st.iter(
+		leveldbStateKeyPrefix(key),
+		func(_, value []byte) (bool, error) {
+			raw = value
+
+			return false, nil
+		},
+		false,
+	); err != nil {
+		return nil, false, err
+	}
+
+	if raw == nil {
+		return nil, false, nil
+	}
+
+	stt, err := st.loadState(raw)
 	if err != nil {
-		if errors.Is(err, util.NotFoundError) {
-			return nil, false, nil
-		}
+		return nil, false, err
+	}
 
+	return stt, stt != nil, nil
+}
+
+func (st *Database) StateAtHeight(key string, height base.Height) (state.State, bool, error) {
+	prefix := leveldbStateKeyPrefix(key)
+
+	var stt state.State
+	if err := st.iter(
+		prefix,
+		func(k, value []byte) (bool, error) {
+			switch h, err := leveldbHeightFromKey(prefix, k); {
+			case err != nil:
+				return false, err
+			case h > height:
+				return true, nil
+			default:
+				i, err := st.loadState(value)
+				if err != nil {
+					return false, err
+				}
+				stt = i
+
+				return false, nil
+			}
+		},
+		false,
+	); err != nil {
 		return nil, false, err
 	}
 
-	stt, err := st.loadState(b)
+	return stt, stt != nil, nil
+}
+
+func (st *Database) StateHistory(
+	key string,
+	height base.Height,
+	limit int64,
+	callback func(state.State) (bool, error),
+) error {
+	prefix := leveldbStateKeyPrefix(key)
+
+	var count int64
+
+	return st.iter(
+		prefix,
+		func(k, value []byte) (bool, error) {
+			if height > base.NilHeight {
+				switch h, err := leveldbHeightFromKey(prefix, k); {
+				case err != nil:
+					return false, err
+				case h >= height:
+					return true, nil
+				}
+			}
+
+			stt, err := st.loadState(value)
+			if err != nil {
+				return false, err
+			}
+
+			if keep, err := callback(stt); err != nil || !keep {
+				return false, err
+			}
+
+			count++
+
+			return limit < 1 || count < limit, nil
+		},
+		false,
+	)
+}
+
+func (st *Database) States(height base.Height, callback func(state.State) (bool, error)) error {
+	var lastKey []byte
+	var last []byte
+
+	flush := func() (bool, error) {
+		if last == nil {
+			return true, nil
+		}
+
+		stt, err := st.loadState(last)
+		if err != nil {
+			return false, err
+		}
+
+		last = nil
+
+		return callback(stt)
+	}
+
+	var stopped bool
+	if err := st.iter(
+		keyPrefixState,
+		func(k, value []byte) (bool, error) {
+			// NOTE key is prefix + state key + delimiter + height
+			if len(k) < len(keyPrefixState)+len(leveldbKeyDelimiter)+20 {
+				return false, errors.Errorf("too short state key")
+			}
+
+			// NOTE key and value are kept over the next iteration, so they
+			// are copied.
+			sk := k[:len(k)-20]
+			h, err := leveldbHeightFromKey(sk, k)
+			if err != nil {
+				return false, err
+			}
+
+			if !bytes.Equal(sk, lastKey) {
+				if keep, err := flush(); err != nil || !keep {
+					stopped = true
+
+					return false, err
+				}
+
+				lastKey = append([]byte(nil), sk...)
+			}
+
+			if h <= height {
+				last = append([]byte(nil), value...)
+			}
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return err
+	}
+
+	if stopped {
+		return nil
+	}
+
+	_, err := flush()
 
-	return stt, st != nil, err
+	return err
 }
 
 func (st *Database) NewState(sta state.State) error {
-	if b, err := marshal(sta, st.enc); err != nil {
+	batch := &leveldb.Batch{}
+	if err := setState(batch, sta, st.enc); err != nil {
 		return err
-	} else if err := st.db.Put(leveldbStateKey(sta.Key()), b, nil); err != nil {
-		return mergeError(err)
 	}
 
-	return nil
+	return mergeError(st.db.Write(batch, nil))
 }
 
 func (st *Database) HasOperationFact(h valuehash.Hash) (bool, error) {
@@ -741,6 +1200,24 @@ func (st *Database) HasOperationFact(h valuehash.Hash) (bool, error) {
 	return found, mergeError(err)
 }
 
+func (st *Database) OperationFactHeight(h valuehash.Hash) (base.Height, bool, error) {
+	b, err := st.get(leveldbOperationFactHashKey(h))
+	if err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return base.NilHeight, false, nil
+		}
+
+		return base.NilHeight, false, err
+	}
+
+	height, err := base.NewHeightFromBytes(b)
+	if err != nil {
+		return base.NilHeight, false, err
+	}
+
+	return height, true, nil
+}
+
 func (st *Database) NewSession(blk block.Block) (storage.DatabaseSession, error) {
 	return NewSession(st, blk)
 }
@@ -804,6 +1281,10 @@ func (st *Database) LastVoteproof(stage base.Stage) base.Voteproof {
 func (st *Database) Voteproof(height base.Height, stage base.Stage) (base.Voteproof, error) {
 	var raw []byte
 	if b, err := st.get(leveldbVoteproofKey(height, stage)); err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return nil, nil
+		}
+
 		return nil, err
 	} else {
 		raw = b
@@ -816,7 +1297,7 @@ func (st *Database) Voteproof(height base.Height, stage base.Stage) (base.Votepr
 	if i, err := st.loadHinter(raw); err != nil {
 		return nil, err
 	} else if j, ok := i.(base.Voteproof); !ok {
-		return nil, errors.Errorf("wrong voteproof, not %t", i)
+		return nil, errors.Errorf("wrong voteproof, not %T", i)
 	} else {
 		return j, nil
 	}
@@ -855,21 +1336,19 @@ func (st *Database) SetBlockdataMaps(bds []block.BlockdataMap) error {
 }
 
 func (st *Database) LocalBlockdataMapsByHeight(height base.Height, callback func(block.BlockdataMap) (bool, error)) error {
-	return st.iter(
+	return st.iterFrom(
 		keyPrefixBlockdataMap,
+		leveldbBlockdataMapKey(height),
 		func(_, value []byte) (bool, error) {
 			switch bd, err := st.loadBlockdataMap(value); {
 			case err != nil:
 				return false, err
-			case bd.Height() < height:
-				return true, nil
 			case !bd.IsLocal():
 				return true, nil
 			default:
 				return callback(bd)
 			}
 		},
-		true,
 	)
 }
 
@@ -901,17 +1380,34 @@ func (st *Database) newStagedOperationFilter() func(valuehash.Hash) (bool, error
 	}
 }
 
+func leveldbHeightBytes(height base.Height) []byte {
+	return []byte(fmt.Sprintf("%020d", height.Int64()))
+}
+
+func leveldbHeightFromKey(prefix, key []byte) (base.Height, error) {
+	if len(key) < len(prefix)+20 {
+		return base.NilHeight, errors.Errorf("too short key for height")
+	}
+
+	i, err := strconv.ParseInt(string(key[len(prefix):len(prefix)+20]), 10, 64)
+	if err != nil {
+		return base.NilHeight, errors.Wrap(err, "invalid height in key")
+	}
+
+	return base.Height(i), nil
+}
+
 func leveldbBlockHeightKey(height base.Height) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixBlockHeight,
-		[]byte(fmt.Sprintf("%020d", height.Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
 func leveldbManifestHeightKey(height base.Height) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixManifestHeight,
-		[]byte(fmt.Sprintf("%020d", height.Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
@@ -929,24 +1425,37 @@ func leveldbManifestKey(h valuehash.Hash) []byte {
 	)
 }
 
-func leveldbBlockOperationsKey(blk block.Manifest) []byte {
+func leveldbBlockOperationsKey(height base.Height) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixBlockOperations,
-		[]byte(fmt.Sprintf("%020d", blk.Height().Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
-func leveldbBlockStatesKey(blk block.Manifest) []byte {
+// leveldbBlockStatesKey indexes the state keys, which are updated in the
+// block.
+func leveldbBlockStatesKey(height base.Height, key string) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixBlockStates,
-		[]byte(fmt.Sprintf("%020d", blk.Height().Int64())),
+		leveldbHeightBytes(height),
+		[]byte(key),
 	)
 }
 
-func leveldbStateKey(key string) []byte {
+func leveldbStateKeyPrefix(key string) []byte {
 	return util.ConcatBytesSlice(
 		keyPrefixState,
 		[]byte(key),
+		leveldbKeyDelimiter,
+	)
+}
+
+// leveldbStateKey keeps every version of state by height; the last one is the
+// latest state.
+func leveldbStateKey(key string, height base.Height) []byte {
+	return util.ConcatBytesSlice(
+		leveldbStateKeyPrefix(key),
+		leveldbHeightBytes(height),
 	)
 }
 
@@ -970,12 +1479,12 @@ func leveldbVoteproofKey(height base.Height, stage base.Stage) []byte {
 
 	return util.ConcatBytesSlice(
 		prefix,
-		[]byte(fmt.Sprintf("%020d", height.Int64())),
+		leveldbHeightBytes(height),
 	)
 }
 
 func leveldbBlockdataMapKey(height base.Height) []byte {
-	return util.ConcatBytesSlice(keyPrefixBlockdataMap, height.Bytes())
+	return util.ConcatBytesSlice(keyPrefixBlockdataMap, leveldbHeightBytes(height))
 }
 
 func leveldbUnstageOperations(st *Database, batch *leveldb.Batch, facts []valuehash.Hash) error {
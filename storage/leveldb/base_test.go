@@ -2,13 +2,19 @@ package leveldbstorage
 
 import (
 	"context"
+	"os"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/stretchr/testify/suite"
 )
@@ -18,10 +24,72 @@ type testDatabase struct {
 	database *Database
 }
 
+func (t *testDatabase) SetupSuite() {
+	t.BaseTestDatabase.SetupSuite()
+
+	_ = t.Encs.TestAddHinter(state.StateV0{})
+	_ = t.Encs.TestAddHinter(state.StringValueHinter)
+	_ = t.Encs.TestAddHinter(operation.FixedTreeNodeHinter)
+}
+
 func (t *testDatabase) SetupTest() {
 	t.database = NewMemDatabase(t.Encs, t.JSONEnc)
 }
 
+func (t *testDatabase) TearDownTest() {
+	_ = t.database.Close()
+}
+
+func (t *testDatabase) newBlock(height base.Height, sts []state.State, ops []valuehash.Hash) block.Block {
+	blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	i := (interface{})(blk).(block.BlockUpdater)
+	i = i.SetINITVoteproof(base.NewVoteproofV0(blk.Height(), blk.Round(), nil, base.ThresholdRatio(100), base.StageINIT))
+	i = i.SetACCEPTVoteproof(base.NewVoteproofV0(blk.Height(), blk.Round(), nil, base.ThresholdRatio(100), base.StageACCEPT))
+	i = i.SetStates(sts)
+
+	if len(ops) > 0 {
+		tg := tree.NewFixedTreeGenerator(uint64(len(ops)))
+		for j := range ops {
+			t.NoError(tg.Add(operation.NewFixedTreeNode(uint64(j), ops[j].Bytes(), true, nil)))
+		}
+
+		tr, err := tg.Tree()
+		t.NoError(err)
+
+		i = i.SetOperationsTree(tr)
+	}
+
+	return i.(block.BlockV0)
+}
+
+func (t *testDatabase) saveNewBlock(height base.Height) (block.Block, block.BlockdataMap) {
+	return t.saveBlock(t.newBlock(height, nil, nil))
+}
+
+func (t *testDatabase) saveBlock(blk block.Block) (block.Block, block.BlockdataMap) {
+	bs, err := t.database.NewSession(blk)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), blk))
+	bd := t.NewBlockdataMap(blk.Height(), blk.Hash(), true)
+	t.NoError(bs.Commit(context.Background(), bd))
+	t.NoError(bs.Close())
+
+	return blk, bd
+}
+
+func (t *testDatabase) newState(key, value string, height base.Height) state.State {
+	v, err := state.NewStringValue(value)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(key, v, height)
+	t.NoError(err)
+
+	return st
+}
+
 func (t *testDatabase) TestNew() {
 	t.Implements((*storage.Database)(nil), t.database)
 }
@@ -419,6 +487,489 @@ func (t *testDatabase) TestLocalBlockdataMapsByHeight() {
 	t.NoError(err)
 }
 
+func (t *testDatabase) TestSetBlockContext() {
+	blk := t.newBlock(base.Height(33), nil, nil)
+
+	bs, err := t.database.NewSession(blk)
+	t.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond*1)
+	defer cancel()
+
+	<-ctx.Done()
+
+	err = bs.SetBlock(ctx, blk)
+	t.True(errors.Is(err, context.DeadlineExceeded))
+}
+
+func (t *testDatabase) TestSaveBlockContext() {
+	blk := t.newBlock(base.Height(33), nil, nil)
+
+	bs, err := t.database.NewSession(blk)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), blk))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond*1)
+	defer cancel()
+
+	bd := t.NewBlockdataMap(blk.Height(), blk.Hash(), true)
+	err = bs.Commit(ctx, bd)
+	t.True(errors.Is(err, context.DeadlineExceeded))
+
+	_, found, err := t.database.LastManifest()
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testDatabase) TestCancelSession() {
+	blk := t.newBlock(base.Height(33), nil, nil)
+
+	bs, err := t.database.NewSession(blk)
+	t.NoError(err)
+
+	t.NoError(bs.SetBlock(context.Background(), blk))
+	t.NoError(bs.Cancel())
+
+	_, found, err := t.database.LastManifest()
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testDatabase) TestVoteproofs() {
+	blk, _ := t.saveNewBlock(base.Height(33))
+
+	for _, stage := range []base.Stage{base.StageINIT, base.StageACCEPT} {
+		vp, err := t.database.Voteproof(blk.Height(), stage)
+		t.NoError(err)
+		t.NotNil(vp)
+		t.Equal(blk.Height(), vp.Height())
+		t.Equal(stage, vp.Stage())
+
+		lvp := t.database.LastVoteproof(stage)
+		t.NotNil(lvp)
+		t.Equal(blk.Height(), lvp.Height())
+	}
+
+	vp, err := t.database.Voteproof(blk.Height()+1, base.StageINIT)
+	t.NoError(err)
+	t.Nil(vp)
+}
+
+func (t *testDatabase) TestManifests() {
+	var blocks []block.Block
+	for i := base.Height(0); i < 5; i++ {
+		blk, _ := t.saveNewBlock(i)
+		blocks = append(blocks, blk)
+	}
+
+	var heights []base.Height
+	t.NoError(t.database.Manifests(true, false, 0, func(height base.Height, h valuehash.Hash, m block.Manifest) (bool, error) {
+		heights = append(heights, height)
+
+		t.True(h.Equal(blocks[height].Hash()))
+		t.CompareManifest(blocks[height].Manifest(), m)
+
+		return true, nil
+	}))
+	t.Equal([]base.Height{0, 1, 2, 3, 4}, heights)
+
+	heights = nil
+	t.NoError(t.database.Manifests(false, true, 3, func(height base.Height, h valuehash.Hash, m block.Manifest) (bool, error) {
+		heights = append(heights, height)
+
+		t.True(h.Equal(blocks[height].Hash()))
+		t.Nil(m)
+
+		return true, nil
+	}))
+	t.Equal([]base.Height{4, 3, 2}, heights)
+}
+
+func (t *testDatabase) TestState() {
+	key := util.UUID().String()
+
+	_, found, err := t.database.State(key)
+	t.NoError(err)
+	t.False(found)
+
+	st33 := t.newState(key, "33", base.Height(33))
+	t.saveBlock(t.newBlock(base.Height(33), []state.State{st33}, nil))
+
+	st34 := t.newState(key, "34", base.Height(34))
+	t.saveBlock(t.newBlock(base.Height(34), []state.State{st34}, nil))
+
+	ust, found, err := t.database.State(key)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(st34.Value().Interface(), ust.Value().Interface())
+	t.Equal(base.Height(34), ust.Height())
+
+	st35 := t.newState(key, "35", base.Height(35))
+	t.NoError(t.database.NewState(st35))
+
+	ust, found, err = t.database.State(key)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(st35.Value().Interface(), ust.Value().Interface())
+}
+
+func (t *testDatabase) TestStateAtHeight() {
+	key := util.UUID().String()
+
+	for _, i := range []base.Height{33, 35} {
+		st := t.newState(key, i.String(), i)
+		t.saveBlock(t.newBlock(i, []state.State{st}, nil))
+	}
+
+	_, found, err := t.database.StateAtHeight(key, base.Height(32))
+	t.NoError(err)
+	t.False(found)
+
+	for height, expected := range map[base.Height]base.Height{33: 33, 34: 33, 35: 35, 36: 35} {
+		st, found, err := t.database.StateAtHeight(key, height)
+		t.NoError(err)
+		t.True(found)
+		t.Equal(expected, st.Height())
+		t.Equal(expected.String(), st.Value().Interface())
+	}
+
+	_, found, err = t.database.StateAtHeight(util.UUID().String(), base.Height(35))
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testDatabase) TestStateHistory() {
+	key := util.UUID().String()
+
+	for _, i := range []base.Height{33, 34, 35} {
+		st := t.newState(key, i.String(), i)
+		t.saveBlock(t.newBlock(i, []state.State{st}, nil))
+	}
+
+	collect := func(height base.Height, limit int64) []base.Height {
+		var heights []base.Height
+		t.NoError(t.database.StateHistory(key, height, limit, func(st state.State) (bool, error) {
+			t.Equal(key, st.Key())
+			t.Equal(st.Height().String(), st.Value().Interface())
+
+			heights = append(heights, st.Height())
+
+			return true, nil
+		}))
+
+		return heights
+	}
+
+	t.Equal([]base.Height{35, 34, 33}, collect(base.NilHeight, 0))
+	t.Equal([]base.Height{35, 34}, collect(base.NilHeight, 2))
+	t.Equal([]base.Height{34, 33}, collect(base.Height(35), 0))
+	t.Equal([]base.Height{33}, collect(base.Height(34), 1))
+	t.Empty(collect(base.Height(33), 0))
+
+	{ // unknown
+		var found bool
+		t.NoError(t.database.StateHistory(util.UUID().String(), base.NilHeight, 0, func(state.State) (bool, error) {
+			found = true
+
+			return true, nil
+		}))
+		t.False(found)
+	}
+}
+
+func (t *testDatabase) TestStates() {
+	keys := []string{"a", "ab", "b"}
+
+	for _, i := range []base.Height{33, 34, 35} {
+		var sts []state.State
+		for _, key := range keys {
+			if key == "b" && i == 35 {
+				continue
+			}
+
+			sts = append(sts, t.newState(key, key+i.String(), i))
+		}
+
+		t.saveBlock(t.newBlock(i, sts, nil))
+	}
+
+	collect := func(height base.Height) map[string]base.Height {
+		var ks []string
+		m := map[string]base.Height{}
+		t.NoError(t.database.States(height, func(st state.State) (bool, error) {
+			t.Equal(st.Key()+st.Height().String(), st.Value().Interface())
+
+			ks = append(ks, st.Key())
+			m[st.Key()] = st.Height()
+
+			return true, nil
+		}))
+
+		t.True(sort.StringsAreSorted(ks))
+
+		return m
+	}
+
+	t.Equal(map[string]base.Height{"a": 35, "ab": 35, "b": 34}, collect(base.Height(35)))
+	t.Equal(map[string]base.Height{"a": 34, "ab": 34, "b": 34}, collect(base.Height(34)))
+	t.Equal(map[string]base.Height{"a": 33, "ab": 33, "b": 33}, collect(base.Height(33)))
+	t.Empty(collect(base.Height(32)))
+
+	{ // NOTE stop in the middle
+		var count int
+		t.NoError(t.database.States(base.Height(35), func(state.State) (bool, error) {
+			count++
+
+			return false, nil
+		}))
+		t.Equal(1, count)
+	}
+}
+
+func (t *testDatabase) TestOperationFactHeight() {
+	fact := valuehash.RandomSHA256()
+	t.saveBlock(t.newBlock(base.Height(33), nil, []valuehash.Hash{valuehash.RandomSHA256(), fact}))
+
+	height, found, err := t.database.OperationFactHeight(fact)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(33), height)
+
+	{ // unknown
+		height, found, err := t.database.OperationFactHeight(valuehash.RandomSHA256())
+		t.NoError(err)
+		t.False(found)
+		t.Equal(base.NilHeight, height)
+	}
+}
+
+func (t *testDatabase) TestCleanByHeight() {
+	key := util.UUID().String()
+
+	var blocks []block.Block
+	var facts []valuehash.Hash
+	for i := base.Height(33); i < 38; i++ {
+		fact := valuehash.RandomSHA256()
+		facts = append(facts, fact)
+
+		blk, _ := t.saveBlock(t.newBlock(i, []state.State{t.newState(key, i.String(), i)}, []valuehash.Hash{fact}))
+		blocks = append(blocks, blk)
+	}
+
+	t.NoError(t.database.CleanByHeight(base.Height(35)))
+
+	m, found, err := t.database.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(34), m.Height())
+
+	for i := range blocks {
+		blk := blocks[i]
+		removed := blk.Height() >= 35
+
+		_, found, err := t.database.ManifestByHeight(blk.Height())
+		t.NoError(err)
+		t.Equal(!removed, found)
+
+		_, found, err = t.database.Manifest(blk.Hash())
+		t.NoError(err)
+		t.Equal(!removed, found)
+
+		_, found, err = t.database.BlockdataMap(blk.Height())
+		t.NoError(err)
+		t.Equal(!removed, found)
+
+		vp, err := t.database.Voteproof(blk.Height(), base.StageACCEPT)
+		t.NoError(err)
+		t.Equal(!removed, vp != nil)
+
+		found, err = t.database.HasOperationFact(facts[i])
+		t.NoError(err)
+		t.Equal(!removed, found)
+	}
+
+	st, found, err := t.database.State(key)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(34), st.Height())
+
+	lvp := t.database.LastVoteproof(base.StageACCEPT)
+	t.NotNil(lvp)
+	t.Equal(base.Height(34), lvp.Height())
+
+	t.NoError(t.database.CleanByHeight(base.PreGenesisHeight))
+
+	_, found, err = t.database.LastManifest()
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testDatabase) TestPrune() {
+	keyA := util.UUID().String()
+	keyB := util.UUID().String()
+
+	var blocks []block.Block
+	var facts []valuehash.Hash
+	for i := base.Height(33); i < 38; i++ {
+		fact := valuehash.RandomSHA256()
+		facts = append(facts, fact)
+
+		sts := []state.State{t.newState(keyA, i.String(), i)}
+		if i == 33 {
+			sts = append(sts, t.newState(keyB, i.String(), i))
+		}
+
+		blk, _ := t.saveBlock(t.newBlock(i, sts, []valuehash.Hash{fact}))
+		blocks = append(blocks, blk)
+	}
+
+	h, err := storage.PrunedHeight(t.database)
+	t.NoError(err)
+	t.Equal(base.NilHeight, h)
+
+	t.NoError(t.database.Prune(base.Height(36)))
+
+	h, err = storage.PrunedHeight(t.database)
+	t.NoError(err)
+	t.Equal(base.Height(36), h)
+
+	for i := range blocks {
+		blk := blocks[i]
+		pruned := blk.Height() < 36
+
+		_, found, err := t.database.ManifestByHeight(blk.Height())
+		t.NoError(err)
+		t.True(found)
+
+		_, found, err = t.database.blockByHeight(blk.Height())
+		t.NoError(err)
+		t.Equal(!pruned, found)
+
+		// NOTE the operation facts of pruned heights are kept
+		found, err = t.database.HasOperationFact(facts[i])
+		t.NoError(err)
+		t.True(found)
+
+		height, found, err := t.database.OperationFactHeight(facts[i])
+		t.NoError(err)
+		t.True(found)
+		t.Equal(blk.Height(), height)
+	}
+
+	// NOTE the latest states are kept
+	st, found, err := t.database.State(keyA)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(37), st.Height())
+
+	st, found, err = t.database.State(keyB)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(33), st.Height())
+
+	var heights []base.Height
+	t.NoError(t.database.StateHistory(keyA, base.NilHeight, 0, func(st state.State) (bool, error) {
+		heights = append(heights, st.Height())
+
+		return true, nil
+	}))
+	t.Equal([]base.Height{37, 36, 35}, heights)
+
+	// NOTE the states of pruned height are still served
+	var sts []state.State
+	t.NoError(t.database.States(base.Height(36), func(st state.State) (bool, error) {
+		sts = append(sts, st)
+
+		return true, nil
+	}))
+	t.Equal(2, len(sts))
+
+	// NOTE lower height is ignored
+	t.NoError(t.database.Prune(base.Height(34)))
+
+	h, err = storage.PrunedHeight(t.database)
+	t.NoError(err)
+	t.Equal(base.Height(36), h)
+
+	err = t.database.Prune(base.Height(38))
+	t.Error(err)
+	t.Contains(err.Error(), "higher than last block")
+}
+
+func (t *testDatabase) TestCopy() {
+	for i := base.Height(33); i < 36; i++ {
+		_, _ = t.saveNewBlock(i)
+	}
+
+	other := NewMemDatabase(t.Encs, t.JSONEnc)
+	defer other.Close()
+
+	t.NoError(other.Copy(t.database))
+
+	for i := base.Height(33); i < 36; i++ {
+		a, found, err := t.database.ManifestByHeight(i)
+		t.NoError(err)
+		t.True(found)
+
+		b, found, err := other.ManifestByHeight(i)
+		t.NoError(err)
+		t.True(found)
+
+		t.CompareManifest(a, b)
+	}
+
+	err := other.Copy(dummyDatabase{})
+	t.Error(err)
+	t.Contains(err.Error(), "only leveldbstorage.Database")
+}
+
+func (t *testDatabase) TestNewDatabaseFromURI() {
+	{
+		st, err := NewDatabaseFromURI("memory://", t.Encs)
+		t.NoError(err)
+		t.NoError(st.Close())
+	}
+
+	{
+		_, err := NewDatabaseFromURI("leveldb://", t.Encs)
+		t.Error(err)
+		t.Contains(err.Error(), "empty leveldb path")
+	}
+
+	{
+		_, err := NewDatabaseFromURI("mongodb://localhost/mitum", t.Encs)
+		t.Error(err)
+		t.Contains(err.Error(), "not leveldb uri")
+	}
+
+	p, err := os.MkdirTemp("", "leveldb-")
+	t.NoError(err)
+	defer os.RemoveAll(p)
+
+	st, err := NewDatabaseFromURI("leveldb://"+p, t.Encs)
+	t.NoError(err)
+
+	t.database = st
+
+	blk, _ := t.saveNewBlock(base.Height(33))
+	t.NoError(st.Close())
+
+	// NOTE reopen
+	st, err = NewDatabaseFromURI("leveldb://"+p, t.Encs)
+	t.NoError(err)
+	t.database = st
+
+	m, found, err := st.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.CompareManifest(blk.Manifest(), m)
+}
+
+type dummyDatabase struct {
+	storage.Database
+}
+
 func TestLeveldbDatabase(t *testing.T) {
 	suite.Run(t, new(testDatabase))
 }
@@ -0,0 +1,218 @@
+package leveldbstorage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/storage"
+	"github.com/spikeekips/mitum/util/valuehash"
+	"github.com/stretchr/testify/suite"
+)
+
+type testMigration struct {
+	storage.BaseTestDatabase
+	database *Database
+}
+
+func (t *testMigration) SetupTest() {
+	t.database = NewMemDatabase(t.Encs, t.JSONEnc)
+}
+
+func (t *testMigration) TearDownTest() {
+	_ = t.database.Close()
+}
+
+func (t *testMigration) saveBlock() {
+	blk, err := block.NewTestBlockV0(base.Height(33), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+
+	bs, err := t.database.NewSession(blk)
+	t.NoError(err)
+	t.NoError(bs.SetBlock(context.Background(), blk))
+	t.NoError(bs.Commit(context.Background(), t.NewBlockdataMap(blk.Height(), blk.Hash(), true)))
+}
+
+// migrations returns the migrations, which set the info of version to "up";
+// the down sets it to "down".
+func (t *testMigration) migrations(n uint64, failed uint64) *storage.Migrations {
+	ms := storage.NewMigrations()
+
+	for i := uint64(1); i <= n; i++ {
+		version := i
+		key := fmt.Sprintf("migration-%d", version)
+
+		up := func(_ context.Context, db storage.Database) error {
+			if version == failed {
+				return errors.Errorf("killme")
+			}
+
+			return db.SetInfo(key, []byte("up"))
+		}
+		down := func(_ context.Context, db storage.Database) error {
+			return db.SetInfo(key, []byte("down"))
+		}
+
+		t.NoError(ms.Register(storage.NewMigration(version, key, up, down)))
+	}
+
+	return ms
+}
+
+func (t *testMigration) info(key string) string {
+	b, found, err := t.database.Info(key)
+	t.NoError(err)
+	if !found {
+		return ""
+	}
+
+	return string(b)
+}
+
+func (t *testMigration) schemaVersion() uint64 {
+	v, found, err := storage.SchemaVersion(t.database)
+	t.NoError(err)
+	t.True(found)
+
+	return v
+}
+
+func (t *testMigration) TestRegister() {
+	ms := storage.NewMigrations()
+
+	up := func(context.Context, storage.Database) error { return nil }
+
+	t.NoError(ms.Register(storage.NewMigration(1, "a", up, nil)))
+
+	err := ms.Register(storage.NewMigration(1, "a", up, nil))
+	t.Error(err)
+	t.Contains(err.Error(), "already registered")
+
+	err = ms.Register(storage.NewMigration(3, "c", up, nil))
+	t.Error(err)
+	t.Contains(err.Error(), "should be the next")
+
+	err = ms.Register(storage.NewMigration(2, "b", nil, nil))
+	t.Error(err)
+	t.Contains(err.Error(), "empty up function")
+
+	t.Equal(uint64(1), ms.Latest())
+}
+
+func (t *testMigration) TestEmptyDatabase() {
+	ms := t.migrations(2, 0)
+
+	applied, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), ms.Latest())
+	t.NoError(err)
+	t.Empty(applied)
+
+	// NOTE empty database follows the latest schema without migrations
+	t.Equal(uint64(2), t.schemaVersion())
+	t.Equal("", t.info("migration-1"))
+}
+
+func (t *testMigration) TestWithoutSchemaVersion() {
+	t.saveBlock()
+
+	ms := t.migrations(2, 0)
+
+	applied, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), ms.Latest())
+	t.NoError(err)
+	t.Equal(2, len(applied))
+
+	t.Equal(uint64(2), t.schemaVersion())
+	t.Equal("up", t.info("migration-1"))
+	t.Equal("up", t.info("migration-2"))
+}
+
+func (t *testMigration) TestDryrun() {
+	t.saveBlock()
+
+	ms := t.migrations(2, 0)
+
+	applied, err := storage.NewMigrator(t.database, ms, true).Migrate(context.Background(), ms.Latest())
+	t.NoError(err)
+	t.Equal(2, len(applied))
+
+	_, found, err := storage.SchemaVersion(t.database)
+	t.NoError(err)
+	t.False(found)
+	t.Equal("", t.info("migration-1"))
+}
+
+func (t *testMigration) TestRollback() {
+	t.NoError(storage.SetSchemaVersion(t.database, 0))
+
+	ms := t.migrations(3, 0)
+
+	_, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), 3)
+	t.NoError(err)
+	t.Equal(uint64(3), t.schemaVersion())
+
+	applied, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), 1)
+	t.NoError(err)
+	t.Equal(2, len(applied))
+	t.Equal(uint64(3), applied[0].Version())
+	t.Equal(uint64(2), applied[1].Version())
+
+	t.Equal(uint64(1), t.schemaVersion())
+	t.Equal("up", t.info("migration-1"))
+	t.Equal("down", t.info("migration-2"))
+	t.Equal("down", t.info("migration-3"))
+}
+
+func (t *testMigration) TestRollbackWithoutDown() {
+	t.NoError(storage.SetSchemaVersion(t.database, 1))
+
+	ms := storage.NewMigrations()
+	t.NoError(ms.Register(storage.NewMigration(1, "a", func(context.Context, storage.Database) error {
+		return nil
+	}, nil)))
+
+	_, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), 0)
+	t.Error(err)
+	t.Contains(err.Error(), "can not be rolled back")
+	t.Equal(uint64(1), t.schemaVersion())
+}
+
+func (t *testMigration) TestFailed() {
+	t.NoError(storage.SetSchemaVersion(t.database, 0))
+
+	ms := t.migrations(3, 3)
+
+	_, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), 3)
+	t.True(errors.Is(err, storage.MigrationError))
+	t.Contains(err.Error(), "killme")
+
+	// NOTE the applied migrations are rolled back
+	t.Equal(uint64(0), t.schemaVersion())
+	t.Equal("down", t.info("migration-1"))
+	t.Equal("down", t.info("migration-2"))
+	t.Equal("", t.info("migration-3"))
+}
+
+func (t *testMigration) TestNewerDatabase() {
+	t.NoError(storage.SetSchemaVersion(t.database, 3))
+
+	ms := t.migrations(2, 0)
+
+	_, err := storage.NewMigrator(t.database, ms, false).Migrate(context.Background(), ms.Latest())
+	t.Error(err)
+	t.Contains(err.Error(), "newer than known latest")
+}
+
+func (t *testMigration) TestClean() {
+	t.saveBlock()
+
+	t.NoError(t.database.Clean())
+
+	// NOTE the cleaned database keeps the current schema version
+	t.Equal(Migrations.Latest(), t.schemaVersion())
+}
+
+func TestMigration(t *testing.T) {
+	suite.Run(t, new(testMigration))
+}
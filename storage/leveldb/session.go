@@ -7,15 +7,17 @@ import (
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/encoder"
 	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
 type DatabaseSession struct {
-	st    *Database
-	block block.Block
-	batch *leveldb.Batch
+	st              *Database
+	block           block.Block
+	batch           *leveldb.Batch
+	acceptVoteproof []byte
 }
 
 func NewSession(st *Database, blk block.Block) (*DatabaseSession, error) {
@@ -32,9 +34,40 @@ func (bst *DatabaseSession) Block() block.Block {
 	return bst.block
 }
 
-func (bst *DatabaseSession) SetBlock(_ context.Context, blk block.Block) error {
+func (bst *DatabaseSession) SetBlock(ctx context.Context, blk block.Block) error {
+	if blk == nil {
+		return errors.Errorf("empty block")
+	}
+
+	type result struct {
+		batch *leveldb.Batch
+		err   error
+	}
+
+	finished := make(chan result, 1)
+	go func() {
+		batch, err := bst.setBlock(blk)
+		finished <- result{batch: batch, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case r := <-finished:
+		if r.err != nil {
+			return r.err
+		}
+
+		bst.batch = r.batch
+		bst.block = blk
+
+		return nil
+	}
+}
+
+func (bst *DatabaseSession) setBlock(blk block.Block) (*leveldb.Batch, error) {
 	if bst.block.Height() != blk.Height() {
-		return errors.Errorf(
+		return nil, errors.Errorf(
 			"block has different height from initial block; initial=%d != block=%d",
 			bst.block.Height(),
 			blk.Height(),
@@ -42,51 +75,51 @@ func (bst *DatabaseSession) SetBlock(_ context.Context, blk block.Block) error {
 	}
 
 	if bst.block.Round() != blk.Round() {
-		return errors.Errorf(
+		return nil, errors.Errorf(
 			"block has different round from initial block; initial=%d != block=%d",
 			bst.block.Round(),
 			blk.Round(),
 		)
 	}
 
+	batch := &leveldb.Batch{}
+
 	if b, err := marshal(blk, bst.st.enc); err != nil {
-		return err
+		return nil, err
 	} else {
-		bst.batch.Put(leveldbBlockHashKey(blk.Hash()), b)
+		batch.Put(leveldbBlockHashKey(blk.Hash()), b)
 	}
 
 	if b, err := marshal(blk.Manifest(), bst.st.enc); err != nil {
-		return err
+		return nil, err
 	} else {
 		key := leveldbManifestKey(blk.Hash())
-		bst.batch.Put(key, b)
+		batch.Put(key, b)
 	}
 
 	if b, err := marshal(blk.Hash(), bst.st.enc); err != nil {
-		return err
+		return nil, err
 	} else {
-		bst.batch.Put(leveldbBlockHeightKey(blk.Height()), b)
-		bst.batch.Put(leveldbManifestHeightKey(blk.Height()), b)
+		batch.Put(leveldbBlockHeightKey(blk.Height()), b)
+		batch.Put(leveldbManifestHeightKey(blk.Height()), b)
 	}
 
-	if err := bst.setOperationsTree(blk.OperationsTree()); err != nil {
-		return err
+	if err := bst.setOperationsTree(batch, blk.Height(), blk.OperationsTree()); err != nil {
+		return nil, err
 	}
 
-	if err := bst.setStates(blk.States()); err != nil {
-		return err
+	if err := bst.setStates(batch, blk.States()); err != nil {
+		return nil, err
 	}
 
-	if err := bst.setVoteproofs(blk.ConsensusInfo().INITVoteproof(), blk.ConsensusInfo().ACCEPTVoteproof()); err != nil {
-		return err
+	if err := bst.setVoteproofs(batch, blk.ConsensusInfo().INITVoteproof(), blk.ConsensusInfo().ACCEPTVoteproof()); err != nil {
+		return nil, err
 	}
 
-	bst.block = blk
-
-	return nil
+	return batch, nil
 }
 
-func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
+func (bst *DatabaseSession) setOperationsTree(batch *leveldb.Batch, height base.Height, tr tree.FixedTree) error {
 	if tr.Len() < 1 {
 		return nil
 	}
@@ -94,12 +127,12 @@ func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
 	if b, err := marshal(tr, bst.st.enc); err != nil { // block 1st
 		return err
 	} else {
-		bst.batch.Put(leveldbBlockOperationsKey(bst.block), b)
+		batch.Put(leveldbBlockOperationsKey(height), b)
 	}
 
 	// store operation hashes
 	if err := tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
-		bst.batch.Put(leveldbOperationFactHashKey(valuehash.NewBytes(no.Key())), nil)
+		batch.Put(leveldbOperationFactHashKey(valuehash.NewBytes(no.Key())), height.Bytes())
 
 		return true, nil
 	}); err != nil {
@@ -109,30 +142,30 @@ func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
 	return nil
 }
 
-func (bst *DatabaseSession) setStates(sts []state.State) error {
+func (bst *DatabaseSession) setStates(batch *leveldb.Batch, sts []state.State) error {
 	for i := range sts {
-		if b, err := marshal(sts[i], bst.st.enc); err != nil {
+		if err := setState(batch, sts[i], bst.st.enc); err != nil {
 			return err
-		} else {
-			bst.batch.Put(leveldbStateKey(sts[i].Key()), b)
 		}
 	}
 
 	return nil
 }
 
-func (bst *DatabaseSession) setVoteproofs(init, accept base.Voteproof) error {
+func (bst *DatabaseSession) setVoteproofs(batch *leveldb.Batch, init, accept base.Voteproof) error {
 	if init != nil {
 		if b, err := marshal(init, bst.st.enc); err != nil {
 			return err
 		} else {
-			bst.batch.Put(leveldbVoteproofKey(init.Height(), base.StageINIT), b)
+			batch.Put(leveldbVoteproofKey(init.Height(), base.StageINIT), b)
 		}
 	}
 
-	if accept != nil {
-		if err := bst.SetACCEPTVoteproof(accept); err != nil {
+	if accept != nil && bst.acceptVoteproof == nil {
+		if b, err := marshalACCEPTVoteproof(accept, bst.st.enc); err != nil {
 			return err
+		} else {
+			batch.Put(leveldbVoteproofKey(accept.Height(), base.StageACCEPT), b)
 		}
 	}
 
@@ -140,39 +173,74 @@ func (bst *DatabaseSession) setVoteproofs(init, accept base.Voteproof) error {
 }
 
 func (bst *DatabaseSession) Commit(ctx context.Context, bd block.BlockdataMap) error {
+	if bst.block == nil {
+		return errors.Errorf("database session already closed")
+	}
+
 	if bst.batch.Len() < 1 {
 		if err := bst.SetBlock(ctx, bst.block); err != nil {
 			return err
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if bst.acceptVoteproof != nil {
+		bst.batch.Put(leveldbVoteproofKey(bst.block.Height(), base.StageACCEPT), bst.acceptVoteproof)
+	}
+
 	if b, err := marshal(bd, bst.st.enc); err != nil {
 		return err
 	} else {
 		bst.batch.Put(leveldbBlockdataMapKey(bd.Height()), b)
 	}
 
-	return mergeError(bst.st.db.Write(bst.batch, nil))
+	if err := mergeError(bst.st.db.Write(bst.batch, nil)); err != nil {
+		return err
+	}
+
+	bst.batch.Reset()
+
+	return nil
 }
 
+// Cancel discards the uncommitted data. The data of leveldb DatabaseSession is
+// written at once by Commit, so nothing is left in database.
 func (bst *DatabaseSession) Cancel() error {
+	bst.batch.Reset()
+	bst.acceptVoteproof = nil
+
 	return nil
 }
 
 func (bst *DatabaseSession) Close() error {
+	if bst.block == nil {
+		return errors.Errorf("database session already closed")
+	}
+
+	bst.batch.Reset()
+	bst.acceptVoteproof = nil
+	bst.block = nil
+
 	return nil
 }
 
 func (bst *DatabaseSession) SetACCEPTVoteproof(voteproof base.Voteproof) error {
-	if s := voteproof.Stage(); s != base.StageACCEPT {
-		return errors.Errorf("not accept voteproof, %v", s)
-	}
-
-	if b, err := marshal(voteproof, bst.st.enc); err != nil {
+	if b, err := marshalACCEPTVoteproof(voteproof, bst.st.enc); err != nil {
 		return err
 	} else {
-		bst.batch.Put(leveldbVoteproofKey(voteproof.Height(), base.StageACCEPT), b)
+		bst.acceptVoteproof = b
 
 		return nil
 	}
 }
+
+func marshalACCEPTVoteproof(voteproof base.Voteproof, enc encoder.Encoder) ([]byte, error) {
+	if s := voteproof.Stage(); s != base.StageACCEPT {
+		return nil, errors.Errorf("not accept voteproof, %v", s)
+	}
+
+	return marshal(voteproof, enc)
+}
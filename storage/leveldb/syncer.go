@@ -29,7 +29,8 @@ func NewSyncerSession(main *Database) *SyncerSession {
 		}),
 		main:       main,
 		database:   NewMemDatabase(main.Encoders(), main.Encoder()),
-		heightFrom: base.Height(-1),
+		heightFrom: base.NilHeight,
+		heightTo:   base.NilHeight,
 	}
 }
 
@@ -41,24 +42,21 @@ func (st *SyncerSession) manifestKey(height base.Height) []byte {
 }
 
 func (st *SyncerSession) Manifest(height base.Height) (block.Manifest, bool, error) {
-	raw, err := st.database.DB().Get(st.manifestKey(height), nil)
+	raw, err := st.database.get(st.manifestKey(height))
 	if err != nil {
 		if errors.Is(err, util.NotFoundError) {
 			return nil, false, nil
 		}
 
-		return nil, false, mergeError(err)
+		return nil, false, err
 	}
 
 	m, err := st.database.loadManifest(raw)
 	if err != nil {
-		if errors.Is(err, util.NotFoundError) {
-			return nil, false, nil
-		}
 		return nil, false, err
 	}
 
-	return m, true, nil
+	return m, m != nil, nil
 }
 
 func (st *SyncerSession) SetManifests(manifests []block.Manifest) error {
@@ -83,13 +81,17 @@ func (st *SyncerSession) SetManifests(manifests []block.Manifest) error {
 			key := st.manifestKey(m.Height())
 			batch.Put(key, b)
 		}
+
+		st.checkHeight(m.Height())
 	}
 
 	return mergeError(st.database.DB().Write(batch, nil))
 }
 
 func (st *SyncerSession) HasBlock(height base.Height) (bool, error) {
-	return st.database.db.Has(leveldbBlockHeightKey(height), nil)
+	found, err := st.database.db.Has(leveldbBlockHeightKey(height), nil)
+
+	return found, mergeError(err)
 }
 
 func (st *SyncerSession) block(height base.Height) (block.Block, bool, error) {
@@ -123,11 +125,7 @@ func (st *SyncerSession) SetBlocks(blocks []block.Block, maps []block.BlockdataM
 
 		st.checkHeight(blk.Height())
 
-		if bs, err := st.database.NewSession(blk); err != nil {
-			return err
-		} else if err := bs.SetBlock(context.Background(), blk); err != nil {
-			return err
-		} else if err := bs.Commit(context.Background(), maps[i]); err != nil {
+		if err := commitBlock(st.database, blk, maps[i]); err != nil {
 			return err
 		}
 	}
@@ -135,53 +133,66 @@ func (st *SyncerSession) SetBlocks(blocks []block.Block, maps []block.BlockdataM
 	return nil
 }
 
+// Commit moves the blocks in session to the main database by height order.
 func (st *SyncerSession) Commit() error {
-	st.Log().Debug().
+	l := st.Log().With().
 		Int64("from_height", st.heightFrom.Int64()).
 		Int64("to_height", st.heightTo.Int64()).
-		Msg("trying to commit blocks")
+		Logger()
+
+	l.Debug().Msg("trying to commit blocks")
+
+	var heights []base.Height
+	if err := st.database.iter(
+		keyPrefixBlockHeight,
+		func(key, _ []byte) (bool, error) {
+			height, err := leveldbHeightFromKey(keyPrefixBlockHeight, key)
+			if err != nil {
+				return false, err
+			}
+
+			heights = append(heights, height)
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return err
+	}
+
+	for i := range heights {
+		height := heights[i]
 
-	for i := st.heightFrom; i <= st.heightTo; i++ {
 		var blk block.Block
-		switch j, found, err := st.block(i); {
+		switch j, found, err := st.block(height); {
 		case err != nil:
 			return err
 		case !found:
-			return util.NotFoundError.Errorf("block not found")
+			return util.NotFoundError.Errorf("block, %d not found", height)
 		default:
 			blk = j
 		}
 
 		var m block.BlockdataMap
-		switch j, found, err := st.database.BlockdataMap(i); {
+		switch j, found, err := st.database.BlockdataMap(height); {
 		case err != nil:
 			return err
 		case !found:
-			return util.NotFoundError.Errorf("block data map not found")
+			return util.NotFoundError.Errorf("block data map, %d not found", height)
 		default:
 			m = j
 		}
 
-		if err := st.commitBlock(blk, m); err != nil {
-			st.Log().Error().Err(err).Int64("height", i.Int64()).Msg("failed to commit block")
+		if err := commitBlock(st.main, blk, m); err != nil {
+			l.Error().Err(err).Int64("height", height.Int64()).Msg("failed to commit block")
 
 			return err
 		}
 
-		st.Log().Debug().Int64("height", i.Int64()).Msg("committed block")
+		l.Trace().Int64("height", height.Int64()).Msg("committed block")
 	}
 
-	return nil
-}
-
-func (st *SyncerSession) commitBlock(blk block.Block, m block.BlockdataMap) error {
-	if bs, err := st.main.NewSession(blk); err != nil {
-		return err
-	} else if err := bs.SetBlock(context.Background(), blk); err != nil {
-		return err
-	} else if err := bs.Commit(context.Background(), m); err != nil {
-		return err
-	}
+	l.Debug().Msg("blocks committed to main database")
 
 	return nil
 }
@@ -191,7 +202,7 @@ func (st *SyncerSession) checkHeight(height base.Height) {
 	defer st.Unlock()
 
 	switch {
-	case st.heightFrom < 0:
+	case st.heightFrom <= base.NilHeight:
 		st.heightFrom = height
 		st.heightTo = height
 	case st.heightFrom > height:
@@ -205,5 +216,23 @@ func (st *SyncerSession) Close() error {
 	return mergeError(st.database.DB().Close())
 }
 
-func (st *SyncerSession) SetSkipLastBlock(bool) {
+// SetSkipLastBlock does nothing; leveldb Database does not keep the separate
+// last block pointer, the highest stored block is always the last block.
+func (st *SyncerSession) SetSkipLastBlock(bool) {}
+
+func commitBlock(db *Database, blk block.Block, m block.BlockdataMap) error {
+	bs, err := db.NewSession(blk)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = bs.Close()
+	}()
+
+	if err := bs.SetBlock(context.Background(), blk); err != nil {
+		return err
+	}
+
+	return bs.Commit(context.Background(), m)
 }
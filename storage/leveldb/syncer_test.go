@@ -0,0 +1,99 @@
+package leveldbstorage
+
+import (
+	"github.com/spikeekips/mitum/base"
+	"github.com/spikeekips/mitum/base/block"
+)
+
+func (t *testDatabase) TestSyncerSessionManifests() {
+	ss, err := t.database.NewSyncerSession()
+	t.NoError(err)
+	defer ss.Close()
+
+	var manifests []block.Manifest
+	for i := base.Height(33); i < 36; i++ {
+		manifests = append(manifests, t.newBlock(i, nil, nil).Manifest())
+	}
+
+	t.NoError(ss.SetManifests(manifests))
+
+	for i := range manifests {
+		m, found, err := ss.Manifest(manifests[i].Height())
+		t.NoError(err)
+		t.True(found)
+		t.CompareManifest(manifests[i], m)
+	}
+
+	_, found, err := ss.Manifest(base.Height(36))
+	t.NoError(err)
+	t.False(found)
+
+	// NOTE manifests are not stored in main database
+	_, found, err = t.database.LastManifest()
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testDatabase) TestSyncerSessionCommit() {
+	t.saveNewBlock(base.Height(32))
+
+	ss, err := t.database.NewSyncerSession()
+	t.NoError(err)
+	defer ss.Close()
+
+	var blocks []block.Block
+	var maps []block.BlockdataMap
+	for i := base.Height(33); i < 36; i++ {
+		blk := t.newBlock(i, nil, nil)
+		blocks = append(blocks, blk)
+		maps = append(maps, t.NewBlockdataMap(blk.Height(), blk.Hash(), true))
+	}
+
+	t.NoError(ss.SetBlocks(blocks, maps))
+
+	for i := range blocks {
+		found, err := ss.HasBlock(blocks[i].Height())
+		t.NoError(err)
+		t.True(found)
+	}
+
+	// NOTE before commit
+	m, found, err := t.database.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(32), m.Height())
+
+	ss.SetSkipLastBlock(true)
+	t.NoError(ss.Commit())
+
+	m, found, err = t.database.LastManifest()
+	t.NoError(err)
+	t.True(found)
+	t.CompareManifest(blocks[len(blocks)-1], m)
+
+	for i := range blocks {
+		blk := blocks[i]
+
+		m, found, err := t.database.ManifestByHeight(blk.Height())
+		t.NoError(err)
+		t.True(found)
+		t.CompareManifest(blk, m)
+
+		bd, found, err := t.database.BlockdataMap(blk.Height())
+		t.NoError(err)
+		t.True(found)
+		block.CompareBlockdataMap(t.Assert(), maps[i], bd)
+	}
+}
+
+func (t *testDatabase) TestSyncerSessionSetBlocksWrongMaps() {
+	ss, err := t.database.NewSyncerSession()
+	t.NoError(err)
+	defer ss.Close()
+
+	blk := t.newBlock(base.Height(33), nil, nil)
+
+	err = ss.SetBlocks([]block.Block{blk}, nil)
+	t.Error(err)
+	t.Contains(err.Error(), "different size")
+}
@@ -3,10 +3,12 @@ package leveldbstorage
 import (
 	"bytes"
 
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/encoder"
 	"github.com/spikeekips/mitum/util/hint"
+	"github.com/syndtr/goleveldb/leveldb"
 	leveldbErrors "github.com/syndtr/goleveldb/leveldb/errors"
 )
 
@@ -37,6 +39,18 @@ func marshal(i interface{}, enc encoder.Encoder) ([]byte, error) {
 	return encodeWithEncoder(b, enc), nil
 }
 
+func setState(batch *leveldb.Batch, sta state.State, enc encoder.Encoder) error {
+	b, err := marshal(sta, enc)
+	if err != nil {
+		return err
+	}
+
+	batch.Put(leveldbStateKey(sta.Key(), sta.Height()), b)
+	batch.Put(leveldbBlockStatesKey(sta.Height(), sta.Key()), nil)
+
+	return nil
+}
+
 func mergeError(err error) error {
 	if err == nil {
 		return nil
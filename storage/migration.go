@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+// SchemaVersionInfoKey is the info key of the schema version of database.
+var SchemaVersionInfoKey = "schema_version"
+
+var MigrationError = util.NewError("failed to migrate database")
+
+// MigrationFunc changes the stored documents of database from one schema
+// version to another.
+type MigrationFunc func(context.Context, Database) error
+
+// Migration upgrades the database schema from Version()-1 to Version(). The
+// down function restores the previous schema; if it is nil, the migration can
+// not be rolled back.
+type Migration struct {
+	version     uint64
+	description string
+	up          MigrationFunc
+	down        MigrationFunc
+}
+
+func NewMigration(version uint64, description string, up, down MigrationFunc) Migration {
+	return Migration{version: version, description: description, up: up, down: down}
+}
+
+func (mg Migration) IsValid([]byte) error {
+	switch {
+	case mg.version < 1:
+		return errors.Errorf("schema version of migration should be over 0")
+	case len(mg.description) < 1:
+		return errors.Errorf("empty description of migration, %d", mg.version)
+	case mg.up == nil:
+		return errors.Errorf("empty up function of migration, %d", mg.version)
+	default:
+		return nil
+	}
+}
+
+func (mg Migration) Version() uint64 {
+	return mg.version
+}
+
+func (mg Migration) Description() string {
+	return mg.description
+}
+
+func (mg Migration) CanRollback() bool {
+	return mg.down != nil
+}
+
+func (mg Migration) MarshalZerologObject(e *zerolog.Event) {
+	e.Uint64("version", mg.version).Str("description", mg.description).Bool("can_rollback", mg.CanRollback())
+}
+
+// Migrations is the registry of Migration by schema version. The versions
+// should be continuous from 1, so the latest version is the number of the
+// registered migrations.
+type Migrations struct {
+	sync.RWMutex
+	m map[uint64]Migration
+}
+
+func NewMigrations() *Migrations {
+	return &Migrations{m: map[uint64]Migration{}}
+}
+
+// Register registers Migration. The version can be registered only once and
+// it should be the next of the latest version.
+func (ms *Migrations) Register(mg Migration) error {
+	if err := mg.IsValid(nil); err != nil {
+		return err
+	}
+
+	ms.Lock()
+	defer ms.Unlock()
+
+	if _, found := ms.m[mg.version]; found {
+		return errors.Errorf("migration, %d already registered", mg.version)
+	}
+
+	if latest := uint64(len(ms.m)); mg.version != latest+1 {
+		return errors.Errorf("migration, %d should be the next of latest version, %d", mg.version, latest)
+	}
+
+	ms.m[mg.version] = mg
+
+	return nil
+}
+
+// Latest returns the latest schema version. Without migrations, it is 0.
+func (ms *Migrations) Latest() uint64 {
+	ms.RLock()
+	defer ms.RUnlock()
+
+	return uint64(len(ms.m))
+}
+
+// SortedMigrations returns the migrations by ascending version.
+func (ms *Migrations) SortedMigrations() []Migration {
+	ms.RLock()
+	defer ms.RUnlock()
+
+	mgs := make([]Migration, 0, len(ms.m))
+	for i := range ms.m {
+		mgs = append(mgs, ms.m[i])
+	}
+
+	sort.Slice(mgs, func(i, j int) bool {
+		return mgs[i].version < mgs[j].version
+	})
+
+	return mgs
+}
+
+// Plan returns the migrations to move the schema version from current to
+// target. If target is lower than current, the migrations are ordered to be
+// rolled back.
+func (ms *Migrations) Plan(current, target uint64) ([]Migration, error) {
+	ms.RLock()
+	defer ms.RUnlock()
+
+	latest := uint64(len(ms.m))
+	switch {
+	case current > latest:
+		return nil, errors.Errorf("schema version of database, %d is newer than known latest, %d", current, latest)
+	case target > latest:
+		return nil, errors.Errorf("target schema version, %d is newer than known latest, %d", target, latest)
+	case current == target:
+		return nil, nil
+	}
+
+	var mgs []Migration
+	if current < target {
+		for i := current + 1; i <= target; i++ {
+			mgs = append(mgs, ms.m[i])
+		}
+
+		return mgs, nil
+	}
+
+	for i := current; i > target; i-- {
+		mg := ms.m[i]
+		if !mg.CanRollback() {
+			return nil, errors.Errorf("migration, %d can not be rolled back", i)
+		}
+
+		mgs = append(mgs, mg)
+	}
+
+	return mgs, nil
+}
+
+// SchemaVersion returns the schema version of database. If not stored, false
+// is returned.
+func SchemaVersion(db Database) (uint64, bool, error) {
+	switch b, found, err := db.Info(SchemaVersionInfoKey); {
+	case err != nil:
+		return 0, false, err
+	case !found:
+		return 0, false, nil
+	default:
+		v, err := util.BytesToUint64(b)
+		if err != nil {
+			return 0, false, errors.Wrap(err, "invalid schema version")
+		}
+
+		return v, true, nil
+	}
+}
+
+func SetSchemaVersion(db Database, v uint64) error {
+	return db.SetInfo(SchemaVersionInfoKey, util.Uint64ToBytes(v))
+}
+
+// Migrator moves the schema version of database by Migrations. The database
+// without schema version is treated as below:
+//
+// - empty database: it is created by the current layout, so the latest version
+// is stored without migration.
+//
+// - not empty: it was created before the schema version was introduced, so it
+// is version 0.
+//
+// If one of the migrations fails, the migrations applied by the same Migrate
+// are rolled back in reverse order.
+type Migrator struct {
+	*logging.Logging
+	db     Database
+	ms     *Migrations
+	dryrun bool
+}
+
+func NewMigrator(db Database, ms *Migrations, dryrun bool) *Migrator {
+	return &Migrator{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "database-migrator")
+		}),
+		db:     db,
+		ms:     ms,
+		dryrun: dryrun,
+	}
+}
+
+// Current returns the schema version of database.
+func (mr *Migrator) Current() (uint64, error) {
+	switch v, found, err := SchemaVersion(mr.db); {
+	case err != nil:
+		return 0, err
+	case found:
+		return v, nil
+	}
+
+	switch _, found, err := mr.db.LastManifest(); {
+	case err != nil:
+		return 0, err
+	case found:
+		return 0, nil
+	default:
+		return mr.ms.Latest(), nil
+	}
+}
+
+// Migrate moves the schema version to target and returns the applied
+// migrations. In dry-run mode, the migrations are returned without running.
+func (mr *Migrator) Migrate(ctx context.Context, target uint64) ([]Migration, error) {
+	_, found, err := SchemaVersion(mr.db)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := mr.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	mgs, err := mr.ms.Plan(current, target)
+	if err != nil {
+		return nil, err
+	}
+
+	l := mr.Log().With().Uint64("current", current).Uint64("target", target).Bool("dryrun", mr.dryrun).Logger()
+
+	if len(mgs) < 1 {
+		l.Debug().Msg("database schema is up to date")
+
+		if !found && !mr.dryrun {
+			if err := SetSchemaVersion(mr.db, current); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	if mr.dryrun {
+		for i := range mgs {
+			l.Info().Object("migration", mgs[i]).Msg("migration will be applied")
+		}
+
+		return mgs, nil
+	}
+
+	up := current < target
+
+	var applied []Migration
+	for i := range mgs {
+		mg := mgs[i]
+		if err := mr.apply(ctx, mg, up); err != nil {
+			l.Error().Err(err).Object("migration", mg).Msg("failed to migrate; rolling back")
+
+			if rerr := mr.rollback(applied, up); rerr != nil {
+				return nil, MigrationError.Wrap(errors.Wrapf(err, "failed to roll back: %v", rerr))
+			}
+
+			return nil, MigrationError.Wrap(err)
+		}
+
+		applied = append(applied, mg)
+
+		l.Info().Object("migration", mg).Bool("up", up).Msg("migration applied")
+	}
+
+	return applied, nil
+}
+
+func (mr *Migrator) apply(ctx context.Context, mg Migration, up bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f, version := mg.up, mg.version
+	if !up {
+		f, version = mg.down, mg.version-1
+	}
+
+	if err := f(ctx, mr.db); err != nil {
+		return errors.Wrapf(err, "migration, %d", mg.version)
+	}
+
+	return SetSchemaVersion(mr.db, version)
+}
+
+// rollback reverts the applied migrations. It is not stopped by the context of
+// Migrate, the failed migration may be caused by the canceled context.
+func (mr *Migrator) rollback(applied []Migration, up bool) error {
+	var mgs []Migration
+	for i := len(applied) - 1; i >= 0; i-- {
+		mg := applied[i]
+		if up && !mg.CanRollback() {
+			return errors.Errorf("migration, %d can not be rolled back", mg.version)
+		}
+
+		mgs = append(mgs, mg)
+	}
+
+	for i := range mgs {
+		if err := mr.apply(context.Background(), mgs[i], !up); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
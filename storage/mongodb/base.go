@@ -35,6 +35,9 @@ const (
 	ColNameBlockdataMap    = "blockdata_map"
 )
 
+// Migrations is the registry of the schema migrations of mongodb database.
+var Migrations = storage.NewMigrations()
+
 var allCollections = []string{
 	ColNameInfo,
 	ColNameManifest,
@@ -362,6 +365,11 @@ func (st *Database) Clean() error {
 		return err
 	}
 
+	// NOTE the cleaned database follows the current schema.
+	if err := storage.SetSchemaVersion(st, Migrations.Latest()); err != nil {
+		return err
+	}
+
 	st.Lock()
 	defer st.Unlock()
 
@@ -406,6 +414,78 @@ func (st *Database) CleanByHeight(height base.Height) error {
 	}
 }
 
+// Prune removes the old state versions lower than height. For each state key,
+// the latest version lower than height is kept. The operations are kept for
+// HasOperationFact.
+func (st *Database) Prune(height base.Height) error {
+	if st.readonly {
+		return errors.Errorf("readonly mode")
+	}
+
+	if top := st.lastHeight(); height > top {
+		return errors.Errorf("prune height, %d is higher than last block, %d", height, top)
+	}
+
+	switch h, err := storage.PrunedHeight(st); {
+	case err != nil:
+		return err
+	case height <= h:
+		return nil
+	}
+
+	if err := st.pruneStates(height); err != nil {
+		return err
+	}
+
+	return storage.SetPrunedHeight(st, height)
+}
+
+func (st *Database) pruneStates(height base.Height) error {
+	var models []mongo.WriteModel
+	var lastKey string
+
+	if err := st.client.Find(
+		context.Background(),
+		ColNameState,
+		util.EmptyBSONFilter().AddOp("height", height, "$lt").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			// NOTE sorted by key and descending height; the first state of key
+			// is kept.
+			key, ok := cursor.Current.Lookup("key").StringValueOK()
+			if !ok {
+				return false, errors.Errorf("invalid state document; key not found")
+			}
+
+			if key != lastKey {
+				lastKey = key
+
+				return true, nil
+			}
+
+			models = append(models, mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": cursor.Current.Lookup("_id")}))
+
+			return true, nil
+		},
+		options.Find().
+			SetSort(util.NewBSONFilter("key", 1).Add("height", -1).D()).
+			SetProjection(bson.M{"_id": 1, "key": 1, "height": 1}),
+	); err != nil {
+		return err
+	}
+
+	if len(models) < 1 {
+		return nil
+	}
+
+	if err := st.client.Bulk(context.Background(), ColNameState, models, false); err != nil {
+		return err
+	}
+
+	st.Log().Debug().Int64("height", height.Int64()).Int("states", len(models)).Msg("states pruned")
+
+	return nil
+}
+
 func (st *Database) Copy(source storage.Database) error {
 	if st.readonly {
 		return errors.Errorf("readonly mode")
@@ -834,6 +914,103 @@ func (st *Database) State(key string) (state.State, bool, error) {
 	return sta, sta != nil, nil
 }
 
+func (st *Database) StateAtHeight(key string, height base.Height) (state.State, bool, error) {
+	if top := st.lastHeight(); height > top {
+		height = top
+	}
+
+	var sta state.State
+
+	if err := st.client.Find(
+		context.TODO(),
+		ColNameState,
+		util.NewBSONFilter("key", key).AddOp("height", height, "$lte").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			i, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+			sta = i
+
+			return false, nil
+		},
+		options.Find().SetSort(util.NewBSONFilter("height", -1).D()).SetLimit(1),
+	); err != nil {
+		return nil, false, err
+	}
+
+	return sta, sta != nil, nil
+}
+
+func (st *Database) StateHistory(
+	key string,
+	height base.Height,
+	limit int64,
+	callback func(state.State) (bool, error),
+) error {
+	filter := util.NewBSONFilter("key", key)
+	if top := st.lastHeight(); height > base.NilHeight && height <= top {
+		filter = filter.AddOp("height", height, "$lt")
+	} else {
+		filter = filter.AddOp("height", top, "$lte")
+	}
+
+	opts := options.Find().SetSort(util.NewBSONFilter("height", -1).D())
+	if limit > 0 {
+		opts = opts.SetLimit(limit)
+	}
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameState,
+		filter.D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(sta)
+		},
+		opts,
+	)
+}
+
+func (st *Database) States(height base.Height, callback func(state.State) (bool, error)) error {
+	if top := st.lastHeight(); height > top {
+		height = top
+	}
+
+	var lastKey string
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameState,
+		util.EmptyBSONFilter().AddOp("height", height, "$lte").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			// NOTE sorted by key and descending height; the first state of key
+			// is the latest one.
+			key, ok := cursor.Current.Lookup("key").StringValueOK()
+			if !ok {
+				return false, errors.Errorf("invalid state document; key not found")
+			}
+
+			if key == lastKey {
+				return true, nil
+			}
+			lastKey = key
+
+			sta, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(sta)
+		},
+		options.Find().SetSort(util.NewBSONFilter("key", 1).Add("height", -1).D()),
+	)
+}
+
 func (st *Database) NewState(sta state.State) error {
 	if st.readonly {
 		return errors.Errorf("readonly mode")
@@ -850,6 +1027,74 @@ func (st *Database) NewState(sta state.State) error {
 	return nil
 }
 
+// OperationsByFields calls callback with the fact hash and height of the
+// operations, which match the filter of the index fields of FieldIndexer. The
+// operations are sorted by height. If limit is lower than 1, no limit.
+func (st *Database) OperationsByFields(
+	filter bson.M,
+	limit int64,
+	callback func(valuehash.Hash, base.Height) (bool, error),
+) error {
+	d, err := fieldsFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(util.NewBSONFilter("height", 1).D())
+	if limit > 0 {
+		opts = opts.SetLimit(limit)
+	}
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameOperation,
+		util.NewBSONFilterFromD(d).AddOp("height", st.lastHeight(), "$lte").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			fact, height, err := loadOperationFactFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(fact, height)
+		},
+		opts,
+	)
+}
+
+// StatesByFields calls callback with the states, which match the filter of the
+// index fields of FieldIndexer. Every version of state is matched and sorted
+// by height. If limit is lower than 1, no limit.
+func (st *Database) StatesByFields(
+	filter bson.M,
+	limit int64,
+	callback func(state.State) (bool, error),
+) error {
+	d, err := fieldsFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().SetSort(util.NewBSONFilter("height", 1).D())
+	if limit > 0 {
+		opts = opts.SetLimit(limit)
+	}
+
+	return st.client.Find(
+		context.TODO(),
+		ColNameState,
+		util.NewBSONFilterFromD(d).AddOp("height", st.lastHeight(), "$lte").D(),
+		func(cursor *mongo.Cursor) (bool, error) {
+			sta, err := loadStateFromDecoder(cursor.Decode, st.encs)
+			if err != nil {
+				return false, err
+			}
+
+			return callback(sta)
+		},
+		opts,
+	)
+}
+
 func (st *Database) HasOperationFact(h valuehash.Hash) (bool, error) {
 	if st.operationFactCache.Has(h.String()) {
 		return true, nil
@@ -872,6 +1117,32 @@ func (st *Database) HasOperationFact(h valuehash.Hash) (bool, error) {
 	return count > 0, nil
 }
 
+func (st *Database) OperationFactHeight(h valuehash.Hash) (base.Height, bool, error) {
+	height := base.NilHeight
+
+	if err := st.client.GetByFilter(
+		ColNameOperation,
+		util.NewBSONFilter("fact", h.String()).AddOp("height", st.lastHeight(), "$lte").D(),
+		func(res *mongo.SingleResult) error {
+			i, err := loadOperationHeightFromDecoder(res.Decode, st.encs)
+			if err != nil {
+				return err
+			}
+			height = i
+
+			return nil
+		},
+	); err != nil {
+		if errors.Is(err, util.NotFoundError) {
+			return base.NilHeight, false, nil
+		}
+
+		return base.NilHeight, false, err
+	}
+
+	return height, true, nil
+}
+
 func (st *Database) NewSession(blk block.Block) (storage.DatabaseSession, error) {
 	if st.readonly {
 		return nil, errors.Errorf("readonly mode")
@@ -891,6 +1162,13 @@ func (st *Database) initialize() error {
 		}
 	}
 
+	fieldModels := fieldIndexModels()
+	for _, col := range []string{ColNameOperation, ColNameState} {
+		if err := st.CreateIndex(col, fieldModels[col], FieldIndexPrefix); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -13,9 +13,12 @@ import (
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
 	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
 	"github.com/spikeekips/mitum/util/cache"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"github.com/stretchr/testify/suite"
 	"go.mongodb.org/mongo-driver/bson"
@@ -49,25 +52,56 @@ func (t *testDatabase) TestNew() {
 	t.Implements((*storage.Database)(nil), t.database)
 }
 
-func (t *testDatabase) saveNewBlock(height base.Height) (block.Block, block.BlockdataMap) {
+func (t *testDatabase) newBlock(height base.Height, sts []state.State, ops []valuehash.Hash) block.Block {
 	blk, err := block.NewTestBlockV0(height, base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
 	t.NoError(err)
 
 	i := (interface{})(blk).(block.BlockUpdater)
 	i = i.SetINITVoteproof(base.NewVoteproofV0(blk.Height(), blk.Round(), nil, base.ThresholdRatio(100), base.StageINIT))
 	i = i.SetACCEPTVoteproof(base.NewVoteproofV0(blk.Height(), blk.Round(), nil, base.ThresholdRatio(100), base.StageACCEPT))
-	blk = i.(block.BlockV0)
+	i = i.SetStates(sts)
+
+	if len(ops) > 0 {
+		tg := tree.NewFixedTreeGenerator(uint64(len(ops)))
+		for j := range ops {
+			t.NoError(tg.Add(operation.NewFixedTreeNode(uint64(j), ops[j].Bytes(), true, nil)))
+		}
+
+		tr, err := tg.Tree()
+		t.NoError(err)
+
+		i = i.SetOperationsTree(tr)
+	}
+
+	return i.(block.BlockV0)
+}
 
+func (t *testDatabase) saveNewBlock(height base.Height) (block.Block, block.BlockdataMap) {
+	return t.saveBlock(t.newBlock(height, nil, nil))
+}
+
+func (t *testDatabase) saveBlock(blk block.Block) (block.Block, block.BlockdataMap) {
 	bs, err := t.database.NewSession(blk)
 	t.NoError(err)
 
 	t.NoError(bs.SetBlock(context.Background(), blk))
 	bd := t.NewBlockdataMap(blk.Height(), blk.Hash(), true)
 	t.NoError(bs.Commit(context.Background(), bd))
+	t.NoError(bs.Close())
 
 	return blk, bd
 }
 
+func (t *testDatabase) newState(key, value string, height base.Height) state.State {
+	v, err := state.NewStringValue(value)
+	t.NoError(err)
+
+	st, err := state.NewStateV0(key, v, height)
+	t.NoError(err)
+
+	return st
+}
+
 func (t *testDatabase) saveBlockdataMap(st *Database, bd block.BlockdataMap) error {
 	if doc, err := NewBlockdataMapDoc(bd, st.enc); err != nil {
 		return err
@@ -382,6 +416,87 @@ func (t *testDatabase) TestHasOperation() {
 	}
 }
 
+func (t *testDatabase) TestFieldIndexer() {
+	defer func() {
+		fieldIndexersLock.Lock()
+		fieldIndexers = map[string]map[hint.Type]FieldIndexer{}
+		fieldIndexersLock.Unlock()
+	}()
+
+	_ = t.Encs.TestAddHinter(state.BytesValueHinter)
+	_ = t.Encs.TestAddHinter(state.StateV0{})
+
+	t.NoError(RegisterFieldIndexer(NewStateFieldIndexer(
+		state.BytesValueType,
+		[]string{"value"},
+		func(st state.State) (bson.M, error) {
+			return bson.M{"value": string(st.Value().Interface().([]byte))}, nil
+		},
+	)))
+	t.NoError(RegisterFieldIndexer(NewOperationFieldIndexer(
+		operation.KVOperationType,
+		[]string{"key"},
+		func(op operation.Operation) (bson.M, error) {
+			return bson.M{"key": op.(operation.KVOperation).Key()}, nil
+		},
+	)))
+
+	t.NoError(t.database.Initialize())
+
+	blk, err := block.NewTestBlockV0(base.Height(34), base.Round(0), valuehash.RandomSHA256(), valuehash.RandomSHA256())
+	t.NoError(err)
+	t.database.setLastBlock(blk, true, false)
+
+	for _, h := range []base.Height{33, 34, 35} {
+		v, err := state.NewBytesValue([]byte("showme"))
+		t.NoError(err)
+
+		st, err := state.NewStateV0(util.UUID().String(), v, h)
+		t.NoError(err)
+		t.NoError(t.database.NewState(st))
+	}
+
+	var heights []base.Height
+	t.NoError(t.database.StatesByFields(bson.M{"value": "showme"}, 0, func(st state.State) (bool, error) {
+		heights = append(heights, st.Height())
+
+		return true, nil
+	}))
+	t.Equal([]base.Height{33, 34}, heights)
+
+	heights = nil
+	t.NoError(t.database.StatesByFields(bson.M{"value": "findme"}, 0, func(st state.State) (bool, error) {
+		heights = append(heights, st.Height())
+
+		return true, nil
+	}))
+	t.Empty(heights)
+
+	op, err := operation.NewKVOperation(t.PK, []byte("showme"), "findme", []byte("value"), nil)
+	t.NoError(err)
+
+	{
+		doc, err := NewOperationDoc(op.Fact().Hash(), t.database.enc, base.Height(33))
+		t.NoError(err)
+
+		doc.fields, err = extractFields(ColNameOperation, op.Hint().Type(), op)
+		t.NoError(err)
+
+		_, err = t.database.client.Add(ColNameOperation, doc)
+		t.NoError(err)
+	}
+
+	var facts []valuehash.Hash
+	t.NoError(t.database.OperationsByFields(bson.M{"key": "findme"}, 0, func(fact valuehash.Hash, height base.Height) (bool, error) {
+		t.Equal(base.Height(33), height)
+		facts = append(facts, fact)
+
+		return true, nil
+	}))
+	t.Equal(1, len(facts))
+	t.True(op.Fact().Hash().Equal(facts[0]))
+}
+
 func (t *testDatabase) TestCreateIndexNew() {
 	allIndexes := func(col string) []string {
 		iv := t.database.client.Collection(col).Indexes()
@@ -540,6 +655,195 @@ func (t *testDatabase) TestLocalBlockdataMapsByHeight() {
 	t.NoError(err)
 }
 
+func (t *testDatabase) TestStateAtHeight() {
+	key := util.UUID().String()
+
+	for _, i := range []base.Height{33, 35} {
+		st := t.newState(key, i.String(), i)
+		t.saveBlock(t.newBlock(i, []state.State{st}, nil))
+	}
+
+	_, found, err := t.database.StateAtHeight(key, base.Height(32))
+	t.NoError(err)
+	t.False(found)
+
+	for height, expected := range map[base.Height]base.Height{33: 33, 34: 33, 35: 35, 36: 35} {
+		st, found, err := t.database.StateAtHeight(key, height)
+		t.NoError(err)
+		t.True(found)
+		t.Equal(expected, st.Height())
+		t.Equal(expected.String(), st.Value().Interface())
+	}
+
+	_, found, err = t.database.StateAtHeight(util.UUID().String(), base.Height(35))
+	t.NoError(err)
+	t.False(found)
+}
+
+func (t *testDatabase) TestStateHistory() {
+	key := util.UUID().String()
+
+	for _, i := range []base.Height{33, 34, 35} {
+		st := t.newState(key, i.String(), i)
+		t.saveBlock(t.newBlock(i, []state.State{st}, nil))
+	}
+
+	collect := func(height base.Height, limit int64) []base.Height {
+		var heights []base.Height
+		t.NoError(t.database.StateHistory(key, height, limit, func(st state.State) (bool, error) {
+			t.Equal(key, st.Key())
+			t.Equal(st.Height().String(), st.Value().Interface())
+
+			heights = append(heights, st.Height())
+
+			return true, nil
+		}))
+
+		return heights
+	}
+
+	t.Equal([]base.Height{35, 34, 33}, collect(base.NilHeight, 0))
+	t.Equal([]base.Height{35, 34}, collect(base.NilHeight, 2))
+	t.Equal([]base.Height{34, 33}, collect(base.Height(35), 0))
+	t.Equal([]base.Height{33}, collect(base.Height(34), 1))
+	t.Empty(collect(base.Height(33), 0))
+
+	{ // unknown
+		var found bool
+		t.NoError(t.database.StateHistory(util.UUID().String(), base.NilHeight, 0, func(state.State) (bool, error) {
+			found = true
+
+			return true, nil
+		}))
+		t.False(found)
+	}
+}
+
+func (t *testDatabase) TestOperationFactHeight() {
+	fact := valuehash.RandomSHA256()
+	t.saveBlock(t.newBlock(base.Height(33), nil, []valuehash.Hash{valuehash.RandomSHA256(), fact}))
+
+	height, found, err := t.database.OperationFactHeight(fact)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(33), height)
+
+	{ // unknown
+		height, found, err := t.database.OperationFactHeight(valuehash.RandomSHA256())
+		t.NoError(err)
+		t.False(found)
+		t.Equal(base.NilHeight, height)
+	}
+
+	{ // NOTE not yet committed height
+		other := valuehash.RandomSHA256()
+		blk := t.newBlock(base.Height(34), nil, []valuehash.Hash{other})
+
+		bs, err := t.database.NewSession(blk)
+		t.NoError(err)
+		t.NoError(bs.SetBlock(context.Background(), blk))
+
+		_, found, err := t.database.OperationFactHeight(other)
+		t.NoError(err)
+		t.False(found)
+
+		t.NoError(bs.Commit(context.Background(), t.NewBlockdataMap(blk.Height(), blk.Hash(), true)))
+		t.NoError(bs.Close())
+
+		height, found, err := t.database.OperationFactHeight(other)
+		t.NoError(err)
+		t.True(found)
+		t.Equal(base.Height(34), height)
+	}
+}
+
+func (t *testDatabase) TestPrune() {
+	keyA := util.UUID().String()
+	keyB := util.UUID().String()
+
+	var blocks []block.Block
+	var facts []valuehash.Hash
+	for i := base.Height(33); i < 38; i++ {
+		fact := valuehash.RandomSHA256()
+		facts = append(facts, fact)
+
+		sts := []state.State{t.newState(keyA, i.String(), i)}
+		if i == 33 {
+			sts = append(sts, t.newState(keyB, i.String(), i))
+		}
+
+		blk, _ := t.saveBlock(t.newBlock(i, sts, []valuehash.Hash{fact}))
+		blocks = append(blocks, blk)
+	}
+
+	h, err := storage.PrunedHeight(t.database)
+	t.NoError(err)
+	t.Equal(base.NilHeight, h)
+
+	t.NoError(t.database.Prune(base.Height(36)))
+
+	h, err = storage.PrunedHeight(t.database)
+	t.NoError(err)
+	t.Equal(base.Height(36), h)
+
+	for i := range blocks {
+		blk := blocks[i]
+
+		_, found, err := t.database.ManifestByHeight(blk.Height())
+		t.NoError(err)
+		t.True(found)
+
+		// NOTE the operation facts of pruned heights are kept
+		found, err = t.database.HasOperationFact(facts[i])
+		t.NoError(err)
+		t.True(found)
+
+		height, found, err := t.database.OperationFactHeight(facts[i])
+		t.NoError(err)
+		t.True(found)
+		t.Equal(blk.Height(), height)
+	}
+
+	// NOTE the latest states are kept
+	st, found, err := t.database.State(keyA)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(37), st.Height())
+
+	st, found, err = t.database.State(keyB)
+	t.NoError(err)
+	t.True(found)
+	t.Equal(base.Height(33), st.Height())
+
+	var heights []base.Height
+	t.NoError(t.database.StateHistory(keyA, base.NilHeight, 0, func(st state.State) (bool, error) {
+		heights = append(heights, st.Height())
+
+		return true, nil
+	}))
+	t.Equal([]base.Height{37, 36, 35}, heights)
+
+	// NOTE the states of pruned height are still served
+	var sts []state.State
+	t.NoError(t.database.States(base.Height(36), func(st state.State) (bool, error) {
+		sts = append(sts, st)
+
+		return true, nil
+	}))
+	t.Equal(2, len(sts))
+
+	// NOTE lower height is ignored
+	t.NoError(t.database.Prune(base.Height(34)))
+
+	h, err = storage.PrunedHeight(t.database)
+	t.NoError(err)
+	t.Equal(base.Height(36), h)
+
+	err = t.database.Prune(base.Height(38))
+	t.Error(err)
+	t.Contains(err.Error(), "higher than last block")
+}
+
 func TestMongodbDatabase(t *testing.T) {
 	suite.Run(t, new(testDatabase))
 }
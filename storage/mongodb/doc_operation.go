@@ -5,12 +5,14 @@ import (
 	"github.com/spikeekips/mitum/util/encoder"
 	bsonenc "github.com/spikeekips/mitum/util/encoder/bson"
 	"github.com/spikeekips/mitum/util/valuehash"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type OperationDoc struct {
 	BaseDoc
 	fact   valuehash.Hash
 	height base.Height
+	fields bson.M
 }
 
 func NewOperationDoc(fact valuehash.Hash, enc encoder.Encoder, height base.Height) (OperationDoc, error) {
@@ -35,5 +37,36 @@ func (od OperationDoc) MarshalBSON() ([]byte, error) {
 	m["fact"] = od.fact.String()
 	m["height"] = od.height
 
+	if len(od.fields) > 0 {
+		m[fieldsDocKey] = od.fields
+	}
+
 	return bsonenc.Marshal(m)
 }
+
+func loadOperationHeightFromDecoder(decoder func(interface{}) error, _ *encoder.Encoders) (base.Height, error) {
+	var hd struct {
+		HT base.Height `bson:"height"`
+	}
+
+	if err := decoder(&hd); err != nil {
+		return base.NilHeight, err
+	}
+
+	return hd.HT, nil
+}
+
+func loadOperationFactFromDecoder(decoder func(interface{}) error, _ *encoder.Encoders) (
+	valuehash.Hash, base.Height, error,
+) {
+	var hd struct {
+		FC string      `bson:"fact"`
+		HT base.Height `bson:"height"`
+	}
+
+	if err := decoder(&hd); err != nil {
+		return nil, base.NilHeight, err
+	}
+
+	return valuehash.NewBytesFromString(hd.FC), hd.HT, nil
+}
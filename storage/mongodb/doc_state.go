@@ -10,7 +10,8 @@ import (
 
 type StateDoc struct {
 	BaseDoc
-	state state.State
+	state  state.State
+	fields bson.M
 }
 
 func NewStateDoc(st state.State, enc encoder.Encoder) (StateDoc, error) {
@@ -19,9 +20,19 @@ func NewStateDoc(st state.State, enc encoder.Encoder) (StateDoc, error) {
 		return StateDoc{}, err
 	}
 
+	var fields bson.M
+	if v := st.Value(); v != nil {
+		i, err := extractFields(ColNameState, v.Hint().Type(), st)
+		if err != nil {
+			return StateDoc{}, err
+		}
+		fields = i
+	}
+
 	return StateDoc{
 		BaseDoc: b,
 		state:   st,
+		fields:  fields,
 	}, nil
 }
 
@@ -34,6 +45,10 @@ func (sd StateDoc) MarshalBSON() ([]byte, error) {
 	m["key"] = sd.state.Key()
 	m["height"] = sd.state.Height()
 
+	if len(sd.fields) > 0 {
+		m[fieldsDocKey] = sd.fields
+	}
+
 	return bsonenc.Marshal(m)
 }
 
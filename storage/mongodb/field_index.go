@@ -0,0 +1,297 @@
+package mongodbstorage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/hint"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FieldIndexPrefix is the name prefix of the indexes of FieldIndexer. It should
+// not start with IndexPrefix, the default indexes are recreated independently.
+var FieldIndexPrefix = "mitum-field_"
+
+// fieldsDocKey is the key of the extracted index fields in document.
+const fieldsDocKey = "f"
+
+var (
+	fieldIndexersLock sync.RWMutex
+	fieldIndexers     = map[string] /* collection */ map[hint.Type]FieldIndexer{}
+)
+
+// FieldIndexer declares the application-defined index fields of the operation
+// or state value, which has the hint type. The fields are extracted when the
+// block is committed by DatabaseSession and can be queried by
+// Database.OperationsByFields and Database.StatesByFields.
+type FieldIndexer struct {
+	col     string
+	ht      hint.Type
+	fields  []string
+	extract func(interface{}) (bson.M, error)
+}
+
+// NewOperationFieldIndexer declares the index fields of the operation, which
+// has the hint type.
+func NewOperationFieldIndexer(
+	ht hint.Type,
+	fields []string,
+	extract func(operation.Operation) (bson.M, error),
+) FieldIndexer {
+	fi := FieldIndexer{
+		col:    ColNameOperation,
+		ht:     ht,
+		fields: fields,
+	}
+
+	if extract == nil {
+		return fi
+	}
+
+	fi.extract = func(i interface{}) (bson.M, error) {
+		op, ok := i.(operation.Operation)
+		if !ok {
+			return nil, errors.Errorf("not operation.Operation, %T", i)
+		}
+
+		return extract(op)
+	}
+
+	return fi
+}
+
+// NewStateFieldIndexer declares the index fields of the state, which has the
+// state.Value of the hint type.
+func NewStateFieldIndexer(
+	ht hint.Type,
+	fields []string,
+	extract func(state.State) (bson.M, error),
+) FieldIndexer {
+	fi := FieldIndexer{
+		col:    ColNameState,
+		ht:     ht,
+		fields: fields,
+	}
+
+	if extract == nil {
+		return fi
+	}
+
+	fi.extract = func(i interface{}) (bson.M, error) {
+		st, ok := i.(state.State)
+		if !ok {
+			return nil, errors.Errorf("not state.State, %T", i)
+		}
+
+		return extract(st)
+	}
+
+	return fi
+}
+
+func (fi FieldIndexer) IsValid([]byte) error {
+	if err := fi.ht.IsValid(nil); err != nil {
+		return errors.Wrap(err, "invalid hint type of field indexer")
+	}
+
+	if fi.extract == nil {
+		return errors.Errorf("empty extract function of field indexer")
+	}
+
+	if len(fi.fields) < 1 {
+		return errors.Errorf("empty fields of field indexer")
+	}
+
+	founds := map[string]struct{}{}
+	for i := range fi.fields {
+		f := fi.fields[i]
+		if err := isValidFieldName(f); err != nil {
+			return err
+		}
+
+		if _, found := founds[f]; found {
+			return errors.Errorf("duplicated field of field indexer, %q", f)
+		}
+		founds[f] = struct{}{}
+	}
+
+	return nil
+}
+
+func (fi FieldIndexer) Collection() string {
+	return fi.col
+}
+
+func (fi FieldIndexer) Type() hint.Type {
+	return fi.ht
+}
+
+func (fi FieldIndexer) Fields() []string {
+	return fi.fields
+}
+
+// Extract returns the index fields of the operation or state. The unknown fields
+// are not allowed.
+func (fi FieldIndexer) Extract(i interface{}) (bson.M, error) {
+	m, err := fi.extract(i)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to extract index fields, %q", fi.ht)
+	}
+
+	for k := range m {
+		var found bool
+		for j := range fi.fields {
+			if k == fi.fields[j] {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, errors.Errorf("unknown index field, %q of %q", k, fi.ht)
+		}
+	}
+
+	return m, nil
+}
+
+// RegisterFieldIndexer registers FieldIndexer. The indexes of the fields are
+// created by Database.Initialize(), so it should be called before the
+// Database is initialized. The hint type can be registered only once in
+// collection.
+func RegisterFieldIndexer(fi FieldIndexer) error {
+	if err := fi.IsValid(nil); err != nil {
+		return err
+	}
+
+	fieldIndexersLock.Lock()
+	defer fieldIndexersLock.Unlock()
+
+	m, found := fieldIndexers[fi.col]
+	if !found {
+		m = map[hint.Type]FieldIndexer{}
+		fieldIndexers[fi.col] = m
+	}
+
+	if _, found := m[fi.ht]; found {
+		return errors.Errorf("field indexer of %q already registered in %q", fi.ht, fi.col)
+	}
+
+	m[fi.ht] = fi
+
+	return nil
+}
+
+func fieldIndexer(col string, ht hint.Type) (FieldIndexer, bool) {
+	fieldIndexersLock.RLock()
+	defer fieldIndexersLock.RUnlock()
+
+	fi, found := fieldIndexers[col][ht]
+
+	return fi, found
+}
+
+func hasFieldIndexers(col string) bool {
+	fieldIndexersLock.RLock()
+	defer fieldIndexersLock.RUnlock()
+
+	return len(fieldIndexers[col]) > 0
+}
+
+// fieldIndexModels returns the index models of the registered fields; the
+// same field of the different hint types shares the index.
+func fieldIndexModels() map[string] /* collection */ []mongo.IndexModel {
+	fieldIndexersLock.RLock()
+	defer fieldIndexersLock.RUnlock()
+
+	models := map[string][]mongo.IndexModel{}
+	for col := range fieldIndexers {
+		founds := map[string]struct{}{}
+		for ht := range fieldIndexers[col] {
+			fields := fieldIndexers[col][ht].fields
+			for i := range fields {
+				founds[fields[i]] = struct{}{}
+			}
+		}
+
+		fields := make([]string, len(founds))
+		var i int
+		for f := range founds {
+			fields[i] = f
+			i++
+		}
+		sort.Strings(fields)
+
+		for i := range fields {
+			models[col] = append(models[col], mongo.IndexModel{
+				Keys: bson.D{
+					bson.E{Key: fieldKey(fields[i]), Value: 1},
+					bson.E{Key: "height", Value: 1},
+				},
+				Options: options.Index().
+					SetName(fmt.Sprintf("%s%s_%s", FieldIndexPrefix, col, fields[i])).
+					SetSparse(true),
+			})
+		}
+	}
+
+	return models
+}
+
+func extractFields(col string, ht hint.Type, i interface{}) (bson.M, error) {
+	fi, found := fieldIndexer(col, ht)
+	if !found {
+		return nil, nil
+	}
+
+	return fi.Extract(i)
+}
+
+// fieldsFilter converts the filter of index fields to the document filter.
+func fieldsFilter(filter bson.M) (bson.D, error) {
+	if len(filter) < 1 {
+		return nil, errors.Errorf("empty fields filter")
+	}
+
+	keys := make([]string, len(filter))
+	var i int
+	for k := range filter {
+		if err := isValidFieldName(k); err != nil {
+			return nil, err
+		}
+
+		keys[i] = k
+		i++
+	}
+	sort.Strings(keys)
+
+	d := make(bson.D, len(keys))
+	for i := range keys {
+		d[i] = bson.E{Key: fieldKey(keys[i]), Value: filter[keys[i]]}
+	}
+
+	return d, nil
+}
+
+func fieldKey(f string) string {
+	return fieldsDocKey + "." + f
+}
+
+func isValidFieldName(f string) error {
+	switch {
+	case len(strings.TrimSpace(f)) < 1:
+		return errors.Errorf("empty index field")
+	case strings.HasPrefix(f, "$"), strings.Contains(f, "."):
+		return errors.Errorf("invalid index field, %q", f)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+//go:build test
+// +build test
+
+package mongodbstorage
+
+import (
+	"testing"
+
+	"github.com/spikeekips/mitum/base/operation"
+	"github.com/spikeekips/mitum/base/state"
+	"github.com/spikeekips/mitum/util/hint"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type testFieldIndexer struct {
+	suite.Suite
+}
+
+func (t *testFieldIndexer) TearDownTest() {
+	fieldIndexersLock.Lock()
+	defer fieldIndexersLock.Unlock()
+
+	fieldIndexers = map[string]map[hint.Type]FieldIndexer{}
+}
+
+func (t *testFieldIndexer) newStateFieldIndexer(ht hint.Type, fields ...string) FieldIndexer {
+	return NewStateFieldIndexer(ht, fields, func(st state.State) (bson.M, error) {
+		return bson.M{fields[0]: st.Key()}, nil
+	})
+}
+
+func (t *testFieldIndexer) TestIsValid() {
+	t.NoError(t.newStateFieldIndexer(state.BytesValueType, "a", "b").IsValid(nil))
+
+	err := t.newStateFieldIndexer(hint.Type(""), "a").IsValid(nil)
+	t.Contains(err.Error(), "invalid hint type")
+
+	err = NewStateFieldIndexer(state.BytesValueType, nil, func(state.State) (bson.M, error) {
+		return nil, nil
+	}).IsValid(nil)
+	t.Contains(err.Error(), "empty fields")
+
+	err = NewStateFieldIndexer(state.BytesValueType, []string{"a"}, nil).IsValid(nil)
+	t.Contains(err.Error(), "empty extract function")
+
+	err = t.newStateFieldIndexer(state.BytesValueType, "a", "a").IsValid(nil)
+	t.Contains(err.Error(), "duplicated field")
+
+	for _, f := range []string{"", " ", "$a", "a.b"} {
+		err = t.newStateFieldIndexer(state.BytesValueType, "a", f).IsValid(nil)
+		t.Error(err, "field=%q", f)
+	}
+}
+
+func (t *testFieldIndexer) TestRegister() {
+	t.NoError(RegisterFieldIndexer(t.newStateFieldIndexer(state.BytesValueType, "a")))
+
+	err := RegisterFieldIndexer(t.newStateFieldIndexer(state.BytesValueType, "b"))
+	t.Contains(err.Error(), "already registered")
+
+	// NOTE same hint type in different collection
+	t.NoError(RegisterFieldIndexer(NewOperationFieldIndexer(
+		state.BytesValueType,
+		[]string{"a"},
+		func(operation.Operation) (bson.M, error) { return nil, nil },
+	)))
+
+	t.True(hasFieldIndexers(ColNameState))
+	t.True(hasFieldIndexers(ColNameOperation))
+	t.False(hasFieldIndexers(ColNameManifest))
+}
+
+func (t *testFieldIndexer) TestIndexModels() {
+	t.NoError(RegisterFieldIndexer(t.newStateFieldIndexer(state.BytesValueType, "b", "a")))
+	t.NoError(RegisterFieldIndexer(t.newStateFieldIndexer(state.StringValueType, "a", "c")))
+
+	models := fieldIndexModels()
+	t.Equal(1, len(models))
+	t.Equal(3, len(models[ColNameState]))
+
+	for i, f := range []string{"a", "b", "c"} {
+		m := models[ColNameState][i]
+		t.Equal(bson.D{{Key: "f." + f, Value: 1}, {Key: "height", Value: 1}}, m.Keys)
+		t.Equal(FieldIndexPrefix+ColNameState+"_"+f, *m.Options.Name)
+	}
+}
+
+func (t *testFieldIndexer) TestExtract() {
+	fi := NewStateFieldIndexer(state.BytesValueType, []string{"a"}, func(st state.State) (bson.M, error) {
+		return bson.M{"a": st.Key(), "b": st.Key()}, nil
+	})
+
+	v, err := state.NewBytesValue([]byte("showme"))
+	t.NoError(err)
+
+	st, err := state.NewStateV0("key", v, 33)
+	t.NoError(err)
+
+	_, err = fi.Extract(st)
+	t.Contains(err.Error(), "unknown index field")
+
+	_, err = fi.Extract(v)
+	t.Contains(err.Error(), "not state.State")
+
+	t.NoError(RegisterFieldIndexer(t.newStateFieldIndexer(state.BytesValueType, "a")))
+
+	fields, err := extractFields(ColNameState, state.BytesValueType, st)
+	t.NoError(err)
+	t.Equal(bson.M{"a": "key"}, fields)
+
+	fields, err = extractFields(ColNameState, state.StringValueType, st)
+	t.NoError(err)
+	t.Nil(fields)
+}
+
+func (t *testFieldIndexer) TestFieldsFilter() {
+	d, err := fieldsFilter(bson.M{"b": 1, "a": bson.M{"$gt": 2}})
+	t.NoError(err)
+	t.Equal(bson.D{{Key: "f.a", Value: bson.M{"$gt": 2}}, {Key: "f.b", Value: 1}}, d)
+
+	_, err = fieldsFilter(nil)
+	t.Contains(err.Error(), "empty fields filter")
+
+	_, err = fieldsFilter(bson.M{"$or": bson.A{}})
+	t.Contains(err.Error(), "invalid index field")
+}
+
+func TestFieldIndexer(t *testing.T) {
+	suite.Run(t, new(testFieldIndexer))
+}
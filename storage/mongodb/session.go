@@ -3,15 +3,18 @@ package mongodbstorage
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spikeekips/mitum/base"
 	"github.com/spikeekips/mitum/base/block"
+	"github.com/spikeekips/mitum/base/operation"
 	"github.com/spikeekips/mitum/base/state"
 	"github.com/spikeekips/mitum/storage"
 	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/metrics"
 	"github.com/spikeekips/mitum/util/tree"
 	"github.com/spikeekips/mitum/util/valuehash"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -128,7 +131,7 @@ func (bst *DatabaseSession) setBlock(blk block.Block) error {
 		bst.manifestModel = mongo.NewInsertOneModel().SetDocument(doc)
 	}
 
-	if err := bst.setOperationsTree(blk.OperationsTree()); err != nil {
+	if err := bst.setOperationsTree(blk.OperationsTree(), blk.Operations()); err != nil {
 		return err
 	}
 
@@ -146,7 +149,12 @@ func (bst *DatabaseSession) setBlock(blk block.Block) error {
 }
 
 func (bst *DatabaseSession) Commit(ctx context.Context, bd block.BlockdataMap) error {
+	started := time.Now()
 	err := bst.commit(ctx, bd)
+
+	metrics.DatabaseCommitDuration.WithLabelValues("mongodb", strconv.FormatBool(err == nil)).
+		Observe(time.Since(started).Seconds())
+
 	if err == nil {
 		return nil
 	}
@@ -236,7 +244,7 @@ func (bst *DatabaseSession) commit(ctx context.Context, bd block.BlockdataMap) e
 	return nil
 }
 
-func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
+func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree, ops []operation.Operation) error {
 	started := time.Now()
 	defer func() {
 		bst.statesValue.Store("set-operations-tree", time.Since(started))
@@ -246,12 +254,30 @@ func (bst *DatabaseSession) setOperationsTree(tr tree.FixedTree) error {
 		return nil
 	}
 
+	// NOTE operations by fact for FieldIndexer
+	var opsByFact map[string]operation.Operation
+	if hasFieldIndexers(ColNameOperation) {
+		opsByFact = map[string]operation.Operation{}
+		for i := range ops {
+			opsByFact[ops[i].Fact().Hash().String()] = ops[i]
+		}
+	}
+
 	var models []mongo.WriteModel
 	if err := tr.Traverse(func(no tree.FixedTreeNode) (bool, error) {
-		doc, err := NewOperationDoc(valuehash.NewBytes(no.Key()), bst.st.enc, bst.block.Height())
+		fact := valuehash.NewBytes(no.Key())
+		doc, err := NewOperationDoc(fact, bst.st.enc, bst.block.Height())
 		if err != nil {
 			return false, err
 		}
+
+		if op, found := opsByFact[fact.String()]; found {
+			fields, err := extractFields(ColNameOperation, op.Hint().Type(), op)
+			if err != nil {
+				return false, err
+			}
+			doc.fields = fields
+		}
 		models = append(models, mongo.NewInsertOneModel().SetDocument(doc))
 
 		return true, nil
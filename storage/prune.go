@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spikeekips/mitum/base"
+)
+
+// PrunedHeightInfoKey is the info key of the pruned height.
+var PrunedHeightInfoKey = "pruned_height"
+
+// Pruner removes the old state history and block data. After Prune(height),
+// the block data of the blocks lower than height are removed and for each
+// state key, only the latest version lower than height is kept with the newer
+// versions, so the states at height and higher can be still served.
+//
+// > The index of operation facts is kept, so the duplicated facts of the
+// pruned heights can be still detected by HasOperationFact.
+type Pruner interface {
+	Prune(base.Height) error
+}
+
+// PrunedHeight returns the pruned height; the blocks lower than it are pruned.
+// If not pruned, base.NilHeight is returned.
+func PrunedHeight(db Database) (base.Height, error) {
+	switch b, found, err := db.Info(PrunedHeightInfoKey); {
+	case err != nil:
+		return base.NilHeight, err
+	case !found:
+		return base.NilHeight, nil
+	default:
+		h, err := base.NewHeightFromBytes(b)
+		if err != nil {
+			return base.NilHeight, errors.Wrap(err, "invalid pruned height")
+		}
+
+		return h, nil
+	}
+}
+
+// SetPrunedHeight stores the pruned height. The lower height than the current
+// one is ignored.
+func SetPrunedHeight(db Database, height base.Height) error {
+	switch h, err := PrunedHeight(db); {
+	case err != nil:
+		return err
+	case height <= h:
+		return nil
+	}
+
+	return db.SetInfo(PrunedHeightInfoKey, height.Bytes())
+}
@@ -38,10 +38,23 @@ type Database interface {
 	Proposals(func(base.Proposal) (bool, error), bool /* sort */) error
 
 	State(key string) (state.State, bool, error)
+	// NOTE StateAtHeight returns the state of key at the given height, that
+	// is, the latest state not higher than the height.
+	StateAtHeight(string /* key */, base.Height) (state.State, bool, error)
+	// NOTE StateHistory iterates the states of key by descending height. If
+	// height is not base.NilHeight, only the states under the height are
+	// iterated.
+	StateHistory(string /* key */, base.Height, int64 /* limit */, func(state.State) (bool, error)) error
+	// NOTE States iterates the latest state of every key at the given height
+	// by ascending order of key.
+	States(base.Height, func(state.State) (bool, error)) error
 	LastVoteproof(base.Stage) base.Voteproof
 	Voteproof(base.Height, base.Stage) (base.Voteproof, error)
 
 	HasOperationFact(valuehash.Hash) (bool, error)
+	// NOTE OperationFactHeight returns the height of block, which contains the
+	// operation fact.
+	OperationFactHeight(valuehash.Hash) (base.Height, bool, error)
 
 	// NOTE StagedOperationOperations returns operation.Operation by incoming order.
 	StagedOperationsByFact(facts []valuehash.Hash) ([]operation.Operation, error)
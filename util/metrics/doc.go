@@ -0,0 +1,4 @@
+/*
+Package metrics provides prometheus metrics of the node.
+*/
+package metrics
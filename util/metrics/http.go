@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// HTTPMiddleware collects the number and latency of requests by handler. It
+// should be used as mux middleware, so the handler is identified by the path
+// template of the matched route.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		labels := prometheus.Labels{"handler": HandlerLabel(r)}
+
+		promhttp.InstrumentHandlerDuration(
+			NetworkRequestDuration.MustCurryWith(labels),
+			promhttp.InstrumentHandlerCounter(NetworkRequests.MustCurryWith(labels), next),
+		).ServeHTTP(w, r)
+	})
+}
+
+// HandlerLabel returns the path template of the route matched with request.
+func HandlerLabel(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unknown"
+	}
+
+	if i, err := route.GetPathTemplate(); err == nil {
+		return i
+	}
+
+	return "unknown"
+}
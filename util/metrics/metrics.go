@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mitum"
+
+// Registry is the prometheus registry of mitum metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	ConsensusState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "state",
+		Help:      "current consensus state; 1 is the current state",
+	}, []string{"state"})
+	ConsensusHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "height",
+		Help:      "height of last voteproof",
+	})
+	ConsensusRound = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "round",
+		Help:      "round of last voteproof",
+	})
+	ConsensusStuck = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "stuck",
+		Help:      "1 if consensus is stuck",
+	})
+	Ballots = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "ballots_total",
+		Help:      "number of received ballots by stage",
+	}, []string{"stage"})
+	Voteproofs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "voteproofs_total",
+		Help:      "number of processed voteproofs by stage and result",
+	}, []string{"stage", "result"})
+	ProposalProcessorState = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "proposal_processor",
+		Name:      "state_seconds",
+		Help:      "time spent in each proposal processor state",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"state"})
+	NetworkRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "network",
+		Name:      "requests_total",
+		Help:      "number of network requests by handler",
+	}, []string{"handler", "method", "code"})
+	NetworkRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "network",
+		Name:      "request_seconds",
+		Help:      "latency of network requests by handler",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"handler", "method"})
+	RateLimitRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "network",
+		Name:      "ratelimit_rejected_total",
+		Help:      "number of requests rejected by rate limit",
+	}, []string{"handler"})
+	SyncerTargetHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "syncer",
+		Name:      "target_height",
+		Help:      "target height of syncing",
+	})
+	SyncerHeight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "syncer",
+		Name:      "height",
+		Help:      "last height saved by syncing",
+	})
+	DatabaseCommitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "commit_seconds",
+		Help:      "duration of database session commit",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"database", "success"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ConsensusState,
+		ConsensusHeight,
+		ConsensusRound,
+		ConsensusStuck,
+		Ballots,
+		Voteproofs,
+		ProposalProcessorState,
+		NetworkRequests,
+		NetworkRequestDuration,
+		RateLimitRejected,
+		SyncerTargetHeight,
+		SyncerHeight,
+		DatabaseCommitDuration,
+	)
+}
+
+// Handler returns http.Handler, which exports the metrics of Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// SetConsensusState marks the given state as current state.
+func SetConsensusState(from, to string) {
+	if len(from) > 0 {
+		ConsensusState.WithLabelValues(from).Set(0)
+	}
+
+	ConsensusState.WithLabelValues(to).Set(1)
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spikeekips/mitum/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type testMetrics struct {
+	suite.Suite
+}
+
+func (t *testMetrics) TestConsensusState() {
+	SetConsensusState("", "BOOTING")
+	t.Equal(float64(1), testutil.ToFloat64(ConsensusState.WithLabelValues("BOOTING")))
+
+	SetConsensusState("BOOTING", "JOINING")
+	t.Equal(float64(0), testutil.ToFloat64(ConsensusState.WithLabelValues("BOOTING")))
+	t.Equal(float64(1), testutil.ToFloat64(ConsensusState.WithLabelValues("JOINING")))
+}
+
+func (t *testMetrics) TestHTTPMiddleware() {
+	router := mux.NewRouter()
+	router.Use(HTTPMiddleware)
+	router.Path("/blockdata/{path:.*}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := "/blockdata/{path:.*}"
+	before := testutil.ToFloat64(NetworkRequests.WithLabelValues(handler, "get", "200"))
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf("/blockdata/%d", i), nil))
+		t.Equal(http.StatusOK, w.Code)
+	}
+
+	t.Equal(before+3, testutil.ToFloat64(NetworkRequests.WithLabelValues(handler, "get", "200")))
+}
+
+func (t *testMetrics) TestServer() {
+	port, err := util.FreePort("tcp")
+	t.NoError(err)
+
+	bind := fmt.Sprintf("127.0.0.1:%d", port)
+
+	sv := NewServer(bind)
+	t.NoError(sv.Start())
+	defer func() {
+		_ = sv.Stop()
+	}()
+
+	ConsensusHeight.Set(33)
+
+	client := &http.Client{Timeout: time.Second * 3}
+	res, err := client.Get("http://" + bind + DefaultPath)
+	t.NoError(err)
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	t.Equal(http.StatusOK, res.StatusCode)
+
+	b, err := ioutil.ReadAll(res.Body)
+	t.NoError(err)
+	t.True(strings.Contains(string(b), "mitum_consensus_height 33"))
+}
+
+func (t *testMetrics) TestServerBindAlreadyUsed() {
+	port, err := util.FreePort("tcp")
+	t.NoError(err)
+
+	bind := fmt.Sprintf("127.0.0.1:%d", port)
+
+	sv := NewServer(bind)
+	t.NoError(sv.Start())
+	defer func() {
+		_ = sv.Stop()
+	}()
+
+	err = NewServer(bind).Start()
+	t.Error(err)
+	t.Contains(err.Error(), "failed to open metrics server")
+}
+
+func TestMetrics(t *testing.T) {
+	suite.Run(t, new(testMetrics))
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/spikeekips/mitum/util"
+	"github.com/spikeekips/mitum/util/logging"
+)
+
+var DefaultPath = "/metrics"
+
+// Server serves the metrics through plain http.
+type Server struct {
+	*logging.Logging
+	*util.ContextDaemon
+	bind     string
+	listener net.Listener
+}
+
+func NewServer(bind string) *Server {
+	sv := &Server{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "metrics-server")
+		}),
+		bind: bind,
+	}
+
+	sv.ContextDaemon = util.NewContextDaemon("metrics-server", sv.run)
+
+	return sv
+}
+
+func (sv *Server) SetLogging(l *logging.Logging) *logging.Logging {
+	_ = sv.ContextDaemon.SetLogging(l)
+
+	return sv.Logging.SetLogging(l)
+}
+
+func (sv *Server) Start() error {
+	listener, err := net.Listen("tcp", sv.bind)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open metrics server, %q", sv.bind)
+	}
+
+	sv.listener = listener
+
+	return sv.ContextDaemon.Start()
+}
+
+func (sv *Server) run(ctx context.Context) error {
+	mx := http.NewServeMux()
+	mx.Handle(DefaultPath, Handler())
+
+	server := &http.Server{Handler: mx, ReadHeaderTimeout: time.Second * 3}
+
+	errch := make(chan error, 1)
+	go func() {
+		if err := server.Serve(sv.listener); !errors.Is(err, http.ErrServerClosed) {
+			sv.Log().Error().Err(err).Msg("metrics server failed")
+
+			errch <- err
+		}
+	}()
+
+	sv.Log().Debug().Str("bind", sv.bind).Msg("metrics server started")
+
+	select {
+	case err := <-errch:
+		return err
+	case <-ctx.Done():
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+
+		return server.Shutdown(sctx) // nolint:contextcheck
+	}
+}
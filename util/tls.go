@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"math/big"
 	"net"
@@ -17,12 +18,15 @@ func GenerateED25519Privatekey() (ed25519.PrivateKey, error) {
 	return priv, err
 }
 
-func GenerateTLSCertsPair(host string, key ed25519.PrivateKey) (*pem.Block, *pem.Block, error) {
+func GenerateTLSCertsPair(
+	host string, key ed25519.PrivateKey, extensions ...pkix.Extension,
+) (*pem.Block, *pem.Block, error) {
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(1),
-		DNSNames:     []string{host},
-		NotBefore:    time.Now().Add(time.Minute * -1),
-		NotAfter:     time.Now().Add(time.Hour * 24 * 1825),
+		SerialNumber:    big.NewInt(1),
+		DNSNames:        []string{host},
+		NotBefore:       time.Now().Add(time.Minute * -1),
+		NotAfter:        time.Now().Add(time.Hour * 24 * 1825),
+		ExtraExtensions: extensions,
 	}
 
 	if i := net.ParseIP(host); i != nil {
@@ -50,8 +54,8 @@ func GenerateTLSCertsPair(host string, key ed25519.PrivateKey) (*pem.Block, *pem
 		nil
 }
 
-func GenerateTLSCerts(host string, key ed25519.PrivateKey) ([]tls.Certificate, error) {
-	k, c, err := GenerateTLSCertsPair(host, key)
+func GenerateTLSCerts(host string, key ed25519.PrivateKey, extensions ...pkix.Extension) ([]tls.Certificate, error) {
+	k, c, err := GenerateTLSCertsPair(host, key, extensions...)
 	if err != nil {
 		return nil, err
 	}
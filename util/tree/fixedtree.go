@@ -191,7 +191,8 @@ func (tr FixedTree) Traverse(f func(FixedTreeNode) (bool, error)) error {
 }
 
 // Proof returns the nodes to prove whether node is in tree. It always returns
-// root node + N(2 children).
+// root node + N(2 children). The missing child is nil, so the first 2 children
+// of leaf node are nil.
 func (tr FixedTree) Proof(index uint64) ([]FixedTreeNode, error) {
 	self, err := tr.Node(index)
 	if err != nil {
@@ -386,6 +387,10 @@ func proveFixedTreeProof(pr []FixedTreeNode) error {
 	}
 
 	for i := range pr {
+		if pr[i] == nil {
+			continue
+		}
+
 		if err := pr[i].IsValid(nil); err != nil {
 			return InvalidNodeError.Errorf("node, %d", i)
 		}
@@ -393,8 +398,12 @@ func proveFixedTreeProof(pr []FixedTreeNode) error {
 
 	for i := 0; i < len(pr[:len(pr)-1])/2; i++ {
 		a, b := pr[(i*2)], pr[(i*2)+1]
+		if a == nil { // NOTE leaf node has no children
+			continue
+		}
+
 		if p, err := parentNodeInProof(i, pr, a.Index()); err != nil {
-			return errors.Wrapf(err, "nodes, %d and %d", a.Index(), b.Index())
+			return errors.Wrapf(err, "children of node, %d", a.Index())
 		} else if h, err := FixedTreeNodeHash(p, a, b); err != nil {
 			return err
 		} else if !bytes.Equal(p.Hash(), h) {
@@ -405,6 +414,106 @@ func proveFixedTreeProof(pr []FixedTreeNode) error {
 	return nil
 }
 
+// ProveFixedTreeNode checks whether the node of key is in the tree of root
+// with the proof from FixedTree.Proof. Unlike ProveFixedTreeProof, the hash of
+// every node in the path from the node to root is regenerated, so the key of
+// node is bound to root.
+func ProveFixedTreeNode(pr []FixedTreeNode, key, root []byte) error {
+	if err := proveFixedTreeNode(pr, key, root); err != nil {
+		return InvalidProofError.Wrap(err)
+	}
+
+	return nil
+}
+
+func proveFixedTreeNode(pr []FixedTreeNode, key, root []byte) error {
+	if err := proveFixedTreeProof(pr); err != nil {
+		return err
+	}
+
+	top := pr[len(pr)-1]
+	if !bytes.Equal(top.Hash(), root) {
+		return HashNotMatchError.Errorf("root hash does not match")
+	}
+
+	last := len(pr[:len(pr)-1])/2 - 1
+
+	var p FixedTreeNode
+	if last == 0 {
+		p = top
+	} else {
+		p = findNodeInProof(pr, 1, key)
+	}
+
+	switch {
+	case p == nil:
+		return util.NotFoundError.Errorf("node not found in proof")
+	case !bytes.Equal(p.Key(), key):
+		return errors.Errorf("key of node does not match")
+	}
+
+	for i := 0; i <= last; i++ {
+		if err := checkChildrenInProof(p, pr[(i*2)], pr[(i*2)+1]); err != nil {
+			return err
+		}
+
+		if h, err := FixedTreeNodeHash(p, pr[(i*2)], pr[(i*2)+1]); err != nil {
+			return err
+		} else if !bytes.Equal(p.Hash(), h) {
+			return HashNotMatchError.Errorf("node, %d has wrong hash", p.Index())
+		}
+
+		switch {
+		case i == last:
+			if p.Index() != 0 {
+				return errors.Errorf("node, %d is not root", p.Index())
+			}
+		case i == last-1:
+			p = top
+		default:
+			p = findNodeInProofByIndex(pr, i+2, (p.Index()-1)/2)
+			if p == nil {
+				return errors.Errorf("parent node not found in proof")
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkChildrenInProof(p, a, b FixedTreeNode) error {
+	switch {
+	case a == nil && b != nil:
+		return errors.Errorf("right child without left child, %d", p.Index())
+	case a != nil && a.Index() != p.Index()*2+1:
+		return errors.Errorf("wrong left child of node, %d", p.Index())
+	case b != nil && b.Index() != p.Index()*2+2:
+		return errors.Errorf("wrong right child of node, %d", p.Index())
+	default:
+		return nil
+	}
+}
+
+func findNodeInProof(pr []FixedTreeNode, i int, key []byte) FixedTreeNode {
+	for _, n := range pr[(i * 2) : (i*2)+2] {
+		if n != nil && bytes.Equal(n.Key(), key) {
+			return n
+		}
+	}
+
+	return nil
+}
+
+func findNodeInProofByIndex(pr []FixedTreeNode, i int, index uint64) FixedTreeNode {
+	for _, n := range pr[(i * 2) : (i*2)+2] {
+		if n != nil && n.Index() == index {
+			return n
+		}
+	}
+
+	return nil
+}
+
 func parentNodeInProof(i int, pr []FixedTreeNode, index uint64) (FixedTreeNode, error) {
 	maxSize := int(math.Pow(2, float64(len(pr[:len(pr)-1])/2)+1)) - 1
 
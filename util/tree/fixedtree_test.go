@@ -217,6 +217,81 @@ func (t *testFixedTree) TestProof() {
 	t.NoError(ProveFixedTreeProof(pr))
 }
 
+func (t *testFixedTree) TestProveNode() {
+	for l := uint64(1); l < 20; l++ {
+		trg := NewFixedTreeGenerator(l)
+
+		for i := uint64(0); i < l; i++ {
+			n := NewBaseFixedTreeNode(t.hint, i, util.UUID().Bytes())
+			t.NoError(trg.Add(n))
+		}
+
+		tr, err := trg.Tree()
+		t.NoError(err)
+
+		for i := uint64(0); i < l; i++ {
+			n, err := tr.Node(i)
+			t.NoError(err)
+
+			pr, err := tr.Proof(i)
+			t.NoError(err)
+
+			t.NoError(ProveFixedTreeProof(pr), "size=%d index=%d", l, i)
+			t.NoError(ProveFixedTreeNode(pr, n.Key(), tr.Root()), "size=%d index=%d", l, i)
+		}
+	}
+}
+
+func (t *testFixedTree) TestProveNodeWrong() {
+	l := uint64(15)
+	trg := NewFixedTreeGenerator(l)
+
+	for i := uint64(0); i < l; i++ {
+		n := NewBaseFixedTreeNode(t.hint, i, util.UUID().Bytes())
+		t.NoError(trg.Add(n))
+	}
+
+	tr, err := trg.Tree()
+	t.NoError(err)
+
+	n, err := tr.Node(9)
+	t.NoError(err)
+
+	pr, err := tr.Proof(n.Index())
+	t.NoError(err)
+
+	{ // unknown key
+		err := ProveFixedTreeNode(pr, util.UUID().Bytes(), tr.Root())
+		t.True(errors.Is(err, InvalidProofError))
+	}
+
+	{ // wrong root
+		err := ProveFixedTreeNode(pr, n.Key(), util.UUID().Bytes())
+		t.True(errors.Is(err, InvalidProofError))
+		t.True(errors.Is(err, HashNotMatchError))
+	}
+
+	{ // wrong key of leaf node
+		upr := make([]FixedTreeNode, len(pr))
+		copy(upr, pr)
+
+		key := util.UUID().Bytes()
+		for i := range upr {
+			if upr[i] != nil && upr[i].Index() == n.Index() {
+				j := upr[i].(BaseFixedTreeNode)
+				j.key = key
+				upr[i] = j
+			}
+		}
+
+		t.NoError(ProveFixedTreeProof(upr))
+
+		err := ProveFixedTreeNode(upr, key, tr.Root())
+		t.True(errors.Is(err, InvalidProofError))
+		t.True(errors.Is(err, HashNotMatchError))
+	}
+}
+
 func (t *testFixedTree) TestEncodeJSON() {
 	l := uint64(15)
 	trg := NewFixedTreeGenerator(l)